@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "重新生成 testdata 中的 golden 文件")
+
+// assertGolden 比较生成文件与 testdata/<golden>，-update 时覆盖 golden 文件
+func assertGolden(t *testing.T, path, golden string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	golden = filepath.Join("testdata", golden)
+	if *update {
+		require.NoError(t, os.WriteFile(golden, got, 0o644))
+		return
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "run go test ./cmd/boxgen -update to refresh %s", golden)
+}
+
+func TestParseProto(t *testing.T) {
+	src, err := os.ReadFile("testdata/user.proto")
+	require.NoError(t, err)
+	f, err := parseProto(string(src))
+	require.NoError(t, err)
+
+	assert.Equal(t, "user.v1", f.pkg)
+	assert.Equal(t, "userpb", f.goPackage)
+	require.Len(t, f.services, 1)
+	assert.Equal(t, []protoMethod{
+		{Name: "GetUser", Path: "/api/v1/user_service/get_user", Request: "GetUserRequest", Response: "User"},
+		{Name: "ListUsers", Path: "/api/v1/users", Request: "ListUsersRequest", Response: "ListUsersResponse", Method: "GET"},
+		{Name: "UpdateUser", Path: "/api/v1/users/:id", Request: "User", Response: "emptypb.Empty", Method: "PUT"},
+		{Name: "WatchUsers", Path: "/api/v1/user_service/watch_users", Request: "ListUsersRequest", Response: "User", Stream: true},
+		{Name: "Chat", Path: "/api/v1/user_service/chat", Request: "ChatMessage", Response: "ChatMessage", Bidi: true},
+		{Name: "Search", Path: "/api/v1/user_service/search", Request: "GetUserRequest", Response: "User_Profile"},
+	}, f.services[0].Methods)
+	assert.Equal(t, []errorCode{
+		{Ident: "ErrUserNotFound", Name: "UserNotFound", Value: 10001, Description: "用户不存在", HTTPStatus: 404},
+		{Ident: "ErrNameTaken", Name: "NameTaken", Value: 10002, Description: "用户名已被占用"},
+		{Ident: "ErrPasswordWeak", Name: "PasswordWeak", Value: 10003, Description: humanize("PasswordWeak")},
+	}, f.errors)
+	assert.Len(t, f.warnings, 1)
+
+	_, err = parseProto("package a;\nservice S {\n")
+	assert.Error(t, err)
+	_, err = parseProto("service S {}\n")
+	assert.Error(t, err)
+}
+
+func TestGenerateProto(t *testing.T) {
+	dir := t.TempDir()
+	g := &generator{out: dir, langs: []string{"en", "ja"}}
+
+	// 已翻译的文案在重新生成时保留
+	existing := "package userpb\n\nimport \"github.com/code-sigs/go-box/pkg/rpcerror\"\n\n" +
+		"func init() {\n\trpcerror.RegisterMessages(\"en\", map[int64]string{\n\t\tErrUserNotFound: \"The user does not exist\",\n\t})\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user_errors_en.go"), []byte(existing), 0o644))
+
+	require.NoError(t, g.proto("testdata/user.proto"))
+	assertGolden(t, filepath.Join(dir, "user.box.go"), "user.box.go.golden")
+	assertGolden(t, filepath.Join(dir, "user_errors_en.go"), "user_errors_en.go.golden")
+	assertGolden(t, filepath.Join(dir, "user_errors_ja.go"), "user_errors_ja.go.golden")
+}
+
+func TestGenerateGoPackage(t *testing.T) {
+	dir := t.TempDir()
+	src, err := os.ReadFile("testdata/model/user.go")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user.go"), src, 0o644))
+
+	g := &generator{langs: []string{"en"}}
+	require.NoError(t, g.goPackage(dir))
+	assertGolden(t, filepath.Join(dir, "boxgen_repository.go"), "model/boxgen_repository.go.golden")
+	assertGolden(t, filepath.Join(dir, "boxgen_errors.go"), "model/boxgen_errors.go.golden")
+	assertGolden(t, filepath.Join(dir, "boxgen_errors_en.go"), "model/boxgen_errors_en.go.golden")
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goPackage Go 包中 boxgen 关心的部分
+type goPackage struct {
+	name     string
+	entities []entity
+	errors   []errorCode
+	imports  map[string]string // 字段类型引用的包：包名 -> 路径
+}
+
+// entity 带 boxgen:repository 注解的结构体
+type entity struct {
+	Name    string
+	Key     string // 主键类型，默认 string
+	Lookups []lookup
+}
+
+// lookup 带 boxgen 标签的字段，生成按字段查询的方法与索引
+type lookup struct {
+	Field  string // Go 字段名
+	Param  string // 方法参数名
+	BSON   string // 文档中的字段名
+	Type   string // 字段类型源码
+	Unique bool   // unique 生成 GetByX 返回单条，index 生成 FindByX 返回列表
+}
+
+// parseGoPackage 解析目录下的 Go 文件（跳过测试与 boxgen 生成的文件）。
+// 支持的注解：
+//
+//	type 前      boxgen:repository [key=int64]    生成仓库接口与 Mongo 实现
+//	字段标签     boxgen:"unique" / boxgen:"index" 生成 GetByX / FindByX 与对应索引
+//	const 块前   boxgen:errors                    为块中的错误码生成注册与文案，行尾注释为描述，可含 boxgen:http=404
+func parseGoPackage(dir string) (*goPackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkg := &goPackage{imports: map[string]string{}}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || strings.HasPrefix(name, "boxgen_") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if pkg.name == "" {
+			pkg.name = file.Name.Name
+		}
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			switch gd.Tok {
+			case token.TYPE:
+				if err := pkg.addEntities(file, gd); err != nil {
+					return nil, err
+				}
+			case token.CONST:
+				if hasAnnotation(commentLines(gd.Doc), "boxgen:errors") {
+					pkg.addErrors(gd)
+				}
+			}
+		}
+	}
+	if pkg.name == "" {
+		return nil, fmt.Errorf("no Go files")
+	}
+	sort.Slice(pkg.entities, func(i, j int) bool { return pkg.entities[i].Name < pkg.entities[j].Name })
+	return pkg, nil
+}
+
+func (p *goPackage) addEntities(file *ast.File, gd *ast.GenDecl) error {
+	for _, spec := range gd.Specs {
+		ts := spec.(*ast.TypeSpec)
+		doc := ts.Doc
+		if doc == nil && len(gd.Specs) == 1 {
+			doc = gd.Doc
+		}
+		lines := commentLines(doc)
+		if !hasAnnotation(lines, "boxgen:repository") {
+			continue
+		}
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			return fmt.Errorf("%s: boxgen:repository requires a struct type", ts.Name.Name)
+		}
+		ent := entity{Name: ts.Name.Name, Key: "string"}
+		for _, opt := range strings.Fields(annotation(lines, "boxgen:repository")) {
+			if v, ok := strings.CutPrefix(opt, "key="); ok {
+				ent.Key = v
+			}
+		}
+		for _, field := range st.Fields.List {
+			if field.Tag == nil || len(field.Names) == 0 {
+				continue
+			}
+			tag, _ := strconv.Unquote(field.Tag.Value)
+			kind := reflect.StructTag(tag).Get("boxgen")
+			if kind == "" {
+				continue
+			}
+			if kind != "unique" && kind != "index" {
+				return fmt.Errorf("%s.%s: unknown boxgen tag %q", ts.Name.Name, field.Names[0].Name, kind)
+			}
+			typ := exprString(field.Type)
+			p.collectImports(file, field.Type)
+			for _, n := range field.Names {
+				p.addLookup(&ent, n.Name, tag, typ, kind == "unique")
+			}
+		}
+		p.entities = append(p.entities, ent)
+	}
+	return nil
+}
+
+func (p *goPackage) addLookup(ent *entity, field, tag, typ string, unique bool) {
+	bsonName, _, _ := strings.Cut(reflect.StructTag(tag).Get("bson"), ",")
+	if bsonName == "" || bsonName == "-" {
+		// 与 mongo 驱动的默认规则一致
+		bsonName = strings.ToLower(field)
+	}
+	ent.Lookups = append(ent.Lookups, lookup{Field: field, Param: lowerFirst(field), BSON: bsonName, Type: typ, Unique: unique})
+}
+
+// addErrors 收集 const 块中的常量，名称去掉 Err / Error 前缀作为 errs.CodeInfo.Name
+func (p *goPackage) addErrors(gd *ast.GenDecl) {
+	for _, spec := range gd.Specs {
+		vs := spec.(*ast.ValueSpec)
+		desc := strings.Join(commentLines(vs.Comment), " ")
+		if desc == "" {
+			desc = strings.Join(commentLines(vs.Doc), " ")
+		}
+		for _, n := range vs.Names {
+			if n.Name == "_" || !n.IsExported() {
+				continue
+			}
+			name := strings.TrimPrefix(strings.TrimPrefix(n.Name, "Error"), "Err")
+			p.errors = append(p.errors, newErrorCode(n.Name, name, desc))
+		}
+	}
+}
+
+// collectImports 记录字段类型中 pkg.Type 引用的包
+func (p *goPackage) collectImports(file *ast.File, expr ast.Expr) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		id, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		for _, imp := range file.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			name := filepath.Base(path)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if name == id.Name {
+				p.imports[id.Name] = path
+			}
+		}
+		return false
+	})
+}
+
+// commentLines 返回注释各行去掉注释符号后的内容，与 CommentGroup.Text 不同，保留 //boxgen:xxx 形式的指令行
+func commentLines(cg *ast.CommentGroup) []string {
+	if cg == nil {
+		return nil
+	}
+	var lines []string
+	for _, c := range cg.List {
+		text := strings.TrimPrefix(c.Text, "//")
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+func exprString(expr ast.Expr) string {
+	return types.ExprString(expr)
+}
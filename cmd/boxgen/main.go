@@ -0,0 +1,149 @@
+// boxgen 根据 proto 文件或带注解的 Go 代码生成 go-box 服务的重复代码：
+//
+//   - proto service：Router 路由绑定（router.Bind，不使用反射），服务端实现与 gRPC 客户端各一份
+//   - proto enum 或 Go const 块（注释含 boxgen:errors）：错误码常量、errs.Register 注册与多语言文案
+//   - Go struct（注释含 boxgen:repository）：基于 repository.BaseRepository 的仓库接口与 Mongo 实现
+//
+// 用法：
+//
+//	//go:generate go run github.com/code-sigs/go-box/cmd/boxgen -out ../pb user.proto
+//	//go:generate go run github.com/code-sigs/go-box/cmd/boxgen .
+//
+// 参数为 .proto 文件或 Go 包目录，可混合传入多个。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	out := flag.String("out", "", "proto 生成代码的输出目录，默认与 proto 文件同目录，应为 protoc 生成的 .pb.go 所在目录")
+	langs := flag.String("langs", "en", "需要生成文案桩的语言，逗号分隔；默认语言 zh-CN 使用注释中的描述")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: boxgen [-out dir] [-langs en,ja] <file.proto | package dir>...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	g := &generator{out: *out}
+	for _, lang := range strings.Split(*langs, ",") {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			g.langs = append(g.langs, lang)
+		}
+	}
+	for _, arg := range flag.Args() {
+		var err error
+		if strings.HasSuffix(arg, ".proto") {
+			err = g.proto(arg)
+		} else {
+			err = g.goPackage(arg)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "boxgen: %s: %v\n", arg, err)
+			os.Exit(1)
+		}
+	}
+}
+
+type generator struct {
+	out   string
+	langs []string
+}
+
+// proto 为 proto 文件生成 <name>.box.go 与错误码文案桩
+func (g *generator) proto(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	file, err := parseProto(string(src))
+	if err != nil {
+		return err
+	}
+	for _, w := range file.warnings {
+		fmt.Fprintf(os.Stderr, "boxgen: %s: %s\n", path, w)
+	}
+	dir := g.out
+	if dir == "" {
+		dir = filepath.Dir(path)
+	}
+	base := strings.TrimSuffix(filepath.Base(path), ".proto")
+	if len(file.services) > 0 || len(file.errors) > 0 {
+		code, err := renderProto(file, filepath.Base(path))
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(dir, base+".box.go"), code); err != nil {
+			return err
+		}
+	}
+	return g.i18nStubs(dir, base+"_errors", file.goPackage, file.errors)
+}
+
+// goPackage 为 Go 包目录生成 boxgen_repository.go、boxgen_errors.go 与文案桩
+func (g *generator) goPackage(dir string) error {
+	pkg, err := parseGoPackage(dir)
+	if err != nil {
+		return err
+	}
+	if len(pkg.entities) > 0 {
+		code, err := renderRepositories(pkg)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(dir, "boxgen_repository.go"), code); err != nil {
+			return err
+		}
+	}
+	if len(pkg.errors) > 0 {
+		code, err := renderGoErrors(pkg)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(dir, "boxgen_errors.go"), code); err != nil {
+			return err
+		}
+	}
+	return g.i18nStubs(dir, "boxgen_errors", pkg.name, pkg.errors)
+}
+
+// i18nStubs 为每种语言生成可编辑的文案文件，已有文件中翻译过的文案会保留
+func (g *generator) i18nStubs(dir, base, pkgName string, codes []errorCode) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	for _, lang := range g.langs {
+		path := filepath.Join(dir, base+"_"+langSuffix(lang)+".go")
+		existing, err := readMessages(path)
+		if err != nil {
+			return err
+		}
+		code, err := renderMessages(pkgName, lang, codes, existing)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(path, code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, code []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, code, 0o644); err != nil {
+		return err
+	}
+	fmt.Println("boxgen: wrote", path)
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// camel USER_NOT_FOUND、get_user、getUser 转为 UserNotFound、GetUser
+func camel(s string) string {
+	var b strings.Builder
+	upper := true
+	allUpper := strings.ToUpper(s) == s
+	for _, r := range s {
+		switch {
+		case r == '_' || r == '-' || r == '.' || r == ' ':
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		case allUpper:
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// snake GetUser、UserHTTPService 转为 get_user、user_http_service
+func snake(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// lowerFirst 首字母小写，用作参数名
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToLower(runes[0])
+	name := string(runes)
+	if isKeyword(name) {
+		name += "_"
+	}
+	return name
+}
+
+// humanize UserNotFound 转为 "User not found"，作为未翻译文案的占位
+func humanize(s string) string {
+	words := strings.Split(snake(s), "_")
+	if len(words) == 0 || words[0] == "" {
+		return s
+	}
+	words[0] = camel(words[0])
+	return strings.Join(words, " ")
+}
+
+// langSuffix zh-CN 转为 zh_cn，用于文件名
+func langSuffix(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(lang, "-", "_"))
+}
+
+func isKeyword(s string) bool {
+	switch s {
+	case "break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func",
+		"go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct",
+		"switch", "type", "var":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// protoFile proto 文件中 boxgen 关心的部分
+type protoFile struct {
+	pkg       string // proto package
+	goPackage string // 生成代码的 Go 包名
+	services  []protoService
+	errors    []errorCode
+	wellKnown map[string]string // 引用到的 Well-Known 类型所需的 import：包名 -> 路径
+	warnings  []string
+}
+
+type protoService struct {
+	Name    string
+	Methods []protoMethod
+}
+
+type protoMethod struct {
+	Name     string
+	Path     string
+	Request  string // Go 类型，不含 *
+	Response string
+	Method   string // HTTP 方法，为空时为 POST
+	Stream   bool   // 服务端流式，生成 NDJSON 路由
+	Bidi     bool   // 双向流，生成 WebSocket 路由
+}
+
+// errorCode 一个错误码，来自 proto enum 值或 Go 常量
+type errorCode struct {
+	Ident       string // Go 常量名
+	Name        string // errs.CodeInfo.Name
+	Value       int64  // 仅 proto 来源使用，Go 来源引用已有常量
+	Description string // 默认语言文案
+	HTTPStatus  int
+}
+
+var (
+	protoPackageRe   = regexp.MustCompile(`^package\s+([\w.]+)\s*;`)
+	protoGoPackageRe = regexp.MustCompile(`^option\s+go_package\s*=\s*"([^"]+)"\s*;`)
+	protoServiceRe   = regexp.MustCompile(`^service\s+(\w+)\s*\{`)
+	protoEnumRe      = regexp.MustCompile(`^enum\s+(\w+)\s*\{`)
+	protoMessageRe   = regexp.MustCompile(`^message\s+(\w+)\s*\{`)
+	protoRPCRe       = regexp.MustCompile(`^rpc\s+(\w+)\s*\(\s*(stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\)`)
+	protoEnumValueRe = regexp.MustCompile(`^(\w+)\s*=\s*(-?\d+)`)
+	blockCommentRe   = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	httpStatusRe     = regexp.MustCompile(`boxgen:http=(\d+)`)
+)
+
+var httpMethods = map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+
+// wellKnownTypes 请求或响应可直接使用的 Well-Known 类型
+var wellKnownTypes = map[string][2]string{
+	"google.protobuf.Empty":  {"emptypb", "google.golang.org/protobuf/types/known/emptypb"},
+	"google.protobuf.Struct": {"structpb", "google.golang.org/protobuf/types/known/structpb"},
+	"google.protobuf.Any":    {"anypb", "google.golang.org/protobuf/types/known/anypb"},
+}
+
+// parseProto 按行解析 proto，只识别 boxgen 需要的声明，不做完整语法校验。
+// 支持的注解（写在声明前的 // 注释中）：
+//
+//	service 前  boxgen:prefix /api/v1   默认路由前缀
+//	rpc 前      boxgen:route /user/get  指定路由，可加方法前缀如 boxgen:route PUT /users/:id；boxgen:skip 不生成
+//	enum 前     boxgen:errors           生成错误码，值的行尾注释为描述，可含 boxgen:http=404
+func parseProto(src string) (*protoFile, error) {
+	f := &protoFile{wellKnown: map[string]string{}}
+	src = blockCommentRe.ReplaceAllString(src, "")
+
+	type block struct {
+		kind string // service / enum / message / other
+		name string
+		errs bool
+	}
+	var (
+		stack   []block
+		comment []string // 紧邻当前行之前的注释
+		prefix  string   // 当前 service 的路由前缀
+		opening *block   // 本行声明的块，遇到 { 时入栈
+	)
+	inside := func(kind string) *block {
+		if len(stack) > 0 && stack[len(stack)-1].kind == kind {
+			return &stack[len(stack)-1]
+		}
+		return nil
+	}
+
+	for n, raw := range strings.Split(src, "\n") {
+		code, trailing := splitComment(raw)
+		line := strings.TrimSpace(code)
+		if line == "" {
+			if strings.TrimSpace(raw) != "" {
+				comment = append(comment, strings.TrimSpace(trailing))
+			} else {
+				comment = nil
+			}
+			continue
+		}
+		lead := comment
+		comment = nil
+
+		switch {
+		case protoPackageRe.MatchString(line):
+			f.pkg = protoPackageRe.FindStringSubmatch(line)[1]
+		case protoGoPackageRe.MatchString(line):
+			f.goPackage = goPackageName(protoGoPackageRe.FindStringSubmatch(line)[1])
+		case protoServiceRe.MatchString(line):
+			name := protoServiceRe.FindStringSubmatch(line)[1]
+			prefix = strings.TrimRight(annotation(lead, "boxgen:prefix"), "/")
+			f.services = append(f.services, protoService{Name: name})
+			opening = &block{kind: "service", name: name}
+		case protoEnumRe.MatchString(line):
+			name := protoEnumRe.FindStringSubmatch(line)[1]
+			opening = &block{kind: "enum", name: name, errs: hasAnnotation(lead, "boxgen:errors")}
+		case protoMessageRe.MatchString(line):
+			opening = &block{kind: "message", name: protoMessageRe.FindStringSubmatch(line)[1]}
+		case inside("service") != nil && protoRPCRe.MatchString(line):
+			m := protoRPCRe.FindStringSubmatch(line)
+			svc := &f.services[len(f.services)-1]
+			if hasAnnotation(lead, "boxgen:skip") {
+				break
+			}
+			if m[2] != "" && m[4] == "" {
+				f.warnings = append(f.warnings, fmt.Sprintf("line %d: %s.%s is a client streaming method, skipped", n+1, svc.Name, m[1]))
+				break
+			}
+			req, err := f.goType(m[3])
+			if err != nil {
+				f.warnings = append(f.warnings, fmt.Sprintf("line %d: %s.%s: %v, skipped", n+1, svc.Name, m[1], err))
+				break
+			}
+			resp, err := f.goType(m[5])
+			if err != nil {
+				f.warnings = append(f.warnings, fmt.Sprintf("line %d: %s.%s: %v, skipped", n+1, svc.Name, m[1], err))
+				break
+			}
+			method := protoMethod{Name: m[1], Request: req, Response: resp, Stream: m[2] == "" && m[4] != "", Bidi: m[2] != ""}
+			route := annotation(lead, "boxgen:route")
+			if verb, rest, ok := strings.Cut(route, " "); ok && httpMethods[verb] {
+				route = strings.TrimSpace(rest)
+				if verb != "POST" {
+					method.Method = verb
+				}
+			}
+			if route == "" {
+				route = prefix + "/" + snake(svc.Name) + "/" + snake(m[1])
+			}
+			if method.Method != "" && (method.Stream || method.Bidi) {
+				f.warnings = append(f.warnings, fmt.Sprintf("line %d: %s.%s: streaming methods ignore the route method", n+1, svc.Name, m[1]))
+				method.Method = ""
+			}
+			method.Path = route
+			svc.Methods = append(svc.Methods, method)
+		case inside("enum") != nil && inside("enum").errs && protoEnumValueRe.MatchString(line):
+			m := protoEnumValueRe.FindStringSubmatch(line)
+			value, err := strconv.ParseInt(m[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			if value == 0 {
+				// proto3 枚举的零值通常是 UNSPECIFIED，不是错误码
+				break
+			}
+			desc := strings.TrimSpace(trailing)
+			if desc == "" {
+				desc = strings.Join(lead, " ")
+			}
+			e := newErrorCode("Err"+camel(m[1]), camel(m[1]), desc)
+			e.Value = value
+			f.errors = append(f.errors, e)
+		}
+
+		for _, r := range line {
+			switch r {
+			case '{':
+				if opening != nil {
+					stack = append(stack, *opening)
+					opening = nil
+				} else {
+					stack = append(stack, block{kind: "other"})
+				}
+			case '}':
+				if len(stack) == 0 {
+					return nil, fmt.Errorf("line %d: unbalanced '}'", n+1)
+				}
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("unclosed %s %s", stack[len(stack)-1].kind, stack[len(stack)-1].name)
+	}
+	if f.goPackage == "" {
+		f.goPackage = goPackageName(strings.ReplaceAll(f.pkg, ".", "/"))
+	}
+	if f.goPackage == "" {
+		return nil, fmt.Errorf("missing package or option go_package")
+	}
+	return f, nil
+}
+
+// goType 将 proto 消息名转为生成代码中的 Go 类型，嵌套消息 Outer.Inner 对应 Outer_Inner
+func (f *protoFile) goType(name string) (string, error) {
+	if wk, ok := wellKnownTypes[name]; ok {
+		f.wellKnown[wk[0]] = wk[1]
+		return wk[0] + "." + name[strings.LastIndex(name, ".")+1:], nil
+	}
+	if f.pkg != "" {
+		name = strings.TrimPrefix(name, f.pkg+".")
+	}
+	if strings.Contains(name, ".") && !startsUpper(name) {
+		return "", fmt.Errorf("type %s from another package is not supported", name)
+	}
+	return strings.ReplaceAll(name, ".", "_"), nil
+}
+
+// newErrorCode desc 中的 boxgen:http=404 解析为 HTTP 状态，desc 为空时用 name 生成占位描述
+func newErrorCode(ident, name, desc string) errorCode {
+	e := errorCode{Ident: ident, Name: name}
+	if m := httpStatusRe.FindStringSubmatch(desc); m != nil {
+		e.HTTPStatus, _ = strconv.Atoi(m[1])
+		desc = strings.TrimSpace(httpStatusRe.ReplaceAllString(desc, ""))
+	}
+	e.Description = desc
+	if e.Description == "" {
+		e.Description = humanize(e.Name)
+	}
+	return e
+}
+
+// goPackageName 取 go_package 中 ; 之后的包名，没有时取路径最后一段
+func goPackageName(goPackage string) string {
+	if goPackage == "" {
+		return ""
+	}
+	if _, name, ok := strings.Cut(goPackage, ";"); ok {
+		return name
+	}
+	return strings.NewReplacer("-", "_", ".", "_").Replace(path.Base(goPackage))
+}
+
+// splitComment 按第一个不在字符串中的 // 拆分代码与行尾注释
+func splitComment(line string) (code, comment string) {
+	inString := false
+	for i := 0; i+1 < len(line); i++ {
+		switch {
+		case line[i] == '"' && (i == 0 || line[i-1] != '\\'):
+			inString = !inString
+		case !inString && line[i] == '/' && line[i+1] == '/':
+			return line[:i], line[i+2:]
+		}
+	}
+	return line, ""
+}
+
+// annotation 返回注释中 key 之后的值，如 "boxgen:route /user/get" 返回 "/user/get"
+func annotation(comment []string, key string) string {
+	for _, c := range comment {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(c), key); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func hasAnnotation(comment []string, key string) bool {
+	for _, c := range comment {
+		if strings.HasPrefix(strings.TrimSpace(c), key) {
+			return true
+		}
+	}
+	return false
+}
+
+func startsUpper(s string) bool {
+	return s != "" && s[0] >= 'A' && s[0] <= 'Z'
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const header = "// Code generated by boxgen. DO NOT EDIT.\n"
+
+var funcs = template.FuncMap{
+	"lowerFirst": lowerFirst,
+	"quote":      strconv.Quote,
+}
+
+var protoTmpl = template.Must(template.New("proto").Funcs(funcs).Parse(header + `// source: {{.Source}}
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+{{range $svc := .Services}}
+// Register{{$svc.Name}}Routes 将 {{$svc.Name}} 的方法注册为路由，srv 为服务端实现
+func Register{{$svc.Name}}Routes(r router.Routes, srv {{$svc.Name}}Server) {
+{{- range $svc.Methods}}
+{{- if .Stream}}
+	router.BindStream(r, {{quote .Path}}, srv.{{.Name}})
+{{- else if .Bidi}}
+	router.BindWS(r, {{quote .Path}}, ws.Config{}, srv.{{.Name}})
+{{- else}}
+	router.{{template "bind" .}}, srv.{{.Name}})
+{{- end}}
+{{- end}}
+}
+
+// Register{{$svc.Name}}ClientRoutes 将 HTTP 请求转发给 gRPC 客户端，用于网关
+func Register{{$svc.Name}}ClientRoutes(r router.Routes, client {{$svc.Name}}Client) {
+{{- range $svc.Methods}}
+{{- if .Stream}}
+	router.BindClientStream(r, {{quote .Path}}, client.{{.Name}})
+{{- else if .Bidi}}
+	router.BindClientWS(r, {{quote .Path}}, ws.Config{}, client.{{.Name}})
+{{- else}}
+	router.{{template "bind" .}}, func(ctx context.Context, req *{{.Request}}) (*{{.Response}}, error) {
+		return client.{{.Name}}(ctx, req)
+	})
+{{- end}}
+{{- end}}
+}
+{{end}}
+{{- if .Errors}}
+// 错误码
+const (
+{{- range .Errors}}
+	{{.Ident}} = {{.Value}} // {{.Description}}
+{{- end}}
+)
+{{template "register" .Errors}}
+{{- end}}
+`))
+
+var goErrorsTmpl = template.Must(template.New("goErrors").Funcs(funcs).Parse(header + `
+package {{.Package}}
+
+import (
+	"github.com/code-sigs/go-box/pkg/errs"
+	"github.com/code-sigs/go-box/pkg/rpcerror"
+)
+{{template "register" .Errors}}`))
+
+const bindTmpl = `{{define "bind" -}}
+{{if eq .Method "GET"}}BindGet(r, {{quote .Path}}
+{{- else if .Method}}BindMethod(r, {{quote .Method}}, {{quote .Path}}
+{{- else}}Bind(r, {{quote .Path}}
+{{- end}}
+{{- end}}`
+
+const registerTmpl = `{{define "register"}}
+func init() {
+{{- range .}}
+	errs.Register({{.Ident}}, {{quote .Name}}, {{quote .Description}}, {{.HTTPStatus}})
+{{- end}}
+	rpcerror.RegisterMessages("zh-CN", map[int64]string{
+{{- range .}}
+		{{.Ident}}: {{quote .Description}},
+{{- end}}
+	})
+}
+{{end}}`
+
+var repoTmpl = template.Must(template.New("repo").Funcs(funcs).Parse(header + `
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+{{range $e := .Entities}}
+// {{$e.Name}}Repository {{$e.Name}} 的数据访问接口
+type {{$e.Name}}Repository interface {
+	repository.BaseRepository[{{$e.Name}}, {{$e.Key}}]
+{{- range $e.Lookups}}
+{{- if .Unique}}
+	// GetBy{{.Field}} 按 {{.BSON}} 查询，不存在时返回 nil
+	GetBy{{.Field}}(ctx context.Context, {{.Param}} {{.Type}}) (*{{$e.Name}}, error)
+{{- else}}
+	// FindBy{{.Field}} 按 {{.BSON}} 查询全部匹配的记录
+	FindBy{{.Field}}(ctx context.Context, {{.Param}} {{.Type}}) ([]*{{$e.Name}}, error)
+{{- end}}
+{{- end}}
+	// EnsureIndexes 创建 boxgen 标签声明的索引，可在启动或迁移中调用
+	EnsureIndexes(ctx context.Context) error
+}
+
+type {{lowerFirst $e.Name}}Repository struct {
+	*mongorepo.MongoRepository[{{$e.Name}}, {{$e.Key}}]
+}
+
+// New{{$e.Name}}Repository 创建 {{$e.Name}} 的 Mongo 仓库
+func New{{$e.Name}}Repository(db *mongo.Database, opts ...mongorepo.Option) {{$e.Name}}Repository {
+	return &{{lowerFirst $e.Name}}Repository{MongoRepository: mongorepo.NewMongoRepository[{{$e.Name}}, {{$e.Key}}](db, opts...)}
+}
+{{range $e.Lookups}}
+{{- if .Unique}}
+func (r *{{lowerFirst $e.Name}}Repository) GetBy{{.Field}}(ctx context.Context, {{.Param}} {{.Type}}) (*{{$e.Name}}, error) {
+	return r.FindOne(ctx, map[string]any{ {{- quote .BSON}}: {{.Param -}} })
+}
+{{- else}}
+func (r *{{lowerFirst $e.Name}}Repository) FindBy{{.Field}}(ctx context.Context, {{.Param}} {{.Type}}) ([]*{{$e.Name}}, error) {
+	return r.Find(ctx, map[string]any{ {{- quote .BSON}}: {{.Param -}} }, nil)
+}
+{{- end}}
+{{end}}
+func (r *{{lowerFirst $e.Name}}Repository) EnsureIndexes(ctx context.Context) error {
+{{- range $e.Lookups}}
+	if _, err := r.CreateIndex(ctx, map[string]int{ {{- quote .BSON}}: 1}, map[string]any{"unique": {{.Unique}}}); err != nil {
+		return err
+	}
+{{- end}}
+	return nil
+}
+{{end}}`))
+
+var messagesTmpl = template.Must(template.New("messages").Funcs(funcs).Parse(`// 由 boxgen 生成，可直接编辑：重新生成时保留已翻译的文案，新增的错误码以 TODO 标出。
+
+package {{.Package}}
+
+import "github.com/code-sigs/go-box/pkg/rpcerror"
+
+func init() {
+	rpcerror.RegisterMessages({{quote .Lang}}, map[int64]string{
+{{- range .Messages}}
+		{{.Ident}}: {{quote .Text}},{{if .TODO}} // TODO: translate{{end}}
+{{- end}}
+	})
+}
+`))
+
+func init() {
+	template.Must(protoTmpl.Parse(registerTmpl))
+	template.Must(protoTmpl.Parse(bindTmpl))
+	template.Must(goErrorsTmpl.Parse(registerTmpl))
+}
+
+func renderProto(f *protoFile, source string) ([]byte, error) {
+	imports := map[string]bool{}
+	for _, svc := range f.services {
+		for _, m := range svc.Methods {
+			imports["github.com/code-sigs/go-box/pkg/router"] = true
+			switch {
+			case m.Bidi:
+				imports["github.com/code-sigs/go-box/pkg/ws"] = true
+			case !m.Stream:
+				// 网关转发的闭包需要 context
+				imports["context"] = true
+			}
+		}
+	}
+	for _, path := range f.wellKnown {
+		imports[path] = true
+	}
+	if len(f.errors) > 0 {
+		imports["github.com/code-sigs/go-box/pkg/errs"] = true
+		imports["github.com/code-sigs/go-box/pkg/rpcerror"] = true
+	}
+	var services []protoService
+	for _, svc := range f.services {
+		if len(svc.Methods) > 0 {
+			services = append(services, svc)
+		}
+	}
+	return execute(protoTmpl, map[string]any{
+		"Source":   source,
+		"Package":  f.goPackage,
+		"Imports":  importLines(imports),
+		"Services": services,
+		"Errors":   f.errors,
+	})
+}
+
+func renderGoErrors(p *goPackage) ([]byte, error) {
+	return execute(goErrorsTmpl, map[string]any{"Package": p.name, "Errors": p.errors})
+}
+
+func renderRepositories(p *goPackage) ([]byte, error) {
+	imports := map[string]bool{
+		"context": true,
+		"github.com/code-sigs/go-box/pkg/repository":                 true,
+		"go.mongodb.org/mongo-driver/mongo":                          true,
+		"mongorepo github.com/code-sigs/go-box/pkg/repository/mongo": true,
+	}
+	for _, path := range p.imports {
+		imports[path] = true
+	}
+	return execute(repoTmpl, map[string]any{"Package": p.name, "Imports": importLines(imports), "Entities": p.entities})
+}
+
+type message struct {
+	Ident string
+	Text  string
+	TODO  bool
+}
+
+// renderMessages existing 为已有文件中的文案，键为常量名
+func renderMessages(pkgName, lang string, codes []errorCode, existing map[string]string) ([]byte, error) {
+	messages := make([]message, 0, len(codes))
+	for _, c := range codes {
+		stub := humanize(c.Name)
+		if text, ok := existing[c.Ident]; ok {
+			// 仍为占位文案的视为未翻译
+			messages = append(messages, message{Ident: c.Ident, Text: text, TODO: text == stub})
+			continue
+		}
+		messages = append(messages, message{Ident: c.Ident, Text: stub, TODO: true})
+	}
+	return execute(messagesTmpl, map[string]any{"Package": pkgName, "Lang": lang, "Messages": messages})
+}
+
+// readMessages 读取已有文案文件中 RegisterMessages 的映射，文件不存在时返回空
+func readMessages(path string) (map[string]string, error) {
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), path, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	messages := map[string]string{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		lit, isLit := kv.Value.(*ast.BasicLit)
+		if ok && isLit && lit.Kind == token.STRING {
+			if text, err := strconv.Unquote(lit.Value); err == nil {
+				messages[key.Name] = text
+			}
+		}
+		return false
+	})
+	return messages, nil
+}
+
+func execute(t *template.Template, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, buf.String())
+	}
+	return code, nil
+}
+
+// importLines 将 "path" 或 "alias path" 形式的 import 排序，标准库在前并以空行分隔
+func importLines(imports map[string]bool) []string {
+	var std, others []string
+	for _, imp := range sortedKeys(imports) {
+		line := strconv.Quote(imp)
+		path := imp
+		if alias, p, ok := strings.Cut(imp, " "); ok {
+			line, path = alias+" "+strconv.Quote(p), p
+		}
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			others = append(others, line)
+		} else {
+			std = append(std, line)
+		}
+	}
+	if len(std) > 0 && len(others) > 0 {
+		std = append(std, "")
+	}
+	return append(std, others...)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// User 用户
+// boxgen:repository
+type User struct {
+	ID       string    `bson:"_id"`
+	Email    string    `bson:"email" boxgen:"unique"`
+	TenantID string    `bson:"tenant_id,omitempty" boxgen:"index"`
+	Nickname string    `bson:"nickname"`
+	LoginAt  time.Time `bson:"login_at" boxgen:"index"`
+}
+
+// Order 订单
+// boxgen:repository key=int64
+type Order struct {
+	ID     int64  `bson:"_id"`
+	UserID string `boxgen:"index"`
+}
+
+// boxgen:errors
+const (
+	ErrUserNotFound = 20001 // 用户不存在 boxgen:http=404
+	// 邮箱已被注册
+	ErrEmailTaken = 20002
+	errInternal   = 20003
+)
@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/redis"
+	"github.com/code-sigs/go-box/pkg/utils"
+	"github.com/golang-jwt/jwt/v5"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrNoToken            = errors.New("auth: token not found")
+	ErrInvalidToken       = errors.New("auth: invalid token")
+	ErrTokenExpired       = errors.New("auth: token expired")
+	ErrTokenRevoked       = errors.New("auth: token revoked")
+	ErrPermissionDenied   = errors.New("auth: permission denied")
+	ErrRevocationDisabled = errors.New("auth: revocation requires a redis client")
+	ErrNoSigningKey       = errors.New("auth: active key has no private key")
+)
+
+// token 类型，写入 typ 声明，防止 refresh token 被当作 access token 使用
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+)
+
+// Config 认证配置
+type Config struct {
+	Issuer     string        `mapstructure:"issuer"`                    // 签发方，校验时要求一致
+	Audience   string        `mapstructure:"audience"`                  // 受众，为空不校验
+	AccessTTL  time.Duration `mapstructure:"accessTTL" default:"2h"`    // access token 有效期
+	RefreshTTL time.Duration `mapstructure:"refreshTTL" default:"168h"` // refresh token 有效期
+	Leeway     time.Duration `mapstructure:"leeway" default:"30s"`      // 校验时间时允许的时钟偏差
+	Prefix     string        `mapstructure:"prefix" default:"auth:"`    // 吊销记录的 Redis key 前缀
+	Keys       []KeyConfig   `mapstructure:"keys"`                      // 签名密钥，第一个用于签发，其余只用于校验；轮换时把新密钥放在最前
+}
+
+// Claims token 声明，UserID 等身份字段会写入 ctx 供日志、链路和 gRPC metadata 透传
+type Claims struct {
+	UserID     string         `json:"uid"`
+	TenantID   string         `json:"tid,omitempty"`
+	PlatformID string         `json:"pid,omitempty"`
+	LoginID    string         `json:"lid,omitempty"` // 登录会话，同一会话的 access 与 refresh token 相同
+	Roles      []string       `json:"roles,omitempty"`
+	Extra      map[string]any `json:"ext,omitempty"`
+	Type       string         `json:"typ"`
+	IssuedAtMs int64          `json:"iatms,omitempty"` // 毫秒精度的签发时间，iat 只精确到秒，用于与 RevokeUser 的吊销时间比较
+	jwt.RegisteredClaims
+}
+
+// HasRole 是否拥有任一角色
+func (c *Claims) HasRole(roles ...string) bool {
+	for _, have := range c.Roles {
+		for _, want := range roles {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TokenPair Issue 与 Refresh 的结果
+type TokenPair struct {
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
+
+// Manager 签发、校验、刷新与吊销 token
+type Manager struct {
+	cfg  Config
+	rdb  *redis.RedisClient
+	keys atomic.Pointer[keySet]
+}
+
+// NewManager 创建 Manager，rdb 为 nil 时不支持吊销，Parse 也不检查黑名单
+func NewManager(cfg Config, rdb *redis.RedisClient) (*Manager, error) {
+	if cfg.AccessTTL <= 0 {
+		cfg.AccessTTL = 2 * time.Hour
+	}
+	if cfg.RefreshTTL <= 0 {
+		cfg.RefreshTTL = 7 * 24 * time.Hour
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "auth:"
+	}
+	m := &Manager{cfg: cfg, rdb: rdb}
+	if err := m.RotateKeys(cfg.Keys); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RotateKeys 替换密钥，可在配置变更回调中调用；旧密钥保留在列表中直到其签发的 token 全部过期
+func (m *Manager) RotateKeys(keys []KeyConfig) error {
+	ks, err := newKeySet(keys)
+	if err != nil {
+		return err
+	}
+	m.keys.Store(ks)
+	return nil
+}
+
+// Issue 为 claims 签发一对 token，LoginID 为空时生成新的会话 ID
+func (m *Manager) Issue(ctx context.Context, claims Claims) (*TokenPair, error) {
+	if claims.LoginID == "" {
+		claims.LoginID = utils.GenerateUUIDStr()
+	}
+	now := time.Now()
+	access, accessExp, err := m.sign(claims, TypeAccess, now, m.cfg.AccessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, refreshExp, err := m.sign(claims, TypeRefresh, now, m.cfg.RefreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{AccessToken: access, RefreshToken: refresh, ExpiresAt: accessExp, RefreshExpiresAt: refreshExp}, nil
+}
+
+// Parse 校验 access token 并返回声明
+func (m *Manager) Parse(ctx context.Context, token string) (*Claims, error) {
+	return m.parse(ctx, token, TypeAccess)
+}
+
+// Refresh 用 refresh token 换取新的一对 token，旧 refresh token 随即吊销只能使用一次；
+// 未配置 Redis 时无法吊销，refresh token 在有效期内可重复使用
+func (m *Manager) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := m.parse(ctx, refreshToken, TypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+	if m.rdb != nil {
+		// SETNX 保证并发刷新时只有一个请求成功
+		ok, err := m.rdb.DB().SetNX(ctx, m.revokedKey(claims.ID), 1, time.Until(claims.ExpiresAt.Time)+m.cfg.Leeway).Result()
+		if err != nil {
+			return nil, fmt.Errorf("auth: revoke refresh token: %w", err)
+		}
+		if !ok {
+			return nil, ErrTokenRevoked
+		}
+	}
+	next := *claims
+	next.IssuedAtMs = 0
+	next.RegisteredClaims = jwt.RegisteredClaims{}
+	return m.Issue(ctx, next)
+}
+
+// Revoke 吊销 token（access 或 refresh），直到其过期
+func (m *Manager) Revoke(ctx context.Context, token string) error {
+	if m.rdb == nil {
+		return ErrRevocationDisabled
+	}
+	claims := &Claims{}
+	// 已过期的 token 无需吊销，签名仍需有效以防伪造的 jti 写满 Redis
+	if _, err := jwt.ParseWithClaims(token, claims, m.keys.Load().keyFunc, jwt.WithoutClaimsValidation()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.ExpiresAt == nil || claims.ID == "" {
+		return ErrInvalidToken
+	}
+	ttl := time.Until(claims.ExpiresAt.Time) + m.cfg.Leeway
+	if ttl <= 0 {
+		return nil
+	}
+	return m.rdb.Set(ctx, m.revokedKey(claims.ID), 1, ttl)
+}
+
+// RevokeUser 吊销用户此前签发的全部 token，如修改密码、封禁后强制下线
+func (m *Manager) RevokeUser(ctx context.Context, userID string) error {
+	if m.rdb == nil {
+		return ErrRevocationDisabled
+	}
+	return m.rdb.Set(ctx, m.userRevokedKey(userID), time.Now().UnixMilli(), m.cfg.RefreshTTL+m.cfg.Leeway)
+}
+
+func (m *Manager) sign(claims Claims, typ string, now time.Time, ttl time.Duration) (string, time.Time, error) {
+	ks := m.keys.Load()
+	if ks.active.sign == nil {
+		return "", time.Time{}, ErrNoSigningKey
+	}
+	exp := now.Add(ttl)
+	claims.Type = typ
+	claims.IssuedAtMs = now.UnixMilli()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        utils.GenerateUUIDStr(),
+		Issuer:    m.cfg.Issuer,
+		Subject:   claims.UserID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(exp),
+	}
+	if m.cfg.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{m.cfg.Audience}
+	}
+	t := jwt.NewWithClaims(ks.active.method, claims)
+	t.Header["kid"] = ks.active.id
+	signed, err := t.SignedString(ks.active.sign)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("auth: sign token: %w", err)
+	}
+	return signed, exp, nil
+}
+
+func (m *Manager) parse(ctx context.Context, token, typ string) (*Claims, error) {
+	if token == "" {
+		return nil, ErrNoToken
+	}
+	opts := []jwt.ParserOption{jwt.WithLeeway(m.cfg.Leeway), jwt.WithExpirationRequired(), jwt.WithIssuedAt()}
+	if m.cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(m.cfg.Issuer))
+	}
+	if m.cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(m.cfg.Audience))
+	}
+	claims := &Claims{}
+	if _, err := jwt.ParseWithClaims(token, claims, m.keys.Load().keyFunc, opts...); err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Type != typ {
+		return nil, fmt.Errorf("%w: expected %s token", ErrInvalidToken, typ)
+	}
+	if err := m.checkRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// checkRevoked 一次往返同时检查 token 黑名单与用户级吊销时间
+func (m *Manager) checkRevoked(ctx context.Context, claims *Claims) error {
+	if m.rdb == nil {
+		return nil
+	}
+	pipe := m.rdb.Pipeline()
+	revoked := pipe.Exists(ctx, m.revokedKey(claims.ID))
+	userRevokedAt := pipe.Get(ctx, m.userRevokedKey(claims.UserID))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return fmt.Errorf("auth: check revocation: %w", err)
+	}
+	if revoked.Val() > 0 {
+		return ErrTokenRevoked
+	}
+	if v := userRevokedAt.Val(); v != "" {
+		at, err := strconv.ParseInt(v, 10, 64)
+		if err == nil && claims.issuedAtMs() < revokedAtMs(at) {
+			return ErrTokenRevoked
+		}
+	}
+	return nil
+}
+
+// issuedAtMs 签发时间（毫秒），不含 iatms 的旧 token 取 iat
+func (c *Claims) issuedAtMs() int64 {
+	if c.IssuedAtMs > 0 {
+		return c.IssuedAtMs
+	}
+	if c.IssuedAt != nil {
+		return c.IssuedAt.UnixMilli()
+	}
+	return 0
+}
+
+// revokedAtMs 吊销时间（毫秒）；旧版本以秒记录，按该秒末处理，当秒签发的 token 同样失效
+func revokedAtMs(at int64) int64 {
+	if at < 1e12 {
+		return at*1000 + 999
+	}
+	return at
+}
+
+func (m *Manager) revokedKey(jti string) string {
+	return m.cfg.Prefix + "revoked:" + jti
+}
+
+func (m *Manager) userRevokedKey(userID string) string {
+	return m.cfg.Prefix + "revoked-user:" + userID
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/code-sigs/go-box/pkg/redis"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKeys = []KeyConfig{{ID: "k1", Secret: "0123456789abcdef0123456789abcdef"}}
+
+func newTestManager(t *testing.T, cfg Config) (*Manager, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb, err := redis.NewRedisClient(&redis.RedisConfig{Address: []string{mr.Addr()}})
+	require.NoError(t, err)
+	if cfg.Keys == nil {
+		cfg.Keys = testKeys
+	}
+	m, err := NewManager(cfg, rdb)
+	require.NoError(t, err)
+	return m, mr
+}
+
+func TestIssueAndParse(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, Config{Issuer: "go-box", Audience: "api"})
+
+	pair, err := m.Issue(ctx, Claims{UserID: "u1", TenantID: "t1", Roles: []string{"admin"}})
+	require.NoError(t, err)
+	claims, err := m.Parse(ctx, pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", claims.UserID)
+	assert.Equal(t, "t1", claims.TenantID)
+	assert.Equal(t, "u1", claims.Subject)
+	assert.NotEmpty(t, claims.LoginID)
+	assert.True(t, claims.HasRole("admin"))
+
+	// refresh token 不能当作 access token 使用
+	_, err = m.Parse(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = m.Parse(ctx, "")
+	assert.ErrorIs(t, err, ErrNoToken)
+	_, err = m.Parse(ctx, pair.AccessToken+"x")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// 签发方不一致
+	other, _ := newTestManager(t, Config{Issuer: "other", Audience: "api"})
+	_, err = other.Parse(ctx, pair.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestParseExpired(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, Config{AccessTTL: time.Millisecond})
+
+	pair, err := m.Issue(ctx, Claims{UserID: "u1"})
+	require.NoError(t, err)
+	time.Sleep(1100 * time.Millisecond)
+	_, err = m.Parse(ctx, pair.AccessToken)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, Config{})
+
+	pair, err := m.Issue(ctx, Claims{UserID: "u1"})
+	require.NoError(t, err)
+	next, err := m.Refresh(ctx, pair.RefreshToken)
+	require.NoError(t, err)
+	claims, err := m.Parse(ctx, next.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", claims.UserID)
+
+	// 同一会话，refresh token 只能使用一次
+	first, _ := m.Parse(ctx, pair.AccessToken)
+	assert.Equal(t, first.LoginID, claims.LoginID)
+	_, err = m.Refresh(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	_, err = m.Refresh(ctx, next.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestRevoke(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, Config{})
+
+	pair, err := m.Issue(ctx, Claims{UserID: "u1"})
+	require.NoError(t, err)
+	require.NoError(t, m.Revoke(ctx, pair.AccessToken))
+	_, err = m.Parse(ctx, pair.AccessToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	assert.ErrorIs(t, m.Revoke(ctx, "invalid"), ErrInvalidToken)
+
+	noRedis, err := NewManager(Config{Keys: testKeys}, nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, noRedis.Revoke(ctx, pair.AccessToken), ErrRevocationDisabled)
+	assert.ErrorIs(t, noRedis.RevokeUser(ctx, "u1"), ErrRevocationDisabled)
+}
+
+func TestRevokeUser(t *testing.T) {
+	ctx := context.Background()
+	m, mr := newTestManager(t, Config{})
+
+	before, err := m.Issue(ctx, Claims{UserID: "u1"})
+	require.NoError(t, err)
+	other, err := m.Issue(ctx, Claims{UserID: "u2"})
+	require.NoError(t, err)
+	time.Sleep(2 * time.Millisecond)
+	require.NoError(t, m.RevokeUser(ctx, "u1"))
+
+	_, err = m.Parse(ctx, before.AccessToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	_, err = m.Refresh(ctx, before.RefreshToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	_, err = m.Parse(ctx, other.AccessToken)
+	assert.NoError(t, err)
+
+	// 吊销后同一秒内重新登录签发的 token 有效
+	after, err := m.Issue(ctx, Claims{UserID: "u1"})
+	require.NoError(t, err)
+	_, err = m.Parse(ctx, after.AccessToken)
+	assert.NoError(t, err)
+
+	// 兼容旧版本以秒记录的吊销时间：当秒签发的 token 同样失效
+	require.NoError(t, mr.Set(m.userRevokedKey("u1"), strconv.FormatInt(time.Now().Unix(), 10)))
+	_, err = m.Parse(ctx, after.AccessToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestRotateKeys(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t, Config{})
+
+	old, err := m.Issue(ctx, Claims{UserID: "u1"})
+	require.NoError(t, err)
+
+	// 新密钥放在最前用于签发，旧密钥保留用于校验
+	k2 := KeyConfig{ID: "k2", Secret: "fedcba9876543210fedcba9876543210"}
+	require.NoError(t, m.RotateKeys([]KeyConfig{k2, testKeys[0]}))
+	_, err = m.Parse(ctx, old.AccessToken)
+	assert.NoError(t, err)
+	pair, err := m.Issue(ctx, Claims{UserID: "u1"})
+	require.NoError(t, err)
+	token, _, err := jwt.NewParser().ParseUnverified(pair.AccessToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "k2", token.Header["kid"])
+
+	// 移除旧密钥后其签发的 token 失效
+	require.NoError(t, m.RotateKeys([]KeyConfig{k2}))
+	_, err = m.Parse(ctx, old.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = m.Parse(ctx, pair.AccessToken)
+	assert.NoError(t, err)
+
+	assert.Error(t, m.RotateKeys(nil))
+	assert.Error(t, m.RotateKeys([]KeyConfig{k2, k2}))
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/code-sigs/go-box/pkg/trace"
+)
+
+// 写入 ctx 的身份字段，与 NewGRPCConn 透传的 metadata key 一致，logger.WithContextFields 也可直接使用
+const (
+	KeyUserID     = trace.BaggageUserID
+	KeyTenantID   = trace.BaggageTenantID
+	KeyPlatformID = "platform-id"
+	KeyLoginID    = "login-id"
+)
+
+type claimsKey struct{}
+
+type tokenKey struct{}
+
+// NewContext 将身份写入 ctx：Claims 本身、以字符串 key 存放的身份字段（供日志与 gRPC metadata 透传），
+// 以及 user-id、tenant-id baggage（随链路传到下游）；token 非空时出站 gRPC 调用会携带它
+func NewContext(ctx context.Context, claims *Claims, token string) context.Context {
+	ctx = context.WithValue(ctx, claimsKey{}, claims)
+	if token != "" {
+		ctx = context.WithValue(ctx, tokenKey{}, token)
+	}
+	for _, kv := range [][2]string{
+		{KeyUserID, claims.UserID},
+		{KeyTenantID, claims.TenantID},
+		{KeyPlatformID, claims.PlatformID},
+		{KeyLoginID, claims.LoginID},
+	} {
+		if kv[1] != "" {
+			ctx = context.WithValue(ctx, kv[0], kv[1])
+		}
+	}
+	if claims.UserID != "" {
+		ctx = trace.WithBaggage(ctx, KeyUserID, claims.UserID)
+	}
+	if claims.TenantID != "" {
+		ctx = trace.WithBaggage(ctx, KeyTenantID, claims.TenantID)
+	}
+	return ctx
+}
+
+// FromContext 返回中间件或拦截器写入的 Claims
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// UserID 返回当前用户 ID，未认证时为空
+func UserID(ctx context.Context) string {
+	if claims, ok := FromContext(ctx); ok {
+		return claims.UserID
+	}
+	return ""
+}
+
+// TokenFromContext 返回认证时使用的原始 access token
+func TokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey{}).(string)
+	return token
+}
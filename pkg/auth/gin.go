@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/router"
+	"github.com/gin-gonic/gin"
+)
+
+// ClaimsKey gin.Context 中保存 Claims 的 key
+const ClaimsKey = "auth.claims"
+
+// GinMiddleware 校验 Authorization: Bearer <token>（WebSocket 等无法设置请求头的场景可用 access_token 查询参数），
+// 通过后将身份写入 c.Request.Context() 与 c.Keys（Claims 及 user-id、tenant-id、platform-id）；失败返回 401，Redis 等内部错误返回 500
+func GinMiddleware(m *Manager) gin.HandlerFunc {
+	return ginMiddleware(m, false)
+}
+
+// GinOptionalMiddleware 同 GinMiddleware，但没有 token 时直接放行，用于登录与匿名均可访问的接口
+func GinOptionalMiddleware(m *Manager) gin.HandlerFunc {
+	return ginMiddleware(m, true)
+}
+
+// RequireRoles 要求拥有任一角色，需放在 GinMiddleware 之后，否则返回 403
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := FromContext(c.Request.Context())
+		if !ok || !claims.HasRole(roles...) {
+			abort(c, http.StatusForbidden, ErrPermissionDenied)
+			return
+		}
+		c.Next()
+	}
+}
+
+func ginMiddleware(m *Manager, optional bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerToken(c.GetHeader("Authorization"))
+		if token == "" {
+			token = c.Query("access_token")
+		}
+		if token == "" && optional {
+			c.Next()
+			return
+		}
+		ctx := c.Request.Context()
+		claims, err := m.Parse(ctx, token)
+		if err != nil {
+			if isAuthError(err) {
+				abort(c, http.StatusUnauthorized, err)
+				return
+			}
+			logger.Errorw(ctx, "authenticate request failed", "error", err.Error())
+			abort(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.Request = c.Request.WithContext(NewContext(ctx, claims, token))
+		c.Set(ClaimsKey, claims)
+		// 与 router 约定的身份 key，供 router.KeyByUser 限流及 handler 透传用户、租户
+		for _, kv := range [][2]string{
+			{KeyUserID, claims.UserID},
+			{KeyTenantID, claims.TenantID},
+			{KeyPlatformID, claims.PlatformID},
+		} {
+			if kv[1] != "" {
+				c.Set(kv[0], kv[1])
+			}
+		}
+		c.Next()
+	}
+}
+
+func abort(c *gin.Context, status int, err error) {
+	c.AbortWithStatusJSON(status, router.StandardResponse[any]{Code: int64(status), Message: err.Error()})
+}
+
+func bearerToken(header string) string {
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// isAuthError 区分凭证问题与依赖故障
+func isAuthError(err error) bool {
+	return errors.Is(err, ErrNoToken) || errors.Is(err, ErrInvalidToken) ||
+		errors.Is(err, ErrTokenExpired) || errors.Is(err, ErrTokenRevoked)
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/ratelimit"
+	"github.com/code-sigs/go-box/pkg/router"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGinMiddleware_RateLimitByUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	m, _ := newTestManager(t, Config{})
+	limiter := ratelimit.NewLocalTokenBucket(1, time.Minute, 1, 100)
+
+	engine := gin.New()
+	engine.GET("/profile", GinMiddleware(m), router.RateLimitMiddleware(limiter, router.KeyByUser()), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(KeyUserID))
+	})
+
+	call := func(token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		// 同一 IP，只有按用户计算配额时才互不影响
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	u1, err := m.Issue(ctx, Claims{UserID: "u1"})
+	require.NoError(t, err)
+	u2, err := m.Issue(ctx, Claims{UserID: "u2"})
+	require.NoError(t, err)
+
+	w := call(u1.AccessToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "u1", w.Body.String())
+	assert.Equal(t, http.StatusTooManyRequests, call(u1.AccessToken).Code)
+	assert.Equal(t, http.StatusOK, call(u2.AccessToken).Code)
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor 从 metadata 的 authorization 校验 token 并将身份写入 ctx，
+// public 中的方法（如 /user.User/Login）无需认证，失败返回 Unauthenticated
+func UnaryServerInterceptor(m *Manager, public ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, m, info.FullMethod, public)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 同 UnaryServerInterceptor，用于流式调用
+func StreamServerInterceptor(m *Manager, public ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), m, info.FullMethod, public)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryClientInterceptor 将 ctx 中的 access token 写入出站 metadata，下游服务可再次校验
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if token := TokenFromContext(ctx); token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func authenticate(ctx context.Context, m *Manager, method string, public []string) (context.Context, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			token = bearerToken(v[0])
+		}
+	}
+	if slices.Contains(public, method) {
+		if token == "" {
+			return ctx, nil
+		}
+		// 公开方法携带了 token 时仍尝试识别身份，失败不拒绝
+		if claims, err := m.Parse(ctx, token); err == nil {
+			return NewContext(ctx, claims, token), nil
+		}
+		return ctx, nil
+	}
+	claims, err := m.Parse(ctx, token)
+	if err != nil {
+		if isAuthError(err) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return NewContext(ctx, claims, token), nil
+}
+
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
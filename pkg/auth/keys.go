@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/code-sigs/go-box/pkg/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 支持的签名算法
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// KeyConfig 签名密钥，密钥内容可使用 ${file:...}、${vault:...} 占位符
+type KeyConfig struct {
+	ID         string `mapstructure:"id"`         // kid，写入 token 头用于选择校验密钥
+	Algorithm  string `mapstructure:"algorithm"`  // HS256 / RS256，默认 HS256
+	Secret     string `mapstructure:"secret"`     // HS256 密钥，建议不少于 32 字节
+	PrivateKey string `mapstructure:"privateKey"` // RS256 PEM 私钥，只校验不签发的服务可不配置
+	PublicKey  string `mapstructure:"publicKey"`  // RS256 PEM 公钥，为空时从私钥推导
+}
+
+type key struct {
+	id     string
+	method jwt.SigningMethod
+	sign   any // []byte 或 *rsa.PrivateKey，nil 表示只能校验
+	verify any // []byte 或 *rsa.PublicKey
+}
+
+// keySet 第一个密钥用于签发，全部密钥用于校验
+type keySet struct {
+	active *key
+	byID   map[string]*key
+}
+
+func newKeySet(configs []KeyConfig) (*keySet, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("auth: at least one key is required")
+	}
+	ks := &keySet{byID: make(map[string]*key, len(configs))}
+	for i, cfg := range configs {
+		k, err := parseKey(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := ks.byID[k.id]; ok {
+			return nil, fmt.Errorf("auth: duplicate key id %q", k.id)
+		}
+		ks.byID[k.id] = k
+		if i == 0 {
+			ks.active = k
+		}
+	}
+	return ks, nil
+}
+
+func parseKey(cfg KeyConfig) (*key, error) {
+	if cfg.ID == "" {
+		return nil, errors.New("auth: key id is required")
+	}
+	switch cfg.Algorithm {
+	case AlgHS256, "":
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("auth: key %q: secret is required", cfg.ID)
+		}
+		secret := []byte(cfg.Secret)
+		return &key{id: cfg.ID, method: jwt.SigningMethodHS256, sign: secret, verify: secret}, nil
+	case AlgRS256:
+		k := &key{id: cfg.ID, method: jwt.SigningMethodRS256}
+		if cfg.PrivateKey != "" {
+			priv, err := utils.ParseRSAPrivateKey([]byte(cfg.PrivateKey))
+			if err != nil {
+				return nil, fmt.Errorf("auth: key %q: %w", cfg.ID, err)
+			}
+			k.sign, k.verify = priv, &priv.PublicKey
+		}
+		if cfg.PublicKey != "" {
+			pub, err := utils.ParseRSAPublicKey([]byte(cfg.PublicKey))
+			if err != nil {
+				return nil, fmt.Errorf("auth: key %q: %w", cfg.ID, err)
+			}
+			k.verify = pub
+		}
+		if k.verify == nil {
+			return nil, fmt.Errorf("auth: key %q: privateKey or publicKey is required", cfg.ID)
+		}
+		return k, nil
+	default:
+		return nil, fmt.Errorf("auth: key %q: unsupported algorithm %q", cfg.ID, cfg.Algorithm)
+	}
+}
+
+// keyFunc 按 kid 选择校验密钥，并确认算法与密钥一致，防止算法替换攻击
+func (ks *keySet) keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	k, ok := ks.byID[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if t.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+	}
+	return k.verify, nil
+}
@@ -0,0 +1,281 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+)
+
+var (
+	ErrOpen            = errors.New("circuit breaker is open")
+	ErrTooManyRequests = errors.New("circuit breaker is half-open, too many probe requests")
+)
+
+// State 熔断器状态
+type State int
+
+const (
+	StateClosed   State = iota // 正常放行
+	StateOpen                  // 拒绝请求，OpenTimeout 后进入半开
+	StateHalfOpen              // 放行少量探测请求
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Config 熔断参数，零值字段使用默认值
+type Config struct {
+	Window                time.Duration `mapstructure:"window" default:"10s"`               // 统计窗口
+	Buckets               int           `mapstructure:"buckets" default:"10"`               // 窗口分桶数
+	MinRequests           int           `mapstructure:"minRequests" default:"20"`           // 窗口内请求数达到后才判断是否熔断
+	FailureRateThreshold  float64       `mapstructure:"failureRateThreshold" default:"0.5"` // 失败率阈值 0~1
+	SlowCallThreshold     time.Duration `mapstructure:"slowCallThreshold"`                  // 慢调用耗时阈值，0 不统计慢调用
+	SlowCallRateThreshold float64       `mapstructure:"slowCallRateThreshold"`              // 慢调用比例阈值 0~1，默认 1
+	OpenTimeout           time.Duration `mapstructure:"openTimeout" default:"30s"`          // 熔断持续时间，之后进入半开
+	HalfOpenProbes        int           `mapstructure:"halfOpenProbes" default:"5"`         // 半开时的探测请求数，全部成功后恢复
+
+	IsFailure     func(err error) bool              `mapstructure:"-"` // 判断错误是否计入失败，默认非 nil 且不是 context.Canceled
+	OnStateChange func(name string, from, to State) `mapstructure:"-"` // 状态变化回调，持锁同步调用，不能再调用该熔断器的方法
+}
+
+func (c Config) withDefaults() Config {
+	if c.Window <= 0 {
+		c.Window = 10 * time.Second
+	}
+	if c.Buckets <= 0 {
+		c.Buckets = 10
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = 20
+	}
+	if c.FailureRateThreshold <= 0 || c.FailureRateThreshold > 1 {
+		c.FailureRateThreshold = 0.5
+	}
+	if c.SlowCallRateThreshold <= 0 || c.SlowCallRateThreshold > 1 {
+		c.SlowCallRateThreshold = 1
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = 30 * time.Second
+	}
+	if c.HalfOpenProbes <= 0 {
+		c.HalfOpenProbes = 5
+	}
+	if c.IsFailure == nil {
+		c.IsFailure = defaultIsFailure
+	}
+	return c
+}
+
+func defaultIsFailure(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+// Counts 统计窗口内的调用数
+type Counts struct {
+	Requests int // 总数
+	Failures int // 失败数
+	Slow     int // 慢调用数
+}
+
+type bucket struct {
+	start time.Time
+	Counts
+}
+
+// Breaker 基于滑动窗口失败率与慢调用比例的熔断器
+type Breaker struct {
+	name    string
+	cfg     Config
+	metrics *Metrics
+
+	mu         sync.Mutex
+	state      State
+	generation uint64 // 每次状态变化递增，丢弃上一状态中发出的请求结果
+	openedAt   time.Time
+	buckets    []bucket
+	probes     int // 半开状态已放行的探测数
+	successes  int // 半开状态成功的探测数
+}
+
+// New 创建熔断器，通常通过 Get / Do 按名称获取共享实例
+func New(name string, cfg Config) *Breaker {
+	cfg = cfg.withDefaults()
+	return &Breaker{
+		name:    name,
+		cfg:     cfg,
+		buckets: make([]bucket, cfg.Buckets),
+	}
+}
+
+// Name 熔断器名称
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State 当前状态
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState(time.Now())
+}
+
+// Counts 当前窗口内的统计
+func (b *Breaker) Counts() Counts {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.windowCounts(time.Now())
+}
+
+// Do 执行 fn，熔断时直接返回 ErrOpen 或 ErrTooManyRequests，fn 的 panic 计为失败后继续抛出
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return b.DoWithAcceptable(ctx, fn, nil)
+}
+
+// DoWithAcceptable 同 Do，acceptable 返回 true 的错误（如记录不存在、参数错误）不计入失败
+func (b *Breaker) DoWithAcceptable(ctx context.Context, fn func(ctx context.Context) error, acceptable func(err error) bool) error {
+	generation, err := b.allow()
+	if err != nil {
+		b.metrics.incRequest(b.name, "rejected")
+		return err
+	}
+	start := time.Now()
+	failed := true
+	defer func() {
+		// fn panic 时 failed 仍为 true
+		b.record(generation, failed, time.Since(start))
+	}()
+	err = fn(ctx)
+	failed = b.cfg.IsFailure(err) && (acceptable == nil || !acceptable(err))
+	return err
+}
+
+func (b *Breaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.currentState(time.Now()) {
+	case StateOpen:
+		return 0, ErrOpen
+	case StateHalfOpen:
+		if b.probes >= b.cfg.HalfOpenProbes {
+			return 0, ErrTooManyRequests
+		}
+		b.probes++
+	}
+	return b.generation, nil
+}
+
+func (b *Breaker) record(generation uint64, failed bool, elapsed time.Duration) {
+	slow := b.cfg.SlowCallThreshold > 0 && elapsed >= b.cfg.SlowCallThreshold
+	switch {
+	case failed:
+		b.metrics.incRequest(b.name, "failure")
+	case slow:
+		b.metrics.incRequest(b.name, "slow")
+	default:
+		b.metrics.incRequest(b.name, "success")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if b.currentState(now) == StateOpen || generation != b.generation {
+		return
+	}
+	if b.state == StateHalfOpen {
+		if failed || slow {
+			b.setState(StateOpen, now)
+			return
+		}
+		if b.successes++; b.successes >= b.cfg.HalfOpenProbes {
+			b.setState(StateClosed, now)
+		}
+		return
+	}
+
+	bk := b.bucketAt(now)
+	bk.Requests++
+	if failed {
+		bk.Failures++
+	}
+	if slow {
+		bk.Slow++
+	}
+	c := b.windowCounts(now)
+	if c.Requests < b.cfg.MinRequests {
+		return
+	}
+	if float64(c.Failures) >= b.cfg.FailureRateThreshold*float64(c.Requests) ||
+		(b.cfg.SlowCallThreshold > 0 && float64(c.Slow) >= b.cfg.SlowCallRateThreshold*float64(c.Requests)) {
+		b.setState(StateOpen, now)
+	}
+}
+
+// currentState 处理打开超时后转为半开，调用方需持有 b.mu
+func (b *Breaker) currentState(now time.Time) State {
+	if b.state == StateOpen && now.Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.setState(StateHalfOpen, now)
+	}
+	return b.state
+}
+
+// setState 调用方需持有 b.mu
+func (b *Breaker) setState(to State, now time.Time) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	b.generation++
+	b.probes, b.successes = 0, 0
+	switch to {
+	case StateOpen:
+		b.openedAt = now
+	case StateClosed:
+		for i := range b.buckets {
+			b.buckets[i] = bucket{}
+		}
+	}
+	b.metrics.setState(b.name, from, to)
+	logger.Warnw(context.Background(), "circuit breaker state changed", "breaker", b.name, "from", from.String(), "to", to.String())
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(b.name, from, to)
+	}
+}
+
+// bucketAt 返回 now 所在的桶，过期的桶先清零，调用方需持有 b.mu
+func (b *Breaker) bucketAt(now time.Time) *bucket {
+	width := b.cfg.Window / time.Duration(b.cfg.Buckets)
+	start := now.Truncate(width)
+	bk := &b.buckets[int(start.UnixNano()/int64(width))%len(b.buckets)]
+	if !bk.start.Equal(start) {
+		*bk = bucket{start: start}
+	}
+	return bk
+}
+
+// windowCounts 汇总窗口内未过期的桶，调用方需持有 b.mu
+func (b *Breaker) windowCounts(now time.Time) Counts {
+	var c Counts
+	for _, bk := range b.buckets {
+		if now.Sub(bk.start) >= b.cfg.Window {
+			continue
+		}
+		c.Requests += bk.Requests
+		c.Failures += bk.Failures
+		c.Slow += bk.Slow
+	}
+	return c
+}
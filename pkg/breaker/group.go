@@ -0,0 +1,106 @@
+package breaker
+
+import (
+	"context"
+	"sync"
+)
+
+// Group 按名称管理熔断器，同名调用共享同一个实例
+type Group struct {
+	mu        sync.Mutex
+	cfg       Config
+	overrides map[string]Config
+	breakers  map[string]*Breaker
+	metrics   *Metrics
+}
+
+// NewGroup 创建熔断器组，cfg 为组内熔断器的默认参数
+func NewGroup(cfg Config) *Group {
+	return &Group{
+		cfg:       cfg,
+		overrides: make(map[string]Config),
+		breakers:  make(map[string]*Breaker),
+	}
+}
+
+// Configure 为指定名称设置独立参数，已创建的同名熔断器会被替换并重新统计
+func (g *Group) Configure(name string, cfg Config) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.overrides[name] = cfg
+	delete(g.breakers, name)
+}
+
+// SetMetrics 设置指标，仅对之后创建的熔断器生效，应在启动时调用
+func (g *Group) SetMetrics(m *Metrics) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.metrics = m
+}
+
+// Get 返回指定名称的熔断器，不存在时创建
+func (g *Group) Get(name string) *Breaker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if b, ok := g.breakers[name]; ok {
+		return b
+	}
+	cfg, ok := g.overrides[name]
+	if !ok {
+		cfg = g.cfg
+	}
+	b := New(name, cfg)
+	b.metrics = g.metrics
+	g.breakers[name] = b
+	return b
+}
+
+// Do 使用名为 name 的熔断器执行 fn
+func (g *Group) Do(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	return g.Get(name).Do(ctx, fn)
+}
+
+// DoWithAcceptable 使用名为 name 的熔断器执行 fn，acceptable 返回 true 的错误不计入失败
+func (g *Group) DoWithAcceptable(ctx context.Context, name string, fn func(ctx context.Context) error, acceptable func(err error) bool) error {
+	return g.Get(name).DoWithAcceptable(ctx, fn, acceptable)
+}
+
+// Default 包级函数使用的熔断器组，gRPC 客户端与 Redis / Mongo / ES 封装也使用它，
+// 可通过 Configure 调整 "redis"、"elastic"、"mongo:<collection>"、"grpc:<target><method>" 等熔断器的参数
+var Default = NewGroup(Config{})
+
+// Get 返回 Default 中指定名称的熔断器
+func Get(name string) *Breaker {
+	return Default.Get(name)
+}
+
+// Configure 为 Default 中指定名称设置独立参数
+func Configure(name string, cfg Config) {
+	Default.Configure(name, cfg)
+}
+
+// SetMetrics 设置 Default 的指标
+func SetMetrics(m *Metrics) {
+	Default.SetMetrics(m)
+}
+
+// Do 使用 Default 中名为 name 的熔断器执行 fn
+func Do(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	return Default.Do(ctx, name, fn)
+}
+
+// DoWithAcceptable 使用 Default 中名为 name 的熔断器执行 fn，acceptable 返回 true 的错误不计入失败
+func DoWithAcceptable(ctx context.Context, name string, fn func(ctx context.Context) error, acceptable func(err error) bool) error {
+	return Default.DoWithAcceptable(ctx, name, fn, acceptable)
+}
+
+// Execute 带返回值的 Do
+func Execute[T any](ctx context.Context, name string, fn func(ctx context.Context) (T, error)) (T, error) {
+	var out T
+	err := Do(ctx, name, func(ctx context.Context) error {
+		var err error
+		out, err = fn(ctx)
+		return err
+	})
+	return out, err
+}
@@ -0,0 +1,77 @@
+package breaker
+
+import (
+	"errors"
+
+	"github.com/code-sigs/go-box/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics 熔断器指标，所有方法对 nil 接收者安全
+type Metrics struct {
+	state        *prometheus.GaugeVec
+	requests     *prometheus.CounterVec
+	stateChanges *prometheus.CounterVec
+}
+
+// NewMetrics 创建指标并注册到 reg，reg 为 nil 时使用 metrics.Default()；
+// 已注册同名指标时复用已有实例
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	if reg == nil {
+		reg = metrics.Default().Registerer()
+	}
+	m := &Metrics{
+		state: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "breaker", Name: "state",
+			Help: "Current circuit breaker state: 0 closed, 1 open, 2 half-open.",
+		}, []string{"name"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "breaker", Name: "requests_total",
+			Help: "Calls through the circuit breaker, partitioned by result: success, failure, slow, rejected.",
+		}, []string{"name", "result"}),
+		stateChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "breaker", Name: "state_changes_total",
+			Help: "Circuit breaker state transitions.",
+		}, []string{"name", "from", "to"}),
+	}
+	var err error
+	if m.state, err = register(reg, m.state); err != nil {
+		return nil, err
+	}
+	if m.requests, err = register(reg, m.requests); err != nil {
+		return nil, err
+	}
+	if m.stateChanges, err = register(reg, m.stateChanges); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// register 注册指标，已存在同名指标时返回已注册的实例
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) (C, error) {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing, nil
+			}
+		}
+		return c, err
+	}
+	return c, nil
+}
+
+func (m *Metrics) incRequest(name, result string) {
+	if m == nil {
+		return
+	}
+	m.requests.WithLabelValues(name, result).Inc()
+}
+
+func (m *Metrics) setState(name string, from, to State) {
+	if m == nil {
+		return
+	}
+	m.state.WithLabelValues(name).Set(float64(to))
+	m.stateChanges.WithLabelValues(name, from.String(), to.String()).Inc()
+}
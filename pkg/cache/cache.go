@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/redis"
+)
+
+// ErrNotFound 缓存未命中
+var ErrNotFound = errors.New("cache: not found")
+
+// LoaderFunc 缓存未命中时加载数据
+type LoaderFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// Cache 通用缓存接口，本地 LRU 与 Redis 实现可互相替换
+type Cache[K comparable, V any] interface {
+	// Get 获取缓存，未命中返回 ErrNotFound
+	Get(ctx context.Context, key K) (V, error)
+	// Set 写入缓存，ttl <= 0 使用实现的默认过期时间
+	Set(ctx context.Context, key K, value V, ttl time.Duration) error
+	// Delete 删除缓存
+	Delete(ctx context.Context, keys ...K) error
+	// GetOrLoad 未命中时调用 loader 并写入缓存，同一 key 的并发加载只执行一次
+	GetOrLoad(ctx context.Context, key K, loader LoaderFunc[K, V], ttl time.Duration) (V, error)
+}
+
+// 缓存类型
+const (
+	TypeLocal   = "local"   // 进程内 LRU
+	TypeRedis   = "redis"   // Redis
+	TypeLayered = "layered" // 本地 + Redis 两级缓存
+)
+
+// Config 缓存配置
+type Config struct {
+	Type     string        `mapstructure:"type" default:"local"` // local / redis / layered
+	Size     int           `mapstructure:"size" default:"10000"` // 本地缓存最大条数
+	TTL      time.Duration `mapstructure:"ttl" default:"5m"`     // 默认过期时间
+	LocalTTL time.Duration `mapstructure:"localTTL"`             // 两级缓存中本地层的过期时间，为空使用 TTL
+	Prefix   string        `mapstructure:"prefix"`               // Redis key 前缀
+}
+
+// New 根据配置创建缓存，Type 为 redis / layered 时 rdb 不能为空
+func New[K comparable, V any](cfg Config, rdb *redis.RedisClient) (Cache[K, V], error) {
+	switch cfg.Type {
+	case TypeLocal, "":
+		return NewLocal[K, V](cfg.Size, cfg.TTL), nil
+	case TypeRedis:
+		if rdb == nil {
+			return nil, fmt.Errorf("cache: redis client is required for type %q", cfg.Type)
+		}
+		return NewRedis[K, V](rdb, cfg.Prefix, cfg.TTL), nil
+	case TypeLayered:
+		if rdb == nil {
+			return nil, fmt.Errorf("cache: redis client is required for type %q", cfg.Type)
+		}
+		localTTL := cfg.LocalTTL
+		if localTTL <= 0 {
+			localTTL = cfg.TTL
+		}
+		return NewLayered[K, V](NewLocal[K, V](cfg.Size, localTTL), NewRedis[K, V](rdb, cfg.Prefix, cfg.TTL)), nil
+	default:
+		return nil, fmt.Errorf("cache: unknown type %q", cfg.Type)
+	}
+}
+
+// keyString 将 key 转为 singleflight / Redis 使用的字符串
+func keyString[K comparable](key K) string {
+	if s, ok := any(key).(string); ok {
+		return s
+	}
+	return fmt.Sprint(key)
+}
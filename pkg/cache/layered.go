@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Layered 两级缓存：先查本地，再查远端，远端命中后回填本地
+type Layered[K comparable, V any] struct {
+	local  Cache[K, V]
+	remote Cache[K, V]
+}
+
+// NewLayered 组合本地缓存和远端缓存
+func NewLayered[K comparable, V any](local, remote Cache[K, V]) *Layered[K, V] {
+	return &Layered[K, V]{local: local, remote: remote}
+}
+
+func (c *Layered[K, V]) Get(ctx context.Context, key K) (V, error) {
+	if v, err := c.local.Get(ctx, key); err == nil {
+		return v, nil
+	}
+	v, err := c.remote.Get(ctx, key)
+	if err != nil {
+		return v, err
+	}
+	_ = c.local.Set(ctx, key, v, 0)
+	return v, nil
+}
+
+// Set 先写远端再写本地，远端失败时不写本地
+func (c *Layered[K, V]) Set(ctx context.Context, key K, value V, ttl time.Duration) error {
+	if err := c.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	return c.local.Set(ctx, key, value, ttl)
+}
+
+// Delete 同时删除两级缓存；其他进程的本地缓存需等待过期
+func (c *Layered[K, V]) Delete(ctx context.Context, keys ...K) error {
+	return errors.Join(c.remote.Delete(ctx, keys...), c.local.Delete(ctx, keys...))
+}
+
+func (c *Layered[K, V]) GetOrLoad(ctx context.Context, key K, loader LoaderFunc[K, V], ttl time.Duration) (V, error) {
+	return c.local.GetOrLoad(ctx, key, func(ctx context.Context, key K) (V, error) {
+		return c.remote.GetOrLoad(ctx, key, loader, ttl)
+	}, ttl)
+}
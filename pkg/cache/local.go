@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/lru/expirable"
+	"golang.org/x/sync/singleflight"
+)
+
+// Local 基于 expirable LRU 的进程内缓存
+type Local[K comparable, V any] struct {
+	lru   *expirable.LRU[K, V]
+	group singleflight.Group
+}
+
+// NewLocal 创建本地缓存，size 为 0 不限条数，ttl 为 0 不过期；
+// Set 传入的 ttl 不能超过这里的 ttl
+func NewLocal[K comparable, V any](size int, ttl time.Duration) *Local[K, V] {
+	return &Local[K, V]{lru: expirable.NewLRU[K, V](size, nil, ttl)}
+}
+
+// LRU 返回底层 LRU，用于统计、遍历等
+func (c *Local[K, V]) LRU() *expirable.LRU[K, V] {
+	return c.lru
+}
+
+func (c *Local[K, V]) Get(_ context.Context, key K) (V, error) {
+	if v, ok := c.lru.Get(key); ok {
+		return v, nil
+	}
+	var zero V
+	return zero, ErrNotFound
+}
+
+func (c *Local[K, V]) Set(_ context.Context, key K, value V, ttl time.Duration) error {
+	c.lru.Add(key, value)
+	if ttl > 0 {
+		c.lru.UpdateTTL(key, ttl)
+	}
+	return nil
+}
+
+func (c *Local[K, V]) Delete(_ context.Context, keys ...K) error {
+	c.lru.RemoveMany(keys)
+	return nil
+}
+
+func (c *Local[K, V]) GetOrLoad(ctx context.Context, key K, loader LoaderFunc[K, V], ttl time.Duration) (V, error) {
+	if v, ok := c.lru.Get(key); ok {
+		return v, nil
+	}
+	v, err, _ := c.group.Do(keyString(key), func() (interface{}, error) {
+		if v, ok := c.lru.Peek(key); ok {
+			return v, nil
+		}
+		v, err := loader(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		_ = c.Set(ctx, key, v, ttl)
+		return v, nil
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return v.(V), nil
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// Redis 基于 Redis 的分布式缓存，值以 JSON 存储
+type Redis[K comparable, V any] struct {
+	rdb    *redis.RedisClient
+	prefix string
+	ttl    time.Duration
+	group  singleflight.Group
+}
+
+// NewRedis 创建 Redis 缓存，key 为 prefix 加 key 的字符串形式，ttl 为默认过期时间，0 不过期
+func NewRedis[K comparable, V any](rdb *redis.RedisClient, prefix string, ttl time.Duration) *Redis[K, V] {
+	return &Redis[K, V]{rdb: rdb, prefix: prefix, ttl: ttl}
+}
+
+func (c *Redis[K, V]) key(key K) string {
+	return c.prefix + keyString(key)
+}
+
+func (c *Redis[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var v V
+	if err := c.rdb.GetUnmarshal(ctx, c.key(key), &v); err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return v, ErrNotFound
+		}
+		return v, fmt.Errorf("cache: redis get: %w", err)
+	}
+	return v, nil
+}
+
+func (c *Redis[K, V]) Set(ctx context.Context, key K, value V, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+	if err := c.rdb.SetMarshal(ctx, c.key(key), value, ttl); err != nil {
+		return fmt.Errorf("cache: redis set: %w", err)
+	}
+	return nil
+}
+
+func (c *Redis[K, V]) Delete(ctx context.Context, keys ...K) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = c.key(k)
+	}
+	if err := c.rdb.Del(ctx, names...); err != nil {
+		return fmt.Errorf("cache: redis delete: %w", err)
+	}
+	return nil
+}
+
+// GetOrLoad 同一进程内的并发加载合并为一次，跨进程不加锁
+func (c *Redis[K, V]) GetOrLoad(ctx context.Context, key K, loader LoaderFunc[K, V], ttl time.Duration) (V, error) {
+	v, err := c.Get(ctx, key)
+	if !errors.Is(err, ErrNotFound) {
+		return v, err
+	}
+	res, err, _ := c.group.Do(c.key(key), func() (interface{}, error) {
+		v, err := loader(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Set(ctx, key, v, ttl); err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return res.(V), nil
+}
@@ -4,9 +4,14 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"log"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
@@ -22,8 +27,9 @@ type HttpConfig struct {
 	Port int    `mapstructure:"port"`
 }
 
-// LoadConfig 是一个泛型函数，用于加载指定 key 下的配置到任意结构体中
-func LoadConfig[T any](configPath string, fileName string, envPrefix string, configKey string) (*T, error) {
+// LoadConfig 是一个泛型函数，用于加载指定 key 下的配置到任意结构体中。
+// overlays 为同目录下依次合并的覆盖文件名（如 "config.prod"、"config.local"），后者优先，不存在的文件跳过
+func LoadConfig[T any](configPath string, fileName string, envPrefix string, configKey string, overlays ...string) (*T, error) {
 	v := viper.New()
 
 	// 设置默认值（可选）
@@ -36,31 +42,95 @@ func LoadConfig[T any](configPath string, fileName string, envPrefix string, con
 	replacer := strings.NewReplacer("_", ".")
 	v.SetEnvKeyReplacer(replacer) //
 	// 加载配置文件
-	if configPath != "" {
-		v.AddConfigPath(configPath)
-	} else {
-		v.AddConfigPath(DefaultConfigPath)
+	if configPath == "" {
+		configPath = DefaultConfigPath
 	}
-	v.SetConfigName(fileName)
-	v.SetConfigType("yaml")
+	v.AddConfigPath(configPath)
+	setConfigSource(v, configPath, fileName)
 
 	if err := v.ReadInConfig(); err != nil {
-		var configFileNotFoundError viper.ConfigFileNotFoundError
-		if !errors.As(err, &configFileNotFoundError) {
+		if !isNotFound(err) {
 			return nil, fmt.Errorf("error reading config file: %w", err)
 		}
 		log.Println("Config file not found, using defaults and environment variables.")
 	}
+	if err := mergeOverlays(v, configPath, overlays); err != nil {
+		return nil, err
+	}
 
 	// 解析指定路径下的配置到泛型结构体 T 中
-	cfg := new(T)
 	fullKey := fmt.Sprintf("%s", envPrefix)
 	if configKey != "" {
 		fullKey = fmt.Sprintf("%s.%s", envPrefix, configKey)
 	}
-	if err := v.UnmarshalKey(fullKey, cfg); err != nil {
-		return nil, fmt.Errorf("unable to decode '%s' into struct: %v", fullKey, err)
+	cfg, err := unmarshalKey[T](v, fullKey)
+	if err == nil && DumpOnLoad {
+		log.Printf("Effective config %s:\n%s", fullKey, Dump(cfg))
 	}
+	return cfg, err
+}
 
+// setConfigSource 设置要读取的配置文件，格式由扩展名决定：
+// 带扩展名（config.toml）时直接读取该文件，不带扩展名（config）时依次查找 json/toml/yaml 等支持的格式
+func setConfigSource(v *viper.Viper, configPath, name string) {
+	ext := strings.TrimPrefix(filepath.Ext(name), ".")
+	if slices.Contains(viper.SupportedExts, strings.ToLower(ext)) {
+		v.SetConfigFile(filepath.Join(configPath, name))
+		return
+	}
+	v.SetConfigName(name)
+}
+
+// isNotFound 判断是否为配置文件不存在
+func isNotFound(err error) bool {
+	var configFileNotFoundError viper.ConfigFileNotFoundError
+	return errors.As(err, &configFileNotFoundError) || errors.Is(err, fs.ErrNotExist)
+}
+
+// mergeOverlays 按顺序合并覆盖文件，map 按 key 深度合并，其余值整体替换
+func mergeOverlays(v *viper.Viper, configPath string, overlays []string) error {
+	for _, name := range overlays {
+		setConfigSource(v, configPath, name)
+		if err := v.MergeInConfig(); err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("error merging config file %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// unmarshalKey 将 key 下的配置解析到 T，写入默认值并按 validate 标签校验，key 为空时解析整个配置
+func unmarshalKey[T any](v *viper.Viper, key string) (*T, error) {
+	cfg := new(T)
+	// 默认值先于解析写入，配置文件中未出现的字段保留默认值
+	if err := applyDefaults(reflect.ValueOf(cfg), "", true, false); err != nil {
+		return nil, err
+	}
+	if d, ok := any(cfg).(Defaulter); ok {
+		d.SetDefaults()
+	}
+	// 占位符在解码时替换，不写回 viper，热更新时会重新解析
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		secretHook(),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))
+	var err error
+	if key == "" {
+		err = v.Unmarshal(cfg, hook)
+	} else {
+		err = v.UnmarshalKey(key, cfg, hook)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode '%s' into struct: %v", key, err)
+	}
+	if err := applyDefaults(reflect.ValueOf(cfg), "", false, true); err != nil {
+		return nil, err
+	}
+	if err := Validate(cfg, key); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
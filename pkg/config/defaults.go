@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaulter 由配置结构体实现，在解析前设置代码中的默认值
+type Defaulter interface {
+	SetDefaults()
+}
+
+// ApplyDefaults 将 default 标签的值写入仍为零值的字段，并调用 Defaulter。
+// 支持字符串、布尔、整数、浮点、time.Duration 以及逗号分隔的切片，嵌套结构体递归处理，nil 指针跳过
+func ApplyDefaults(cfg any) error {
+	if err := applyDefaults(reflect.ValueOf(cfg), "", true, true); err != nil {
+		return err
+	}
+	if d, ok := cfg.(Defaulter); ok {
+		d.SetDefaults()
+	}
+	return nil
+}
+
+// applyDefaults 遍历结构体字段写入默认值。
+// mapstructure 解码切片时会复用已有元素，因此切片、map 的默认值需在解码后且仍为空时再写入
+func applyDefaults(v reflect.Value, path string, scalars, collections bool) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		name := path + sf.Name
+		if tag, ok := sf.Tag.Lookup("default"); ok && fv.IsZero() {
+			collection := fv.Kind() == reflect.Slice || fv.Kind() == reflect.Map
+			if (collection && collections) || (!collection && scalars) {
+				if err := setDefault(fv, tag); err != nil {
+					return fmt.Errorf("invalid default for %s: %w", name, err)
+				}
+			}
+			continue
+		}
+		if fv.Kind() == reflect.Struct || fv.Kind() == reflect.Ptr {
+			if err := applyDefaults(fv, name+".", scalars, collections); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setDefault(fv reflect.Value, raw string) error {
+	if fv.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		parts := strings.Split(raw, ",")
+		s := reflect.MakeSlice(fv.Type(), len(parts), len(parts))
+		for i, p := range parts {
+			if err := setDefault(s.Index(i), strings.TrimSpace(p)); err != nil {
+				return err
+			}
+		}
+		fv.Set(s)
+	default:
+		return fmt.Errorf("unsupported kind %s", fv.Kind())
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// sensitiveKey 需要脱敏的字段名
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|privatekey|accesskey|apikey|customerkey)`)
+
+const dumpMask = "******"
+
+// DumpOnLoad 为 true 时 LoadConfig 加载成功后打印脱敏后的生效配置，便于启动排查
+var DumpOnLoad = false
+
+// Dump 以 JSON 输出生效后的配置（已合并环境变量和默认值），字段名使用 mapstructure 标签。
+// 名称包含 password、secret、token 等的字段，以及带 `mask:"true"` 标签的字段会被脱敏
+func Dump(cfg any) string {
+	data, err := json.MarshalIndent(dumpValue(reflect.ValueOf(cfg)), "", "  ")
+	if err != nil {
+		return "<config dump failed: " + err.Error() + ">"
+	}
+	return string(data)
+}
+
+// DumpHandler 返回输出脱敏配置的 HTTP 处理器，可挂载到 /debug/config
+func DumpHandler(cfgs map[string]any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		out := make(map[string]interface{}, len(cfgs))
+		for name, cfg := range cfgs {
+			out[name] = dumpValue(reflect.ValueOf(cfg))
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(out)
+	})
+}
+
+func dumpValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return dumpValue(v.Elem())
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil
+	}
+	switch t := v.Interface().(type) {
+	case time.Duration:
+		return t.String()
+	case time.Time:
+		return t.Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(sf.Tag.Get("mapstructure"), ",")
+			if name == "-" {
+				continue
+			}
+			fv := v.Field(i)
+			// squash 的嵌入结构体字段提升到当前层
+			if strings.Contains(opts, "squash") {
+				if m, ok := dumpValue(fv).(map[string]interface{}); ok {
+					for k, val := range m {
+						out[k] = val
+					}
+				}
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			if sf.Tag.Get("mask") == "true" || sensitiveKey.MatchString(name) {
+				out[name] = maskValue(fv)
+				continue
+			}
+			out[name] = dumpValue(fv)
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := stringKey(iter.Key())
+			if sensitiveKey.MatchString(k) {
+				out[k] = maskValue(iter.Value())
+				continue
+			}
+			out[k] = dumpValue(iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = dumpValue(v.Index(i))
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
+
+// maskValue 非零值替换为掩码，零值保留以便确认未配置
+func maskValue(v reflect.Value) interface{} {
+	if !v.IsValid() || v.IsZero() {
+		return dumpValue(v)
+	}
+	return dumpMask
+}
+
+func stringKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	data, _ := json.Marshal(k.Interface())
+	return strings.Trim(string(data), `"`)
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// 远程配置源类型
+const (
+	RemoteEtcd   = "etcd"
+	RemoteConsul = "consul"
+	RemoteNacos  = "nacos"
+)
+
+// RemoteConfig 远程配置中心参数
+type RemoteConfig struct {
+	Provider     string   `mapstructure:"provider"`     // etcd / consul / nacos
+	Endpoints    []string `mapstructure:"endpoints"`    // 服务地址，consul/nacos 使用第一个可用地址
+	Key          string   `mapstructure:"key"`          // etcd/consul 的 key，nacos 的 dataId
+	Group        string   `mapstructure:"group"`        // nacos 分组，默认 DEFAULT_GROUP
+	Namespace    string   `mapstructure:"namespace"`    // nacos 命名空间 ID
+	Username     string   `mapstructure:"username"`     // etcd/nacos 用户名
+	Password     string   `mapstructure:"password"`     // etcd/nacos 密码
+	Token        string   `mapstructure:"token"`        // consul ACL token
+	Format       string   `mapstructure:"format"`       // 配置内容格式：yaml / json / toml，默认 yaml
+	FallbackFile string   `mapstructure:"fallbackFile"` // 本地回落文件：远程读取成功时写入，远程不可用时读取
+	Watch        bool     `mapstructure:"watch"`        // 是否监听远程变更
+}
+
+// RemoteProvider 远程配置源
+type RemoteProvider interface {
+	// Get 读取配置内容
+	Get(ctx context.Context) ([]byte, error)
+	// Watch 监听配置变更，每次变更推送最新内容，ctx 结束后关闭通道
+	Watch(ctx context.Context) (<-chan []byte, error)
+}
+
+// NewRemoteProvider 根据配置创建远程配置源，etcd 如需复用注册中心连接请使用 NewEtcdProvider。
+// 返回的配置源实现 io.Closer 时（etcd），不再使用后由调用方关闭
+func NewRemoteProvider(rc *RemoteConfig) (RemoteProvider, error) {
+	if len(rc.Endpoints) == 0 {
+		return nil, fmt.Errorf("remote config endpoints are empty")
+	}
+	switch strings.ToLower(rc.Provider) {
+	case RemoteEtcd:
+		return newEtcdProviderFromConfig(rc)
+	case RemoteConsul:
+		return newConsulProvider(rc), nil
+	case RemoteNacos:
+		return newNacosProvider(rc), nil
+	default:
+		return nil, fmt.Errorf("unsupported remote config provider: %s", rc.Provider)
+	}
+}
+
+// LoadRemoteConfig 从远程配置中心读取配置并解析 configKey 下的内容到 T。
+// 远程不可用时读取 FallbackFile；rc.Watch 为 true 时在后台监听变更并回调 onChange，直到 ctx 结束
+func LoadRemoteConfig[T any](ctx context.Context, rc *RemoteConfig, configKey string, onChange func(*T)) (*T, error) {
+	p, err := NewRemoteProvider(rc)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := LoadRemoteConfigFrom[T](ctx, p, rc, configKey, onChange)
+	// 自行创建的连接（如 etcd）不监听时加载完即关闭，监听时在 ctx 结束后关闭
+	if c, ok := p.(io.Closer); ok {
+		if err != nil || !rc.Watch {
+			_ = c.Close()
+		} else {
+			context.AfterFunc(ctx, func() { _ = c.Close() })
+		}
+	}
+	return cfg, err
+}
+
+// LoadRemoteConfigFrom 与 LoadRemoteConfig 相同，使用调用方提供的配置源（如复用注册中心的 etcd 连接）
+func LoadRemoteConfigFrom[T any](ctx context.Context, p RemoteProvider, rc *RemoteConfig, configKey string, onChange func(*T)) (*T, error) {
+	getCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	data, err := p.Get(getCtx)
+	cancel()
+	if err != nil {
+		if rc.FallbackFile == "" {
+			return nil, fmt.Errorf("error reading remote config: %w", err)
+		}
+		log.Printf("Remote config unavailable, using fallback file %s: %v", rc.FallbackFile, err)
+		if data, err = os.ReadFile(rc.FallbackFile); err != nil {
+			return nil, fmt.Errorf("error reading fallback config file: %w", err)
+		}
+	} else {
+		saveFallback(rc.FallbackFile, data)
+	}
+
+	cfg, err := parseRemote[T](data, rc.Format, configKey)
+	if err != nil {
+		return nil, err
+	}
+	if rc.Watch {
+		ch, err := p.Watch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error watching remote config: %w", err)
+		}
+		go func() {
+			last := cfg
+			for data := range ch {
+				next, err := parseRemote[T](data, rc.Format, configKey)
+				if err != nil {
+					log.Printf("Remote config reload failed, keeping previous value: %v", err)
+					continue
+				}
+				saveFallback(rc.FallbackFile, data)
+				if reflect.DeepEqual(last, next) {
+					continue
+				}
+				last = next
+				if onChange != nil {
+					onChange(next)
+				}
+			}
+		}()
+	}
+	return cfg, nil
+}
+
+// parseRemote 按格式解析配置内容
+func parseRemote[T any](data []byte, format, key string) (*T, error) {
+	if format == "" {
+		format = "yaml"
+	}
+	v := viper.New()
+	v.SetConfigType(format)
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("error parsing remote config: %w", err)
+	}
+	return unmarshalKey[T](v, key)
+}
+
+// saveFallback 将远程配置写入本地回落文件，先写临时文件再重命名，避免写到一半被读取
+func saveFallback(path string, data []byte) {
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		log.Printf("Failed to save fallback config: %v", err)
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		log.Printf("Failed to save fallback config: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		log.Printf("Failed to save fallback config: %v", err)
+	}
+}
+
+// retryDelay 监听出错后的重试间隔，指数增长，最长 30 秒
+func retryDelay(attempt int) time.Duration {
+	d := time.Second << uint(attempt)
+	if attempt > 5 || d > 30*time.Second {
+		return 30 * time.Second
+	}
+	return d
+}
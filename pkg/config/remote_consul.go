@@ -0,0 +1,127 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// consulProvider 基于 Consul KV HTTP 接口的配置源，使用阻塞查询监听变更
+type consulProvider struct {
+	endpoint string
+	key      string
+	token    string
+	client   *http.Client
+}
+
+func newConsulProvider(rc *RemoteConfig) *consulProvider {
+	return &consulProvider{
+		endpoint: httpEndpoint(rc.Endpoints[0]),
+		key:      strings.TrimPrefix(rc.Key, "/"),
+		token:    rc.Token,
+		client:   &http.Client{},
+	}
+}
+
+func (p *consulProvider) Get(ctx context.Context) ([]byte, error) {
+	data, _, err := p.get(ctx, 0)
+	return data, err
+}
+
+// get 读取 key，index 大于 0 时为阻塞查询，直到数据变化或等待超时
+func (p *consulProvider) get(ctx context.Context, index uint64) ([]byte, uint64, error) {
+	q := url.Values{"raw": {""}}
+	if index > 0 {
+		q.Set("index", strconv.FormatUint(index, 10))
+		q.Set("wait", "5m")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint+"/v1/kv/"+p.key+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if p.token != "" {
+		req.Header.Set("X-Consul-Token", p.token)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("consul kv %s: status %d", p.key, resp.StatusCode)
+	}
+	newIndex, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	return body, newIndex, nil
+}
+
+func (p *consulProvider) Watch(ctx context.Context) (<-chan []byte, error) {
+	_, index, err := p.get(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan []byte, 1)
+	go func() {
+		defer close(out)
+		attempt := 0
+		for ctx.Err() == nil {
+			data, next, err := p.get(ctx, index)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("Consul config watch failed: %v", err)
+				if !sleepCtx(ctx, retryDelay(attempt)) {
+					return
+				}
+				attempt++
+				continue
+			}
+			attempt = 0
+			// index 回退说明 Consul 重建了索引，需要从头开始
+			if next < index {
+				index = 0
+				continue
+			}
+			if next == index {
+				continue
+			}
+			index = next
+			select {
+			case out <- data:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// httpEndpoint 补全协议前缀并去掉末尾斜杠
+func httpEndpoint(ep string) string {
+	if !strings.HasPrefix(ep, "http://") && !strings.HasPrefix(ep, "https://") {
+		ep = "http://" + ep
+	}
+	return strings.TrimRight(ep, "/")
+}
+
+// sleepCtx 等待 d，ctx 结束时返回 false
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcdProvider 基于 etcd 的配置源
+type etcdProvider struct {
+	cli   *clientv3.Client
+	key   string
+	owned bool         // 连接由配置源创建，Close 时关闭
+	rev   atomic.Int64 // 最近一次 Get 的 revision，Watch 从其后开始
+}
+
+// NewEtcdProvider 使用已有的 etcd 连接创建配置源，可复用注册中心的连接（etcd.EtcdRegistry.Client）
+func NewEtcdProvider(cli *clientv3.Client, key string) RemoteProvider {
+	return &etcdProvider{cli: cli, key: key}
+}
+
+func newEtcdProviderFromConfig(rc *RemoteConfig) (RemoteProvider, error) {
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   rc.Endpoints,
+		Username:    rc.Username,
+		Password:    rc.Password,
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error connecting etcd: %w", err)
+	}
+	return &etcdProvider{cli: cli, key: rc.Key, owned: true}, nil
+}
+
+// Close 关闭配置源自行创建的连接，复用的连接由调用方关闭
+func (p *etcdProvider) Close() error {
+	if !p.owned {
+		return nil
+	}
+	return p.cli.Close()
+}
+
+func (p *etcdProvider) Get(ctx context.Context) ([]byte, error) {
+	data, _, err := p.get(ctx)
+	return data, err
+}
+
+// get 读取 key 并记录 revision
+func (p *etcdProvider) get(ctx context.Context) ([]byte, int64, error) {
+	resp, err := p.cli.Get(ctx, p.key)
+	if err != nil {
+		return nil, 0, err
+	}
+	p.rev.Store(resp.Header.Revision)
+	if len(resp.Kvs) == 0 {
+		return nil, resp.Header.Revision, fmt.Errorf("etcd key %s not found", p.key)
+	}
+	return resp.Kvs[0].Value, resp.Header.Revision, nil
+}
+
+// Watch 从上次 Get 的 revision 之后开始监听，避免丢失 Get 与 Watch 之间的变更；
+// 监听中断（如压缩、失去 leader）后按 retryDelay 重建，已被压缩时重新读取最新值
+func (p *etcdProvider) Watch(ctx context.Context) (<-chan []byte, error) {
+	rev := p.rev.Load()
+	if rev == 0 {
+		// 未调用过 Get 时以当前 revision 为起点，key 尚不存在也可以监听
+		_, r, err := p.get(ctx)
+		if r == 0 {
+			return nil, err
+		}
+		rev = r
+	}
+	out := make(chan []byte, 1)
+	send := func(data []byte) bool {
+		select {
+		case out <- data:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	go func() {
+		defer close(out)
+		next := rev + 1
+		attempt := 0
+		for ctx.Err() == nil {
+			var err error
+			wch := p.cli.Watch(clientv3.WithRequireLeader(ctx), p.key, clientv3.WithRev(next))
+			for resp := range wch {
+				if err = resp.Err(); err != nil {
+					break
+				}
+				attempt = 0
+				next = resp.Header.Revision + 1
+				for _, ev := range resp.Events {
+					if ev.Type == clientv3.EventTypePut && !send(ev.Kv.Value) {
+						return
+					}
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				err = errors.New("watch channel closed")
+			}
+			log.Printf("Etcd config watch failed: %v", err)
+			if errors.Is(err, rpctypes.ErrCompacted) {
+				// 压缩后无法补齐中间的变更，直接读取最新值
+				data, r, getErr := p.get(ctx)
+				switch {
+				case getErr == nil:
+					next = r + 1
+					if !send(data) {
+						return
+					}
+					continue
+				case r > 0:
+					next = r + 1
+				default:
+					log.Printf("Etcd config reload failed: %v", getErr)
+				}
+			}
+			if !sleepCtx(ctx, retryDelay(attempt)) {
+				return
+			}
+			attempt++
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// nacosProvider 基于 Nacos Open API 的配置源，使用长轮询监听变更
+type nacosProvider struct {
+	endpoints []string
+	dataID    string
+	group     string
+	tenant    string
+	username  string
+	password  string
+	client    *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	tokenExpire time.Time
+}
+
+func newNacosProvider(rc *RemoteConfig) *nacosProvider {
+	group := rc.Group
+	if group == "" {
+		group = "DEFAULT_GROUP"
+	}
+	endpoints := make([]string, 0, len(rc.Endpoints))
+	for _, ep := range rc.Endpoints {
+		endpoints = append(endpoints, httpEndpoint(ep))
+	}
+	return &nacosProvider{
+		endpoints: endpoints,
+		dataID:    rc.Key,
+		group:     group,
+		tenant:    rc.Namespace,
+		username:  rc.Username,
+		password:  rc.Password,
+		client:    &http.Client{},
+	}
+}
+
+func (p *nacosProvider) Get(ctx context.Context) ([]byte, error) {
+	q := url.Values{"dataId": {p.dataID}, "group": {p.group}}
+	if p.tenant != "" {
+		q.Set("tenant", p.tenant)
+	}
+	var lastErr error
+	for _, ep := range p.endpoints {
+		data, err := p.do(ctx, ep, http.MethodGet, "/nacos/v1/cs/configs", q, nil)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (p *nacosProvider) Watch(ctx context.Context) (<-chan []byte, error) {
+	data, err := p.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan []byte, 1)
+	go func() {
+		defer close(out)
+		sum := md5Hex(data)
+		attempt := 0
+		for ctx.Err() == nil {
+			changed, err := p.listen(ctx, sum)
+			if err == nil && changed {
+				data, err = p.Get(ctx)
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("Nacos config watch failed: %v", err)
+				if !sleepCtx(ctx, retryDelay(attempt)) {
+					return
+				}
+				attempt++
+				continue
+			}
+			attempt = 0
+			if !changed {
+				continue
+			}
+			sum = md5Hex(data)
+			select {
+			case out <- data:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// listen 长轮询等待配置变化，返回 true 表示 md5 已变化
+func (p *nacosProvider) listen(ctx context.Context, sum string) (bool, error) {
+	// 格式：dataId^2group^2md5[^2tenant]^1
+	item := p.dataID + "\x02" + p.group + "\x02" + sum
+	if p.tenant != "" {
+		item += "\x02" + p.tenant
+	}
+	form := url.Values{"Listening-Configs": {item + "\x01"}}
+	var lastErr error
+	for _, ep := range p.endpoints {
+		body, err := p.do(ctx, ep, http.MethodPost, "/nacos/v1/cs/configs/listener", nil, form)
+		if err == nil {
+			return strings.TrimSpace(string(body)) != "", nil
+		}
+		lastErr = err
+	}
+	return false, lastErr
+}
+
+// do 发送请求，配置了用户名时自动登录并携带 accessToken
+func (p *nacosProvider) do(ctx context.Context, endpoint, method, path string, query, form url.Values) ([]byte, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	token, err := p.token(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		query.Set("accessToken", token)
+	}
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint+path+"?"+query.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Long-Pulling-Timeout", "30000")
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nacos %s: status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// token 获取并缓存登录 token，未配置用户名时返回空
+func (p *nacosProvider) token(ctx context.Context, endpoint string) (string, error) {
+	if p.username == "" {
+		return "", nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.accessToken != "" && time.Now().Before(p.tokenExpire) {
+		return p.accessToken, nil
+	}
+	form := url.Values{"username": {p.username}, "password": {p.password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/nacos/v1/auth/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("nacos login: status %d", resp.StatusCode)
+	}
+	var result struct {
+		AccessToken string `json:"accessToken"`
+		TokenTTL    int64  `json:"tokenTtl"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("nacos login: %w", err)
+	}
+	p.accessToken = result.AccessToken
+	// 提前 10% 刷新
+	p.tokenExpire = time.Now().Add(time.Duration(result.TokenTTL) * time.Second * 9 / 10)
+	return p.accessToken, nil
+}
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// SecretResolver 解析占位符 ${scheme:ref} 中的 ref，返回实际值
+type SecretResolver func(ref string) (string, error)
+
+var (
+	placeholder = regexp.MustCompile(`\$\{(\w+):([^}]+)\}`)
+
+	resolversMu sync.RWMutex
+	resolvers   = map[string]SecretResolver{
+		"env":   resolveEnv,
+		"file":  resolveFile,
+		"vault": resolveVault,
+	}
+)
+
+// RegisterSecretResolver 注册自定义占位符，如 RegisterSecretResolver("kms", ...) 后可在配置中使用 ${kms:xxx}
+func RegisterSecretResolver(scheme string, r SecretResolver) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	resolvers[scheme] = r
+}
+
+// ResolveSecrets 替换字符串中的 ${env:VAR}、${file:/run/secrets/x}、${vault:path#field} 占位符
+func ResolveSecrets(s string) (string, error) {
+	var firstErr error
+	out := placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		sub := placeholder.FindStringSubmatch(m)
+		resolversMu.RLock()
+		r, ok := resolvers[sub[1]]
+		resolversMu.RUnlock()
+		if !ok {
+			// 未知 scheme 原样保留
+			return m
+		}
+		v, err := r(sub[2])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("resolve %s: %w", m, err)
+			}
+			return m
+		}
+		return v
+	})
+	return out, firstErr
+}
+
+// secretHook 解码时替换字符串中的占位符，先于类型转换执行，因此 port: ${env:PORT} 也可解析为整数
+func secretHook() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, _ reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String {
+			return data, nil
+		}
+		s := data.(string)
+		if !strings.Contains(s, "${") {
+			return data, nil
+		}
+		return ResolveSecrets(s)
+	}
+}
+
+func resolveEnv(name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return v, nil
+}
+
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+var vaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// resolveVault 读取 Vault 中的密钥，格式 path#field，如 ${vault:secret/data/app#redisPassword}。
+// 地址和 token 取自环境变量 VAULT_ADDR、VAULT_TOKEN，兼容 KV v1 和 v2
+func resolveVault(ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || field == "" {
+		return "", fmt.Errorf("vault reference must be path#field")
+	}
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return "", fmt.Errorf("VAULT_ADDR is not set")
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", os.Getenv("VAULT_TOKEN"))
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	resp, err := vaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault %s: status %d", path, resp.StatusCode)
+	}
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("vault %s: %w", path, err)
+	}
+	data := body.Data
+	// KV v2 的数据嵌套在 data.data 中
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, hasMeta := data["metadata"]; hasMeta {
+			data = inner
+		}
+	}
+	val, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("vault %s: field %s not found", path, field)
+	}
+	return fmt.Sprint(val), nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+// newValidator 创建校验器，字段名使用 mapstructure 标签，错误信息与配置文件中的 key 对应
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("mapstructure"), ",", 2)[0]
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return f.Name
+		}
+		return name
+	})
+	return v
+}
+
+// ValidationError 配置校验失败，Problems 为每个字段的错误描述
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate 按 validate 标签校验配置（required、min、max、oneof 等），
+// prefix 为配置所在的 key，用于拼出完整路径，如 box.redis.poolSize must be > 0
+func Validate(cfg any, prefix string) error {
+	err := validate.Struct(cfg)
+	if err == nil {
+		return nil
+	}
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		var invalid *validator.InvalidValidationError
+		if errors.As(err, &invalid) {
+			// 非结构体类型无需校验
+			return nil
+		}
+		return err
+	}
+	problems := make([]string, 0, len(verrs))
+	for _, fe := range verrs {
+		problems = append(problems, fieldPath(prefix, fe.Namespace())+" "+describe(fe))
+	}
+	return &ValidationError{Problems: problems}
+}
+
+// fieldPath 将校验器的 Namespace（根类型名.字段...）替换为配置 key
+func fieldPath(prefix, namespace string) string {
+	path := namespace
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		path = namespace[i+1:]
+	}
+	if prefix == "" {
+		return path
+	}
+	return prefix + "." + path
+}
+
+// describe 生成可读的错误描述
+func describe(fe validator.FieldError) string {
+	sized := false
+	switch fe.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		sized = true
+	}
+	bound := func(op string) string {
+		if sized {
+			return fmt.Sprintf("length must be %s %s", op, fe.Param())
+		}
+		return fmt.Sprintf("must be %s %s", op, fe.Param())
+	}
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return "is required"
+	case "min", "gte":
+		return bound(">=")
+	case "max", "lte":
+		return bound("<=")
+	case "gt":
+		return bound(">")
+	case "lt":
+		return bound("<")
+	case "len":
+		return bound("==")
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s], got %v", fe.Param(), fe.Value())
+	case "url", "uri", "email", "ip", "hostname", "hostname_port", "cidr":
+		return fmt.Sprintf("must be a valid %s, got %v", fe.Tag(), fe.Value())
+	default:
+		return fmt.Sprintf("failed on '%s' validation", fe.ActualTag())
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Watch 加载配置文件 path 中 key 下的配置，文件变更后重新解析并回调 onChange。
+// 解析失败或内容未变化时不回调，调用方继续使用旧配置
+func Watch[T any](path string, key string, onChange func(*T)) (*T, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	cfg, err := unmarshalKey[T](v, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	last := cfg
+	v.OnConfigChange(func(e fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		next, err := unmarshalKey[T](v, key)
+		if err != nil {
+			log.Printf("Config reload of %s failed, keeping previous value: %v", e.Name, err)
+			return
+		}
+		// 编辑器保存时可能触发多次事件，内容未变化时忽略
+		if reflect.DeepEqual(last, next) {
+			return
+		}
+		last = next
+		if onChange != nil {
+			onChange(next)
+		}
+	})
+	v.WatchConfig()
+	return cfg, nil
+}
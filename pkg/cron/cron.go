@@ -0,0 +1,349 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/redis"
+	"github.com/code-sigs/go-box/pkg/trace"
+	"github.com/code-sigs/go-box/pkg/utils"
+	"github.com/robfig/cron/v3"
+)
+
+// parser 支持 5 段（分 时 日 月 周）与带秒的 6 段表达式，以及 @every 1m、@daily 等描述符
+var parser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+var (
+	ErrDuplicateJob = errors.New("cron job already exists")
+	ErrStopped      = errors.New("cron scheduler stopped")
+)
+
+// Job 定时任务，ctx 在任务超时或调度器强制停止时取消
+type Job func(ctx context.Context) error
+
+// RunRecord 一次任务执行的记录，传给 WithHistory 注册的钩子
+type RunRecord struct {
+	Name      string        // 任务名
+	Scheduled time.Time     // 计划触发时间
+	Start     time.Time     // 实际开始时间，跳过时为零值
+	Duration  time.Duration // 执行耗时
+	Err       error         // 任务返回的错误，panic 时为 *utils.PanicError
+	Skipped   bool          // 未执行：上一次仍在运行或其他实例持有锁
+	Reason    string        // 跳过原因
+}
+
+// Option 调度器可选参数
+type Option func(*Scheduler)
+
+// WithRedis 启用分布式锁，同一任务在集群中同一时刻只有一个实例执行
+func WithRedis(rdb *redis.RedisClient) Option {
+	return func(s *Scheduler) { s.rdb = rdb }
+}
+
+// WithLockPrefix 设置分布式锁 key 前缀，默认 "cron:"，多个服务共用 redis 时按服务区分
+func WithLockPrefix(prefix string) Option {
+	return func(s *Scheduler) { s.lockPrefix = prefix }
+}
+
+// WithLocation 设置解析表达式使用的时区，默认 time.Local
+func WithLocation(loc *time.Location) Option {
+	return func(s *Scheduler) { s.location = loc }
+}
+
+// WithHistory 注册执行记录钩子，可用于落库或上报指标，钩子同步调用，不应阻塞
+func WithHistory(hook func(ctx context.Context, record RunRecord)) Option {
+	return func(s *Scheduler) { s.hooks = append(s.hooks, hook) }
+}
+
+// JobOption 单个任务的可选参数
+type JobOption func(*entry)
+
+// WithTimeout 设置单次执行超时
+func WithTimeout(d time.Duration) JobOption {
+	return func(e *entry) { e.timeout = d }
+}
+
+// WithLockTTL 设置锁过期时间，持锁期间自动续期，默认 30s
+func WithLockTTL(d time.Duration) JobOption {
+	return func(e *entry) { e.lockTTL = d }
+}
+
+// WithMinLockHold 任务结束后至少持锁的时长，用于吸收实例间的时钟偏差，默认 5s，
+// 不应超过任务的调度间隔
+func WithMinLockHold(d time.Duration) JobOption {
+	return func(e *entry) { e.minHold = d }
+}
+
+// WithoutLock 每个实例都执行该任务，如清理本地缓存
+func WithoutLock() JobOption {
+	return func(e *entry) { e.noLock = true }
+}
+
+type entry struct {
+	name     string
+	spec     string
+	schedule cron.Schedule
+	job      Job
+	timeout  time.Duration
+	lockTTL  time.Duration
+	minHold  time.Duration
+	noLock   bool
+
+	mu      sync.Mutex
+	running bool
+	stop    chan struct{}
+}
+
+// Scheduler 定时任务调度器
+type Scheduler struct {
+	rdb        *redis.RedisClient
+	lockPrefix string
+	location   *time.Location
+	hooks      []func(ctx context.Context, record RunRecord)
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	started bool
+	stopped bool
+
+	ctx    context.Context    // 任务的父 ctx，强制停止时取消
+	cancel context.CancelFunc // 取消 ctx
+	stopCh chan struct{}      // 停止调度新的执行
+	loops  sync.WaitGroup     // 每个任务的调度循环
+	runs   sync.WaitGroup     // 执行中的任务
+}
+
+// New 创建调度器，添加任务后调用 Start
+func New(opts ...Option) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		lockPrefix: "cron:",
+		location:   time.Local,
+		entries:    make(map[string]*entry),
+		ctx:        ctx,
+		cancel:     cancel,
+		stopCh:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Add 添加任务，name 在调度器内唯一，同时作为分布式锁的 key；Start 之后添加的任务立即开始调度
+func (s *Scheduler) Add(name, spec string, job Job, opts ...JobOption) error {
+	schedule, err := parser.Parse(spec)
+	if err != nil {
+		return fmt.Errorf("parse cron spec %q: %w", spec, err)
+	}
+	e := &entry{
+		name:     name,
+		spec:     spec,
+		schedule: schedule,
+		job:      job,
+		lockTTL:  30 * time.Second,
+		minHold:  5 * time.Second,
+		stop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return ErrStopped
+	}
+	if _, ok := s.entries[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateJob, name)
+	}
+	s.entries[name] = e
+	if s.started {
+		s.startLoop(e)
+	}
+	return nil
+}
+
+// Remove 移除任务，不影响正在执行的那一次
+func (s *Scheduler) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[name]; ok {
+		close(e.stop)
+		delete(s.entries, name)
+	}
+}
+
+// Start 开始调度，重复调用无效
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+	for _, e := range s.entries {
+		s.startLoop(e)
+	}
+}
+
+// Stop 停止调度并等待执行中的任务结束；ctx 到期时取消任务 ctx 并返回错误。
+// 签名与 router.OnShutdown 一致，可直接注册
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.stopCh)
+	}
+	s.mu.Unlock()
+	s.loops.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return fmt.Errorf("stop cron scheduler: %w", ctx.Err())
+	}
+}
+
+// startLoop 调用方需持有 s.mu
+func (s *Scheduler) startLoop(e *entry) {
+	s.loops.Add(1)
+	go func() {
+		defer s.loops.Done()
+		for {
+			next := e.schedule.Next(time.Now().In(s.location))
+			if next.IsZero() {
+				return
+			}
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-timer.C:
+				// 在同一把锁下登记，保证 Stop 中 runs.Wait 不会漏掉刚触发的执行
+				s.mu.Lock()
+				if s.stopped {
+					s.mu.Unlock()
+					return
+				}
+				s.runs.Add(1)
+				s.mu.Unlock()
+				go func() {
+					defer s.runs.Done()
+					s.run(e, next)
+				}()
+			case <-e.stop:
+				timer.Stop()
+				return
+			case <-s.stopCh:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) run(e *entry, scheduled time.Time) {
+	ctx, span := trace.Start(s.ctx, "cron "+e.name)
+	record := RunRecord{Name: e.name, Scheduled: scheduled}
+	defer func() {
+		trace.End(span, record.Err)
+		s.emit(ctx, record)
+	}()
+
+	e.mu.Lock()
+	if e.running {
+		e.mu.Unlock()
+		record.Skipped, record.Reason = true, "previous run still in progress"
+		return
+	}
+	e.running = true
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		e.running = false
+		e.mu.Unlock()
+	}()
+
+	if s.rdb != nil && !e.noLock {
+		lock := redis.NewRedisLock(s.rdb, s.lockPrefix+e.name, e.lockTTL)
+		ok, err := lock.Lock()
+		if err != nil {
+			record.Err = fmt.Errorf("acquire cron lock: %w", err)
+			return
+		}
+		if !ok {
+			record.Skipped, record.Reason = true, "lock held by another instance"
+			return
+		}
+		// 当前执行仍计入 runs，这里追加的计数保证 Stop 会等待锁释放
+		defer func() {
+			s.runs.Add(1)
+			go func() {
+				defer s.runs.Done()
+				s.unlock(ctx, e, lock, scheduled)
+			}()
+		}()
+	}
+
+	runCtx := ctx
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+	record.Start = time.Now()
+	record.Err = safeRun(runCtx, e.job)
+	record.Duration = time.Since(record.Start)
+}
+
+// unlock 从计划时间起持锁满 minHold 后释放，避免时钟稍慢的实例在释放后再次执行同一次调度；停止时立即释放
+func (s *Scheduler) unlock(ctx context.Context, e *entry, lock *redis.RedisLock, scheduled time.Time) {
+	if wait := time.Until(scheduled.Add(e.minHold)); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.stopCh:
+			timer.Stop()
+		}
+	}
+	if _, err := lock.Unlock(); err != nil {
+		logger.Warnw(ctx, "release cron lock failed", "job", e.name, "error", err.Error())
+	}
+}
+
+func (s *Scheduler) emit(ctx context.Context, record RunRecord) {
+	var perr *utils.PanicError
+	switch {
+	case errors.As(record.Err, &perr):
+		logger.Errorw(ctx, "cron job panic", "job", record.Name, "panic", fmt.Sprint(perr.Value), "stack", string(perr.Stack))
+	case record.Err != nil:
+		logger.Errorw(ctx, "cron job failed", "job", record.Name, "error", record.Err.Error(), "duration", record.Duration.String())
+	case record.Skipped:
+		logger.Debugw(ctx, "cron job skipped", "job", record.Name, "reason", record.Reason)
+	}
+	for _, hook := range s.hooks {
+		hook(ctx, record)
+	}
+}
+
+// safeRun 执行任务并把 panic 转为 *utils.PanicError
+func safeRun(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &utils.PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return job(ctx)
+}
@@ -0,0 +1,296 @@
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+)
+
+// ErrBulkIndexerClosed 在 BulkIndexer 已关闭后继续写入时返回
+var ErrBulkIndexerClosed = errors.New("bulk indexer 已关闭")
+
+// BulkIndexerConfig 定义异步批量索引器的刷新策略与回调
+type BulkIndexerConfig[T IndexNamer] struct {
+	NumWorkers    int           // 并发 worker 数，默认 1
+	QueueSize     int           // 待写入队列长度，默认 1024
+	FlushCount    int           // 单批文档数达到该值时刷新，默认 1000
+	FlushBytes    int           // 单批请求体字节数达到该值时刷新，默认 5MB
+	FlushInterval time.Duration // 定时刷新间隔，默认 1 秒
+	Refresh       string        // bulk 请求的 refresh 参数，默认不刷新
+
+	// OnItemFailure 单条文档写入失败时回调（ES 返回的 item 错误或整批请求失败）
+	OnItemFailure func(ctx context.Context, item BulkIndexerItem[T], err error)
+	// OnItemSuccess 单条文档写入成功时回调（可选）
+	OnItemSuccess func(ctx context.Context, item BulkIndexerItem[T])
+	// OnFlush 每批刷新完成后回调（可选）
+	OnFlush func(ctx context.Context, count int, took time.Duration)
+}
+
+// BulkIndexerItem 表示一条待写入的文档
+type BulkIndexerItem[T IndexNamer] struct {
+	Index      string // 最终索引名
+	DocumentID string // 文档 ID，可为空
+	Doc        *T
+}
+
+// BulkIndexerStats 批量索引器统计
+type BulkIndexerStats struct {
+	NumAdded   uint64 // 已入队文档数
+	NumFlushed uint64 // 已提交文档数
+	NumFailed  uint64 // 失败文档数
+	NumBatches uint64 // 已发送批次数
+}
+
+// BulkIndexer 是后台异步批量写入器，按数量/字节/时间间隔刷新
+type BulkIndexer[T IndexNamer] struct {
+	client *ElasticClient[T]
+	cfg    BulkIndexerConfig[T]
+	queue  chan bulkEntry[T]
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+
+	added   atomic.Uint64
+	flushed atomic.Uint64
+	failed  atomic.Uint64
+	batches atomic.Uint64
+}
+
+type bulkEntry[T IndexNamer] struct {
+	item BulkIndexerItem[T]
+	meta []byte
+	body []byte
+}
+
+// NewBulkIndexer 创建并启动异步批量索引器，使用完毕后需调用 Drain
+func (c *ElasticClient[T]) NewBulkIndexer(cfg BulkIndexerConfig[T]) *BulkIndexer[T] {
+	if cfg.NumWorkers <= 0 {
+		cfg.NumWorkers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.FlushCount <= 0 {
+		cfg.FlushCount = 1000
+	}
+	if cfg.FlushBytes <= 0 {
+		cfg.FlushBytes = 5 * 1024 * 1024
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+
+	b := &BulkIndexer[T]{
+		client: c,
+		cfg:    cfg,
+		queue:  make(chan bulkEntry[T], cfg.QueueSize),
+	}
+	for i := 0; i < cfg.NumWorkers; i++ {
+		b.wg.Add(1)
+		go b.worker()
+	}
+	return b
+}
+
+// Add 将文档放入写入队列，队列满时阻塞直到 ctx 结束
+func (b *BulkIndexer[T]) Add(ctx context.Context, doc *T, id string, strategy IndexStrategy) error {
+	if doc == nil {
+		return errors.New("文档为空")
+	}
+	if strategy == nil {
+		strategy = DefaultIndexStrategy
+	}
+	item := BulkIndexerItem[T]{
+		Index:      strategy((*doc).IndexName()),
+		DocumentID: id,
+		Doc:        doc,
+	}
+
+	meta := map[string]map[string]interface{}{"index": {"_index": item.Index}}
+	if id != "" {
+		meta["index"]["_id"] = id
+	}
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("编码批量索引 meta 失败: %w", err)
+	}
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("编码批量文档失败: %w", err)
+	}
+	entry := bulkEntry[T]{item: item, meta: metaBytes, body: body}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrBulkIndexerClosed
+	}
+	select {
+	case b.queue <- entry:
+		b.added.Add(1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Drain 停止接收新文档，刷新队列中剩余的数据并等待所有 worker 退出
+func (b *BulkIndexer[T]) Drain(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("等待 bulk indexer 刷新超时: %w", ctx.Err())
+	}
+}
+
+// Stats 返回当前统计信息
+func (b *BulkIndexer[T]) Stats() BulkIndexerStats {
+	return BulkIndexerStats{
+		NumAdded:   b.added.Load(),
+		NumFlushed: b.flushed.Load(),
+		NumFailed:  b.failed.Load(),
+		NumBatches: b.batches.Load(),
+	}
+}
+
+func (b *BulkIndexer[T]) worker() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	var (
+		buf   bytes.Buffer
+		items []BulkIndexerItem[T]
+	)
+	flush := func() {
+		if len(items) == 0 {
+			return
+		}
+		b.flush(&buf, items)
+		buf.Reset()
+		items = items[:0]
+	}
+
+	for {
+		select {
+		case entry, ok := <-b.queue:
+			if !ok {
+				flush()
+				return
+			}
+			// 加入当前条目会超过字节阈值时先刷新
+			if len(items) > 0 && buf.Len()+len(entry.meta)+len(entry.body)+2 > b.cfg.FlushBytes {
+				flush()
+			}
+			buf.Write(entry.meta)
+			buf.WriteByte('\n')
+			buf.Write(entry.body)
+			buf.WriteByte('\n')
+			items = append(items, entry.item)
+			if len(items) >= b.cfg.FlushCount || buf.Len() >= b.cfg.FlushBytes {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// flush 发送一批数据并逐条回调结果
+func (b *BulkIndexer[T]) flush(buf *bytes.Buffer, items []BulkIndexerItem[T]) {
+	ctx := context.Background()
+	start := time.Now()
+	payload := buf.Bytes()
+
+	res, err := b.client.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		opts := []func(*esapi.BulkRequest){b.client.es.Bulk.WithContext(ctx)}
+		if b.cfg.Refresh != "" {
+			opts = append(opts, b.client.es.Bulk.WithRefresh(b.cfg.Refresh))
+		}
+		return b.client.es.Bulk(bytes.NewReader(payload), opts...)
+	})
+	b.batches.Add(1)
+	if err != nil {
+		b.failAll(ctx, items, err)
+		return
+	}
+	defer res.Body.Close()
+
+	var r struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error,omitempty"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		b.failAll(ctx, items, fmt.Errorf("解析批量响应失败: %w", err))
+		return
+	}
+
+	for i, item := range items {
+		var itemErr error
+		if i < len(r.Items) {
+			for _, result := range r.Items[i] {
+				if result.Status >= 300 {
+					if result.Error != nil {
+						itemErr = fmt.Errorf("%s: %s", result.Error.Type, result.Error.Reason)
+					} else {
+						itemErr = fmt.Errorf("批量写入失败, status=%d", result.Status)
+					}
+				}
+			}
+		} else if r.Errors {
+			itemErr = errors.New("批量响应缺少对应条目")
+		}
+		if itemErr != nil {
+			b.failed.Add(1)
+			if b.cfg.OnItemFailure != nil {
+				b.cfg.OnItemFailure(ctx, item, itemErr)
+			}
+			continue
+		}
+		b.flushed.Add(1)
+		if b.cfg.OnItemSuccess != nil {
+			b.cfg.OnItemSuccess(ctx, item)
+		}
+	}
+	if b.cfg.OnFlush != nil {
+		b.cfg.OnFlush(ctx, len(items), time.Since(start))
+	}
+}
+
+func (b *BulkIndexer[T]) failAll(ctx context.Context, items []BulkIndexerItem[T], err error) {
+	b.failed.Add(uint64(len(items)))
+	if b.cfg.OnItemFailure == nil {
+		return
+	}
+	for _, item := range items {
+		b.cfg.OnItemFailure(ctx, item, err)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/code-sigs/go-box/pkg/breaker"
+	"github.com/code-sigs/go-box/pkg/utils"
 	"github.com/elastic/go-elasticsearch/v9"
 	"github.com/elastic/go-elasticsearch/v9/esapi"
 	"io"
@@ -21,9 +23,19 @@ type ElasticConfig struct {
 	Username       string       `mapstructure:"username"`       // 用户名
 	Password       string       `mapstructure:"password"`       // 密码
 	Healthcheck    bool         `mapstructure:"healthcheck"`    // 是否启用健康检查
-	RetryOnFailure int          `mapstructure:"retryOnFailure"` // 失败重试次数
+	RetryOnFailure int          `mapstructure:"retryOnFailure"` // 失败重试次数，默认 3；启用 TransportMaxRetries 时不生效
 	Timeout        int64        `mapstructure:"timeout"`        // 超时时间（毫秒）
+	Distribution   string       `mapstructure:"distribution"`   // 集群发行版：elasticsearch（默认）/ opensearch
 	HTTPClient     *http.Client // 可选 HTTP 客户端（用于 TLS/超时/测试）
+
+	RetryBackoffMin       int64 `mapstructure:"retryBackoffMin"`       // 重试退避初始间隔（毫秒），默认 100
+	RetryBackoffMax       int64 `mapstructure:"retryBackoffMax"`       // 重试退避最大间隔（毫秒），默认 5000
+	RetryOnStatus         []int `mapstructure:"retryOnStatus"`         // 可重试的状态码，默认 429/500/502/503/504
+	TransportMaxRetries   int   `mapstructure:"transportMaxRetries"`   // >0 时改由底层 transport 切换节点重试并按节点退避，应用层不再重试；默认不启用
+	DiscoverNodesOnStart  bool  `mapstructure:"discoverNodesOnStart"`  // 启动时嗅探集群节点
+	DiscoverNodesInterval int64 `mapstructure:"discoverNodesInterval"` // 周期性嗅探节点间隔（秒），0 不启用
+
+	Breaker bool `mapstructure:"breaker"` // 启用熔断，参数通过 breaker.Configure("elastic", ...) 调整
 }
 
 // IndexNamer 接口要求实现获取基础索引名的方法
@@ -40,6 +52,9 @@ func YearlyIndexStrategy(base string) string  { return fmt.Sprintf("%s-%d", base
 func MonthlyIndexStrategy(base string) string {
 	return fmt.Sprintf("%s-%s", base, time.Now().Format("2006.01"))
 }
+func DailyIndexStrategy(base string) string {
+	return fmt.Sprintf("%s-%s", base, time.Now().Format("2006.01.02"))
+}
 
 // ElasticClient 是用于处理实现 IndexNamer 接口的文档的 Elasticsearch 客户端
 type ElasticClient[T IndexNamer] struct {
@@ -57,6 +72,8 @@ func NewElasticClient[T IndexNamer](cfg *ElasticConfig) (*ElasticClient[T], erro
 	if cfg.HTTPClient != nil {
 		esCfg.Transport = cfg.HTTPClient.Transport
 	}
+	applyTransportRetry(&esCfg, cfg)
+	applyDistribution(&esCfg, cfg)
 
 	client, err := elasticsearch.NewClient(esCfg)
 	if err != nil {
@@ -77,7 +94,7 @@ func NewElasticClient[T IndexNamer](cfg *ElasticConfig) (*ElasticClient[T], erro
 	return &ElasticClient[T]{es: client, config: cfg}, nil
 }
 
-// 内部辅助函数：执行请求带超时和重试
+// 内部辅助函数：执行请求带超时和重试，仅对网络错误和可重试状态码进行指数退避重试
 func (c *ElasticClient[T]) doRequestWithRetry(ctx context.Context, fn func(ctx context.Context) (*esapi.Response, error)) (*esapi.Response, error) {
 	timeout := c.config.Timeout
 	if timeout <= 0 {
@@ -87,25 +104,69 @@ func (c *ElasticClient[T]) doRequestWithRetry(ctx context.Context, fn func(ctx c
 	if retries <= 0 {
 		retries = 3
 	}
+	if transportRetryEnabled(c.config) {
+		// transport 已切换节点重试，应用层不再重复
+		retries = 1
+	}
 
-	var lastErr error
-	for i := 0; i < retries; i++ {
-		ctxTimeout, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
-		res, err := fn(ctxTimeout)
-		cancel()
-		if err == nil && res != nil && !res.IsError() {
-			return res, nil
+	var res *esapi.Response
+	err := utils.Retry(ctx, retries, c.backoff, func(ctx context.Context) error {
+		if !c.config.Breaker {
+			var err error
+			res, err = c.attempt(ctx, timeout, fn)
+			return err
 		}
-		if err != nil {
-			lastErr = err
+		// 每次尝试单独计入熔断统计，非重试类状态码（如 404、400）不计入失败
+		return breaker.DoWithAcceptable(ctx, breakerName, func(ctx context.Context) error {
+			var err error
+			res, err = c.attempt(ctx, timeout, fn)
+			return err
+		}, func(err error) bool {
+			var se *statusError
+			return errors.As(err, &se) && !c.isRetryableStatus(se.status)
+		})
+	}, utils.RetryIf(func(err error) bool {
+		if errors.Is(err, breaker.ErrOpen) || errors.Is(err, breaker.ErrTooManyRequests) {
+			return false
 		}
-		if res != nil && res.IsError() {
-			b, _ := io.ReadAll(res.Body)
-			lastErr = fmt.Errorf("ES请求错误: %s", string(b))
+		var se *statusError
+		return !errors.As(err, &se) || c.isRetryableStatus(se.status)
+	}))
+	switch {
+	case err == nil:
+		return res, nil
+	case ctx.Err() != nil:
+		return nil, fmt.Errorf("等待重试时取消: %w", err)
+	case errors.Is(err, breaker.ErrOpen), errors.Is(err, breaker.ErrTooManyRequests):
+		return nil, err
+	default:
+		var se *statusError
+		if errors.As(err, &se) && !c.isRetryableStatus(se.status) {
+			return nil, err
 		}
-		time.Sleep(time.Duration(200*(i+1)) * time.Millisecond)
+		return nil, fmt.Errorf("请求失败重试 %d 次仍失败: %w", retries, err)
 	}
-	return nil, fmt.Errorf("请求失败重试 %d 次仍失败: %w", retries, lastErr)
+}
+
+// attempt 执行单次请求，ES 返回错误状态码时转为 *statusError
+func (c *ElasticClient[T]) attempt(ctx context.Context, timeout int64, fn func(ctx context.Context) (*esapi.Response, error)) (*esapi.Response, error) {
+	ctxTimeout, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+	r, err := fn(ctxTimeout)
+	if err == nil && r != nil && !r.IsError() {
+		// 响应体需在调用方读取完成后才能取消 ctx
+		r.Body = &cancelOnClose{ReadCloser: r.Body, cancel: cancel}
+		return r, nil
+	}
+	defer cancel()
+	if r != nil && r.IsError() {
+		b, _ := io.ReadAll(r.Body)
+		r.Body.Close()
+		return nil, &statusError{status: r.StatusCode, body: string(b)}
+	}
+	if err == nil {
+		err = errors.New("ES请求无响应")
+	}
+	return nil, err
 }
 
 // CreateDocument 索引单个文档。id 可为空（由 ES 自动生成）
@@ -123,14 +184,15 @@ func (c *ElasticClient[T]) CreateDocument(ctx context.Context, doc *T, id string
 		return fmt.Errorf("编码文档失败: %w", err)
 	}
 
-	req := esapi.IndexRequest{
-		Index:      index,
-		DocumentID: id,
-		Body:       &buf,
-		Refresh:    "true",
-	}
-
+	data := buf.Bytes()
 	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		// 每次重试重新构造 Body，避免发送已读完的空请求体
+		req := esapi.IndexRequest{
+			Index:      index,
+			DocumentID: id,
+			Body:       bytes.NewReader(data),
+			Refresh:    "true",
+		}
 		return req.Do(ctx, c.es)
 	})
 	if err != nil {
@@ -167,8 +229,9 @@ func (c *ElasticClient[T]) BulkCreateDocuments(ctx context.Context, docs []*T, i
 		}
 	}
 
+	data := buf.Bytes()
 	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
-		return c.es.Bulk(&buf, c.es.Bulk.WithContext(ctx), c.es.Bulk.WithRefresh("true"))
+		return c.es.Bulk(bytes.NewReader(data), c.es.Bulk.WithContext(ctx), c.es.Bulk.WithRefresh("true"))
 	})
 	if err != nil {
 		return err
@@ -207,12 +270,13 @@ func (c *ElasticClient[T]) BulkDeleteDocuments(ctx context.Context, baseIndex st
 		}
 	}
 
-	req := esapi.BulkRequest{
-		Index:   index,
-		Body:    &buf,
-		Refresh: "true",
-	}
+	data := buf.Bytes()
 	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		req := esapi.BulkRequest{
+			Index:   index,
+			Body:    bytes.NewReader(data),
+			Refresh: "true",
+		}
 		return req.Do(ctx, c.es)
 	})
 	if err != nil {
@@ -263,8 +327,39 @@ func (c *ElasticClient[T]) DeleteDocument(ctx context.Context, baseIndex, id str
 	return nil
 }
 
+// Hit 表示带元数据的单条搜索结果
+type Hit[T IndexNamer] struct {
+	ID     string        `json:"_id"`
+	Index  string        `json:"_index"`
+	Score  *float64      `json:"_score"`
+	Sort   []interface{} `json:"sort,omitempty"`
+	Source *T            `json:"_source"`
+}
+
+// SearchOptions 搜索可选参数
+type SearchOptions struct {
+	Indices        []string // 查询的索引，为空时使用 <IndexName>-*
+	SourceIncludes []string // _source 包含字段
+	SourceExcludes []string // _source 排除字段
+	DisableSource  bool     // 不返回 _source，仅返回元数据
+}
+
 // Search 执行搜索请求
 func (c *ElasticClient[T]) Search(ctx context.Context, query map[string]interface{}, indices ...string) ([]*T, int64, error) {
+	hits, total, err := c.SearchHits(ctx, query, SearchOptions{Indices: indices})
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]*T, 0, len(hits))
+	for _, h := range hits {
+		out = append(out, h.Source)
+	}
+	return out, total, nil
+}
+
+// SearchHits 执行搜索请求，返回每条命中的 _id/_index/_score/sort 及文档，支持 _source 过滤
+func (c *ElasticClient[T]) SearchHits(ctx context.Context, query map[string]interface{}, opts SearchOptions) ([]*Hit[T], int64, error) {
+	indices := opts.Indices
 	if len(indices) == 0 {
 		var zero T
 		indices = []string{zero.IndexName() + "-*"}
@@ -273,9 +368,24 @@ func (c *ElasticClient[T]) Search(ctx context.Context, query map[string]interfac
 	if err := json.NewEncoder(&buf).Encode(query); err != nil {
 		return nil, 0, fmt.Errorf("编码查询参数失败: %w", err)
 	}
+	body := buf.Bytes()
 
 	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
-		return c.es.Search(c.es.Search.WithContext(ctx), c.es.Search.WithIndex(indices...), c.es.Search.WithBody(&buf))
+		searchOpts := []func(*esapi.SearchRequest){
+			c.es.Search.WithContext(ctx),
+			c.es.Search.WithIndex(indices...),
+			c.es.Search.WithBody(bytes.NewReader(body)),
+		}
+		if opts.DisableSource {
+			searchOpts = append(searchOpts, c.es.Search.WithSource("false"))
+		}
+		if len(opts.SourceIncludes) > 0 {
+			searchOpts = append(searchOpts, c.es.Search.WithSourceIncludes(opts.SourceIncludes...))
+		}
+		if len(opts.SourceExcludes) > 0 {
+			searchOpts = append(searchOpts, c.es.Search.WithSourceExcludes(opts.SourceExcludes...))
+		}
+		return c.es.Search(searchOpts...)
 	})
 	if err != nil {
 		return nil, 0, err
@@ -287,20 +397,28 @@ func (c *ElasticClient[T]) Search(ctx context.Context, query map[string]interfac
 			Total struct {
 				Value int64 `json:"value"`
 			} `json:"total"`
-			Hits []struct {
-				Source *T `json:"_source"`
-			} `json:"hits"`
+			Hits []*Hit[T] `json:"hits"`
 		} `json:"hits"`
 	}
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
 		return nil, 0, fmt.Errorf("解析搜索结果失败: %w", err)
 	}
+	return result.Hits.Hits, result.Hits.Total.Value, nil
+}
 
-	out := make([]*T, 0, len(result.Hits.Hits))
-	for _, h := range result.Hits.Hits {
-		out = append(out, h.Source)
-	}
-	return out, result.Hits.Total.Value, nil
+// PaginateOptions PaginateSearchWithOptions 的参数
+type PaginateOptions struct {
+	Query      map[string]interface{} // 查询条件，取其中的 "query" 部分
+	SortFields []string               // 格式 []string{"@timestamp:desc", "id:asc"}
+	Size       int                    // 每页条数
+	Cursor     string                 // 上一页返回的游标
+	From       int                    // 跳过的条数，仅在 Cursor 为空时生效，受 index.max_result_window 限制
+	Reverse    bool                   // 反向翻页
+	TimeField  string                 // 时间范围过滤字段，为空时不做时间过滤
+	StartTime  *time.Time             // 时间范围起点（含）
+	EndTime    *time.Time             // 时间范围终点（含）
+	Indices    []string               // 显式指定查询的索引，优先级最高
+	Strategy   IndexStrategy          // 未指定 Indices 时按策略生成索引名，均为空时使用 <IndexName>-*
 }
 
 // SearchPagination 支持 search_after 分页
@@ -314,10 +432,55 @@ func (c *ElasticClient[T]) PaginateSearch(
 	startTime, endTime *time.Time,
 	reverse bool,
 ) ([]*T, string, int64, error) {
+	return c.PaginateSearchWithOptions(ctx, PaginateOptions{
+		Query:      query,
+		SortFields: sortFields,
+		Size:       size,
+		Cursor:     cursor,
+		Reverse:    reverse,
+		TimeField:  "@timestamp",
+		StartTime:  startTime,
+		EndTime:    endTime,
+	})
+}
 
-	// 1. 确定索引模式
+// PaginateSearchPage 按通用分页请求做 from + size 分页，req.Sort 非空时覆盖 opts.SortFields，
+// 深度翻页请使用 PaginateSearchWithOptions 的游标
+func (c *ElasticClient[T]) PaginateSearchPage(ctx context.Context, req utils.PageRequest, opts PaginateOptions) (*utils.PageResponse[*T], error) {
+	req = req.Normalize()
+	if fields := req.SortFields(); len(fields) > 0 {
+		opts.SortFields = make([]string, len(fields))
+		for i, f := range fields {
+			order := "asc"
+			if f.Desc {
+				order = "desc"
+			}
+			opts.SortFields[i] = f.Field + ":" + order
+		}
+	}
+	opts.Size, opts.From, opts.Cursor, opts.Reverse = req.PageSize, req.Offset(), "", false
+	docs, _, total, err := c.PaginateSearchWithOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewPageResponse(req, docs, total), nil
+}
+
+// PaginateSearchWithOptions 支持 search_after 分页，可配置时间字段与索引
+func (c *ElasticClient[T]) PaginateSearchWithOptions(ctx context.Context, opts PaginateOptions) ([]*T, string, int64, error) {
+	query, sortFields, size, cursor := opts.Query, opts.SortFields, opts.Size, opts.Cursor
+	startTime, endTime, reverse := opts.StartTime, opts.EndTime, opts.Reverse
+
+	// 1. 确定索引
 	var zero T
-	baseIndex := zero.IndexName() + "-*"
+	indices := opts.Indices
+	if len(indices) == 0 {
+		if opts.Strategy != nil {
+			indices = []string{opts.Strategy(zero.IndexName())}
+		} else {
+			indices = []string{zero.IndexName() + "-*"}
+		}
+	}
 
 	// 2. 构建查询 DSL
 	if query == nil {
@@ -331,17 +494,18 @@ func (c *ElasticClient[T]) PaginateSearch(
 	}
 
 	// 时间过滤
-	if startTime != nil || endTime != nil {
-		rangeQuery := map[string]interface{}{
-			"range": map[string]interface{}{
-				"@timestamp": map[string]interface{}{},
-			},
-		}
+	if opts.TimeField != "" && (startTime != nil || endTime != nil) {
+		timeRange := map[string]interface{}{}
 		if startTime != nil {
-			rangeQuery["range"].(map[string]interface{})["@timestamp"].(map[string]interface{})["gte"] = startTime.Format(time.RFC3339)
+			timeRange["gte"] = startTime.Format(time.RFC3339)
 		}
 		if endTime != nil {
-			rangeQuery["range"].(map[string]interface{})["@timestamp"].(map[string]interface{})["lte"] = endTime.Format(time.RFC3339)
+			timeRange["lte"] = endTime.Format(time.RFC3339)
+		}
+		rangeQuery := map[string]interface{}{
+			"range": map[string]interface{}{
+				opts.TimeField: timeRange,
+			},
 		}
 		boolQuery["must"] = append(boolQuery["must"].([]interface{}), rangeQuery)
 	}
@@ -353,6 +517,9 @@ func (c *ElasticClient[T]) PaginateSearch(
 		},
 		"size": size,
 	}
+	if opts.From > 0 && cursor == "" {
+		dsl["from"] = opts.From
+	}
 
 	// 4. 排序字段
 	if len(sortFields) > 0 {
@@ -396,12 +563,13 @@ func (c *ElasticClient[T]) PaginateSearch(
 	if err := json.NewEncoder(&buf).Encode(dsl); err != nil {
 		return nil, "", 0, fmt.Errorf("编码查询失败: %w", err)
 	}
+	body := buf.Bytes()
 
 	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
 		return c.es.Search(
 			c.es.Search.WithContext(ctx),
-			c.es.Search.WithIndex(baseIndex),
-			c.es.Search.WithBody(&buf),
+			c.es.Search.WithIndex(indices...),
+			c.es.Search.WithBody(bytes.NewReader(body)),
 		)
 	})
 	if err != nil {
@@ -449,8 +617,8 @@ func (c *ElasticClient[T]) PaginateSearch(
 		nextCursor = base64.URLEncoding.EncodeToString(sortBytes)
 	}
 
-	//logger.Infow(ctx, "elastic", "baseIndex", baseIndex, "dsl", dsl)
-	//logger.Infow(ctx, "elastic", "baseIndex", baseIndex, "raw", raw)
+	//logger.Infow(ctx, "elastic", "indices", indices, "dsl", dsl)
+	//logger.Infow(ctx, "elastic", "indices", indices, "raw", raw)
 
 	return docs, nextCursor, raw.Hits.Total.Value, nil
 }
@@ -0,0 +1,191 @@
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+)
+
+// CreateIndex 创建索引，body 可包含 settings、mappings、aliases，已存在时不报错
+func (c *ElasticClient[T]) CreateIndex(ctx context.Context, name string, body map[string]interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("编码索引定义失败: %w", err)
+	}
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Indices.Create(name,
+			c.es.Indices.Create.WithContext(ctx),
+			c.es.Indices.Create.WithBody(bytes.NewReader(data)),
+		)
+	})
+	if err != nil {
+		if isAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	return res.Body.Close()
+}
+
+// DeleteIndex 删除索引，不存在时不报错
+func (c *ElasticClient[T]) DeleteIndex(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Indices.Delete(names,
+			c.es.Indices.Delete.WithContext(ctx),
+			c.es.Indices.Delete.WithIgnoreUnavailable(true),
+		)
+	})
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// PutMapping 为已有索引追加字段映射，已有字段的类型不能修改，需要修改时使用 Reindex + SwitchAlias
+func (c *ElasticClient[T]) PutMapping(ctx context.Context, index string, mappings map[string]interface{}) error {
+	data, err := json.Marshal(mappings)
+	if err != nil {
+		return fmt.Errorf("编码映射失败: %w", err)
+	}
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Indices.PutMapping([]string{index}, bytes.NewReader(data), c.es.Indices.PutMapping.WithContext(ctx))
+	})
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// AliasIndices 返回别名当前指向的索引，别名不存在时返回空
+func (c *ElasticClient[T]) AliasIndices(ctx context.Context, alias string) ([]string, error) {
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Indices.GetAlias(c.es.Indices.GetAlias.WithContext(ctx), c.es.Indices.GetAlias.WithName(alias))
+	})
+	if err != nil {
+		var se *statusError
+		if errors.As(err, &se) && se.status == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer res.Body.Close()
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("解析别名失败: %w", err)
+	}
+	indices := make([]string, 0, len(body))
+	for index := range body {
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+// SwitchAlias 原子地将别名从当前指向的全部索引切换到 index
+func (c *ElasticClient[T]) SwitchAlias(ctx context.Context, alias, index string) error {
+	current, err := c.AliasIndices(ctx, alias)
+	if err != nil {
+		return err
+	}
+	actions := make([]interface{}, 0, len(current)+1)
+	for _, old := range current {
+		if old != index {
+			actions = append(actions, map[string]interface{}{"remove": map[string]interface{}{"index": old, "alias": alias}})
+		}
+	}
+	actions = append(actions, map[string]interface{}{"add": map[string]interface{}{"index": index, "alias": alias}})
+	data, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return fmt.Errorf("编码别名操作失败: %w", err)
+	}
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Indices.UpdateAliases(bytes.NewReader(data), c.es.Indices.UpdateAliases.WithContext(ctx))
+	})
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// Reindex 将 source 的文档复制到 dest 并等待完成。以后台任务方式执行并轮询，
+// 不受单次请求超时限制，ctx 取消时停止等待但任务仍在集群中运行
+func (c *ElasticClient[T]) Reindex(ctx context.Context, source, dest string) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"source": map[string]interface{}{"index": source},
+		"dest":   map[string]interface{}{"index": dest},
+	})
+	if err != nil {
+		return fmt.Errorf("编码 reindex 请求失败: %w", err)
+	}
+	wait := false
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return esapi.ReindexRequest{Body: bytes.NewReader(data), WaitForCompletion: &wait}.Do(ctx, c.es)
+	})
+	if err != nil {
+		return err
+	}
+	var started struct {
+		Task string `json:"task"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&started)
+	res.Body.Close()
+	if err != nil || started.Task == "" {
+		return fmt.Errorf("解析 reindex 任务失败: %v", err)
+	}
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("等待 reindex 任务 %s 时取消: %w", started.Task, ctx.Err())
+		case <-ticker.C:
+		}
+		done, err := c.taskDone(ctx, started.Task)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// taskDone 查询任务是否完成，任务出错或有写入失败时返回错误
+func (c *ElasticClient[T]) taskDone(ctx context.Context, taskID string) (bool, error) {
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Tasks.Get(taskID, c.es.Tasks.Get.WithContext(ctx))
+	})
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	var task struct {
+		Completed bool            `json:"completed"`
+		Error     json.RawMessage `json:"error"`
+		Response  struct {
+			Failures []json.RawMessage `json:"failures"`
+		} `json:"response"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&task); err != nil {
+		return false, fmt.Errorf("解析任务状态失败: %w", err)
+	}
+	if !task.Completed {
+		return false, nil
+	}
+	if len(task.Error) > 0 {
+		return true, fmt.Errorf("任务 %s 失败: %s", taskID, task.Error)
+	}
+	if len(task.Response.Failures) > 0 {
+		return true, fmt.Errorf("任务 %s 有 %d 条写入失败: %s", taskID, len(task.Response.Failures), task.Response.Failures[0])
+	}
+	return true, nil
+}
@@ -0,0 +1,378 @@
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+)
+
+// LifecyclePolicy 描述一个简化的生命周期策略：hot 阶段滚动，可选 warm，到期删除
+type LifecyclePolicy struct {
+	RolloverMaxAge  string // 滚动条件：最大时长，如 "1d"
+	RolloverMaxSize string // 滚动条件：最大主分片大小，如 "50gb"
+	RolloverMaxDocs int64  // 滚动条件：最大文档数
+	WarmAfter       string // 滚动后多久进入 warm 阶段（只读、合并段），为空不启用
+	DeleteAfter     string // 滚动后多久删除，如 "30d"，为空不删除
+
+	// IndexPatterns OpenSearch 通过策略中的 ism_template 自动关联新建索引，需指定匹配的索引或 data stream；
+	// Elasticsearch 通过索引模板的 index.lifecycle.name 关联，忽略该字段
+	IndexPatterns []string
+}
+
+// IndexTemplate 描述可组合索引模板
+type IndexTemplate struct {
+	IndexPatterns []string               // 匹配的索引模式
+	DataStream    bool                   // 是否为 data stream 模板
+	PolicyName    string                 // 关联的 ILM 策略，OpenSearch 改用 LifecyclePolicy.IndexPatterns 关联
+	RolloverAlias string                 // 非 data stream 模式下的滚动别名
+	Shards        int                    // 主分片数，0 使用集群默认
+	Replicas      *int                   // 副本数，nil 使用集群默认
+	Mappings      map[string]interface{} // 映射定义
+	Priority      int                    // 模板优先级
+}
+
+// PutLifecyclePolicy 创建或更新生命周期策略（Elasticsearch 为 ILM，OpenSearch 为 ISM）
+func (c *ElasticClient[T]) PutLifecyclePolicy(ctx context.Context, name string, policy LifecyclePolicy) error {
+	if name == "" {
+		return errors.New("策略名不能为空")
+	}
+	if c.config.IsOpenSearch() {
+		return c.putISMPolicy(ctx, name, policy)
+	}
+
+	rollover := map[string]interface{}{}
+	if policy.RolloverMaxAge != "" {
+		rollover["max_age"] = policy.RolloverMaxAge
+	}
+	if policy.RolloverMaxSize != "" {
+		rollover["max_primary_shard_size"] = policy.RolloverMaxSize
+	}
+	if policy.RolloverMaxDocs > 0 {
+		rollover["max_docs"] = policy.RolloverMaxDocs
+	}
+	phases := map[string]interface{}{
+		"hot": map[string]interface{}{
+			"actions": map[string]interface{}{"rollover": rollover},
+		},
+	}
+	if policy.WarmAfter != "" {
+		phases["warm"] = map[string]interface{}{
+			"min_age": policy.WarmAfter,
+			"actions": map[string]interface{}{
+				"readonly":   map[string]interface{}{},
+				"forcemerge": map[string]interface{}{"max_num_segments": 1},
+			},
+		}
+	}
+	if policy.DeleteAfter != "" {
+		phases["delete"] = map[string]interface{}{
+			"min_age": policy.DeleteAfter,
+			"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+		}
+	}
+	body := map[string]interface{}{"policy": map[string]interface{}{"phases": phases}}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("编码生命周期策略失败: %w", err)
+	}
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.ILM.PutLifecycle(bytes.NewReader(data), name, c.es.ILM.PutLifecycle.WithContext(ctx))
+	})
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// putISMPolicy 以 OpenSearch ISM 格式写入策略；策略已存在时带 if_seq_no / if_primary_term 更新，
+// 否则 OpenSearch 返回 409，重复调用无法成功
+func (c *ElasticClient[T]) putISMPolicy(ctx context.Context, name string, policy LifecyclePolicy) error {
+	rollover := map[string]interface{}{}
+	if policy.RolloverMaxAge != "" {
+		rollover["min_index_age"] = policy.RolloverMaxAge
+	}
+	if policy.RolloverMaxSize != "" {
+		rollover["min_primary_shard_size"] = policy.RolloverMaxSize
+	}
+	if policy.RolloverMaxDocs > 0 {
+		rollover["min_doc_count"] = policy.RolloverMaxDocs
+	}
+	// 状态按 hot -> warm -> delete 顺序排列，每个状态转移到下一个已启用的状态
+	states := []map[string]interface{}{{
+		"name":    "hot",
+		"actions": []interface{}{map[string]interface{}{"rollover": rollover}},
+	}}
+	var ages []string
+	if policy.WarmAfter != "" {
+		states = append(states, map[string]interface{}{
+			"name": "warm",
+			"actions": []interface{}{
+				map[string]interface{}{"read_only": map[string]interface{}{}},
+				map[string]interface{}{"force_merge": map[string]interface{}{"max_num_segments": 1}},
+			},
+		})
+		ages = append(ages, policy.WarmAfter)
+	}
+	if policy.DeleteAfter != "" {
+		states = append(states, map[string]interface{}{
+			"name":    "delete",
+			"actions": []interface{}{map[string]interface{}{"delete": map[string]interface{}{}}},
+		})
+		ages = append(ages, policy.DeleteAfter)
+	}
+	// 与 ILM 的 min_age 一致，按滚动后的时间转移而不是索引创建时间
+	for i, state := range states {
+		transitions := []interface{}{}
+		if i+1 < len(states) {
+			transitions = append(transitions, map[string]interface{}{
+				"state_name": states[i+1]["name"],
+				"conditions": map[string]interface{}{"min_rollover_age": ages[i]},
+			})
+		}
+		state["transitions"] = transitions
+	}
+	body := map[string]interface{}{
+		"description":   name,
+		"default_state": "hot",
+		"states":        states,
+	}
+	if len(policy.IndexPatterns) > 0 {
+		body["ism_template"] = []interface{}{map[string]interface{}{
+			"index_patterns": policy.IndexPatterns,
+			"priority":       100,
+		}}
+	}
+	data, err := json.Marshal(map[string]interface{}{"policy": body})
+	if err != nil {
+		return fmt.Errorf("编码生命周期策略失败: %w", err)
+	}
+
+	path := "/_plugins/_ism/policies/" + name
+	seqNo, primaryTerm, found, err := c.getISMPolicyVersion(ctx, path)
+	if err != nil {
+		return err
+	}
+	if found {
+		path += fmt.Sprintf("?if_seq_no=%d&if_primary_term=%d", seqNo, primaryTerm)
+	}
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.performRaw(ctx, http.MethodPut, path, data)
+	})
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// getISMPolicyVersion 读取已有策略的 _seq_no 与 _primary_term，策略不存在时 found 为 false
+func (c *ElasticClient[T]) getISMPolicyVersion(ctx context.Context, path string) (seqNo, primaryTerm int64, found bool, err error) {
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.performRaw(ctx, http.MethodGet, path, nil)
+	})
+	if err != nil {
+		var se *statusError
+		if errors.As(err, &se) && se.status == http.StatusNotFound {
+			return 0, 0, false, nil
+		}
+		return 0, 0, false, err
+	}
+	defer res.Body.Close()
+	var version struct {
+		SeqNo       int64 `json:"_seq_no"`
+		PrimaryTerm int64 `json:"_primary_term"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&version); err != nil {
+		return 0, 0, false, fmt.Errorf("解析生命周期策略失败: %w", err)
+	}
+	return version.SeqNo, version.PrimaryTerm, true, nil
+}
+
+// PutIndexTemplate 创建或更新可组合索引模板
+func (c *ElasticClient[T]) PutIndexTemplate(ctx context.Context, name string, tpl IndexTemplate) error {
+	if name == "" || len(tpl.IndexPatterns) == 0 {
+		return errors.New("模板名和索引模式不能为空")
+	}
+	settings := map[string]interface{}{}
+	if tpl.Shards > 0 {
+		settings["number_of_shards"] = tpl.Shards
+	}
+	if tpl.Replicas != nil {
+		settings["number_of_replicas"] = *tpl.Replicas
+	}
+	if tpl.PolicyName != "" {
+		if c.config.IsOpenSearch() {
+			// ISM 不支持在模板中指定策略，由策略的 ism_template（LifecyclePolicy.IndexPatterns）关联
+			if tpl.RolloverAlias != "" {
+				settings["plugins.index_state_management.rollover_alias"] = tpl.RolloverAlias
+			}
+		} else {
+			settings["index.lifecycle.name"] = tpl.PolicyName
+			if tpl.RolloverAlias != "" {
+				settings["index.lifecycle.rollover_alias"] = tpl.RolloverAlias
+			}
+		}
+	}
+	template := map[string]interface{}{"settings": settings}
+	if len(tpl.Mappings) > 0 {
+		template["mappings"] = tpl.Mappings
+	}
+	body := map[string]interface{}{
+		"index_patterns": tpl.IndexPatterns,
+		"template":       template,
+		"priority":       tpl.Priority,
+	}
+	if tpl.DataStream {
+		body["data_stream"] = map[string]interface{}{}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("编码索引模板失败: %w", err)
+	}
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Indices.PutIndexTemplate(name, bytes.NewReader(data), c.es.Indices.PutIndexTemplate.WithContext(ctx))
+	})
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// CreateDataStream 创建 data stream，已存在时不报错
+func (c *ElasticClient[T]) CreateDataStream(ctx context.Context, name string) error {
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Indices.CreateDataStream(name, c.es.Indices.CreateDataStream.WithContext(ctx))
+	})
+	if err != nil {
+		if isAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	return res.Body.Close()
+}
+
+// DeleteDataStream 删除 data stream 及其所有后备索引
+func (c *ElasticClient[T]) DeleteDataStream(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Indices.DeleteDataStream(names, c.es.Indices.DeleteDataStream.WithContext(ctx))
+	})
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// EnsureDataStream 一站式初始化：写入策略、模板（<name>-template，匹配 <name>*）并创建 data stream。
+// 文档类型 T 的 IndexName() 应返回 data stream 名称，写入时使用 AppendDocument 或 DataStreamIndexStrategy。
+func (c *ElasticClient[T]) EnsureDataStream(ctx context.Context, name string, policy LifecyclePolicy, mappings map[string]interface{}) error {
+	policyName := name + "-policy"
+	if len(policy.IndexPatterns) == 0 {
+		policy.IndexPatterns = []string{name + "*"}
+	}
+	if err := c.PutLifecyclePolicy(ctx, policyName, policy); err != nil {
+		return fmt.Errorf("写入生命周期策略失败: %w", err)
+	}
+	if err := c.PutIndexTemplate(ctx, name+"-template", IndexTemplate{
+		IndexPatterns: []string{name + "*"},
+		DataStream:    true,
+		PolicyName:    policyName,
+		Mappings:      mappings,
+		Priority:      200,
+	}); err != nil {
+		return fmt.Errorf("写入索引模板失败: %w", err)
+	}
+	return c.CreateDataStream(ctx, name)
+}
+
+// BootstrapRolloverIndex 为别名滚动模式创建首个索引 <alias>-000001 并设置为写索引
+func (c *ElasticClient[T]) BootstrapRolloverIndex(ctx context.Context, alias string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"aliases": map[string]interface{}{
+			alias: map[string]interface{}{"is_write_index": true},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("编码索引定义失败: %w", err)
+	}
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Indices.Create(alias+"-000001",
+			c.es.Indices.Create.WithContext(ctx),
+			c.es.Indices.Create.WithBody(bytes.NewReader(body)),
+		)
+	})
+	if err != nil {
+		if isAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	return res.Body.Close()
+}
+
+// Rollover 手动触发别名或 data stream 滚动
+func (c *ElasticClient[T]) Rollover(ctx context.Context, alias string) error {
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		return c.es.Indices.Rollover(alias, c.es.Indices.Rollover.WithContext(ctx))
+	})
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// DataStreamIndexStrategy 直接写入 data stream / 滚动别名，不做时间后缀
+func DataStreamIndexStrategy(base string) string { return base }
+
+// AppendDocument 以 op_type=create 方式追加文档，适用于 data stream
+func (c *ElasticClient[T]) AppendDocument(ctx context.Context, doc *T) error {
+	if doc == nil {
+		return errors.New("文档为空")
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(doc); err != nil {
+		return fmt.Errorf("编码文档失败: %w", err)
+	}
+	data := buf.Bytes()
+	index := (*doc).IndexName()
+	res, err := c.doRequestWithRetry(ctx, func(ctx context.Context) (*esapi.Response, error) {
+		req := esapi.IndexRequest{
+			Index:  index,
+			Body:   bytes.NewReader(data),
+			OpType: "create",
+		}
+		return req.Do(ctx, c.es)
+	})
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// performRaw 发送 esapi 未覆盖的请求（如 OpenSearch 插件接口）
+func (c *ElasticClient[T]) performRaw(ctx context.Context, method, path string, body []byte) (*esapi.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := c.es.Perform(req)
+	if err != nil {
+		return nil, err
+	}
+	return &esapi.Response{StatusCode: res.StatusCode, Header: res.Header, Body: res.Body}, nil
+}
+
+func isAlreadyExists(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "already_exists")
+}
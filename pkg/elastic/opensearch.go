@@ -0,0 +1,54 @@
+package elastic
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v9"
+)
+
+// 集群发行版
+const (
+	DistributionElasticsearch = "elasticsearch"
+	DistributionOpenSearch    = "opensearch"
+)
+
+// IsOpenSearch 判断配置是否指向 OpenSearch 集群
+func (cfg *ElasticConfig) IsOpenSearch() bool {
+	return strings.EqualFold(cfg.Distribution, DistributionOpenSearch)
+}
+
+// applyDistribution 根据发行版调整底层客户端配置。
+// v9 客户端会校验响应头 X-Elastic-Product，OpenSearch 不返回该头导致请求被拒绝，
+// 这里通过包装 Transport 补齐该头，并关闭 OpenSearch 不识别的兼容模式与 meta 头。
+func applyDistribution(esCfg *elasticsearch.Config, cfg *ElasticConfig) {
+	if !cfg.IsOpenSearch() {
+		return
+	}
+	base := esCfg.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	esCfg.Transport = &openSearchTransport{base: base}
+	esCfg.EnableCompatibilityMode = false
+	esCfg.DisableMetaHeader = true
+}
+
+// openSearchTransport 为 OpenSearch 响应补齐产品校验头
+type openSearchTransport struct {
+	base http.RoundTripper
+}
+
+func (t *openSearchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err != nil || res == nil {
+		return res, err
+	}
+	if res.Header == nil {
+		res.Header = make(http.Header)
+	}
+	if res.Header.Get("X-Elastic-Product") == "" {
+		res.Header.Set("X-Elastic-Product", "Elasticsearch")
+	}
+	return res, nil
+}
@@ -0,0 +1,101 @@
+package elastic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/utils"
+	"github.com/elastic/go-elasticsearch/v9"
+)
+
+// defaultRetryOnStatus 默认可重试的状态码
+var defaultRetryOnStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// applyTransportRetry 将重试和节点嗅探配置透传给底层 elastictransport。
+// 重试只在一层进行：TransportMaxRetries > 0 时由 transport 切换节点重试，应用层只请求一次；
+// 否则关闭 transport 重试，由 doRequestWithRetry 按 RetryOnFailure 重试，避免重试次数相乘
+func applyTransportRetry(esCfg *elasticsearch.Config, cfg *ElasticConfig) {
+	if transportRetryEnabled(cfg) {
+		esCfg.MaxRetries = cfg.TransportMaxRetries
+		esCfg.RetryOnStatus = retryOnStatus(cfg)
+		esCfg.RetryBackoff = func(attempt int) time.Duration {
+			return backoffWithJitter(cfg, attempt)
+		}
+	} else {
+		esCfg.DisableRetry = true
+	}
+	esCfg.DiscoverNodesOnStart = cfg.DiscoverNodesOnStart
+	if cfg.DiscoverNodesInterval > 0 {
+		esCfg.DiscoverNodesInterval = time.Duration(cfg.DiscoverNodesInterval) * time.Second
+	}
+}
+
+func transportRetryEnabled(cfg *ElasticConfig) bool {
+	return cfg.TransportMaxRetries > 0
+}
+
+func retryOnStatus(cfg *ElasticConfig) []int {
+	if len(cfg.RetryOnStatus) > 0 {
+		return cfg.RetryOnStatus
+	}
+	return defaultRetryOnStatus
+}
+
+// isRetryableStatus 判断状态码是否允许重试
+func (c *ElasticClient[T]) isRetryableStatus(status int) bool {
+	for _, s := range retryOnStatus(c.config) {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff 返回第 attempt 次重试前的等待时间
+func (c *ElasticClient[T]) backoff(attempt int) time.Duration {
+	return backoffWithJitter(c.config, attempt)
+}
+
+// backoffWithJitter 指数退避 + 全抖动：在 [0, min(max, base*2^(attempt-1))] 内随机
+func backoffWithJitter(cfg *ElasticConfig, attempt int) time.Duration {
+	base := time.Duration(cfg.RetryBackoffMin) * time.Millisecond
+	limit := time.Duration(cfg.RetryBackoffMax) * time.Millisecond
+	if limit <= 0 {
+		limit = 5 * time.Second
+	}
+	return utils.ExponentialBackoff(base, limit)(attempt)
+}
+
+// breakerName ES 熔断器在 breaker.Default 中的名称
+const breakerName = "elastic"
+
+// statusError 可按状态码判断是否重试的 ES 响应错误
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("ES请求错误[%d]: %s", e.status, e.body)
+}
+
+// cancelOnClose 在响应体关闭时释放请求的超时 context
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.cancel()
+	return err
+}
@@ -9,3 +9,13 @@ const (
 	ErrorPassword     = 500006 //密码错误
 	ErrorInvalidToken = 500007 //无效token
 )
+
+func init() {
+	Register(ErrorInternal, "Internal", "系统异常", 0)
+	Register(ErrorArgs, "Args", "参数错误", 0)
+	Register(ErrorNotFound, "NotFound", "记录不存在", 0)
+	Register(ErrorNoPermission, "NoPermission", "无操作权限", 0)
+	Register(ErrorNoUser, "NoUser", "用户不存在", 0)
+	Register(ErrorPassword, "Password", "密码错误", 0)
+	Register(ErrorInvalidToken, "InvalidToken", "无效token", 0)
+}
@@ -0,0 +1,62 @@
+package errs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// CodeInfo 已注册错误码的说明
+type CodeInfo struct {
+	Code        int    `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	HTTPStatus  int    `json:"httpStatus,omitempty"` // 0 表示不指定，响应沿用 200
+}
+
+var registry = struct {
+	sync.RWMutex
+	codes map[int]CodeInfo
+}{codes: make(map[int]CodeInfo)}
+
+// Register 注册错误码，通常在 init 中调用，code 重复时 panic 以便启动阶段发现冲突
+func Register(code int, name, description string, httpStatus int) {
+	registry.Lock()
+	defer registry.Unlock()
+	if old, ok := registry.codes[code]; ok {
+		panic(fmt.Sprintf("errs: duplicate error code %d: %s conflicts with %s", code, name, old.Name))
+	}
+	registry.codes[code] = CodeInfo{Code: code, Name: name, Description: description, HTTPStatus: httpStatus}
+}
+
+// Lookup 查询已注册的错误码
+func Lookup(code int) (CodeInfo, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	info, ok := registry.codes[code]
+	return info, ok
+}
+
+// Codes 返回所有已注册的错误码，按 code 升序
+func Codes() []CodeInfo {
+	registry.RLock()
+	out := make([]CodeInfo, 0, len(registry.codes))
+	for _, info := range registry.codes {
+		out = append(out, info)
+	}
+	registry.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+// CatalogHandler 以 JSON 输出错误码目录，供前端和多语言文案维护使用
+func CatalogHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(Codes())
+	})
+}
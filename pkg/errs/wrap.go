@@ -15,6 +15,7 @@ type WrapError struct {
 	file  string
 	line  int
 	cause error
+	stack []uintptr // 仅在错误源头（New 或首次 Wrap 非 WrapError）记录
 }
 
 // New 创建新错误，不包含 cause 和 code
@@ -25,9 +26,10 @@ func New(msg string) error {
 		line = 0
 	}
 	return &WrapError{
-		msg:  msg,
-		file: shortPath(file, 3),
-		line: line,
+		msg:   msg,
+		file:  shortPath(file, 3),
+		line:  line,
+		stack: callers(),
 	}
 }
 
@@ -48,12 +50,16 @@ func Wrap(err error, msgs ...string) error {
 	if len(msgs) > 0 {
 		msg = msgs[0]
 	}
-	return &WrapError{
+	w := &WrapError{
 		msg:   msg,
 		file:  shortPath(file, 3),
 		line:  line,
 		cause: err,
 	}
+	if origin(err) == nil {
+		w.stack = callers()
+	}
+	return w
 }
 
 // WithCode 为错误设置 code
@@ -71,6 +77,7 @@ func WithCode(err error, code int) error {
 			file:  "unknown",
 			line:  0,
 			cause: err,
+			stack: callers(),
 		}
 	}
 	w.code = code
@@ -92,6 +99,16 @@ func (e *WrapError) Code() int {
 	return e.code
 }
 
+// Message 返回本层错误描述，不含位置和 code
+func (e *WrapError) Message() string {
+	return e.msg
+}
+
+// Location 返回创建本层错误的位置，格式为 file:line
+func (e *WrapError) Location() string {
+	return fmt.Sprintf("%s:%d", e.file, e.line)
+}
+
 // Format 实现 %+v 打印完整错误链（避免末尾多余箭头）
 func (e *WrapError) Format(s fmt.State, verb rune) {
 	switch verb {
@@ -119,6 +136,12 @@ func (e *WrapError) Format(s fmt.State, verb rune) {
 					break
 				}
 			}
+			if frames := e.Frames(); len(frames) > 0 {
+				fmt.Fprint(s, "\nstack:")
+				for _, f := range frames {
+					fmt.Fprintf(s, "\n\t%s\n\t\t%s:%d", f.Function, f.File, f.Line)
+				}
+			}
 			return
 		}
 		fallthrough
@@ -129,6 +152,42 @@ func (e *WrapError) Format(s fmt.State, verb rune) {
 	}
 }
 
+// Frames 返回错误源头记录的完整调用栈
+func (e *WrapError) Frames() []runtime.Frame {
+	o := origin(e)
+	if o == nil {
+		return nil
+	}
+	frames := runtime.CallersFrames(o.stack)
+	out := make([]runtime.Frame, 0, len(o.stack))
+	for {
+		f, more := frames.Next()
+		out = append(out, f)
+		if !more {
+			return out
+		}
+	}
+}
+
+// origin 返回错误链中记录了调用栈的 WrapError
+func origin(err error) *WrapError {
+	for err != nil {
+		if we, ok := err.(*WrapError); ok && len(we.stack) > 0 {
+			return we
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
+
+// callers 记录调用 New / Wrap / WithCode 处的调用栈
+func callers() []uintptr {
+	var pcs [32]uintptr
+	// 跳过 runtime.Callers、callers 以及 New / Wrap / WithCode 本身
+	n := runtime.Callers(3, pcs[:])
+	return pcs[:n]
+}
+
 // shortPath 取文件路径最后 n 级目录
 func shortPath(path string, n int) string {
 	parts := strings.Split(filepath.ToSlash(path), "/")
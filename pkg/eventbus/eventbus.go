@@ -0,0 +1,141 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/trace"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// HeaderTraceID 事件头中的链路 ID
+const HeaderTraceID = "x-trace-id"
+
+// Message 后端传输的原始事件
+type Message struct {
+	Topic   string
+	Key     string
+	Headers map[string]string
+	Value   []byte
+}
+
+// Handler 后端回调的原始事件处理函数
+type Handler func(ctx context.Context, msg *Message) error
+
+// Subscription 订阅句柄
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Backend 事件总线后端，生产环境使用 Kafka，测试和本地开发使用内存实现
+type Backend interface {
+	Publish(ctx context.Context, msg *Message) error
+	Subscribe(topic, group string, handler Handler) (Subscription, error)
+	Close() error
+}
+
+// RetryPolicy 订阅者处理失败时的重试策略
+type RetryPolicy struct {
+	MaxAttempts int           // 最多执行次数（含首次），默认 1
+	Backoff     time.Duration // 初始重试间隔，按指数增长，默认 100ms
+	MaxBackoff  time.Duration // 最大重试间隔，默认 10s
+}
+
+// Options 事件总线配置
+type Options struct {
+	Retry RetryPolicy
+}
+
+// Bus 事件总线，业务代码只依赖 Publish / Subscribe，不直接接触具体消息队列
+type Bus struct {
+	backend Backend
+	opts    Options
+}
+
+// New 创建事件总线
+func New(backend Backend, opts Options) *Bus {
+	if opts.Retry.MaxAttempts <= 0 {
+		opts.Retry.MaxAttempts = 1
+	}
+	if opts.Retry.Backoff <= 0 {
+		opts.Retry.Backoff = 100 * time.Millisecond
+	}
+	if opts.Retry.MaxBackoff <= 0 {
+		opts.Retry.MaxBackoff = 10 * time.Second
+	}
+	return &Bus{backend: backend, opts: opts}
+}
+
+// Close 关闭后端
+func (b *Bus) Close() error {
+	return b.backend.Close()
+}
+
+// PublishOption 发布选项
+type PublishOption func(msg *Message)
+
+// WithKey 设置事件 key，相同 key 的事件按顺序投递
+func WithKey(key string) PublishOption {
+	return func(msg *Message) {
+		msg.Key = key
+	}
+}
+
+// WithHeader 追加事件头
+func WithHeader(key, value string) PublishOption {
+	return func(msg *Message) {
+		msg.Headers[key] = value
+	}
+}
+
+// Publish 发布事件，ctx 中的链路信息（traceparent、x-trace-id）会随事件传递给订阅者
+func Publish[T any](ctx context.Context, b *Bus, topic string, event *T, opts ...PublishOption) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("eventbus: encode event: %w", err)
+	}
+	msg := &Message{
+		Topic:   topic,
+		Headers: make(map[string]string),
+		Value:   value,
+	}
+	trace.Inject(ctx, propagation.MapCarrier(msg.Headers))
+	for _, opt := range opts {
+		opt(msg)
+	}
+	return b.backend.Publish(ctx, msg)
+}
+
+// Subscribe 订阅事件，同一 group 内的订阅者分摊事件，不同 group 各自收到全部事件
+func Subscribe[T any](b *Bus, topic, group string, handler func(context.Context, *T) error) (Subscription, error) {
+	return b.backend.Subscribe(topic, group, func(ctx context.Context, msg *Message) error {
+		ctx = trace.Extract(ctx, propagation.MapCarrier(msg.Headers))
+		event := new(T)
+		if err := json.Unmarshal(msg.Value, event); err != nil {
+			return fmt.Errorf("eventbus: decode event: %w", err)
+		}
+		return b.retry(ctx, func() error { return handler(ctx, event) })
+	})
+}
+
+// retry 按重试策略执行 fn
+func (b *Bus) retry(ctx context.Context, fn func() error) error {
+	policy := b.opts.Retry
+	backoff := policy.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || attempt >= policy.MaxAttempts {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		if backoff *= 2; backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
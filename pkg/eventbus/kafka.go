@@ -0,0 +1,120 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/code-sigs/go-box/pkg/kafka"
+)
+
+// KafkaBackend 基于 pkg/kafka 的事件总线后端，消费端的死信、失败策略等沿用 kafka.ConsumerOptions
+type KafkaBackend struct {
+	client *kafka.Kafka[[]byte]
+	opts   kafka.ConsumerOptions
+
+	mu        sync.Mutex
+	producers map[string]*kafka.Producer[[]byte]
+	consumers map[*kafka.Consumer[[]byte]]struct{}
+}
+
+// NewKafkaBackend 创建 Kafka 后端
+func NewKafkaBackend(cfg *kafka.Config, opts kafka.ConsumerOptions) (*KafkaBackend, error) {
+	client, err := kafka.New[[]byte](cfg)
+	if err != nil {
+		return nil, err
+	}
+	client.WithCodec(kafka.RawCodec{})
+	return &KafkaBackend{
+		client:    client,
+		opts:      opts,
+		producers: make(map[string]*kafka.Producer[[]byte]),
+		consumers: make(map[*kafka.Consumer[[]byte]]struct{}),
+	}, nil
+}
+
+// Publish 发送事件，traceID 由 pkg/kafka 写入消息头
+func (b *KafkaBackend) Publish(ctx context.Context, msg *Message) error {
+	producer, err := b.producer(msg.Topic)
+	if err != nil {
+		return err
+	}
+	opts := []kafka.SendOption{kafka.WithHeaders(msg.Headers)}
+	if msg.Key != "" {
+		opts = append(opts, kafka.WithKey(msg.Key))
+	}
+	return producer.SendContext(ctx, &msg.Value, opts...)
+}
+
+// Subscribe 以消费组方式订阅 topic 并在后台消费
+func (b *KafkaBackend) Subscribe(topic, group string, handler Handler) (Subscription, error) {
+	consumer, err := b.client.NewConsumerGroup(topic, group, func(ctx context.Context, value *[]byte) error {
+		msg := &Message{Topic: topic, Headers: make(map[string]string), Value: *value}
+		// 与内存后端一致，handler 可读到发送时的 key 与 WithHeader 写入的消息头
+		if raw, ok := kafka.MessageFromContext(ctx); ok {
+			msg.Key = string(raw.Key)
+			for _, h := range raw.Headers {
+				if h != nil {
+					msg.Headers[string(h.Key)] = string(h.Value)
+				}
+			}
+		}
+		return handler(ctx, msg)
+	}, b.opts)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.consumers[consumer] = struct{}{}
+	b.mu.Unlock()
+	go consumer.Run(context.Background())
+	return &kafkaSubscription{backend: b, consumer: consumer}, nil
+}
+
+// Close 关闭全部生产者和消费者
+func (b *KafkaBackend) Close() error {
+	b.mu.Lock()
+	producers, consumers := b.producers, b.consumers
+	b.producers = make(map[string]*kafka.Producer[[]byte])
+	b.consumers = make(map[*kafka.Consumer[[]byte]]struct{})
+	b.mu.Unlock()
+
+	var firstErr error
+	for consumer := range consumers {
+		if err := consumer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, producer := range producers {
+		if err := producer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (b *KafkaBackend) producer(topic string) (*kafka.Producer[[]byte], error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p, ok := b.producers[topic]; ok {
+		return p, nil
+	}
+	p, err := b.client.NewProducer(topic)
+	if err != nil {
+		return nil, fmt.Errorf("eventbus: create kafka producer: %w", err)
+	}
+	b.producers[topic] = p
+	return p, nil
+}
+
+type kafkaSubscription struct {
+	backend  *KafkaBackend
+	consumer *kafka.Consumer[[]byte]
+}
+
+func (s *kafkaSubscription) Unsubscribe() error {
+	s.backend.mu.Lock()
+	delete(s.backend.consumers, s.consumer)
+	s.backend.mu.Unlock()
+	return s.consumer.Close()
+}
@@ -0,0 +1,179 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+)
+
+// ErrClosed 事件总线已关闭
+var ErrClosed = errors.New("eventbus: closed")
+
+// MemoryBackend 进程内事件总线，适用于单元测试和本地开发，事件不持久化
+type MemoryBackend struct {
+	bufferSize int
+
+	mu     sync.RWMutex
+	groups map[string]map[string]*memoryGroup // topic -> group
+	closed bool
+	wg     sync.WaitGroup
+}
+
+type memoryGroup struct {
+	mu   sync.Mutex
+	subs []*memorySub
+	next int
+}
+
+type memorySub struct {
+	backend *MemoryBackend
+	topic   string
+	group   string
+	ch      chan *Message
+	handler Handler
+	once    sync.Once
+	stop    chan struct{}
+}
+
+// NewMemoryBackend 创建内存后端，bufferSize 为每个订阅者的缓冲事件数，默认 1024
+func NewMemoryBackend(bufferSize int) *MemoryBackend {
+	if bufferSize <= 0 {
+		bufferSize = 1024
+	}
+	return &MemoryBackend{
+		bufferSize: bufferSize,
+		groups:     make(map[string]map[string]*memoryGroup),
+	}
+}
+
+// Publish 将事件投递给 topic 下每个 group 中的一个订阅者，缓冲区满时阻塞
+func (m *MemoryBackend) Publish(ctx context.Context, msg *Message) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return ErrClosed
+	}
+	for _, g := range m.groups[msg.Topic] {
+		sub := g.pick()
+		if sub == nil {
+			continue
+		}
+		copied := *msg
+		select {
+		case sub.ch <- &copied:
+		case <-sub.stop:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Subscribe 注册订阅者，事件在独立协程中按顺序处理
+func (m *MemoryBackend) Subscribe(topic, group string, handler Handler) (Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+	groups, ok := m.groups[topic]
+	if !ok {
+		groups = make(map[string]*memoryGroup)
+		m.groups[topic] = groups
+	}
+	g, ok := groups[group]
+	if !ok {
+		g = &memoryGroup{}
+		groups[group] = g
+	}
+	sub := &memorySub{
+		backend: m,
+		topic:   topic,
+		group:   group,
+		ch:      make(chan *Message, m.bufferSize),
+		handler: handler,
+		stop:    make(chan struct{}),
+	}
+	g.mu.Lock()
+	g.subs = append(g.subs, sub)
+	g.mu.Unlock()
+
+	m.wg.Add(1)
+	go sub.loop()
+	return sub, nil
+}
+
+// Close 停止全部订阅者，已缓冲的事件会处理完再返回
+func (m *MemoryBackend) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	var subs []*memorySub
+	for _, groups := range m.groups {
+		for _, g := range groups {
+			g.mu.Lock()
+			subs = append(subs, g.subs...)
+			g.subs = nil
+			g.mu.Unlock()
+		}
+	}
+	m.mu.Unlock()
+	for _, sub := range subs {
+		sub.once.Do(func() { close(sub.ch) })
+	}
+	m.wg.Wait()
+	return nil
+}
+
+// pick 轮询选择组内订阅者
+func (g *memoryGroup) pick() *memorySub {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.subs) == 0 {
+		return nil
+	}
+	sub := g.subs[g.next%len(g.subs)]
+	g.next++
+	return sub
+}
+
+func (s *memorySub) loop() {
+	defer s.backend.wg.Done()
+	for {
+		select {
+		case msg, ok := <-s.ch:
+			if !ok {
+				return
+			}
+			if err := s.handler(context.Background(), msg); err != nil {
+				logger.Errorw(context.Background(), "eventbus handler failed", "topic", msg.Topic, "group", s.group, "error", err.Error())
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Unsubscribe 取消订阅，未处理的缓冲事件会被丢弃
+func (s *memorySub) Unsubscribe() error {
+	s.backend.mu.RLock()
+	g := s.backend.groups[s.topic][s.group]
+	s.backend.mu.RUnlock()
+	if g != nil {
+		g.mu.Lock()
+		for i, sub := range g.subs {
+			if sub == s {
+				g.subs = append(g.subs[:i], g.subs[i+1:]...)
+				break
+			}
+		}
+		g.mu.Unlock()
+	}
+	s.once.Do(func() { close(s.stop) })
+	return nil
+}
@@ -2,13 +2,19 @@ package rpc
 
 import (
 	"context"
+	"errors"
 
+	"github.com/code-sigs/go-box/pkg/breaker"
+	"github.com/code-sigs/go-box/pkg/rpcerror"
 	"github.com/code-sigs/go-box/pkg/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
-// RPCClientInterceptor 将 http.Header 注入到 gRPC metadata
+// RPCClientInterceptor 将 http.Header 注入到 gRPC metadata，并为调用创建客户端 span
 func RPCClientInterceptor(proxyHeader []string) grpc.UnaryClientInterceptor {
 	return func(
 		ctx context.Context,
@@ -19,6 +25,7 @@ func RPCClientInterceptor(proxyHeader []string) grpc.UnaryClientInterceptor {
 		opts ...grpc.CallOption,
 	) error {
 		//logger.Infof(ctx, "RPCClientInterceptor clientIP: %s", ctx.Value("clientip"))
+		ctx, span := trace.Start(ctx, method, oteltrace.WithSpanKind(oteltrace.SpanKindClient))
 		md := metadata.New(nil)
 		client := ctx.Value("clientip")
 		if client != nil {
@@ -29,8 +36,17 @@ func RPCClientInterceptor(proxyHeader []string) grpc.UnaryClientInterceptor {
 			ctx = trace.WithNewTraceID(ctx)
 		}
 		//logger.Infow(ctx, "RPCClientInterceptor", "proxyHeader", proxyHeader)
+		// 租户、用户同时以 metadata 形式传递，兼容直接读取 metadata 的服务
+		for _, key := range trace.BaggageKeys {
+			if value := trace.GetBaggage(ctx, key); value != "" {
+				md.Set(key, value)
+			}
+		}
 		if len(proxyHeader) != 0 {
 			for _, key := range proxyHeader {
+				if len(md.Get(key)) > 0 {
+					continue
+				}
 				ctxValue := ctx.Value(key)
 				//logger.Infow(ctx, "RPCClientInterceptor", "key", key, "value", ctxValue)
 				if ctxValue != nil {
@@ -40,9 +56,45 @@ func RPCClientInterceptor(proxyHeader []string) grpc.UnaryClientInterceptor {
 				}
 			}
 		}
-		if len(md) > 0 {
-			ctx = metadata.NewOutgoingContext(ctx, md)
+		trace.Inject(ctx, trace.MetadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		trace.End(span, err)
+		return err
+	}
+}
+
+// BreakerClientInterceptor 按目标服务与方法熔断，熔断器名称为 "grpc:<target><method>"，
+// 参数通过 breaker.Configure 调整；熔断时按 Unavailable 返回。需显式启用，如
+// rpc.NewGRPCConn(ctx, "user", reg, grpc.WithChainUnaryInterceptor(rpc.BreakerClientInterceptor()))
+func BreakerClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		err := breaker.DoWithAcceptable(ctx, "grpc:"+cc.Target()+method, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}, acceptableRPCError)
+		if errors.Is(err, breaker.ErrOpen) || errors.Is(err, breaker.ErrTooManyRequests) {
+			err = status.Error(codes.Unavailable, err.Error())
 		}
-		return invoker(ctx, method, req, reply, cc, opts...)
+		return err
+	}
+}
+
+// acceptableRPCError 业务错误和调用方自身的错误不计入熔断失败，只统计服务不可用、超时等
+func acceptableRPCError(err error) bool {
+	if rpcerror.IsRPCError(err) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return false
+	default:
+		return true
 	}
 }
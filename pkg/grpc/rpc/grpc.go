@@ -3,21 +3,26 @@ package rpc
 import (
 	"context"
 
+	"github.com/code-sigs/go-box/pkg/logger"
 	"github.com/code-sigs/go-box/pkg/registry/registry_interface"
 	"github.com/code-sigs/go-box/pkg/resolver"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-// NewGRPCServer 创建带有拦截器的 gRPC 服务端
-func NewGRPCServer() *grpc.Server {
-	return grpc.NewServer(
-		grpc.UnaryInterceptor(RPCServerInterceptor()), // 你的服务端拦截器
-		grpc.MaxRecvMsgSize(1024*1024*100),            // 设置最大接收消息大小为 100MB
-		grpc.MaxSendMsgSize(1024*1024*100),            // 设置最大发送消息大小为 100MB
-		grpc.InitialWindowSize(1024*1024*10),          // 设置初始窗口大小为 10MB
-		grpc.InitialConnWindowSize(1024*1024*10),      // 设置初始连接窗口大小为 10MB
-	)
+// NewGRPCServer 创建带有拦截器的 gRPC 服务端，opts 追加在默认选项之后，
+// 如 grpc.ChainUnaryInterceptor(RateLimitServerInterceptor(limiter, nil))
+func NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	base := []grpc.ServerOption{
+		// 先将 metadata 写入 ctx，再记录访问日志
+		grpc.ChainUnaryInterceptor(RPCServerInterceptor(), logger.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(logger.StreamServerInterceptor()),
+		grpc.MaxRecvMsgSize(1024 * 1024 * 100),       // 设置最大接收消息大小为 100MB
+		grpc.MaxSendMsgSize(1024 * 1024 * 100),       // 设置最大发送消息大小为 100MB
+		grpc.InitialWindowSize(1024 * 1024 * 10),     // 设置初始窗口大小为 10MB
+		grpc.InitialConnWindowSize(1024 * 1024 * 10), // 设置初始连接窗口大小为 10MB
+	}
+	return grpc.NewServer(append(base, opts...)...)
 }
 
 // const (
@@ -33,16 +38,18 @@ func NewGRPCServer() *grpc.Server {
 // 	IMToken    = "im-token"
 // )
 
-func NewGRPCConn(ctx context.Context, serviceName string, registry registry_interface.Registry) (*grpc.ClientConn, error) {
-	client, err := grpc.NewClient(
-		registry.Name()+":///"+serviceName,
+// NewGRPCConn 通过注册中心连接服务，opts 追加在默认选项之后，
+// 如 grpc.WithChainUnaryInterceptor(BreakerClientInterceptor()) 启用熔断
+func NewGRPCConn(ctx context.Context, serviceName string, registry registry_interface.Registry, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	base := []grpc.DialOption{
 		grpc.WithResolvers(resolver.NewBuilder(registry)),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),            // 注意：生产环境中请使用安全连接
-		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(1024*1024*100)), // 设置最大发送消息大小为 100MB
-		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(1024*1024*100)), // 设置最大接收消息大小为 100MB
+		grpc.WithTransportCredentials(insecure.NewCredentials()),                // 注意：生产环境中请使用安全连接
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(1024 * 1024 * 100)), // 设置最大发送消息大小为 100MB
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(1024 * 1024 * 100)), // 设置最大接收消息大小为 100MB
 		grpc.WithUnaryInterceptor(RPCClientInterceptor([]string{"user-id", "login-id", "platform-id", "tenant-id", "nat-type", "device-key", "auth-type", "im-token"})), // 可以传入自定义的 header 列表
 		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
-	)
+	}
+	client, err := grpc.NewClient(registry.Name()+":///"+serviceName, append(base, opts...)...)
 	if err != nil {
 		return nil, err
 	}
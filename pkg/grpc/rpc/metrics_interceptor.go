@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+type rpcMetrics struct {
+	handled  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newRPCMetrics(side string) rpcMetrics {
+	reg := metrics.Default()
+	return rpcMetrics{
+		handled:  reg.Counter("grpc_"+side+"_handled_total", "gRPC calls completed, partitioned by method and status code.", "method", "code"),
+		duration: reg.Histogram("grpc_"+side+"_handling_seconds", "gRPC call latency.", nil, "method"),
+	}
+}
+
+func (m rpcMetrics) observe(method string, start time.Time, err error) {
+	m.handled.WithLabelValues(method, status.Code(err).String()).Inc()
+	m.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// MetricsServerInterceptor 按方法和状态码统计服务端调用数与耗时，注册到 metrics.Default()；
+// 通过 NewGRPCServer(grpc.ChainUnaryInterceptor(rpc.MetricsServerInterceptor())) 启用
+func MetricsServerInterceptor() grpc.UnaryServerInterceptor {
+	m := newRPCMetrics("server")
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// MetricsStreamServerInterceptor 同 MetricsServerInterceptor，用于流式调用，耗时为整个流的持续时间
+func MetricsStreamServerInterceptor() grpc.StreamServerInterceptor {
+	m := newRPCMetrics("server")
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(info.FullMethod, start, err)
+		return err
+	}
+}
+
+// MetricsClientInterceptor 按方法和状态码统计客户端调用数与耗时，通过 grpc.WithChainUnaryInterceptor 启用
+func MetricsClientInterceptor() grpc.UnaryClientInterceptor {
+	m := newRPCMetrics("client")
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.observe(method, start, err)
+		return err
+	}
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitKeyFunc 返回限流 key，如方法名、租户、调用方 IP
+type RateLimitKeyFunc func(ctx context.Context, info *grpc.UnaryServerInfo) string
+
+// RateLimitServerInterceptor 按 key 限流，超限返回 ResourceExhausted 并在 trailer 中写入 retry-after（毫秒）；
+// keyFunc 为 nil 时按方法名限流，限流器出错时放行
+func RateLimitServerInterceptor(l ratelimit.Limiter, keyFunc RateLimitKeyFunc) grpc.UnaryServerInterceptor {
+	if keyFunc == nil {
+		keyFunc = func(_ context.Context, info *grpc.UnaryServerInfo) string { return info.FullMethod }
+	}
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		res, err := l.Allow(ctx, keyFunc(ctx, info))
+		if err != nil {
+			logger.Warnw(ctx, "rate limiter failed", "method", info.FullMethod, "error", err.Error())
+			return handler(ctx, req)
+		}
+		if !res.Allowed {
+			_ = grpc.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.FormatInt(res.RetryAfter.Milliseconds(), 10)))
+			return nil, status.Error(codes.ResourceExhausted, "too many requests")
+		}
+		return handler(ctx, req)
+	}
+}
@@ -2,11 +2,16 @@ package rpc
 
 import (
 	"context"
+
+	"github.com/code-sigs/go-box/pkg/rpcerror"
+	"github.com/code-sigs/go-box/pkg/trace"
+	"go.opentelemetry.io/otel/baggage"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
-// RPCServerInterceptor 将 metadata 的所有键值对放入 context
+// RPCServerInterceptor 将 metadata 的所有键值对放入 context，恢复上游链路并创建服务端 span
 func RPCServerInterceptor() grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
@@ -22,7 +27,31 @@ func RPCServerInterceptor() grpc.UnaryServerInterceptor {
 					ctx = context.WithValue(ctx, key, values[0])
 				}
 			}
+			ctx = trace.Extract(ctx, trace.MetadataCarrier(md))
+			// 上游只通过 metadata 传递租户、用户时补写到 baggage，继续向下游透传
+			for _, key := range trace.BaggageKeys {
+				if v := md.Get(key); len(v) > 0 && baggage.FromContext(ctx).Member(key).Value() == "" {
+					ctx = trace.WithBaggage(ctx, key, v[0])
+				}
+			}
+		}
+		ctx, span := trace.Start(ctx, info.FullMethod, oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+		resp, err := handler(ctx, req)
+		if err != nil {
+			// 带 code 的 errs.WrapError 统一转为 RPCError，再按调用方的 accept-language 渲染文案
+			err = rpcerror.LocalizeError(rpcerror.FromError(err), acceptLanguages(md)...)
+		}
+		trace.End(span, err)
+		return resp, err
+	}
+}
+
+// acceptLanguages 从 metadata 读取 accept-language（兼容 grpc-gateway 的前缀）
+func acceptLanguages(md metadata.MD) []string {
+	for _, key := range []string{"accept-language", "grpcgateway-accept-language"} {
+		if v := md.Get(key); len(v) > 0 {
+			return rpcerror.ParseAcceptLanguage(v[0])
 		}
-		return handler(ctx, req)
 	}
+	return nil
 }
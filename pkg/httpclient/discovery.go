@@ -0,0 +1,158 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/registry/registry_interface"
+)
+
+// endpoint 解析后的请求目标，service 非空时每次请求从注册中心选择实例
+type endpoint struct {
+	service string
+	url     *url.URL // service 目标时只使用 Path 与 RawQuery
+}
+
+func (e *endpoint) name() string {
+	if e.service != "" {
+		return e.service
+	}
+	return e.url.Host
+}
+
+// parseTarget 解析 target，相对路径拼接在 BaseURL 之后，query 追加到原有查询参数
+func (c *Client) parseTarget(target string, query url.Values) (*endpoint, error) {
+	if !strings.Contains(target, "://") && c.cfg.BaseURL != "" {
+		target = strings.TrimRight(c.cfg.BaseURL, "/") + "/" + strings.TrimLeft(target, "/")
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: invalid target %q: %w", target, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("httpclient: target %q has no host", target)
+	}
+	if len(query) > 0 {
+		q := u.Query()
+		for k, vs := range query {
+			for _, v := range vs {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	ep := &endpoint{url: u}
+	if u.Scheme == ServiceScheme {
+		ep.service = u.Host
+	}
+	return ep, nil
+}
+
+// resolve 返回本次请求的完整 URL
+func (c *Client) resolve(ctx context.Context, ep *endpoint) (string, error) {
+	if ep.service == "" {
+		return ep.url.String(), nil
+	}
+	if c.registry == nil {
+		return "", ErrNoRegistry
+	}
+	b, err := c.balancer(ctx, ep.service)
+	if err != nil {
+		return "", err
+	}
+	base, ok := b.pick()
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNoInstance, ep.service)
+	}
+	u := *ep.url
+	u.Scheme, u.Host = base.Scheme, base.Host
+	return u.String(), nil
+}
+
+// balancer 返回服务的实例列表，首次使用时查询并开始监听变化
+func (c *Client) balancer(ctx context.Context, service string) (*balancer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if b, ok := c.balancers[service]; ok {
+		return b, nil
+	}
+	instances, err := c.registry.GetServiceInstances(ctx, service)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: get instances of %s: %w", service, err)
+	}
+	watchCtx, cancel := context.WithCancel(context.Background())
+	ch, err := c.registry.Watch(watchCtx, service)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("httpclient: watch %s: %w", service, err)
+	}
+	b := &balancer{cancel: cancel}
+	b.update(instances)
+	go b.watch(watchCtx, service, ch)
+	c.balancers[service] = b
+	return b, nil
+}
+
+// balancer 轮询选择实例
+type balancer struct {
+	mu     sync.RWMutex
+	addrs  []*url.URL
+	next   atomic.Uint64
+	cancel context.CancelFunc
+}
+
+func (b *balancer) watch(ctx context.Context, service string, ch <-chan []*registry_interface.ServiceInstance) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case instances, ok := <-ch:
+			if !ok {
+				return
+			}
+			b.update(instances)
+			logger.Debugw(ctx, "http service instances updated", "service", service, "count", len(instances))
+		}
+	}
+}
+
+// update 实例地址为 host:port 时按元数据 scheme 补全，默认 http
+func (b *balancer) update(instances []*registry_interface.ServiceInstance) {
+	addrs := make([]*url.URL, 0, len(instances))
+	for _, ins := range instances {
+		raw := ins.Address
+		if !strings.Contains(raw, "://") {
+			scheme := ins.Metadata["scheme"]
+			if scheme == "" {
+				scheme = "http"
+			}
+			raw = scheme + "://" + raw
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		addrs = append(addrs, u)
+	}
+	b.mu.Lock()
+	b.addrs = addrs
+	b.mu.Unlock()
+}
+
+func (b *balancer) pick() (*url.URL, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.addrs) == 0 {
+		return nil, false
+	}
+	return b.addrs[(b.next.Add(1)-1)%uint64(len(b.addrs))], true
+}
+
+func (b *balancer) close() {
+	b.cancel()
+}
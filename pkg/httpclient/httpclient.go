@@ -0,0 +1,344 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/breaker"
+	"github.com/code-sigs/go-box/pkg/registry/registry_interface"
+	"github.com/code-sigs/go-box/pkg/trace"
+	"github.com/code-sigs/go-box/pkg/utils"
+)
+
+// ServiceScheme 通过注册中心解析的目标地址前缀，如 service://user-service/v1/users
+const ServiceScheme = "service"
+
+var (
+	ErrNoRegistry = errors.New("httpclient: service target requires a registry")
+	ErrNoInstance = errors.New("httpclient: no available instance")
+)
+
+// defaultRetryOnStatus 默认可重试的状态码
+var defaultRetryOnStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// defaultProxyHeaders 与 gRPC 客户端一致，ctx 中这些 key 的字符串值作为请求头透传
+var defaultProxyHeaders = []string{"user-id", "login-id", "platform-id", "tenant-id", "nat-type", "device-key", "auth-type", "im-token"}
+
+// Config HTTP 客户端配置
+type Config struct {
+	BaseURL         string            `mapstructure:"baseURL"`                         // 相对路径的前缀，可为 http(s)://host 或 service://name
+	Timeout         time.Duration     `mapstructure:"timeout" default:"10s"`           // 单次尝试的超时，含读取响应体
+	Retries         int               `mapstructure:"retries" default:"2"`             // 失败后的重试次数，只重试幂等请求，-1 不重试
+	RetryBackoffMin time.Duration     `mapstructure:"retryBackoffMin" default:"100ms"` // 重试退避基数
+	RetryBackoffMax time.Duration     `mapstructure:"retryBackoffMax" default:"2s"`    // 重试退避上限
+	RetryOnStatus   []int             `mapstructure:"retryOnStatus"`                   // 可重试的状态码，默认 429、502、503、504
+	Breaker         bool              `mapstructure:"breaker"`                         // 按目标服务或 host 熔断，熔断器名称为 "http:<target>"
+	Metrics         bool              `mapstructure:"metrics"`                         // 记录请求数与耗时到 metrics.Default()
+	Headers         map[string]string `mapstructure:"headers"`                         // 每个请求附带的请求头
+	ProxyHeaders    []string          `mapstructure:"proxyHeaders"`                    // 从 ctx 透传的 key，默认同 gRPC 客户端
+	MaxBodySize     int64             `mapstructure:"maxBodySize" default:"33554432"`  // 响应体上限，超过时返回错误
+}
+
+// StatusError 响应状态码 >= 400 时返回的错误
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("httpclient: %s %s: status %d: %s", e.Method, e.URL, e.StatusCode, truncate(e.Body, 512))
+}
+
+// IsStatus 判断 err 是否为指定状态码的 StatusError
+func IsStatus(err error, code int) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == code
+}
+
+// Option Client 可选参数
+type Option func(*Client)
+
+// WithRegistry 设置注册中心，用于解析 service://name 目标
+func WithRegistry(reg registry_interface.Registry) Option {
+	return func(c *Client) { c.registry = reg }
+}
+
+// WithTransport 设置底层 Transport，外层仍会包装链路注入
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) { c.http.Transport = trace.NewTransport(rt) }
+}
+
+// Client 带重试、熔断、链路与服务发现的 HTTP 客户端，可并发使用
+type Client struct {
+	cfg      Config
+	http     *http.Client
+	registry registry_interface.Registry
+	metrics  *clientMetrics
+
+	mu        sync.Mutex
+	balancers map[string]*balancer
+}
+
+// New 创建客户端，不再使用时调用 Close 停止对注册中心的监听
+func New(cfg Config, opts ...Option) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Retries == 0 {
+		cfg.Retries = 2
+	}
+	if cfg.RetryBackoffMax <= 0 {
+		cfg.RetryBackoffMax = 2 * time.Second
+	}
+	if len(cfg.RetryOnStatus) == 0 {
+		cfg.RetryOnStatus = defaultRetryOnStatus
+	}
+	if cfg.ProxyHeaders == nil {
+		cfg.ProxyHeaders = defaultProxyHeaders
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = 32 << 20
+	}
+	c := &Client{
+		cfg:       cfg,
+		http:      &http.Client{Transport: trace.NewTransport(nil)},
+		balancers: make(map[string]*balancer),
+	}
+	if cfg.Metrics {
+		c.metrics = newClientMetrics()
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Close 停止服务发现的监听
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, b := range c.balancers {
+		b.close()
+		delete(c.balancers, name)
+	}
+}
+
+// RequestOption 单个请求的可选参数
+type RequestOption func(*request)
+
+type request struct {
+	header http.Header
+	query  url.Values
+}
+
+// WithHeader 设置请求头
+func WithHeader(key, value string) RequestOption {
+	return func(r *request) { r.header.Set(key, value) }
+}
+
+// WithQuery 追加查询参数
+func WithQuery(query url.Values) RequestOption {
+	return func(r *request) {
+		for k, vs := range query {
+			for _, v := range vs {
+				r.query.Add(k, v)
+			}
+		}
+	}
+}
+
+// WithIdempotencyKey 设置 Idempotency-Key 请求头，使 POST 等非幂等请求也可重试
+func WithIdempotencyKey(key string) RequestOption {
+	return WithHeader("Idempotency-Key", key)
+}
+
+// GetJSON 发送 GET 请求并将 JSON 响应解码为 T
+func GetJSON[T any](ctx context.Context, c *Client, target string, opts ...RequestOption) (T, error) {
+	return DoJSON[T](ctx, c, http.MethodGet, target, nil, opts...)
+}
+
+// PostJSON 以 JSON 发送 body 并将响应解码为 T
+func PostJSON[T any](ctx context.Context, c *Client, target string, body any, opts ...RequestOption) (T, error) {
+	return DoJSON[T](ctx, c, http.MethodPost, target, body, opts...)
+}
+
+// DoJSON 以 JSON 发送 body 并将响应解码为 T，body 为 nil 时不发送请求体
+func DoJSON[T any](ctx context.Context, c *Client, method, target string, body any, opts ...RequestOption) (T, error) {
+	var out T
+	err := c.Do(ctx, method, target, body, &out, opts...)
+	return out, err
+}
+
+// Do 发送请求，body 为 []byte 时原样发送，其余类型编码为 JSON；
+// out 为 *[]byte 时写入原始响应体，其余非 nil 值按 JSON 解码
+func (c *Client) Do(ctx context.Context, method, target string, body, out any, opts ...RequestOption) error {
+	r := &request{header: make(http.Header), query: make(url.Values)}
+	for k, v := range c.cfg.Headers {
+		r.header.Set(k, v)
+	}
+	var payload []byte
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		payload = b
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("httpclient: encode request body: %w", err)
+		}
+		payload = data
+		r.header.Set("Content-Type", "application/json")
+	}
+	if out != nil {
+		r.header.Set("Accept", "application/json")
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	for _, key := range c.cfg.ProxyHeaders {
+		if v, ok := ctx.Value(key).(string); ok && v != "" && r.header.Get(key) == "" {
+			r.header.Set(key, v)
+		}
+	}
+
+	ep, err := c.parseTarget(target, r.query)
+	if err != nil {
+		return err
+	}
+	retries := c.cfg.Retries
+	if retries < 0 || !idempotent(method, r.header) {
+		retries = 0
+	}
+	start := time.Now()
+	var (
+		respBody []byte
+		status   int
+	)
+	err = utils.Retry(ctx, retries+1, utils.ExponentialBackoff(c.cfg.RetryBackoffMin, c.cfg.RetryBackoffMax), func(ctx context.Context) error {
+		status = 0
+		attempt := func(ctx context.Context) error {
+			var err error
+			respBody, status, err = c.attempt(ctx, method, ep, r.header, payload)
+			return err
+		}
+		if !c.cfg.Breaker {
+			return attempt(ctx)
+		}
+		// 非重试类状态码（如 400、404）是调用方的问题，不计入熔断失败
+		return breaker.DoWithAcceptable(ctx, "http:"+ep.name(), attempt, func(err error) bool {
+			var se *StatusError
+			return errors.As(err, &se) && !c.retryableStatus(se.StatusCode)
+		})
+	}, utils.RetryIf(c.retryable))
+	c.metrics.observe(ep.name(), method, status, start)
+	if err != nil {
+		if errors.Is(err, breaker.ErrOpen) || errors.Is(err, breaker.ErrTooManyRequests) {
+			return fmt.Errorf("httpclient: %s %s: %w", method, ep.name(), err)
+		}
+		return err
+	}
+
+	switch o := out.(type) {
+	case nil:
+	case *[]byte:
+		*o = respBody
+	default:
+		if len(respBody) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("httpclient: decode response from %s: %w", ep.name(), err)
+		}
+	}
+	return nil
+}
+
+// attempt 执行单次请求，每次重新选择实例，返回响应体与状态码，状态码 >= 400 时返回 *StatusError
+func (c *Client) attempt(ctx context.Context, method string, ep *endpoint, header http.Header, payload []byte) ([]byte, int, error) {
+	u, err := c.resolve(ctx, ep)
+	if err != nil {
+		return nil, 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, 0, fmt.Errorf("httpclient: build request: %w", err)
+	}
+	req.Header = header.Clone()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("httpclient: %s %s: %w", method, u, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, c.cfg.MaxBodySize+1))
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("httpclient: read response from %s: %w", u, err)
+	}
+	if int64(len(data)) > c.cfg.MaxBodySize {
+		return nil, resp.StatusCode, fmt.Errorf("httpclient: response from %s exceeds %d bytes", u, c.cfg.MaxBodySize)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, resp.StatusCode, &StatusError{Method: method, URL: u, StatusCode: resp.StatusCode, Header: resp.Header, Body: data}
+	}
+	return data, resp.StatusCode, nil
+}
+
+// retryable 熔断与调用方取消不重试，状态码错误按 RetryOnStatus 判断，其余（连接失败、超时、无实例）重试
+func (c *Client) retryable(err error) bool {
+	if errors.Is(err, breaker.ErrOpen) || errors.Is(err, breaker.ErrTooManyRequests) || errors.Is(err, ErrNoRegistry) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return c.retryableStatus(se.StatusCode)
+	}
+	return true
+}
+
+func (c *Client) retryableStatus(code int) bool {
+	for _, s := range c.cfg.RetryOnStatus {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
+
+// idempotent 重试可能导致请求被执行多次，只有幂等方法或携带 Idempotency-Key 的请求才重试
+func idempotent(method string, header http.Header) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return header.Get("Idempotency-Key") != ""
+	}
+}
+
+func truncate(b []byte, n int) string {
+	if len(b) <= n {
+		return string(b)
+	}
+	return strings.ToValidUTF8(string(b[:n]), "") + "..."
+}
@@ -0,0 +1,36 @@
+package httpclient
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// clientMetrics 出站请求指标，所有方法对 nil 接收者安全
+type clientMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newClientMetrics() *clientMetrics {
+	reg := metrics.Default()
+	return &clientMetrics{
+		requests: reg.Counter("http_client_requests_total", "Outbound HTTP calls, partitioned by target, method and final status code.", "target", "method", "code"),
+		duration: reg.Histogram("http_client_request_duration_seconds", "Outbound HTTP call latency including retries.", nil, "target", "method"),
+	}
+}
+
+// observe status 为最后一次响应的状态码，未收到响应（连接失败、超时、熔断）时为 0，记为 "error"
+func (m *clientMetrics) observe(target, method string, status int, start time.Time) {
+	if m == nil {
+		return
+	}
+	code := "error"
+	if status > 0 {
+		code = strconv.Itoa(status)
+	}
+	m.requests.WithLabelValues(target, method, code).Inc()
+	m.duration.WithLabelValues(target, method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,57 @@
+package idgen
+
+import (
+	"fmt"
+	"math"
+)
+
+// 按 ASCII 顺序排列，保证定长编码的字典序与数值顺序一致
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// base62Len 非负 int64 的最大编码长度
+const base62Len = 11
+
+// EncodeBase62 将非负 ID 编码为 11 位定长 base62 字符串，负数返回空字符串
+func EncodeBase62(id int64) string {
+	if id < 0 {
+		return ""
+	}
+	buf := make([]byte, base62Len)
+	for i := base62Len - 1; i >= 0; i-- {
+		buf[i] = base62Alphabet[id%62]
+		id /= 62
+	}
+	return string(buf)
+}
+
+// DecodeBase62 解析 EncodeBase62 生成的字符串，也接受不补零的短字符串
+func DecodeBase62(s string) (int64, error) {
+	if s == "" || len(s) > base62Len {
+		return 0, fmt.Errorf("idgen: invalid base62 id %q", s)
+	}
+	var id int64
+	for i := 0; i < len(s); i++ {
+		d := base62Index(s[i])
+		if d < 0 {
+			return 0, fmt.Errorf("idgen: invalid base62 id %q", s)
+		}
+		if id > (math.MaxInt64-int64(d))/62 {
+			return 0, fmt.Errorf("idgen: base62 id %q overflows int64", s)
+		}
+		id = id*62 + int64(d)
+	}
+	return id, nil
+}
+
+func base62Index(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 36
+	default:
+		return -1
+	}
+}
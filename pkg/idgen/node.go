@@ -0,0 +1,113 @@
+package idgen
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/code-sigs/go-box/pkg/utils"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// NodeIDFromIP 取 IPv4 地址的低 10 位作为节点号，适用于同一 /22 网段内的实例
+func NodeIDFromIP(ip string) (int64, error) {
+	parsed := net.ParseIP(ip).To4()
+	if parsed == nil {
+		return 0, fmt.Errorf("idgen: invalid ipv4 address %q", ip)
+	}
+	return (int64(parsed[2])<<8 | int64(parsed[3])) & MaxNodeID, nil
+}
+
+// NodeIDFromLocalIP 由本机 IP 计算节点号
+func NodeIDFromLocalIP() (int64, error) {
+	ip, err := utils.GetLocalIP()
+	if err != nil {
+		return 0, fmt.Errorf("idgen: get local ip: %w", err)
+	}
+	return NodeIDFromIP(ip)
+}
+
+// DefaultNodeKeyPrefix ClaimNodeID 占用节点号使用的 etcd key 前缀
+const DefaultNodeKeyPrefix = "idgen/node/"
+
+// ClaimNodeID 在 etcd 中依次尝试占用 <prefix><n>（n 为 0..MaxNodeID），key 绑定 leaseID，
+// 租约过期或撤销后节点号自动释放，保证存活实例的节点号互不相同；
+// 同一租约重复调用返回已占用的节点号。leaseID 可使用注册中心的租约（Register 后的 ServiceInfo.LeaseID）
+func ClaimNodeID(ctx context.Context, cli *clientv3.Client, prefix string, leaseID int64) (int64, error) {
+	if prefix == "" {
+		prefix = DefaultNodeKeyPrefix
+	}
+	resp, err := cli.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return 0, fmt.Errorf("idgen: list node ids: %w", err)
+	}
+	used := make(map[int64]bool, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		n, err := strconv.ParseInt(strings.TrimPrefix(string(kv.Key), prefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		if kv.Lease == leaseID {
+			return n, nil
+		}
+		used[n] = true
+	}
+	value := strconv.FormatInt(leaseID, 10)
+	for n := int64(0); n <= MaxNodeID; n++ {
+		if used[n] {
+			continue
+		}
+		key := prefix + strconv.FormatInt(n, 10)
+		txn, err := cli.Txn(ctx).
+			If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+			Then(clientv3.OpPut(key, value, clientv3.WithLease(clientv3.LeaseID(leaseID)))).
+			Commit()
+		if err != nil {
+			return 0, fmt.Errorf("idgen: claim node id %d: %w", n, err)
+		}
+		if txn.Succeeded {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("idgen: all %d node ids are in use", MaxNodeID+1)
+}
+
+var (
+	defaultOnce sync.Once
+	defaultGen  *Snowflake
+)
+
+// Init 使用指定节点号初始化默认生成器，需在首次调用 NextID 之前调用
+func Init(nodeID int64) error {
+	s, err := NewSnowflake(nodeID)
+	if err != nil {
+		return err
+	}
+	defaultOnce.Do(func() { defaultGen = s })
+	if defaultGen != s {
+		return fmt.Errorf("idgen: default generator already initialized")
+	}
+	return nil
+}
+
+// Default 返回默认生成器，未调用 Init 时节点号由本机 IP 计算，获取失败时为 0
+func Default() *Snowflake {
+	defaultOnce.Do(func() {
+		node, _ := NodeIDFromLocalIP()
+		defaultGen, _ = NewSnowflake(node)
+	})
+	return defaultGen
+}
+
+// NextID 使用默认生成器生成 ID
+func NextID() int64 {
+	return Default().NextID()
+}
+
+// NextString 使用默认生成器生成 base62 字符串 ID
+func NextString() string {
+	return Default().NextString()
+}
@@ -0,0 +1,79 @@
+package idgen
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ID 布局：1 位符号位 | 41 位毫秒时间戳 | 10 位节点号 | 12 位序列号，可使用约 69 年
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+
+	MaxNodeID   = 1<<nodeBits - 1
+	maxSequence = 1<<sequenceBits - 1
+
+	timeShift = nodeBits + sequenceBits
+	nodeShift = sequenceBits
+)
+
+// Epoch 时间戳起点 2024-01-01 00:00:00 UTC，修改后已生成的 ID 不再可比较
+var Epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+// Snowflake 雪花算法 ID 生成器，生成的 ID 按时间单调递增
+type Snowflake struct {
+	mu       sync.Mutex
+	node     int64
+	lastTime int64 // 上次生成 ID 的毫秒时间（相对 Epoch）
+	sequence int64
+}
+
+// NewSnowflake 创建生成器，nodeID 取值 0 ~ MaxNodeID，同一时刻各实例的 nodeID 不能重复
+func NewSnowflake(nodeID int64) (*Snowflake, error) {
+	if nodeID < 0 || nodeID > MaxNodeID {
+		return nil, fmt.Errorf("idgen: node id %d out of range [0, %d]", nodeID, MaxNodeID)
+	}
+	return &Snowflake{node: nodeID}, nil
+}
+
+// NextID 生成下一个 ID。时钟回拨时沿用上次的时间戳继续递增，保证单调
+func (s *Snowflake) NextID() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clock := time.Now().UnixMilli() - Epoch
+	now := max(clock, s.lastTime)
+	if now == s.lastTime {
+		s.sequence = (s.sequence + 1) & maxSequence
+		if s.sequence == 0 {
+			// 当前毫秒序列号用尽，使用下一毫秒；时钟正常时等待其到来
+			now++
+			for clock == s.lastTime && time.Now().UnixMilli()-Epoch < now {
+				time.Sleep(100 * time.Microsecond)
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.lastTime = now
+	return now<<timeShift | s.node<<nodeShift | s.sequence
+}
+
+// NextString 生成下一个 ID 的 base62 字符串形式，字典序与数值顺序一致
+func (s *Snowflake) NextString() string {
+	return EncodeBase62(s.NextID())
+}
+
+// Node 返回生成器的节点号
+func (s *Snowflake) Node() int64 {
+	return s.node
+}
+
+// Parse 解析 ID 中的生成时间、节点号和序列号
+func Parse(id int64) (t time.Time, node, sequence int64) {
+	t = time.UnixMilli(id>>timeShift + Epoch)
+	node = id >> nodeShift & MaxNodeID
+	sequence = id & maxSequence
+	return
+}
@@ -0,0 +1,178 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// ErrProducerClosed 生产者已关闭
+var ErrProducerClosed = errors.New("kafka: producer closed")
+
+// AsyncProducerOptions 异步生产者配置
+type AsyncProducerOptions[T any] struct {
+	FlushMessages  int           // 攒够多少条消息触发一次发送，0 使用 sarama 默认值
+	FlushBytes     int           // 攒够多少字节触发一次发送
+	FlushFrequency time.Duration // 最长攒批时间，默认 10ms
+	MaxInFlight    int           // 已提交但未确认的消息上限，超过后 Send 阻塞，默认 10000
+
+	OnSuccess func(report DeliveryReport[T]) // 投递成功回调，在独立协程中串行调用
+	OnError   func(report DeliveryReport[T]) // 投递失败回调，在独立协程中串行调用
+}
+
+// DeliveryReport 单条消息的投递结果
+type DeliveryReport[T any] struct {
+	Obj       *T
+	Topic     string
+	Partition int32
+	Offset    int64
+	Err       error
+}
+
+// AsyncProducer 基于 sarama.AsyncProducer 的高吞吐生产者
+type AsyncProducer[T any] struct {
+	topic    string
+	producer sarama.AsyncProducer
+	codec    Codec
+	metrics  *Metrics
+	opts     AsyncProducerOptions[T]
+	inflight chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewAsyncProducer 创建异步生产者
+func (k *Kafka[T]) NewAsyncProducer(topic string, opts AsyncProducerOptions[T]) (*AsyncProducer[T], error) {
+	if opts.FlushFrequency <= 0 {
+		opts.FlushFrequency = 10 * time.Millisecond
+	}
+	if opts.MaxInFlight <= 0 {
+		opts.MaxInFlight = 10000
+	}
+	conf := *k.sarama
+	conf.Producer.Return.Successes = true
+	conf.Producer.Return.Errors = true
+	conf.Producer.Flush.Frequency = opts.FlushFrequency
+	if opts.FlushMessages > 0 {
+		conf.Producer.Flush.Messages = opts.FlushMessages
+	}
+	if opts.FlushBytes > 0 {
+		conf.Producer.Flush.Bytes = opts.FlushBytes
+	}
+
+	producer, err := sarama.NewAsyncProducer(k.cfg.Endpoints, &conf)
+	if err != nil {
+		return nil, err
+	}
+	p := &AsyncProducer[T]{
+		topic:    topic,
+		producer: producer,
+		codec:    k.codec,
+		metrics:  k.metrics,
+		opts:     opts,
+		inflight: make(chan struct{}, opts.MaxInFlight),
+	}
+	p.wg.Add(2)
+	go p.handleSuccesses()
+	go p.handleErrors()
+	return p, nil
+}
+
+// Send 提交消息到发送队列，在途消息达到上限时阻塞直到有空位或 ctx 结束
+func (p *AsyncProducer[T]) Send(ctx context.Context, obj *T, opts ...SendOption) error {
+	value, err := p.codec.Marshal(p.topic, obj)
+	if err != nil {
+		return err
+	}
+	msg := &sarama.ProducerMessage{
+		Topic:    p.topic,
+		Value:    sarama.ByteEncoder(value),
+		Metadata: &asyncMeta[T]{obj: obj, start: time.Now()},
+	}
+	for _, opt := range opts {
+		opt(msg)
+	}
+	injectTrace(ctx, msg)
+
+	select {
+	case p.inflight <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		<-p.inflight
+		return ErrProducerClosed
+	}
+	p.producer.Input() <- msg
+	return nil
+}
+
+// Close 停止接收新消息，等待已提交的消息全部确认后返回
+func (p *AsyncProducer[T]) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	p.producer.AsyncClose()
+	p.wg.Wait()
+	return nil
+}
+
+func (p *AsyncProducer[T]) handleSuccesses() {
+	defer p.wg.Done()
+	for msg := range p.producer.Successes() {
+		<-p.inflight
+		p.observe(msg, nil)
+		if p.opts.OnSuccess != nil {
+			p.opts.OnSuccess(p.report(msg, nil))
+		}
+	}
+}
+
+func (p *AsyncProducer[T]) handleErrors() {
+	defer p.wg.Done()
+	for perr := range p.producer.Errors() {
+		<-p.inflight
+		p.observe(perr.Msg, perr.Err)
+		if p.opts.OnError != nil {
+			p.opts.OnError(p.report(perr.Msg, perr.Err))
+		}
+	}
+}
+
+// asyncMeta 随消息传递的原始对象和提交时间
+type asyncMeta[T any] struct {
+	obj   *T
+	start time.Time
+}
+
+func (p *AsyncProducer[T]) observe(msg *sarama.ProducerMessage, err error) {
+	if meta, ok := msg.Metadata.(*asyncMeta[T]); ok {
+		p.metrics.observeProduce(msg.Topic, meta.start, err)
+	}
+}
+
+func (p *AsyncProducer[T]) report(msg *sarama.ProducerMessage, err error) DeliveryReport[T] {
+	var obj *T
+	if meta, ok := msg.Metadata.(*asyncMeta[T]); ok {
+		obj = meta.obj
+	}
+	return DeliveryReport[T]{
+		Obj:       obj,
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Err:       err,
+	}
+}
@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec 消息序列化方式，topic 用于需要按 topic 区分 schema 的实现
+type Codec interface {
+	Marshal(topic string, v interface{}) ([]byte, error)
+	Unmarshal(topic string, data []byte, v interface{}) error
+}
+
+// JSONCodec 使用 encoding/json，默认编码
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(_ string, v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(_ string, data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// RawCodec 原样透传 []byte，适用于上层已完成编码的场景
+type RawCodec struct{}
+
+func (RawCodec) Marshal(_ string, v interface{}) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("kafka: RawCodec expects *[]byte, got %T", v)
+	}
+	return *b, nil
+}
+
+func (RawCodec) Unmarshal(_ string, data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("kafka: RawCodec expects *[]byte, got %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// ProtobufCodec 使用 protobuf 二进制编码，消息类型需实现 proto.Message
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) Marshal(_ string, v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("kafka: %T does not implement proto.Message", v)
+	}
+	return proto.Marshal(msg)
+}
+
+func (ProtobufCodec) Unmarshal(_ string, data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("kafka: %T does not implement proto.Message", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// AvroCodec 使用固定 schema 的 Avro 二进制编码，结构体字段通过 `avro:"name"` 标签映射
+type AvroCodec struct {
+	Schema avro.Schema
+}
+
+// NewAvroCodec 解析 schema 文本创建 Avro 编码
+func NewAvroCodec(schema string) (*AvroCodec, error) {
+	s, err := avro.Parse(schema)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: parse avro schema: %w", err)
+	}
+	return &AvroCodec{Schema: s}, nil
+}
+
+func (c *AvroCodec) Marshal(_ string, v interface{}) ([]byte, error) {
+	return avro.Marshal(c.Schema, v)
+}
+
+func (c *AvroCodec) Unmarshal(_ string, data []byte, v interface{}) error {
+	return avro.Unmarshal(c.Schema, data, v)
+}
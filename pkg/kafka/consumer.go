@@ -0,0 +1,348 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/ratelimit"
+	"github.com/code-sigs/go-box/pkg/trace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// ErrConsumerRunning 消费者已在运行
+var ErrConsumerRunning = errors.New("kafka: consumer already running")
+
+// ConsumerOptions 消费者可选配置
+type ConsumerOptions struct {
+	MaxAttempts     int    // handler 最多执行次数（含首次），默认 1
+	DeadLetterTopic string // 死信队列 topic，超过 MaxAttempts 或无法解码的消息会被转发到此 topic，为空不启用
+
+	RetryBackoff    time.Duration   // 进程内重试的初始间隔，按指数增长，默认 100ms
+	RetryBackoffMax time.Duration   // 进程内重试的最大间隔，默认 10s
+	RetryDelays     []time.Duration // 进程内重试用尽后依次投递到重试 topic（<topic>.retry.<n>）的延迟，为空不启用
+
+	// Concurrency 每个分区并发执行 handler 的协程数，默认 1 即严格按分区顺序处理。
+	// 大于 1 时相同 key 的消息仍按顺序处理，位点只在之前的消息全部完成后提交。
+	Concurrency int
+	// Unordered 为 true 时不再按 key 保序，消息完全并行处理，需显式开启
+	Unordered bool
+
+	// FailurePolicy 消息最终处理失败后的策略：skip / retry / deadletter / stop，
+	// 默认配置了 DeadLetterTopic 时为 deadletter，否则为 skip
+	FailurePolicy string
+	// ErrorHandler 消息解码或处理最终失败时回调，在执行 FailurePolicy 之前调用
+	ErrorHandler func(ctx context.Context, err *MessageError)
+
+	// RateLimiter 限制消费速率，每条消息处理前等待配额，同一消费组的实例共用 key（消费组名）；
+	// 使用 Redis 后端时整个消费组共享配额，限流器出错时放行
+	RateLimiter ratelimit.Limiter
+}
+
+type Consumer[T any] struct {
+	topics  []string
+	groupID string
+	group   sarama.ConsumerGroup
+	routes  map[string]route // topic -> handler
+	codec   Codec
+	metrics *Metrics
+	opts    ConsumerOptions
+	retry   map[string]int      // 重试 topic -> 级别（从 1 开始）
+	sender  sarama.SyncProducer // 用于投递重试和死信消息
+
+	mu      sync.Mutex
+	ctx     context.Context // Run 传入的 ctx，handler 的 ctx 由它派生
+	cancel  context.CancelFunc
+	running bool
+	done    chan struct{}
+	err     error
+	fatal   error // 由 PolicyStop 触发的致命错误
+}
+
+// NewConsumer 创建消费者并立即在后台开始消费，通过 Close 停止，Err 获取导致退出的错误
+func (k *Kafka[T]) NewConsumer(topic string, group string, handler func(context.Context, *T) error) (*Consumer[T], error) {
+	return k.NewConsumerWithOptions(topic, group, handler, ConsumerOptions{})
+}
+
+// NewConsumerWithOptions 创建带可选配置的消费者并立即在后台开始消费
+func (k *Kafka[T]) NewConsumerWithOptions(topic string, group string, handler func(context.Context, *T) error, opts ConsumerOptions) (*Consumer[T], error) {
+	c, err := k.NewConsumerGroup(topic, group, handler, opts)
+	if err != nil {
+		return nil, err
+	}
+	go c.Run(context.Background())
+	return c, nil
+}
+
+// NewConsumerGroup 创建消费者但不启动，调用 Run 开始消费
+func (k *Kafka[T]) NewConsumerGroup(topic string, group string, handler func(context.Context, *T) error, opts ConsumerOptions) (*Consumer[T], error) {
+	return k.newConsumer(group, map[string]route{topic: typedRoute[T]{handler: handler}}, opts)
+}
+
+func (k *Kafka[T]) newConsumer(group string, routes map[string]route, opts ConsumerOptions) (*Consumer[T], error) {
+	if len(routes) == 0 {
+		return nil, errors.New("kafka: consumer requires at least one topic")
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 1
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 100 * time.Millisecond
+	}
+	if opts.RetryBackoffMax <= 0 {
+		opts.RetryBackoffMax = 10 * time.Second
+	}
+	switch opts.failurePolicy() {
+	case PolicySkip, PolicyRetry, PolicyStop:
+	case PolicyDeadLetter:
+		if opts.DeadLetterTopic == "" {
+			return nil, errors.New("kafka: dead letter policy requires DeadLetterTopic")
+		}
+	default:
+		return nil, fmt.Errorf("kafka: unsupported failure policy %q", opts.FailurePolicy)
+	}
+	consumer, err := sarama.NewConsumerGroup(k.cfg.Endpoints, group, k.sarama)
+	if err != nil {
+		return nil, err
+	}
+	c := &Consumer[T]{
+		groupID: group,
+		group:   consumer,
+		routes:  routes,
+		codec:   k.codec,
+		metrics: k.metrics,
+		opts:    opts,
+		retry:   make(map[string]int, len(opts.RetryDelays)),
+		done:    make(chan struct{}),
+	}
+	for topic := range routes {
+		c.topics = append(c.topics, topic)
+		for i := range opts.RetryDelays {
+			retryTopic := retryTopicName(topic, i+1)
+			c.topics = append(c.topics, retryTopic)
+			c.retry[retryTopic] = i + 1
+		}
+	}
+	if opts.DeadLetterTopic != "" || len(opts.RetryDelays) > 0 {
+		c.sender, err = sarama.NewSyncProducer(k.cfg.Endpoints, k.sarama)
+		if err != nil {
+			consumer.Close()
+			return nil, fmt.Errorf("kafka: create retry producer: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// Run 阻塞消费直到 ctx 结束、调用 Close 或遇到不可恢复的错误，正常停止时返回 nil
+func (c *Consumer[T]) Run(ctx context.Context) error {
+	c.mu.Lock()
+	if c.running {
+		c.mu.Unlock()
+		return ErrConsumerRunning
+	}
+	c.running = true
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.mu.Unlock()
+
+	err := c.consumeLoop()
+
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+	close(c.done)
+	return err
+}
+
+// consumeLoop 循环调用 Consume，rebalance 或临时错误后重新加入消费组
+func (c *Consumer[T]) consumeLoop() error {
+	backoff := time.Second
+	for {
+		err := c.group.Consume(c.ctx, c.topics, c)
+		if fatal := c.fatalErr(); fatal != nil {
+			return fatal
+		}
+		if c.ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			backoff = time.Second
+			continue
+		}
+		if isFatalConsumeError(err) {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-c.ctx.Done():
+			return nil
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// isFatalConsumeError 判断是否为重试无意义的错误
+func isFatalConsumeError(err error) bool {
+	var cfgErr sarama.ConfigurationError
+	return errors.Is(err, sarama.ErrClosedConsumerGroup) || errors.As(err, &cfgErr)
+}
+
+// Close 停止消费并等待当前消息处理完成后释放连接
+func (c *Consumer[T]) Close() error {
+	c.mu.Lock()
+	running, cancel := c.running, c.cancel
+	c.mu.Unlock()
+	if running {
+		cancel()
+		<-c.done
+	}
+	if c.sender != nil {
+		c.sender.Close()
+	}
+	return c.group.Close()
+}
+
+// Done 消费停止时关闭
+func (c *Consumer[T]) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err 返回导致消费停止的错误，正常停止或仍在运行时为 nil
+func (c *Consumer[T]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *Consumer[T]) Setup(sess sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (c *Consumer[T]) Cleanup(sess sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (c *Consumer[T]) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	if c.opts.Concurrency > 1 {
+		return c.consumeParallel(sess, claim)
+	}
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			c.metrics.setLag(c.groupID, message.Topic, message.Partition, claim.HighWaterMarkOffset()-message.Offset-1)
+			if err := c.handleMessage(sess.Context(), message); err != nil {
+				// 转发重试或死信失败时不提交位点，结束本轮会话等待重新投递
+				return err
+			}
+			sess.MarkMessage(message, "")
+		case <-sess.Context().Done():
+			return nil
+		}
+	}
+}
+
+type messageKey struct{}
+
+// MessageFromContext 返回 handler 正在处理的原始消息，用于读取 key 与消息头
+func MessageFromContext(ctx context.Context) (*sarama.ConsumerMessage, bool) {
+	msg, ok := ctx.Value(messageKey{}).(*sarama.ConsumerMessage)
+	return msg, ok
+}
+
+// handleMessage 解码并执行 handler，失败时按退避重试，仍失败则投递重试 topic 或死信队列
+func (c *Consumer[T]) handleMessage(sessCtx context.Context, message *sarama.ConsumerMessage) error {
+	// 延迟消息和重试 topic 中的消息需等到预定时间再处理
+	if level, ok := c.retry[message.Topic]; ok {
+		if err := sleepContext(sessCtx, time.Until(retryAt(message, c.opts.RetryDelays[level-1]))); err != nil {
+			return err
+		}
+	} else if at, ok := deliverAt(message); ok {
+		if err := sleepContext(sessCtx, time.Until(at)); err != nil {
+			return err
+		}
+	}
+	if c.opts.RateLimiter != nil {
+		if err := ratelimit.Wait(sessCtx, c.opts.RateLimiter, c.groupID); err != nil {
+			if sessCtx.Err() != nil {
+				return err
+			}
+			logger.Warnw(sessCtx, "kafka rate limiter failed", "group", c.groupID, "error", err.Error())
+		}
+	}
+
+	kv := make(map[string]string)
+	for _, header := range message.Headers {
+		kv[string(header.Key)] = string(header.Value)
+	}
+	ctx := c.ctx
+	if len(kv) > 0 {
+		for k, v := range kv {
+			ctx = context.WithValue(ctx, k, v)
+		}
+	}
+	ctx = context.WithValue(ctx, messageKey{}, message)
+	ctx = extractTrace(ctx, message.Headers)
+	topic := originalTopic(message)
+	ctx, span := trace.Start(ctx, topic+" process",
+		oteltrace.WithSpanKind(oteltrace.SpanKindConsumer),
+		oteltrace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", message.Topic),
+			attribute.String("messaging.consumer.group.name", c.groupID),
+			attribute.Int("messaging.destination.partition.id", int(message.Partition)),
+			attribute.Int64("messaging.kafka.offset", message.Offset),
+		),
+	)
+	defer span.End()
+	r, ok := c.routes[topic]
+	if !ok {
+		err := fmt.Errorf("kafka: no handler registered for topic %s", topic)
+		c.metrics.incConsumed(c.groupID, topic, err)
+		return c.fail(ctx, message, err, 0, true)
+	}
+	obj, err := r.decode(c.codec, topic, message.Value)
+	if err != nil {
+		c.metrics.incConsumed(c.groupID, topic, err)
+		return c.fail(ctx, message, err, 0, true)
+	}
+	attempts := 0
+	retryForever := c.opts.failurePolicy() == PolicyRetry
+	for attempts < c.opts.MaxAttempts || retryForever {
+		if attempts > 0 {
+			if serr := sleepContext(sessCtx, c.retryBackoff(attempts)); serr != nil {
+				return serr
+			}
+			c.metrics.incRetry(c.groupID, topic)
+		}
+		attempts++
+		start := time.Now()
+		err = r.invoke(ctx, obj)
+		c.metrics.observeHandler(c.groupID, topic, start)
+		if err == nil {
+			c.metrics.incConsumed(c.groupID, topic, nil)
+			return nil
+		}
+	}
+	if err := sessCtx.Err(); err != nil {
+		// 停止过程中未处理完的消息不提交，留给下次消费
+		return err
+	}
+	c.metrics.incConsumed(c.groupID, topic, err)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	if next := c.retry[message.Topic] + 1; next <= len(c.opts.RetryDelays) {
+		c.metrics.incRetry(c.groupID, topic)
+		return c.publishRetry(message, err, next)
+	}
+	return c.fail(ctx, message, err, attempts, false)
+}
@@ -0,0 +1,39 @@
+package kafka
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// HeaderDeliverAt 消息预定处理时间（Unix 毫秒），消费者收到后会等到该时间再调用 handler
+const HeaderDeliverAt = "x-deliver-at"
+
+// WithDelay 延迟 d 后再处理消息。
+// 延迟在消费端实现：分区内排在其后的消息会一起等待，延迟差异大的消息应发往不同 topic。
+func WithDelay(d time.Duration) SendOption {
+	return WithDeliverAt(time.Now().Add(d))
+}
+
+// WithDeliverAt 指定消息的最早处理时间
+func WithDeliverAt(t time.Time) SendOption {
+	return func(msg *sarama.ProducerMessage) {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{
+			Key:   []byte(HeaderDeliverAt),
+			Value: []byte(strconv.FormatInt(t.UnixMilli(), 10)),
+		})
+	}
+}
+
+// deliverAt 读取消息的预定处理时间
+func deliverAt(message *sarama.ConsumerMessage) (time.Time, bool) {
+	for _, h := range message.Headers {
+		if h != nil && string(h.Key) == HeaderDeliverAt {
+			if ms, err := strconv.ParseInt(string(h.Value), 10, 64); err == nil {
+				return time.UnixMilli(ms), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,49 @@
+package kafka
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/IBM/sarama"
+)
+
+// 死信消息附带的失败信息头
+const (
+	HeaderDLQError     = "x-dlq-error"
+	HeaderDLQAttempts  = "x-dlq-attempts"
+	HeaderDLQTopic     = "x-dlq-original-topic"
+	HeaderDLQPartition = "x-dlq-original-partition"
+	HeaderDLQOffset    = "x-dlq-original-offset"
+)
+
+// deadLetter 将处理失败的消息原样转发到死信 topic，未配置死信队列时直接丢弃
+func (c *Consumer[T]) deadLetter(message *sarama.ConsumerMessage, cause error, attempts int) error {
+	if c.opts.DeadLetterTopic == "" || cause == nil {
+		return nil
+	}
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+5)
+	for _, h := range message.Headers {
+		// 死信消息需要立即可见，不保留延迟投递时间
+		if h != nil && string(h.Key) != HeaderDeliverAt {
+			headers = append(headers, *h)
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderDLQError), Value: []byte(cause.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderDLQAttempts), Value: []byte(strconv.Itoa(attempts))},
+		sarama.RecordHeader{Key: []byte(HeaderDLQTopic), Value: []byte(originalTopic(message))},
+		sarama.RecordHeader{Key: []byte(HeaderDLQPartition), Value: []byte(strconv.FormatInt(int64(message.Partition), 10))},
+		sarama.RecordHeader{Key: []byte(HeaderDLQOffset), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+	)
+	_, _, err := c.sender.SendMessage(&sarama.ProducerMessage{
+		Topic:   c.opts.DeadLetterTopic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("kafka: send to dead letter topic %s: %w", c.opts.DeadLetterTopic, err)
+	}
+	c.metrics.incDeadLetter(c.groupID, originalTopic(message))
+	return nil
+}
@@ -2,9 +2,14 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
-	"github.com/IBM/sarama"
+	"fmt"
+	"sort"
 	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/code-sigs/go-box/pkg/trace"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 type Config struct {
@@ -12,6 +17,13 @@ type Config struct {
 	Username  string    `mapstructure:"username"`
 	Password  string    `mapstructure:"password"`
 	TLS       TLSConfig `mapstructure:"tls"`
+
+	Mechanism     string                     `mapstructure:"mechanism"`  // SASL 机制：PLAIN / SCRAM-SHA-256 / SCRAM-SHA-512 / OAUTHBEARER，默认 PLAIN
+	OAuthToken    string                     `mapstructure:"oauthToken"` // OAUTHBEARER 静态 token
+	TokenProvider sarama.AccessTokenProvider `mapstructure:"-"`          // OAUTHBEARER token 提供者，优先于 OAuthToken
+
+	Partitioner string `mapstructure:"partitioner"` // 分区策略：hash / murmur2 / crc / random / roundrobin / manual，默认 hash
+	Metrics     bool   `mapstructure:"metrics"`     // 启用生产与消费指标，注册到 metrics.Default()，等同于 WithMetrics
 }
 
 type TLSConfig struct {
@@ -24,22 +36,23 @@ type TLSConfig struct {
 }
 
 type Kafka[T any] struct {
-	sarama *sarama.Config
-	cfg    *Config
+	sarama  *sarama.Config
+	cfg     *Config
+	codec   Codec
+	metrics *Metrics
 }
 
 type Producer[T any] struct {
 	topic    string
 	producer sarama.SyncProducer
+	codec    Codec
+	metrics  *Metrics
 }
 
-type Consumer[T any] struct {
-	handler func(context.Context, *T) error
-}
-
-func New[T any](cfg *Config) *Kafka[T] {
+func New[T any](cfg *Config) (*Kafka[T], error) {
 	kfa := &Kafka[T]{
-		cfg: cfg,
+		cfg:   cfg,
+		codec: JSONCodec{},
 	}
 	kfa.sarama = sarama.NewConfig()
 	kfa.sarama.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRoundRobin()}
@@ -48,37 +61,48 @@ func New[T any](cfg *Config) *Kafka[T] {
 	kfa.sarama.Producer.RequiredAcks = sarama.WaitForAll
 	kfa.sarama.Producer.Return.Successes = true
 	// sasl认证
-	if cfg.Username != "" && cfg.Password != "" {
-		kfa.sarama.Net.SASL.Enable = true
-		kfa.sarama.Net.SASL.User = cfg.Username
-		kfa.sarama.Net.SASL.Password = cfg.Password
+	if err := applySASL(kfa.sarama, cfg); err != nil {
+		return nil, err
 	}
-	return kfa
-}
-
-func (k *Kafka[T]) NewConsumer(topic string, group string, handler func(context.Context, *T) error) (*Consumer[T], error) {
-	c := &Consumer[T]{
-		handler: handler,
+	partitioner, err := partitionerConstructor(cfg.Partitioner)
+	if err != nil {
+		return nil, err
 	}
-	var err error
-	consumer, err := sarama.NewConsumerGroup(k.cfg.Endpoints, group, k.sarama)
+	kfa.sarama.Producer.Partitioner = partitioner
+	// tls
+	tlsCfg, err := buildTLSConfig(&cfg.TLS)
 	if err != nil {
-		return c, err
+		return nil, err
 	}
-	go func() {
-		for {
-			if err := consumer.Consume(context.Background(), []string{topic}, c); err != nil {
-				time.Sleep(time.Second * 10)
-				continue
-			}
+	if tlsCfg != nil {
+		kfa.sarama.Net.TLS.Enable = true
+		kfa.sarama.Net.TLS.Config = tlsCfg
+	}
+	if cfg.Metrics {
+		if kfa.metrics, err = NewMetrics(nil); err != nil {
+			return nil, err
 		}
-	}()
-	return c, nil
+	}
+	return kfa, nil
+}
+
+// WithCodec 设置消息编码，之后创建的生产者和消费者生效，默认 JSONCodec
+func (k *Kafka[T]) WithCodec(codec Codec) *Kafka[T] {
+	k.codec = codec
+	return k
+}
+
+// WithMetrics 设置指标收集器，之后创建的生产者和消费者生效
+func (k *Kafka[T]) WithMetrics(metrics *Metrics) *Kafka[T] {
+	k.metrics = metrics
+	return k
 }
 
 func (k *Kafka[T]) NewProducer(topic string) (*Producer[T], error) {
 	producer := &Producer[T]{
-		topic: topic,
+		topic:   topic,
+		codec:   k.codec,
+		metrics: k.metrics,
 	}
 	var err error
 	producer.producer, err = sarama.NewSyncProducer(k.cfg.Endpoints, k.sarama)
@@ -88,8 +112,13 @@ func (k *Kafka[T]) NewProducer(topic string) (*Producer[T], error) {
 	return producer, nil
 }
 
-func (p *Producer[T]) Send(obj *T, header map[string]string) error {
-	value, err := json.Marshal(obj)
+func (p *Producer[T]) Send(obj *T, opts ...SendOption) error {
+	return p.SendContext(context.Background(), obj, opts...)
+}
+
+// SendContext 发送消息，并将 ctx 中的 traceID 写入消息头
+func (p *Producer[T]) SendContext(ctx context.Context, obj *T, opts ...SendOption) error {
+	value, err := p.codec.Marshal(p.topic, obj)
 	if err != nil {
 		return err
 	}
@@ -97,54 +126,90 @@ func (p *Producer[T]) Send(obj *T, header map[string]string) error {
 		Topic: p.topic,
 		Value: sarama.ByteEncoder(value),
 	}
-	if header != nil {
-		for k, v := range header {
-			msg.Headers = append(msg.Headers, sarama.RecordHeader{
-				Key:   []byte(k),
-				Value: []byte(v),
-			})
-		}
+	for _, opt := range opts {
+		opt(msg)
 	}
+	ctx, span := trace.Start(ctx, p.topic+" publish",
+		oteltrace.WithSpanKind(oteltrace.SpanKindProducer),
+		oteltrace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", p.topic),
+		),
+	)
+	injectTrace(ctx, msg)
+	start := time.Now()
 	_, _, err = p.producer.SendMessage(msg)
+	p.metrics.observeProduce(p.topic, start, err)
+	trace.End(span, err)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (c *Consumer[T]) Setup(sess sarama.ConsumerGroupSession) error {
-	return nil
+// BatchError SendBatch 中部分消息发送失败，Failed 为失败消息在入参中的下标（升序）
+type BatchError struct {
+	Failed []int
+	Err    error
 }
 
-func (c *Consumer[T]) Cleanup(sess sarama.ConsumerGroupSession) error {
-	return nil
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("kafka: %d messages failed: %v", len(e.Failed), e.Err)
 }
 
-func (c *Consumer[T]) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	for {
-		select {
-		case message, ok := <-claim.Messages():
-			if !ok {
-				continue
-			}
-			kv := make(map[string]string)
-			for _, header := range message.Headers {
-				kv[string(header.Key)] = string(header.Value)
-			}
-			ctx := context.Background()
-			if len(kv) > 0 {
-				for k, v := range kv {
-					ctx = context.WithValue(ctx, k, v)
-				}
-			}
-			obj := new(T)
-			err := json.Unmarshal(message.Value, obj)
-			if err == nil {
-				_ = c.handler(ctx, obj)
+func (e *BatchError) Unwrap() error { return e.Err }
+
+// SendBatch 一次提交一批消息，由 sarama 按 broker 合并为批量请求；
+// 编码失败时整批不发送，发送后部分失败时返回 *BatchError
+func (p *Producer[T]) SendBatch(ctx context.Context, objs []*T, opts ...SendOption) error {
+	if len(objs) == 0 {
+		return nil
+	}
+	ctx, span := trace.Start(ctx, p.topic+" publish",
+		oteltrace.WithSpanKind(oteltrace.SpanKindProducer),
+		oteltrace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", p.topic),
+			attribute.Int("messaging.batch.message_count", len(objs)),
+		),
+	)
+	msgs := make([]*sarama.ProducerMessage, len(objs))
+	index := make(map[*sarama.ProducerMessage]int, len(objs))
+	for i, obj := range objs {
+		value, err := p.codec.Marshal(p.topic, obj)
+		if err != nil {
+			trace.End(span, err)
+			return err
+		}
+		msg := &sarama.ProducerMessage{
+			Topic: p.topic,
+			Value: sarama.ByteEncoder(value),
+		}
+		for _, opt := range opts {
+			opt(msg)
+		}
+		injectTrace(ctx, msg)
+		msgs[i] = msg
+		index[msg] = i
+	}
+	start := time.Now()
+	err := p.producer.SendMessages(msgs)
+	p.metrics.observeProduce(p.topic, start, err)
+	trace.End(span, err)
+	if perrs, ok := err.(sarama.ProducerErrors); ok {
+		failed := make([]int, 0, len(perrs))
+		for _, perr := range perrs {
+			if i, ok := index[perr.Msg]; ok {
+				failed = append(failed, i)
 			}
-			sess.MarkMessage(message, "")
-		case <-sess.Context().Done():
-			return nil
 		}
+		sort.Ints(failed)
+		return &BatchError{Failed: failed, Err: err}
 	}
+	return err
+}
+
+// Close 关闭生产者
+func (p *Producer[T]) Close() error {
+	return p.producer.Close()
 }
@@ -0,0 +1,151 @@
+package kafka
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics Kafka 生产与消费指标，所有方法对 nil 接收者安全
+type Metrics struct {
+	produceLatency  *prometheus.HistogramVec
+	produceErrors   *prometheus.CounterVec
+	consumed        *prometheus.CounterVec
+	handlerDuration *prometheus.HistogramVec
+	retries         *prometheus.CounterVec
+	deadLetters     *prometheus.CounterVec
+	lag             *prometheus.GaugeVec
+}
+
+// NewMetrics 创建指标并注册到 reg，reg 为 nil 时使用 metrics.Default()；
+// 多个客户端共用同一个 registry 时会复用已注册的指标
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	if reg == nil {
+		reg = metrics.Default().Registerer()
+	}
+	m := &Metrics{
+		produceLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "kafka", Subsystem: "producer", Name: "send_duration_seconds",
+			Help:    "Time from send to broker acknowledgement.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"topic"}),
+		produceErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "kafka", Subsystem: "producer", Name: "errors_total",
+			Help: "Messages that failed to be produced.",
+		}, []string{"topic"}),
+		consumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "kafka", Subsystem: "consumer", Name: "messages_total",
+			Help: "Messages consumed, partitioned by handling result.",
+		}, []string{"group", "topic", "result"}),
+		handlerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "kafka", Subsystem: "consumer", Name: "handler_duration_seconds",
+			Help:    "Duration of a single handler invocation.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"group", "topic"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "kafka", Subsystem: "consumer", Name: "retries_total",
+			Help: "Handler retries, including re-deliveries through retry topics.",
+		}, []string{"group", "topic"}),
+		deadLetters: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "kafka", Subsystem: "consumer", Name: "dead_letters_total",
+			Help: "Messages forwarded to the dead letter topic.",
+		}, []string{"group", "topic"}),
+		lag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "kafka", Subsystem: "consumer", Name: "lag",
+			Help: "Difference between the partition high water mark and the last consumed offset.",
+		}, []string{"group", "topic", "partition"}),
+	}
+	var err error
+	if m.produceLatency, err = register(reg, m.produceLatency); err != nil {
+		return nil, err
+	}
+	if m.produceErrors, err = register(reg, m.produceErrors); err != nil {
+		return nil, err
+	}
+	if m.consumed, err = register(reg, m.consumed); err != nil {
+		return nil, err
+	}
+	if m.handlerDuration, err = register(reg, m.handlerDuration); err != nil {
+		return nil, err
+	}
+	if m.retries, err = register(reg, m.retries); err != nil {
+		return nil, err
+	}
+	if m.deadLetters, err = register(reg, m.deadLetters); err != nil {
+		return nil, err
+	}
+	if m.lag, err = register(reg, m.lag); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// register 注册指标，已存在同名指标时返回已注册的实例
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) (C, error) {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing, nil
+			}
+		}
+		return c, err
+	}
+	return c, nil
+}
+
+func (m *Metrics) observeProduce(topic string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	if err != nil {
+		m.produceErrors.WithLabelValues(topic).Inc()
+		return
+	}
+	m.produceLatency.WithLabelValues(topic).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) observeHandler(group, topic string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.handlerDuration.WithLabelValues(group, topic).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) incConsumed(group, topic string, err error) {
+	if m == nil {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.consumed.WithLabelValues(group, topic, result).Inc()
+}
+
+func (m *Metrics) incRetry(group, topic string) {
+	if m == nil {
+		return
+	}
+	m.retries.WithLabelValues(group, topic).Inc()
+}
+
+func (m *Metrics) incDeadLetter(group, topic string) {
+	if m == nil {
+		return
+	}
+	m.deadLetters.WithLabelValues(group, topic).Inc()
+}
+
+func (m *Metrics) setLag(group, topic string, partition int32, lag int64) {
+	if m == nil {
+		return
+	}
+	if lag < 0 {
+		lag = 0
+	}
+	m.lag.WithLabelValues(group, topic, strconv.FormatInt(int64(partition), 10)).Set(float64(lag))
+}
@@ -0,0 +1,127 @@
+package kafka
+
+import (
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/IBM/sarama"
+)
+
+// 分区策略
+const (
+	PartitionerHash       = "hash"       // 按 key 的 FNV-1a 哈希分区，key 为空时随机，默认
+	PartitionerMurmur2    = "murmur2"    // 与 Java 客户端默认分区器一致，跨语言生产同一 topic 时使用
+	PartitionerCRC        = "crc"        // 一致性 CRC32 哈希
+	PartitionerRandom     = "random"     // 随机
+	PartitionerRoundRobin = "roundrobin" // 轮询
+	PartitionerManual     = "manual"     // 使用 WithPartition 指定的分区
+)
+
+// partitionerConstructor 根据名称返回 sarama 分区器
+func partitionerConstructor(name string) (sarama.PartitionerConstructor, error) {
+	switch strings.ToLower(name) {
+	case "", PartitionerHash:
+		return sarama.NewHashPartitioner, nil
+	case PartitionerMurmur2:
+		return sarama.NewCustomPartitioner(
+			sarama.WithAbsFirst(),
+			sarama.WithCustomHashFunction(newMurmur2),
+		), nil
+	case PartitionerCRC:
+		return sarama.NewConsistentCRCHashPartitioner, nil
+	case PartitionerRandom:
+		return sarama.NewRandomPartitioner, nil
+	case PartitionerRoundRobin:
+		return sarama.NewRoundRobinPartitioner, nil
+	case PartitionerManual:
+		return sarama.NewManualPartitioner, nil
+	default:
+		return nil, fmt.Errorf("kafka: unsupported partitioner %q", name)
+	}
+}
+
+// SendOption 发送选项
+type SendOption func(msg *sarama.ProducerMessage)
+
+// WithKey 设置消息 key，相同 key 的消息进入同一分区以保证顺序
+func WithKey(key string) SendOption {
+	return func(msg *sarama.ProducerMessage) {
+		msg.Key = sarama.StringEncoder(key)
+	}
+}
+
+// WithHeaders 追加消息头
+func WithHeaders(headers map[string]string) SendOption {
+	return func(msg *sarama.ProducerMessage) {
+		for k, v := range headers {
+			msg.Headers = append(msg.Headers, sarama.RecordHeader{
+				Key:   []byte(k),
+				Value: []byte(v),
+			})
+		}
+	}
+}
+
+// WithPartition 指定分区，仅在分区策略为 manual 时生效
+func WithPartition(partition int32) SendOption {
+	return func(msg *sarama.ProducerMessage) {
+		msg.Partition = partition
+	}
+}
+
+// murmur2 Kafka Java 客户端使用的 32 位 murmur2 哈希
+type murmur2 struct {
+	data []byte
+}
+
+func newMurmur2() hash.Hash32 { return &murmur2{} }
+
+func (m *murmur2) Write(p []byte) (int, error) {
+	m.data = append(m.data, p...)
+	return len(p), nil
+}
+
+func (m *murmur2) Sum(b []byte) []byte {
+	v := m.Sum32()
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func (m *murmur2) Reset()         { m.data = m.data[:0] }
+func (m *murmur2) Size() int      { return 4 }
+func (m *murmur2) BlockSize() int { return 4 }
+
+func (m *murmur2) Sum32() uint32 {
+	const (
+		seed uint32 = 0x9747b28c
+		mul  uint32 = 0x5bd1e995
+		r           = 24
+	)
+	data := m.data
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := uint32(data[i]) | uint32(data[i+1])<<8 | uint32(data[i+2])<<16 | uint32(data[i+3])<<24
+		k *= mul
+		k ^= k >> r
+		k *= mul
+		h *= mul
+		h ^= k
+	}
+	tail := length &^ 3
+	switch length % 4 {
+	case 3:
+		h ^= uint32(data[tail+2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[tail+1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[tail])
+		h *= mul
+	}
+	h ^= h >> 13
+	h *= mul
+	h ^= h >> 15
+	return h
+}
@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/code-sigs/go-box/pkg/logger"
+)
+
+// 消息处理失败后的处理策略
+const (
+	PolicySkip       = "skip"       // 记录错误后跳过，未配置死信队列时的默认策略
+	PolicyRetry      = "retry"      // 按退避无限重试直到成功，会阻塞所在分区
+	PolicyDeadLetter = "deadletter" // 转发死信队列，配置了 DeadLetterTopic 时的默认策略
+	PolicyStop       = "stop"       // 停止消费者，Run 返回 *MessageError
+)
+
+// MessageError 消息解码或处理失败的详细信息
+type MessageError struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Attempts  int  // handler 已执行次数，解码失败时为 0
+	Decode    bool // 是否为解码失败
+	Err       error
+}
+
+func (e *MessageError) Error() string {
+	stage := "handle"
+	if e.Decode {
+		stage = "decode"
+	}
+	return fmt.Sprintf("kafka: %s message %s/%d@%d failed after %d attempts: %v", stage, e.Topic, e.Partition, e.Offset, e.Attempts, e.Err)
+}
+
+func (e *MessageError) Unwrap() error {
+	return e.Err
+}
+
+// failurePolicy 返回生效的失败策略
+func (o *ConsumerOptions) failurePolicy() string {
+	if o.FailurePolicy != "" {
+		return o.FailurePolicy
+	}
+	if o.DeadLetterTopic != "" {
+		return PolicyDeadLetter
+	}
+	return PolicySkip
+}
+
+// fail 回调 ErrorHandler 并按策略处置失败的消息，返回非 nil 时消息不会被提交
+func (c *Consumer[T]) fail(ctx context.Context, message *sarama.ConsumerMessage, cause error, attempts int, decode bool) error {
+	merr := &MessageError{
+		Topic:     originalTopic(message),
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		Key:       message.Key,
+		Value:     message.Value,
+		Attempts:  attempts,
+		Decode:    decode,
+		Err:       cause,
+	}
+	if c.opts.ErrorHandler != nil {
+		c.opts.ErrorHandler(ctx, merr)
+	}
+
+	policy := c.opts.failurePolicy()
+	if decode && policy == PolicyRetry {
+		// 解码失败重试没有意义，退化为死信或跳过
+		policy = PolicySkip
+		if c.opts.DeadLetterTopic != "" {
+			policy = PolicyDeadLetter
+		}
+	}
+	switch policy {
+	case PolicyDeadLetter:
+		return c.deadLetter(message, cause, attempts)
+	case PolicyStop:
+		c.stop(merr)
+		return merr
+	default:
+		if c.opts.ErrorHandler == nil {
+			logger.Errorw(ctx, "kafka message skipped", "group", c.groupID, "error", merr.Error())
+		}
+		return nil
+	}
+}
+
+// stop 记录致命错误并停止消费
+func (c *Consumer[T]) stop(err error) {
+	c.mu.Lock()
+	if c.fatal == nil {
+		c.fatal = err
+	}
+	cancel := c.cancel
+	c.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (c *Consumer[T]) fatalErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fatal
+}
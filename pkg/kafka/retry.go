@@ -0,0 +1,97 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// 重试消息附带的信息头
+const (
+	HeaderRetryOriginalTopic = "x-retry-original-topic"
+	HeaderRetryError         = "x-retry-error"
+)
+
+func retryTopicName(topic string, level int) string {
+	return fmt.Sprintf("%s.retry.%d", topic, level)
+}
+
+// retryBackoff 第 attempt 次失败后的等待时间
+func (c *Consumer[T]) retryBackoff(attempt int) time.Duration {
+	d := c.opts.RetryBackoff
+	for i := 1; i < attempt && d < c.opts.RetryBackoffMax; i++ {
+		d *= 2
+	}
+	if d > c.opts.RetryBackoffMax {
+		d = c.opts.RetryBackoffMax
+	}
+	return d
+}
+
+// publishRetry 将处理失败的消息投递到第 level 级重试 topic
+func (c *Consumer[T]) publishRetry(message *sarama.ConsumerMessage, cause error, level int) error {
+	delay := c.opts.RetryDelays[level-1]
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+3)
+	for _, h := range message.Headers {
+		if h == nil {
+			continue
+		}
+		switch string(h.Key) {
+		case HeaderRetryError, HeaderDeliverAt, HeaderRetryOriginalTopic:
+			continue
+		}
+		headers = append(headers, *h)
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderRetryOriginalTopic), Value: []byte(originalTopic(message))},
+		sarama.RecordHeader{Key: []byte(HeaderRetryError), Value: []byte(cause.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderDeliverAt), Value: []byte(strconv.FormatInt(time.Now().Add(delay).UnixMilli(), 10))},
+	)
+	retryTopic := retryTopicName(originalTopic(message), level)
+	_, _, err := c.sender.SendMessage(&sarama.ProducerMessage{
+		Topic:   retryTopic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("kafka: send to retry topic %s: %w", retryTopic, err)
+	}
+	return nil
+}
+
+// retryAt 读取重试消息的预定处理时间，缺失时按消息时间加延迟计算
+func retryAt(message *sarama.ConsumerMessage, delay time.Duration) time.Time {
+	if t, ok := deliverAt(message); ok {
+		return t
+	}
+	return message.Timestamp.Add(delay)
+}
+
+// originalTopic 返回消息最初所在的 topic
+func originalTopic(message *sarama.ConsumerMessage) string {
+	for _, h := range message.Headers {
+		if h != nil && string(h.Key) == HeaderRetryOriginalTopic {
+			return string(h.Value)
+		}
+	}
+	return message.Topic
+}
+
+// sleepContext 等待 d 或 ctx 结束
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,60 @@
+package kafka
+
+import "context"
+
+// route 单个 topic 的解码和处理逻辑
+type route interface {
+	decode(codec Codec, topic string, data []byte) (interface{}, error)
+	invoke(ctx context.Context, obj interface{}) error
+}
+
+// typedRoute 将消息解码为 *T 后交给 handler
+type typedRoute[T any] struct {
+	handler func(context.Context, *T) error
+}
+
+func (r typedRoute[T]) decode(codec Codec, topic string, data []byte) (interface{}, error) {
+	obj := new(T)
+	if err := codec.Unmarshal(topic, data, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (r typedRoute[T]) invoke(ctx context.Context, obj interface{}) error {
+	return r.handler(ctx, obj.(*T))
+}
+
+// Router 按 topic 注册 handler，一个消费组即可订阅多个 topic，每个 topic 使用各自的消息类型
+type Router struct {
+	routes map[string]route
+}
+
+// NewRouter 创建 topic 路由表
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]route)}
+}
+
+// Handle 注册 topic 的 handler，消息按 T 解码，重复注册时覆盖
+func Handle[T any](r *Router, topic string, handler func(context.Context, *T) error) {
+	r.routes[topic] = typedRoute[T]{handler: handler}
+}
+
+// Topics 返回已注册的 topic
+func (r *Router) Topics() []string {
+	topics := make([]string, 0, len(r.routes))
+	for topic := range r.routes {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// NewConsumerGroupWithRouter 创建订阅 router 中全部 topic 的消费者但不启动，调用 Run 开始消费。
+// 消息类型由各 topic 注册时决定，与 Kafka 的类型参数无关，可使用 New[any] 创建客户端。
+func (k *Kafka[T]) NewConsumerGroupWithRouter(group string, router *Router, opts ConsumerOptions) (*Consumer[T], error) {
+	routes := make(map[string]route, len(router.routes))
+	for topic, r := range router.routes {
+		routes[topic] = r
+	}
+	return k.newConsumer(group, routes, opts)
+}
@@ -0,0 +1,247 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+// Schema Registry 支持的 schema 类型
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOBUF"
+	SchemaTypeJSON     = "JSON"
+)
+
+// Confluent 线上格式的魔数
+const confluentMagicByte byte = 0
+
+// SchemaRegistryConfig Confluent Schema Registry 连接配置
+type SchemaRegistryConfig struct {
+	URL      string        `mapstructure:"url"`      // 地址，如 http://localhost:8081
+	Username string        `mapstructure:"username"` // Basic 认证用户名
+	Password string        `mapstructure:"password"` // Basic 认证密码
+	Timeout  time.Duration `mapstructure:"timeout"`  // 请求超时，默认 10s
+}
+
+// SchemaRegistry Confluent Schema Registry 客户端，按 subject 注册 schema 并按 ID 查询，结果本地缓存
+type SchemaRegistry struct {
+	cfg    SchemaRegistryConfig
+	client *http.Client
+
+	mu      sync.RWMutex
+	ids     map[string]int // subject + schema -> id
+	schemas map[int]string // id -> schema
+	parsed  map[int]avro.Schema
+}
+
+// NewSchemaRegistry 创建 Schema Registry 客户端
+func NewSchemaRegistry(cfg SchemaRegistryConfig) *SchemaRegistry {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &SchemaRegistry{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		ids:     make(map[string]int),
+		schemas: make(map[int]string),
+		parsed:  make(map[int]avro.Schema),
+	}
+}
+
+// Register 在 subject 下注册 schema 并返回 ID，已存在时返回已有 ID
+func (r *SchemaRegistry) Register(ctx context.Context, subject, schema, schemaType string) (int, error) {
+	key := subject + "\x00" + schema
+	r.mu.RLock()
+	id, ok := r.ids[key]
+	r.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	body := map[string]string{"schema": schema}
+	if schemaType != "" && schemaType != SchemaTypeAvro {
+		body["schemaType"] = schemaType
+	}
+	var resp struct {
+		ID int `json:"id"`
+	}
+	if err := r.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", body, &resp); err != nil {
+		return 0, fmt.Errorf("kafka: register schema for subject %s: %w", subject, err)
+	}
+
+	r.mu.Lock()
+	r.ids[key] = resp.ID
+	r.schemas[resp.ID] = schema
+	r.mu.Unlock()
+	return resp.ID, nil
+}
+
+// Schema 按 ID 查询 schema 文本
+func (r *SchemaRegistry) Schema(ctx context.Context, id int) (string, error) {
+	r.mu.RLock()
+	schema, ok := r.schemas[id]
+	r.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+	var resp struct {
+		Schema string `json:"schema"`
+	}
+	if err := r.do(ctx, http.MethodGet, fmt.Sprintf("/schemas/ids/%d", id), nil, &resp); err != nil {
+		return "", fmt.Errorf("kafka: get schema %d: %w", id, err)
+	}
+	r.mu.Lock()
+	r.schemas[id] = resp.Schema
+	r.mu.Unlock()
+	return resp.Schema, nil
+}
+
+// avroSchema 按 ID 获取并解析 Avro schema
+func (r *SchemaRegistry) avroSchema(ctx context.Context, id int) (avro.Schema, error) {
+	r.mu.RLock()
+	s, ok := r.parsed[id]
+	r.mu.RUnlock()
+	if ok {
+		return s, nil
+	}
+	text, err := r.Schema(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s, err = avro.Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: parse avro schema %d: %w", id, err)
+	}
+	r.mu.Lock()
+	r.parsed[id] = s
+	r.mu.Unlock()
+	return s, nil
+}
+
+func (r *SchemaRegistry) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(r.cfg.URL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if r.cfg.Username != "" {
+		req.SetBasicAuth(r.cfg.Username, r.cfg.Password)
+	}
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("status %d: %s", res.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, out)
+}
+
+// RegistryCodec 按 Confluent 线上格式（魔数 + 4 字节 schema ID + 负载）编解码，可与 Java 生态的序列化器互通。
+// 写入时以 <topic>-value 为 subject 注册 Schema；读取 Avro 时使用消息中 ID 对应的写入方 schema 解码。
+type RegistryCodec struct {
+	Registry   *SchemaRegistry
+	Inner      Codec  // 负载编码，需与 SchemaType 对应
+	Schema     string // 写入方 schema 文本
+	SchemaType string // AVRO / PROTOBUF / JSON
+}
+
+// NewAvroRegistryCodec 创建基于 Schema Registry 的 Avro 编码
+func NewAvroRegistryCodec(registry *SchemaRegistry, schema string) (*RegistryCodec, error) {
+	inner, err := NewAvroCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistryCodec{Registry: registry, Inner: inner, Schema: schema, SchemaType: SchemaTypeAvro}, nil
+}
+
+// NewProtobufRegistryCodec 创建基于 Schema Registry 的 Protobuf 编码，schema 为 .proto 文件内容，消息为文件中的第一个 message
+func NewProtobufRegistryCodec(registry *SchemaRegistry, schema string) *RegistryCodec {
+	return &RegistryCodec{Registry: registry, Inner: ProtobufCodec{}, Schema: schema, SchemaType: SchemaTypeProtobuf}
+}
+
+func (c *RegistryCodec) Marshal(topic string, v interface{}) ([]byte, error) {
+	id, err := c.Registry.Register(context.Background(), topic+"-value", c.Schema, c.SchemaType)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := c.Inner.Marshal(topic, v)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 5, 6+len(payload))
+	buf[0] = confluentMagicByte
+	binary.BigEndian.PutUint32(buf[1:], uint32(id))
+	if c.SchemaType == SchemaTypeProtobuf {
+		// 消息索引数组，[0] 简写为单个 0 字节，表示文件中第一个 message
+		buf = append(buf, 0)
+	}
+	return append(buf, payload...), nil
+}
+
+func (c *RegistryCodec) Unmarshal(topic string, data []byte, v interface{}) error {
+	if len(data) < 5 || data[0] != confluentMagicByte {
+		return errors.New("kafka: message is not in schema registry wire format")
+	}
+	id := int(binary.BigEndian.Uint32(data[1:5]))
+	payload := data[5:]
+	switch c.SchemaType {
+	case SchemaTypeAvro:
+		schema, err := c.Registry.avroSchema(context.Background(), id)
+		if err != nil {
+			return err
+		}
+		return avro.Unmarshal(schema, payload, v)
+	case SchemaTypeProtobuf:
+		rest, err := skipMessageIndexes(payload)
+		if err != nil {
+			return err
+		}
+		return c.Inner.Unmarshal(topic, rest, v)
+	default:
+		return c.Inner.Unmarshal(topic, payload, v)
+	}
+}
+
+// skipMessageIndexes 跳过 Protobuf 负载前的消息索引数组（zigzag varint 编码）
+func skipMessageIndexes(data []byte) ([]byte, error) {
+	count, n := binary.Varint(data)
+	if n <= 0 || count < 0 {
+		return nil, errors.New("kafka: invalid protobuf message indexes")
+	}
+	data = data[n:]
+	for i := int64(0); i < count; i++ {
+		_, n = binary.Varint(data)
+		if n <= 0 {
+			return nil, errors.New("kafka: invalid protobuf message indexes")
+		}
+		data = data[n:]
+	}
+	return data, nil
+}
@@ -0,0 +1,77 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// buildTLSConfig 根据配置加载 CA、客户端证书和私钥，未启用 TLS 时返回 nil
+func buildTLSConfig(cfg *TLSConfig) (*tls.Config, error) {
+	if !cfg.EnableTLS {
+		return nil, nil
+	}
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CACert != "" {
+		caPEM, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("kafka tls: read ca cert %s: %w", cfg.CACert, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("kafka tls: no valid certificate found in ca cert %s", cfg.CACert)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, fmt.Errorf("kafka tls: client cert and client key must be configured together")
+		}
+		cert, err := loadClientCert(cfg.ClientCert, cfg.ClientKey, cfg.ClientKeyPassword)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
+
+// loadClientCert 加载客户端证书，私钥为加密 PEM 时使用 password 解密
+func loadClientCert(certFile, keyFile, password string) (tls.Certificate, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("kafka tls: read client cert %s: %w", certFile, err)
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("kafka tls: read client key %s: %w", keyFile, err)
+	}
+
+	if password != "" {
+		block, _ := pem.Decode(keyPEM)
+		if block == nil {
+			return tls.Certificate{}, fmt.Errorf("kafka tls: no PEM data found in client key %s", keyFile)
+		}
+		// 传统加密 PEM 私钥（Proc-Type: 4,ENCRYPTED），标准库已标记废弃但没有替代实现
+		if x509.IsEncryptedPEMBlock(block) {
+			der, err := x509.DecryptPEMBlock(block, []byte(password))
+			if err != nil {
+				return tls.Certificate{}, fmt.Errorf("kafka tls: decrypt client key %s: %w", keyFile, err)
+			}
+			keyPEM = pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der})
+		}
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("kafka tls: load client key pair: %w", err)
+	}
+	return cert, nil
+}
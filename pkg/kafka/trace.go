@@ -0,0 +1,77 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"github.com/code-sigs/go-box/pkg/trace"
+)
+
+// 链路追踪相关消息头
+const (
+	HeaderTraceID     = "x-trace-id"
+	HeaderTraceparent = "traceparent"
+)
+
+// producerCarrier 适配生产消息头，调用方已显式设置的头不覆盖
+type producerCarrier struct {
+	msg *sarama.ProducerMessage
+}
+
+func (c producerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c producerCarrier) Set(key, value string) {
+	if c.Get(key) != "" {
+		return
+	}
+	c.msg.Headers = append(c.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c producerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		keys = append(keys, string(h.Key))
+	}
+	return keys
+}
+
+// consumerCarrier 适配消费消息头，只读
+type consumerCarrier []*sarama.RecordHeader
+
+func (c consumerCarrier) Get(key string) string {
+	for _, h := range c {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c consumerCarrier) Set(string, string) {}
+
+func (c consumerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for _, h := range c {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+	return keys
+}
+
+// injectTrace 将 ctx 中的链路信息（traceparent / baggage / x-trace-id）写入消息头，调用方已显式设置时不覆盖
+func injectTrace(ctx context.Context, msg *sarama.ProducerMessage) {
+	trace.Inject(ctx, producerCarrier{msg: msg})
+}
+
+// extractTrace 从消息头恢复链路，traceparent 作为远端父 span，x-trace-id 作为兼容的 traceID
+func extractTrace(ctx context.Context, headers []*sarama.RecordHeader) context.Context {
+	return trace.Extract(ctx, consumerCarrier(headers))
+}
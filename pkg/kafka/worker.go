@@ -0,0 +1,122 @@
+package kafka
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"github.com/code-sigs/go-box/pkg/utils"
+)
+
+// pendingMessage 已分发但未确认的消息
+type pendingMessage struct {
+	msg  *sarama.ConsumerMessage
+	done bool
+}
+
+// offsetTracker 按分发顺序记录消息，只提交连续完成的前缀，保证不会跳过未处理完的消息
+type offsetTracker struct {
+	mu      sync.Mutex
+	sess    sarama.ConsumerGroupSession
+	pending []*pendingMessage
+}
+
+func (t *offsetTracker) add(msg *sarama.ConsumerMessage) *pendingMessage {
+	p := &pendingMessage{msg: msg}
+	t.mu.Lock()
+	t.pending = append(t.pending, p)
+	t.mu.Unlock()
+	return p
+}
+
+func (t *offsetTracker) complete(p *pendingMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p.done = true
+	n := 0
+	for n < len(t.pending) && t.pending[n].done {
+		n++
+	}
+	if n > 0 {
+		t.sess.MarkMessage(t.pending[n-1].msg, "")
+		t.pending = t.pending[n:]
+	}
+}
+
+// consumeParallel 使用协程池处理单个分区的消息
+func (c *Consumer[T]) consumeParallel(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	// 处理协程 panic 会转为错误，首个错误取消整个分区的消费
+	g, ctx := utils.NewGroup(sess.Context(), 0)
+	tracker := &offsetTracker{sess: sess}
+	n := c.opts.Concurrency
+	queues := make([]chan *pendingMessage, n)
+	for i := range queues {
+		queues[i] = make(chan *pendingMessage, 1)
+	}
+	// 完全并行时所有协程共享一个队列
+	if c.opts.Unordered {
+		for i := range queues {
+			queues[i] = queues[0]
+		}
+	}
+	for i := 0; i < n; i++ {
+		queue := queues[i]
+		g.Go(func(ctx context.Context) error {
+			for p := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := c.handleMessage(ctx, p.msg); err != nil {
+					return err
+				}
+				tracker.complete(p)
+			}
+			return nil
+		})
+	}
+
+	dispatch := func() {
+		for {
+			select {
+			case message, ok := <-claim.Messages():
+				if !ok {
+					return
+				}
+				c.metrics.setLag(c.groupID, message.Topic, message.Partition, claim.HighWaterMarkOffset()-message.Offset-1)
+				p := tracker.add(message)
+				queue := queues[0]
+				if !c.opts.Unordered {
+					queue = queues[keySlot(message.Key, n)]
+				}
+				select {
+				case queue <- p:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+	dispatch()
+
+	closed := make(map[chan *pendingMessage]bool, n)
+	for _, q := range queues {
+		if !closed[q] {
+			closed[q] = true
+			close(q)
+		}
+	}
+	if err := g.Wait(); err != nil && sess.Context().Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// keySlot 按消息 key 选择处理协程，相同 key 总是落在同一协程
+func keySlot(key []byte, n int) int {
+	h := fnv.New32a()
+	h.Write(key)
+	return int(h.Sum32() % uint32(n))
+}
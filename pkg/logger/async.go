@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"io"
+	"sync"
+)
+
+// 异步队列满时的处理策略
+const (
+	AsyncDrop  = "drop"  // 丢弃新日志，不阻塞业务
+	AsyncBlock = "block" // 阻塞等待队列有空位
+)
+
+// AsyncOptions 异步写入参数
+type AsyncOptions struct {
+	QueueSize int    // 队列长度，默认 8192
+	Policy    string // 队列满时的策略：drop / block，默认 block
+}
+
+// WithAsync 文件和终端输出改为后台异步写入，退出前需调用 Close 刷新
+func WithAsync(opts AsyncOptions) Option {
+	return func(o *options) { o.async = &opts }
+}
+
+// asyncWriter 通过有界队列将写入转到后台 goroutine
+type asyncWriter struct {
+	w       io.Writer
+	drop    bool
+	queue   chan []byte
+	syncReq chan chan struct{}
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newAsyncWriter(w io.Writer, opts AsyncOptions) *asyncWriter {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 8192
+	}
+	a := &asyncWriter{
+		w:       w,
+		drop:    opts.Policy == AsyncDrop,
+		queue:   make(chan []byte, opts.QueueSize),
+		syncReq: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Write zap 会复用 p，入队前需要拷贝；关闭后直接同步写入
+func (a *asyncWriter) Write(p []byte) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return a.w.Write(p)
+	}
+	entry := append([]byte(nil), p...)
+	if !a.drop {
+		a.queue <- entry
+		return len(p), nil
+	}
+	select {
+	case a.queue <- entry:
+	default:
+	}
+	return len(p), nil
+}
+
+// Sync 等待已入队的日志写完
+func (a *asyncWriter) Sync() error {
+	ack := make(chan struct{})
+	select {
+	case a.syncReq <- ack:
+		<-ack
+	case <-a.done:
+	}
+	if s, ok := a.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close 写完剩余日志后停止后台 goroutine
+func (a *asyncWriter) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+	<-a.done
+	return nil
+}
+
+func (a *asyncWriter) run() {
+	defer close(a.done)
+	for {
+		select {
+		case entry, ok := <-a.queue:
+			if !ok {
+				return
+			}
+			_, _ = a.w.Write(entry)
+		case ack := <-a.syncReq:
+			for n := len(a.queue); n > 0; n-- {
+				_, _ = a.w.Write(<-a.queue)
+			}
+			close(ack)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+)
+
+// ContextField 描述一个从 ctx 中提取并附加到日志的字段
+type ContextField struct {
+	Key  interface{} // ctx.Value 使用的键
+	Name string      // 日志字段名，为空时使用 Key 的字符串形式
+}
+
+// WithContextKeys 按字符串键从 ctx 提取字段，字段名与键相同，
+// 与 rpc.RPCServerInterceptor 写入 ctx 的 metadata 键（如 user-id、tenant-id、request-id）一致
+func WithContextKeys(keys ...string) Option {
+	return func(o *options) {
+		for _, k := range keys {
+			o.contextFields = append(o.contextFields, ContextField{Key: k, Name: k})
+		}
+	}
+}
+
+// WithContextFields 注册自定义类型键的 ctx 字段
+func WithContextFields(fields ...ContextField) Option {
+	return func(o *options) { o.contextFields = append(o.contextFields, fields...) }
+}
+
+// contextKVs 提取已注册的 ctx 字段，值为空的字段跳过
+func contextKVs(ctx context.Context, fields []ContextField) []interface{} {
+	if ctx == nil || len(fields) == 0 {
+		return nil
+	}
+	kvs := make([]interface{}, 0, len(fields)*2)
+	for _, f := range fields {
+		v := ctx.Value(f.Key)
+		if v == nil {
+			continue
+		}
+		if s, ok := v.(string); ok && s == "" {
+			continue
+		}
+		name := f.Name
+		if name == "" {
+			name = fmt.Sprint(f.Key)
+		}
+		kvs = append(kvs, name, v)
+	}
+	return kvs
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrorE 记录错误日志，err 为 errs.WrapError 或自带堆栈时，以 %+v 格式将完整错误链写入 stack 字段
+func ErrorE(ctx context.Context, msg string, err error, kvs ...interface{}) {
+	Default().logWithTrace(ctx).Errorw(msg, append(kvs, errorKVs(err)...)...)
+}
+
+// ErrorE 同包级 ErrorE
+func (l *Logger) ErrorE(ctx context.Context, msg string, err error, kvs ...interface{}) {
+	l.logWithTrace(ctx).Errorw(msg, append(kvs, errorKVs(err)...)...)
+}
+
+// errorKVs 生成 error 和 stack 字段
+func errorKVs(err error) []interface{} {
+	if err == nil {
+		return nil
+	}
+	kvs := []interface{}{"error", err.Error()}
+	if f := stackError(err); f != nil {
+		kvs = append(kvs, "stack", fmt.Sprintf("%+v", f))
+	}
+	return kvs
+}
+
+// stackError 返回错误链中第一个自定义了 %+v 输出的错误，
+// errs.WrapError 和 github.com/pkg/errors 等携带调用位置的错误都实现了 fmt.Formatter
+func stackError(err error) error {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if _, ok := e.(fmt.Formatter); ok {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/trace"
+	"github.com/gin-gonic/gin"
+)
+
+// traceHeader 请求头中的 traceID，与 trace 包在 ctx 中使用的键一致
+const traceHeader = "x-trace-id"
+
+// GinLogger 返回记录结构化访问日志的 gin 中间件，5xx 记为 error、4xx 记为 warn
+func GinLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		c.Next()
+
+		ctx := c.Request.Context()
+		if trace.GetTraceID(ctx) == "" {
+			if traceID := c.GetHeader(traceHeader); traceID != "" {
+				ctx = trace.WithTraceID(ctx, traceID)
+			}
+		}
+		status := c.Writer.Status()
+		kvs := []interface{}{
+			"method", c.Request.Method,
+			"path", path,
+			"status", status,
+			"latency", time.Since(start).String(),
+			"clientIP", c.ClientIP(),
+		}
+		if len(c.Errors) > 0 {
+			kvs = append(kvs, "errors", c.Errors.String())
+		}
+		l := Default().logWithTrace(ctx)
+		switch {
+		case status >= 500:
+			l.Errorw("http access", kvs...)
+		case status >= 400:
+			l.Warnw("http access", kvs...)
+		default:
+			l.Infow("http access", kvs...)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor 返回记录结构化访问日志的 gRPC 一元服务端拦截器
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRPC(incomingTrace(ctx), "grpc server", info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 返回记录结构化访问日志的 gRPC 流式服务端拦截器
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logRPC(incomingTrace(ss.Context()), "grpc server", info.FullMethod, start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor 返回记录调用日志的 gRPC 一元客户端拦截器
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logRPC(ctx, "grpc client", method, start, err)
+		return err
+	}
+}
+
+// incomingTrace ctx 中没有 traceID 时从请求 metadata 恢复
+func incomingTrace(ctx context.Context) context.Context {
+	if trace.GetTraceID(ctx) != "" {
+		return ctx
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(traceHeader); len(values) > 0 && values[0] != "" {
+			return trace.WithTraceID(ctx, values[0])
+		}
+	}
+	return ctx
+}
+
+// logRPC 按状态码记录 gRPC 访问日志，服务端错误记为 error、客户端错误记为 warn
+func logRPC(ctx context.Context, msg, method string, start time.Time, err error) {
+	code := status.Code(err)
+	kvs := []interface{}{
+		"method", method,
+		"status", code.String(),
+		"latency", time.Since(start).String(),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		kvs = append(kvs, "peer", p.Addr.String())
+	}
+	if err != nil {
+		kvs = append(kvs, "error", err.Error())
+	}
+	l := Default().logWithTrace(ctx)
+	switch code {
+	case codes.OK:
+		l.Infow(msg, kvs...)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded, codes.Unimplemented:
+		l.Errorw(msg, kvs...)
+	default:
+		l.Warnw(msg, kvs...)
+	}
+}
@@ -2,26 +2,42 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/code-sigs/go-box/pkg/trace"
 	rotatelogs "github.com/lestrrat-go/file-rotatelogs"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var (
-	zlogger *zap.Logger
-)
-
 type options struct {
 	logLevel     string
 	maxAgeDays   int
-	enableStdout bool // 新增：是否输出到终端
+	maxSizeMB    int    // 单个文件达到该大小（MB）时切割，0 仅按天切割
+	maxBackups   int    // 保留的历史文件数，设置后按个数清理并忽略 maxAgeDays
+	enableStdout bool   // 新增：是否输出到终端
+	encoder      string // 日志编码：json / console，为空时文件为 json、终端为 console
+
+	compress       bool // 切割后 gzip 压缩历史文件
+	maxTotalSizeMB int  // 日志文件总大小上限（MB），超出时从最旧的文件开始删除
+
+	samplingInitial    int // 每秒内相同级别和内容的日志前 N 条全部输出，0 不采样
+	samplingThereafter int // 超过 samplingInitial 后每 M 条输出 1 条
+
+	sinks  []sinkConfig   // 远程日志投递目标
+	redact *RedactOptions // 脱敏配置，nil 不脱敏
+
+	contextFields []ContextField // 自动从 ctx 提取的字段
+	async         *AsyncOptions  // 异步写入配置，nil 同步写入
 }
 
 type Option func(*options)
@@ -34,16 +50,102 @@ func WithMaxAge(days int) Option {
 	return func(o *options) { o.maxAgeDays = days }
 }
 
+// WithMaxSize 单个日志文件超过 sizeMB 时立即切割，与按天切割同时生效
+func WithMaxSize(sizeMB int) Option {
+	return func(o *options) { o.maxSizeMB = sizeMB }
+}
+
+// WithMaxBackups 最多保留 n 个历史日志文件，设置后不再按 WithMaxAge 清理
+func WithMaxBackups(n int) Option {
+	return func(o *options) { o.maxBackups = n }
+}
+
+// WithCompress 切割后将历史日志文件压缩为 .gz
+func WithCompress(enable bool) Option {
+	return func(o *options) { o.compress = enable }
+}
+
+// WithMaxTotalSize 日志文件（压缩后）总大小超过 sizeMB 时从最旧的文件开始删除
+func WithMaxTotalSize(sizeMB int) Option {
+	return func(o *options) { o.maxTotalSizeMB = sizeMB }
+}
+
 // 新增：配置是否输出到终端
 func WithStdout(enable bool) Option {
 	return func(o *options) { o.enableStdout = enable }
 }
 
+// WithSampling 开启日志采样：每秒内相同级别和内容的日志先输出 initial 条，之后每 thereafter 条输出 1 条，
+// 用于抑制高频重复日志（如消费重试告警）
+func WithSampling(initial, thereafter int) Option {
+	return func(o *options) {
+		o.samplingInitial = initial
+		o.samplingThereafter = thereafter
+	}
+}
+
+// WithEncoder 配置文件和终端统一使用的日志编码：json / console
+func WithEncoder(encoder string) Option {
+	return func(o *options) { o.encoder = encoder }
+}
+
+// Logger 日志实例，库代码可持有自己的实例而不影响全局日志
+type Logger struct {
+	z             *zap.Logger
+	contextFields []ContextField // 自动附加到日志的 ctx 字段
+	closers       []io.Closer    // 异步写入端，Close 时关闭
+}
+
+var (
+	std     atomic.Pointer[Logger] // 包级函数使用的默认实例
+	initMu  sync.Mutex
+	initted *Logger // 最近一次 Init 创建的实例
+)
+
 func init() {
-	Init("./logs") // 默认路径
+	// 未调用 Init 前只输出到终端，导入包时不创建任何目录
+	std.Store(newStdoutLogger())
 }
 
-func Init(logDir string, opts ...Option) {
+// newStdoutLogger 创建仅输出到终端的默认实例
+func newStdoutLogger() *Logger {
+	core := zapcore.NewCore(zapcore.NewConsoleEncoder(newEncoderConfig()), zapcore.AddSync(os.Stdout), zapcore.InfoLevel)
+	return &Logger{z: zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))}
+}
+
+// Init 创建写入 logDir 的日志实例并设为默认实例，之前由 Init 创建的实例会被关闭
+func Init(logDir string, opts ...Option) *Logger {
+	l, err := New(logDir, opts...)
+	if err != nil {
+		panic(err.Error())
+	}
+	initMu.Lock()
+	old := initted
+	initted = l
+	initMu.Unlock()
+
+	std.Store(l)
+	if old != nil {
+		// 剩余日志会先写完
+		_ = old.Close()
+	}
+	return l
+}
+
+// Default 返回包级函数使用的默认实例
+func Default() *Logger {
+	return std.Load()
+}
+
+// SetDefault 替换包级函数使用的默认实例
+func SetDefault(l *Logger) {
+	if l != nil {
+		std.Store(l)
+	}
+}
+
+// New 创建写入 logDir 的日志实例，不修改默认实例
+func New(logDir string, opts ...Option) (*Logger, error) {
 	// 设置默认值
 	conf := &options{
 		logLevel:     "info",
@@ -53,51 +155,145 @@ func Init(logDir string, opts ...Option) {
 	for _, opt := range opts {
 		opt(conf)
 	}
+
+	var r *redactor
+	if conf.redact != nil {
+		var err error
+		if r, err = newRedactor(*conf.redact); err != nil {
+			return nil, fmt.Errorf("failed to create redactor: %w", err)
+		}
+	}
 	if err := os.MkdirAll(logDir, os.ModePerm); err != nil {
-		panic(fmt.Sprintf("failed to create log directory: %v", err))
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
 
-	writer, err := rotatelogs.New(
-		filepath.Join(logDir, "app-%Y-%m-%d.log"),
-		rotatelogs.WithLinkName(filepath.Join(logDir, "latest.log")),
-		rotatelogs.WithMaxAge(time.Duration(conf.maxAgeDays)*24*time.Hour),
-		rotatelogs.WithRotationTime(24*time.Hour),
-	)
+	writer, err := newRotateWriter(conf, filepath.Join(logDir, "app-%Y-%m-%d.log"),
+		rotatelogs.WithLinkName(filepath.Join(logDir, "latest.log")))
 	if err != nil {
-		panic(fmt.Sprintf("failed to create rotatelogs: %v", err))
+		return nil, fmt.Errorf("failed to create rotatelogs: %w", err)
+	}
+	var fallback io.Writer
+	if len(conf.sinks) > 0 {
+		if fallback, err = newRotateWriter(conf, filepath.Join(logDir, "sink-fallback-%Y-%m-%d.log")); err != nil {
+			return nil, fmt.Errorf("failed to create sink fallback log: %w", err)
+		}
 	}
 
-	encoderConfig := zapcore.EncoderConfig{
-		TimeKey:      "ts",
-		LevelKey:     "level",
-		MessageKey:   "msg",
-		CallerKey:    "caller",
-		EncodeLevel:  zapcore.CapitalLevelEncoder,
-		EncodeTime:   zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05"),
-		EncodeCaller: shortCallerEncoder,
+	encoderConfig := newEncoderConfig()
+	l := &Logger{contextFields: conf.contextFields}
+	output := func(w io.Writer) zapcore.WriteSyncer {
+		if conf.async == nil {
+			return zapcore.AddSync(w)
+		}
+		a := newAsyncWriter(w, *conf.async)
+		l.closers = append(l.closers, a)
+		return a
 	}
 
 	level := parseLevel(conf.logLevel)
 	fileCore := zapcore.NewCore(
-		//zapcore.NewConsoleEncoder(encoderConfig),
-		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.AddSync(writer),
+		newEncoder(conf.encoder, "json", encoderConfig),
+		output(writer),
 		level,
 	)
 
-	var core zapcore.Core
+	cores := []zapcore.Core{fileCore}
 	if conf.enableStdout {
-		consoleCore := zapcore.NewCore(
-			zapcore.NewConsoleEncoder(encoderConfig),
-			zapcore.AddSync(os.Stdout),
+		cores = append(cores, zapcore.NewCore(
+			newEncoder(conf.encoder, "console", encoderConfig),
+			output(os.Stdout),
 			level,
-		)
-		core = zapcore.NewTee(fileCore, consoleCore)
-	} else {
-		core = fileCore
+		))
+	}
+
+	for _, sc := range conf.sinks {
+		sinkLevel := level
+		if sc.opts.Level != "" {
+			sinkLevel = parseLevel(sc.opts.Level)
+		}
+		w := newSinkWriter(sc.sink, fallback, sc.opts)
+		l.closers = append(l.closers, w)
+		// 远程目标统一使用 JSON，便于检索
+		cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), w, sinkLevel))
+	}
+
+	if r != nil {
+		// 逐个包装输出端，保留各自的级别过滤
+		for i := range cores {
+			cores[i] = &redactCore{Core: cores[i], r: r}
+		}
+	}
+
+	core := zapcore.NewTee(cores...)
+	if conf.samplingInitial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, conf.samplingInitial, conf.samplingThereafter)
+	}
+
+	l.z = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	return l, nil
+}
+
+// With 返回附加了固定字段的子实例，与父实例共用输出
+func (l *Logger) With(kvs ...interface{}) *Logger {
+	child := *l
+	child.z = l.z.Sugar().With(kvs...).Desugar()
+	return &child
+}
+
+// Zap 返回底层 zap.Logger
+func (l *Logger) Zap() *zap.Logger {
+	return l.z
+}
+
+// Sync 刷新缓冲的日志，包括等待异步队列和远程投递目标写完已入队的日志
+func (l *Logger) Sync() error {
+	return l.z.Sync()
+}
+
+// Close 写完所有异步队列中的日志并关闭远程投递目标，应在进程退出前调用。
+// 关闭后的日志改为同步写入本地，不会丢失
+func (l *Logger) Close() error {
+	_ = l.z.Sync()
+	var errs []error
+	for _, c := range l.closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
 	}
+	return errors.Join(errs...)
+}
 
-	zlogger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+// Sync 刷新默认实例
+func Sync() error {
+	return Default().Sync()
+}
+
+// Close 关闭默认实例
+func Close() error {
+	return Default().Close()
+}
+
+func newEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:      "ts",
+		LevelKey:     "level",
+		MessageKey:   "msg",
+		CallerKey:    "caller",
+		EncodeLevel:  zapcore.CapitalLevelEncoder,
+		EncodeTime:   zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05"),
+		EncodeCaller: shortCallerEncoder,
+	}
+}
+
+// newEncoder 根据名称创建编码器，name 为空时使用 fallback
+func newEncoder(name, fallback string, cfg zapcore.EncoderConfig) zapcore.Encoder {
+	if name == "" {
+		name = fallback
+	}
+	if strings.ToLower(name) == "console" {
+		return zapcore.NewConsoleEncoder(cfg)
+	}
+	return zapcore.NewJSONEncoder(cfg)
 }
 
 // shortCallerEncoder 显示 caller 的上一级目录 + 文件名 + 行号
@@ -126,43 +322,85 @@ func parseLevel(level string) zapcore.Level {
 	}
 }
 
+func (l *Logger) Debugf(ctx context.Context, format string, args ...interface{}) {
+	l.logWithTrace(ctx).Debugf(format, args...)
+}
+
+func (l *Logger) Infof(ctx context.Context, format string, args ...interface{}) {
+	l.logWithTrace(ctx).Infof(format, args...)
+}
+
+func (l *Logger) Warnf(ctx context.Context, format string, args ...interface{}) {
+	l.logWithTrace(ctx).Warnf(format, args...)
+}
+
+func (l *Logger) Errorf(ctx context.Context, format string, args ...interface{}) {
+	l.logWithTrace(ctx).Errorf(format, args...)
+}
+
+func (l *Logger) Debugw(ctx context.Context, msg string, kvs ...interface{}) {
+	l.logWithTrace(ctx).Debugw(msg, kvs...)
+}
+
+func (l *Logger) Infow(ctx context.Context, msg string, kvs ...interface{}) {
+	l.logWithTrace(ctx).Infow(msg, kvs...)
+}
+
+func (l *Logger) Warnw(ctx context.Context, msg string, kvs ...interface{}) {
+	l.logWithTrace(ctx).Warnw(msg, kvs...)
+}
+
+func (l *Logger) Errorw(ctx context.Context, msg string, kvs ...interface{}) {
+	l.logWithTrace(ctx).Errorw(msg, kvs...)
+}
+
 func Debugf(ctx context.Context, format string, args ...interface{}) {
-	logWithTrace(ctx).Debugf(format, args...)
+	Default().logWithTrace(ctx).Debugf(format, args...)
 }
 
 func Infof(ctx context.Context, format string, args ...interface{}) {
-	logWithTrace(ctx).Infof(format, args...)
+	Default().logWithTrace(ctx).Infof(format, args...)
 }
 
 func Warnf(ctx context.Context, format string, args ...interface{}) {
-	logWithTrace(ctx).Warnf(format, args...)
+	Default().logWithTrace(ctx).Warnf(format, args...)
 }
 
 func Errorf(ctx context.Context, format string, args ...interface{}) {
-	logWithTrace(ctx).Errorf(format, args...)
+	Default().logWithTrace(ctx).Errorf(format, args...)
 }
 
 func Debugw(ctx context.Context, msg string, kvs ...interface{}) {
-	logWithTrace(ctx).Debugw(msg, kvs...)
+	Default().logWithTrace(ctx).Debugw(msg, kvs...)
 }
 
 func Infow(ctx context.Context, msg string, kvs ...interface{}) {
-	logWithTrace(ctx).Infow(msg, kvs...)
+	Default().logWithTrace(ctx).Infow(msg, kvs...)
 }
 
 func Warnw(ctx context.Context, msg string, kvs ...interface{}) {
-	logWithTrace(ctx).Warnw(msg, kvs...)
+	Default().logWithTrace(ctx).Warnw(msg, kvs...)
 }
 
 func Errorw(ctx context.Context, msg string, kvs ...interface{}) {
-	logWithTrace(ctx).Errorw(msg, kvs...)
+	Default().logWithTrace(ctx).Errorw(msg, kvs...)
 }
 
-// 提取 traceID 并注入到日志中
-func logWithTrace(ctx context.Context) *zap.SugaredLogger {
-	traceID := trace.GetTraceID(ctx)
-	if traceID != "" {
-		return zlogger.Sugar().With("traceID", traceID)
+// 提取 traceID 及已注册的 ctx 字段并注入到日志中
+func (l *Logger) logWithTrace(ctx context.Context) *zap.SugaredLogger {
+	if ctx == nil {
+		return l.z.Sugar()
+	}
+	kvs := contextKVs(ctx, l.contextFields)
+	if traceID := trace.GetTraceID(ctx); traceID != "" {
+		kvs = append([]interface{}{"traceID", traceID}, kvs...)
+	}
+	// 存在 OpenTelemetry span 时附加标准的 trace_id / span_id，便于从日志跳转到 Tempo、Jaeger
+	if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() {
+		kvs = append(kvs, "trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+	}
+	if len(kvs) == 0 {
+		return l.z.Sugar()
 	}
-	return zlogger.Sugar()
+	return l.z.Sugar().With(kvs...)
 }
@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultRedactKeys 默认脱敏的字段名模式
+var DefaultRedactKeys = []string{"password", "passwd", "secret", "token", "authorization", "id_?card", "credential"}
+
+// RedactOptions 脱敏配置
+type RedactOptions struct {
+	Keys   []string // 字段名模式（正则，不区分大小写），命中的字段值整体替换，为空使用 DefaultRedactKeys
+	Values []string // 值模式（正则），命中的片段被替换，如身份证号、手机号
+	Mask   string   // 替换文本，默认 "******"
+}
+
+// WithRedaction 开启脱敏，作用于结构化字段和格式化后的日志内容
+func WithRedaction(opts RedactOptions) Option {
+	return func(o *options) { o.redact = &opts }
+}
+
+// redactor 按字段名和值模式脱敏
+type redactor struct {
+	keys   *regexp.Regexp
+	inline *regexp.Regexp // 匹配消息中的 key=value / "key":"value" / Authorization: Bearer xxx
+	values []*regexp.Regexp
+	mask   string
+}
+
+func newRedactor(opts RedactOptions) (*redactor, error) {
+	keys := opts.Keys
+	if len(keys) == 0 {
+		keys = DefaultRedactKeys
+	}
+	alt := "(?:" + strings.Join(keys, "|") + ")"
+	r := &redactor{mask: opts.Mask}
+	if r.mask == "" {
+		r.mask = "******"
+	}
+	var err error
+	if r.keys, err = regexp.Compile("(?i)" + alt); err != nil {
+		return nil, fmt.Errorf("invalid redact key pattern: %w", err)
+	}
+	r.inline = regexp.MustCompile(`(?i)(\w*` + alt + `\w*["']?\s*[:=]\s*)` +
+		// 引号值整体脱敏到闭合引号；未加引号的值到空白或分隔符为止，带认证方案时连同其后的凭证一起脱敏
+		`("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|(` + authSchemes + `[ \t]+)?["']?[^\s"',;&}]+)`)
+	for _, v := range opts.Values {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact value pattern %q: %w", v, err)
+		}
+		r.values = append(r.values, re)
+	}
+	return r, nil
+}
+
+// authSchemes 常见的 HTTP 认证方案，Authorization: <scheme> <credential> 中保留方案、脱敏凭证
+const authSchemes = `(?:bearer|basic|digest|negotiate|ntlm|token|apikey|dpop|aws4-hmac-sha256)`
+
+// message 脱敏文本中的 key=value 片段和命中值模式的内容
+func (r *redactor) message(s string) string {
+	s = r.inline.ReplaceAllStringFunc(s, r.inlineValue)
+	for _, re := range r.values {
+		s = re.ReplaceAllString(s, r.mask)
+	}
+	return s
+}
+
+// inlineValue 替换单个 key=value 片段的值，保留键、引号和认证方案
+func (r *redactor) inlineValue(match string) string {
+	m := r.inline.FindStringSubmatch(match)
+	key, value, scheme := m[1], m[2], m[3]
+	switch {
+	case scheme != "":
+		return key + scheme + r.mask
+	case len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0]:
+		return key + value[:1] + r.mask + value[:1]
+	default:
+		return key + r.mask
+	}
+}
+
+func (r *redactor) fields(fields []zapcore.Field) []zapcore.Field {
+	if len(fields) == 0 {
+		return fields
+	}
+	out := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		out[i] = r.field(f)
+	}
+	return out
+}
+
+func (r *redactor) field(f zapcore.Field) zapcore.Field {
+	if r.keys.MatchString(f.Key) {
+		return zap.String(f.Key, r.mask)
+	}
+	switch f.Type {
+	case zapcore.StringType:
+		return zap.String(f.Key, r.message(f.String))
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			return zap.String(f.Key, r.message(err.Error()))
+		}
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok && s != nil {
+			return zap.String(f.Key, r.message(s.String()))
+		}
+	case zapcore.ReflectType, zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+		// 复杂对象先编码为通用结构，再逐层脱敏嵌套字段
+		var v interface{}
+		if f.Type == zapcore.ReflectType {
+			v = f.Interface
+		} else {
+			enc := zapcore.NewMapObjectEncoder()
+			f.AddTo(enc)
+			v = enc.Fields[f.Key]
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return f
+		}
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return f
+		}
+		return zap.Any(f.Key, r.value(generic))
+	}
+	return f
+}
+
+// value 递归脱敏 JSON 解码后的通用结构
+func (r *redactor) value(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			if r.keys.MatchString(k) {
+				t[k] = r.mask
+			} else {
+				t[k] = r.value(child)
+			}
+		}
+		return t
+	case []interface{}:
+		for i := range t {
+			t[i] = r.value(t[i])
+		}
+		return t
+	case string:
+		return r.message(t)
+	default:
+		return v
+	}
+}
+
+// redactCore 在写入前对日志内容脱敏
+type redactCore struct {
+	zapcore.Core
+	r *redactor
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.r.fields(fields)), r: c.r}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.r.message(ent.Message)
+	return c.Core.Write(ent, c.r.fields(fields))
+}
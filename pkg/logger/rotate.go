@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	rotatelogs "github.com/lestrrat-go/file-rotatelogs"
+)
+
+var strftimeVerb = regexp.MustCompile(`%[%+A-Za-z]`)
+
+// newRotateWriter 创建按天切割的日志文件，按配置追加按大小切割、压缩和总大小限制。
+// 同一天内按大小切割的文件以 .1、.2 ... 为后缀
+func newRotateWriter(conf *options, pattern string, extra ...rotatelogs.Option) (*rotatelogs.RotateLogs, error) {
+	ret := &retention{
+		glob:       strftimeVerb.ReplaceAllString(pattern, "*") + "*",
+		maxAge:     time.Duration(conf.maxAgeDays) * 24 * time.Hour,
+		maxBackups: conf.maxBackups,
+		maxTotal:   int64(conf.maxTotalSizeMB) * 1024 * 1024,
+		compress:   conf.compress,
+	}
+	opts := append([]rotatelogs.Option{
+		rotatelogs.WithRotationTime(24 * time.Hour),
+		// rotatelogs 自带的清理匹配不到 .N 后缀的文件，这里关闭它，改由 retention 清理
+		rotatelogs.WithMaxAge(-1),
+		rotatelogs.WithRotationCount(^uint(0)),
+		rotatelogs.WithHandler(ret),
+	}, extra...)
+	if conf.maxSizeMB > 0 {
+		opts = append(opts, rotatelogs.WithRotationSize(int64(conf.maxSizeMB)*1024*1024))
+	}
+	return rotatelogs.New(pattern, opts...)
+}
+
+// retention 在每次切割后清理历史日志文件
+type retention struct {
+	mu         sync.Mutex
+	glob       string
+	maxAge     time.Duration
+	maxBackups int   // 大于 0 时按个数保留，忽略 maxAge
+	maxTotal   int64 // 所有日志文件（含当前文件）的总大小上限，0 不限制
+	compress   bool  // 是否 gzip 压缩历史文件
+}
+
+func (r *retention) Handle(e rotatelogs.Event) {
+	ev, ok := e.(*rotatelogs.FileRotatedEvent)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 事件处理是异步的，ev.CurrentFile() 可能已不是最新文件，因此最新的文件总是保留
+	files := r.files()
+	cutoff := time.Now().Add(-r.maxAge)
+	var total int64
+	for i, f := range files {
+		if i == 0 || f.path == ev.CurrentFile() {
+			total += f.size
+			continue
+		}
+		if r.compress && !strings.HasSuffix(f.path, ".gz") {
+			if gz, err := compressFile(f.path); err == nil {
+				f.path, f.size = gz.path, gz.size
+			}
+		}
+		total += f.size
+		if (r.maxBackups > 0 && i > r.maxBackups) ||
+			(r.maxBackups <= 0 && r.maxAge > 0 && f.modTime.Before(cutoff)) ||
+			(r.maxTotal > 0 && total > r.maxTotal) {
+			_ = os.Remove(f.path)
+		}
+	}
+}
+
+// compressFile 将文件压缩为 .gz 并删除原文件，保留修改时间以维持新旧顺序
+func compressFile(path string) (logFile, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return logFile{}, err
+	}
+	defer src.Close()
+	fi, err := src.Stat()
+	if err != nil {
+		return logFile{}, err
+	}
+
+	dst := path + ".gz"
+	if _, err := os.Stat(dst); err == nil {
+		// 重启后 rotatelogs 可能复用已被压缩掉的序号，避免覆盖旧的压缩文件
+		dst = fmt.Sprintf("%s.%d.gz", path, time.Now().UnixNano())
+	}
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return logFile{}, err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, src)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, dst)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return logFile{}, err
+	}
+	_ = os.Chtimes(dst, fi.ModTime(), fi.ModTime())
+	_ = src.Close()
+	_ = os.Remove(path)
+
+	gz, err := os.Stat(dst)
+	if err != nil {
+		return logFile{}, err
+	}
+	return logFile{path: dst, size: gz.Size(), modTime: fi.ModTime()}, nil
+}
+
+type logFile struct {
+	path       string
+	base       string // 去掉 .N 后缀的文件名
+	generation int
+	size       int64
+	modTime    time.Time
+}
+
+// newer 判断 f 是否比 o 新，修改时间相同时按文件名和序号比较
+func (f logFile) newer(o logFile) bool {
+	if !f.modTime.Equal(o.modTime) {
+		return f.modTime.After(o.modTime)
+	}
+	if f.base != o.base {
+		return f.base > o.base
+	}
+	return f.generation > o.generation
+}
+
+// files 返回所有日志文件，按从新到旧排序
+func (r *retention) files() []logFile {
+	matches, err := filepath.Glob(r.glob)
+	if err != nil {
+		return nil
+	}
+	files := make([]logFile, 0, len(matches))
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_lock") || strings.HasSuffix(path, "_symlink") || strings.HasSuffix(path, ".tmp") {
+			continue
+		}
+		fi, err := os.Lstat(path)
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		name := strings.TrimSuffix(path, ".gz")
+		f := logFile{path: path, base: name, size: fi.Size(), modTime: fi.ModTime()}
+		if i := strings.LastIndexByte(name, '.'); i > 0 {
+			if n, err := strconv.Atoi(name[i+1:]); err == nil {
+				f.base, f.generation = name[:i], n
+			}
+		}
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].newer(files[j]) })
+	return files
+}
@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sink 远程日志投递目标（如 Kafka、Elasticsearch），实现见 pkg/logger/sink
+type Sink interface {
+	// WriteBatch 写入一批已编码的 JSON 日志，返回错误时整批回落到本地文件，
+	// 返回 *PartialWriteError 时只回落其中失败的条目
+	WriteBatch(ctx context.Context, entries [][]byte) error
+	Close() error
+}
+
+// PartialWriteError Sink 部分写入失败，Failed 为失败条目在批次中的下标
+type PartialWriteError struct {
+	Failed []int
+	Err    error
+}
+
+func (e *PartialWriteError) Error() string {
+	return fmt.Sprintf("%d entries failed: %v", len(e.Failed), e.Err)
+}
+
+func (e *PartialWriteError) Unwrap() error { return e.Err }
+
+// SinkOptions 异步投递参数
+type SinkOptions struct {
+	Level         string        // 投递的最低级别，为空与主日志一致
+	BatchSize     int           // 单批条数，默认 500
+	FlushInterval time.Duration // 定时刷新间隔，默认 1 秒
+	QueueSize     int           // 待投递队列长度，默认 10000，队列满时直接写入本地回落文件
+	Timeout       time.Duration // 单批写入超时，默认 5 秒
+}
+
+type sinkConfig struct {
+	sink Sink
+	opts SinkOptions
+}
+
+// WithSink 追加一个异步批量投递的日志目标，投递失败的日志写入日志目录下的 sink-fallback 文件
+func WithSink(sink Sink, opts SinkOptions) Option {
+	return func(o *options) { o.sinks = append(o.sinks, sinkConfig{sink: sink, opts: opts}) }
+}
+
+// sinkWriter 将 zap 写入转为后台批量投递
+type sinkWriter struct {
+	sink     Sink
+	fallback io.Writer
+	opts     SinkOptions
+
+	queue    chan []byte
+	flushReq chan chan struct{}
+	done     chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newSinkWriter(sink Sink, fallback io.Writer, opts SinkOptions) *sinkWriter {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 10000
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	w := &sinkWriter{
+		sink:     sink,
+		fallback: fallback,
+		opts:     opts,
+		queue:    make(chan []byte, opts.QueueSize),
+		flushReq: make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write 实现 zapcore.WriteSyncer，zap 会复用 p，这里需要拷贝
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	entry := append([]byte(nil), p...)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return w.fallback.Write(entry)
+	}
+	select {
+	case w.queue <- entry:
+		return len(p), nil
+	default:
+		return w.fallback.Write(entry)
+	}
+}
+
+// Sync 等待队列中已有的日志投递完成
+func (w *sinkWriter) Sync() error {
+	ack := make(chan struct{})
+	select {
+	case w.flushReq <- ack:
+		<-ack
+	case <-w.done:
+	}
+	return nil
+}
+
+// Close 停止接收日志，投递剩余数据后关闭 sink
+func (w *sinkWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+	<-w.done
+	return w.sink.Close()
+}
+
+func (w *sinkWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, w.opts.BatchSize)
+	add := func(entry []byte) {
+		batch = append(batch, entry)
+		if len(batch) >= w.opts.BatchSize {
+			w.write(batch)
+			batch = batch[:0]
+		}
+	}
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				w.write(batch)
+				return
+			}
+			add(entry)
+		case <-ticker.C:
+			w.write(batch)
+			batch = batch[:0]
+		case ack := <-w.flushReq:
+			for n := len(w.queue); n > 0; n-- {
+				add(<-w.queue)
+			}
+			w.write(batch)
+			batch = batch[:0]
+			close(ack)
+		}
+	}
+}
+
+// write 投递一批日志，失败的条目写入本地回落文件
+func (w *sinkWriter) write(batch [][]byte) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.Timeout)
+	defer cancel()
+	err := w.sink.WriteBatch(ctx, batch)
+	if err == nil {
+		return
+	}
+	var partial *PartialWriteError
+	if errors.As(err, &partial) {
+		fmt.Fprintf(os.Stderr, "logger: sink write failed, %d entries written to fallback: %v\n", len(partial.Failed), err)
+		for _, i := range partial.Failed {
+			if i >= 0 && i < len(batch) {
+				_, _ = w.fallback.Write(batch[i])
+			}
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "logger: sink write failed, %d entries written to fallback: %v\n", len(batch), err)
+	for _, entry := range batch {
+		_, _ = w.fallback.Write(entry)
+	}
+}
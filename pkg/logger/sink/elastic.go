@@ -0,0 +1,53 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/code-sigs/go-box/pkg/elastic"
+	"github.com/code-sigs/go-box/pkg/logger"
+)
+
+var _ logger.Sink = (*ElasticSink)(nil)
+
+// logDocument 已编码的日志文档，原样写入 ES
+type logDocument struct {
+	index string
+	raw   []byte
+}
+
+func (d logDocument) IndexName() string { return d.index }
+
+func (d logDocument) MarshalJSON() ([]byte, error) { return d.raw, nil }
+
+// ElasticSink 将日志批量写入 Elasticsearch 索引
+type ElasticSink struct {
+	client   *elastic.ElasticClient[logDocument]
+	index    string
+	strategy elastic.IndexStrategy
+}
+
+// NewElasticSink 创建 ES 日志投递目标，strategy 为空时按天生成索引 <index>-yyyy.MM.dd
+func NewElasticSink(cfg *elastic.ElasticConfig, index string, strategy elastic.IndexStrategy) (*ElasticSink, error) {
+	client, err := elastic.NewElasticClient[logDocument](cfg)
+	if err != nil {
+		return nil, err
+	}
+	if strategy == nil {
+		strategy = elastic.DailyIndexStrategy
+	}
+	return &ElasticSink{client: client, index: index, strategy: strategy}, nil
+}
+
+// WriteBatch 通过 bulk 接口写入一批日志
+func (s *ElasticSink) WriteBatch(ctx context.Context, entries [][]byte) error {
+	docs := make([]*logDocument, 0, len(entries))
+	for _, e := range entries {
+		docs = append(docs, &logDocument{index: s.index, raw: e})
+	}
+	return s.client.BulkCreateDocuments(ctx, docs, nil, s.strategy)
+}
+
+// Close ES 客户端无需释放资源
+func (s *ElasticSink) Close() error {
+	return nil
+}
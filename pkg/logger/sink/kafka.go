@@ -0,0 +1,49 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/code-sigs/go-box/pkg/kafka"
+	"github.com/code-sigs/go-box/pkg/logger"
+)
+
+var _ logger.Sink = (*KafkaSink)(nil)
+
+// KafkaSink 将日志写入 Kafka topic，每条日志一条消息，整批一起提交
+type KafkaSink struct {
+	producer *kafka.Producer[[]byte]
+}
+
+// NewKafkaSink 创建 Kafka 日志投递目标
+func NewKafkaSink(cfg *kafka.Config, topic string) (*KafkaSink, error) {
+	client, err := kafka.New[[]byte](cfg)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := client.WithCodec(kafka.RawCodec{}).NewProducer(topic)
+	if err != nil {
+		return nil, fmt.Errorf("create log producer: %w", err)
+	}
+	return &KafkaSink{producer: producer}, nil
+}
+
+// WriteBatch 一次提交整批日志，部分失败时返回 *logger.PartialWriteError，只回落失败的条目
+func (s *KafkaSink) WriteBatch(ctx context.Context, entries [][]byte) error {
+	objs := make([]*[]byte, len(entries))
+	for i := range entries {
+		objs[i] = &entries[i]
+	}
+	err := s.producer.SendBatch(ctx, objs)
+	var batchErr *kafka.BatchError
+	if errors.As(err, &batchErr) {
+		return &logger.PartialWriteError{Failed: batchErr.Failed, Err: err}
+	}
+	return err
+}
+
+// Close 关闭生产者
+func (s *KafkaSink) Close() error {
+	return s.producer.Close()
+}
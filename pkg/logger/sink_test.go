@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSink struct {
+	err     func(batch [][]byte) error
+	written [][]byte
+}
+
+func (s *fakeSink) WriteBatch(_ context.Context, entries [][]byte) error {
+	if s.err != nil {
+		if err := s.err(entries); err != nil {
+			return err
+		}
+	}
+	s.written = append(s.written, entries...)
+	return nil
+}
+
+func (s *fakeSink) Close() error { return nil }
+
+func TestSinkWriter_Fallback(t *testing.T) {
+	batch := [][]byte{[]byte("a\n"), []byte("b\n"), []byte("c\n")}
+	cases := []struct {
+		name string
+		err  func([][]byte) error
+		want string
+	}{
+		{"ok", nil, ""},
+		{"all failed", func([][]byte) error { return errors.New("down") }, "a\nb\nc\n"},
+		{"partial", func([][]byte) error { return &PartialWriteError{Failed: []int{1, 5}, Err: errors.New("timeout")} }, "b\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var fallback bytes.Buffer
+			w := newSinkWriter(&fakeSink{err: c.err}, &fallback, SinkOptions{})
+			w.write(batch)
+			assert.NoError(t, w.Close())
+			assert.Equal(t, c.want, fallback.String())
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lru
+
+// AddMany adds all entries while holding the lock once. Returns the number of evictions.
+func (c *Cache[K, V]) AddMany(entries map[K]V) (evicted int) {
+	var ks []K
+	var vs []V
+	c.lock.Lock()
+	for k, v := range entries {
+		if c.lru.Add(k, v) {
+			evicted++
+		}
+	}
+	c.stats.Evicted(evicted)
+	if c.onEvictedCB != nil && len(c.evictedKeys) > 0 {
+		ks, vs = c.evictedKeys, c.evictedVals
+		c.initEvictBuffers()
+	}
+	c.lock.Unlock()
+	// invoke callback outside of critical section
+	for i := 0; i < len(ks); i++ {
+		c.onEvictedCB(ks[i], vs[i], EvictCapacity)
+	}
+	return evicted
+}
+
+// GetMany looks up several keys while holding the lock once.
+// Keys that are not present are omitted from the result.
+func (c *Cache[K, V]) GetMany(keys []K) map[K]V {
+	found := make(map[K]V, len(keys))
+	c.lock.Lock()
+	for _, k := range keys {
+		v, ok := c.lru.Get(k)
+		c.stats.Lookup(ok)
+		if ok {
+			found[k] = v
+		}
+	}
+	c.lock.Unlock()
+	return found
+}
+
+// RemoveMany removes several keys while holding the lock once.
+// Returns the number of keys that were present.
+func (c *Cache[K, V]) RemoveMany(keys []K) (removed int) {
+	var ks []K
+	var vs []V
+	c.lock.Lock()
+	for _, k := range keys {
+		if c.lru.Remove(k) {
+			removed++
+		}
+	}
+	if c.onEvictedCB != nil && len(c.evictedKeys) > 0 {
+		ks, vs = c.evictedKeys, c.evictedVals
+		c.initEvictBuffers()
+	}
+	c.lock.Unlock()
+	// invoke callback outside of critical section
+	for i := 0; i < len(ks); i++ {
+		c.onEvictedCB(ks[i], vs[i], EvictRemoved)
+	}
+	return removed
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lru
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StatsSource is implemented by Cache, expirable.LRU and expirable.ShardedLRU.
+type StatsSource interface {
+	Stats() Stats
+}
+
+// Collector exports the statistics of one or more caches as Prometheus metrics,
+// labelled by cache name.
+type Collector struct {
+	mu     sync.RWMutex
+	caches map[string]StatsSource
+
+	hits        *prometheus.Desc
+	misses      *prometheus.Desc
+	evictions   *prometheus.Desc
+	expirations *prometheus.Desc
+	size        *prometheus.Desc
+	capacity    *prometheus.Desc
+}
+
+// NewCollector returns a collector for the given caches keyed by name.
+// Register it with prometheus.MustRegister or a custom registry.
+// The map is copied; use Add and Remove to change it later.
+func NewCollector(caches map[string]StatsSource) *Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("lru", "cache", name), help, []string{"cache"}, nil)
+	}
+	copied := make(map[string]StatsSource, len(caches))
+	for name, cache := range caches {
+		copied[name] = cache
+	}
+	return &Collector{
+		caches:      copied,
+		hits:        desc("hits_total", "Cache lookups that found a live entry."),
+		misses:      desc("misses_total", "Cache lookups that found nothing or an expired entry."),
+		evictions:   desc("evictions_total", "Entries evicted to make room."),
+		expirations: desc("expirations_total", "Entries removed after their TTL passed."),
+		size:        desc("entries", "Current number of entries."),
+		capacity:    desc("capacity", "Maximum number of entries, 0 means unlimited."),
+	}
+}
+
+// Add starts exporting cache under name, replacing any cache with the same name.
+func (c *Collector) Add(name string, cache StatsSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.caches[name] = cache
+}
+
+// Remove stops exporting the cache registered under name.
+func (c *Collector) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.caches, name)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.evictions
+	ch <- c.expirations
+	ch <- c.size
+	ch <- c.capacity
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for name, cache := range c.caches {
+		s := cache.Stats()
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(s.Hits), name)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(s.Misses), name)
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(s.Evictions), name)
+		ch <- prometheus.MustNewConstMetric(c.expirations, prometheus.CounterValue, float64(s.Expirations), name)
+		ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(s.Len), name)
+		ch <- prometheus.MustNewConstMetric(c.capacity, prometheus.GaugeValue, float64(s.Cap), name)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package expirable
+
+import "time"
+
+// AddMany adds all entries while holding the lock once. Returns the number of evictions.
+func (c *LRU[K, V]) AddMany(entries map[K]V) (evicted int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := time.Now().Add(c.ttl)
+	for k, v := range entries {
+		if c.add(k, v, expiresAt) {
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// GetMany looks up several keys while holding the lock once.
+// Missing and expired keys are omitted from the result.
+func (c *LRU[K, V]) GetMany(keys []K) map[K]V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	found := make(map[K]V, len(keys))
+	now := time.Now()
+	for _, k := range keys {
+		ent, ok := c.items[k]
+		ok = ok && !now.After(ent.ExpiresAt)
+		c.stats.Lookup(ok)
+		if ok {
+			c.evictList.MoveToFront(ent)
+			found[k] = ent.Value
+		}
+	}
+	return found
+}
+
+// RemoveMany removes several keys while holding the lock once.
+// Returns the number of keys that were present.
+func (c *LRU[K, V]) RemoveMany(keys []K) (removed int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		if ent, ok := c.items[k]; ok {
+			c.removeElement(ent, EvictRemoved)
+			removed++
+		}
+	}
+	return removed
+}
+
+// AddMany adds all entries, locking each shard once. Returns the number of evictions.
+func (c *ShardedLRU[K, V]) AddMany(entries map[K]V) (evicted int) {
+	groups := make(map[*LRU[K, V]]map[K]V)
+	for k, v := range entries {
+		s := c.shard(k)
+		if groups[s] == nil {
+			groups[s] = make(map[K]V)
+		}
+		groups[s][k] = v
+	}
+	for s, group := range groups {
+		evicted += s.AddMany(group)
+	}
+	return evicted
+}
+
+// GetMany looks up several keys, locking each shard once.
+func (c *ShardedLRU[K, V]) GetMany(keys []K) map[K]V {
+	found := make(map[K]V, len(keys))
+	for s, group := range c.groupKeys(keys) {
+		for k, v := range s.GetMany(group) {
+			found[k] = v
+		}
+	}
+	return found
+}
+
+// RemoveMany removes several keys, locking each shard once.
+func (c *ShardedLRU[K, V]) RemoveMany(keys []K) (removed int) {
+	for s, group := range c.groupKeys(keys) {
+		removed += s.RemoveMany(group)
+	}
+	return removed
+}
+
+func (c *ShardedLRU[K, V]) groupKeys(keys []K) map[*LRU[K, V]][]K {
+	groups := make(map[*LRU[K, V]][]K)
+	for _, k := range keys {
+		s := c.shard(k)
+		groups[s] = append(groups[s], k)
+	}
+	return groups
+}
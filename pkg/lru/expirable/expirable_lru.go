@@ -12,12 +12,25 @@ import (
 // EvictCallback is used to get a callback when a cache entry is evicted
 type EvictCallback[K comparable, V any] func(key K, value V)
 
+// EvictReasonCallback is an eviction callback that also receives why the entry left the cache.
+type EvictReasonCallback[K comparable, V any] func(key K, value V, reason EvictReason)
+
+// EvictReason tells an eviction callback why an entry left the cache.
+type EvictReason = internal.EvictReason
+
+const (
+	EvictCapacity = internal.EvictCapacity // removed to make room or by Resize
+	EvictExpired  = internal.EvictExpired  // TTL passed
+	EvictRemoved  = internal.EvictRemoved  // Remove, RemoveOldest or RemoveMany
+	EvictPurged   = internal.EvictPurged   // Purge
+)
+
 // LRU implements a thread-safe LRU with expirable entries.
 type LRU[K comparable, V any] struct {
 	size      int
 	evictList *internal.LruList[K, V]
 	items     map[K]*internal.Entry[K, V]
-	onEvict   EvictCallback[K, V]
+	onEvict   EvictReasonCallback[K, V]
 
 	// expirable options
 	mu   sync.Mutex
@@ -28,8 +41,18 @@ type LRU[K comparable, V any] struct {
 	buckets []bucket[K, V]
 	// uint8 because it's number between 0 and numBuckets
 	nextCleanupBucket uint8
+
+	stats internal.Counters
+
+	// stale-while-revalidate options
+	staleFor   time.Duration
+	refresh    func(key K) (V, error)
+	refreshing map[K]struct{}
 }
 
+// Stats is a point-in-time snapshot of cache statistics.
+type Stats = internal.Stats
+
 // bucket is a container for holding entries to be expired
 type bucket[K comparable, V any] struct {
 	entries     map[K]*internal.Entry[K, V]
@@ -51,6 +74,16 @@ const numBuckets = 100
 //
 // Delete expired entries every 1/100th of ttl value. Goroutine which deletes expired entries runs indefinitely.
 func NewLRU[K comparable, V any](size int, onEvict EvictCallback[K, V], ttl time.Duration) *LRU[K, V] {
+	var cb EvictReasonCallback[K, V]
+	if onEvict != nil {
+		cb = func(k K, v V, _ EvictReason) { onEvict(k, v) }
+	}
+	return NewLRUWithEvictReason(size, cb, ttl)
+}
+
+// NewLRUWithEvictReason is like NewLRU, but the eviction callback also receives
+// whether the entry was evicted for capacity, expired, removed or purged.
+func NewLRUWithEvictReason[K comparable, V any](size int, onEvict EvictReasonCallback[K, V], ttl time.Duration) *LRU[K, V] {
 	if size < 0 {
 		size = 0
 	}
@@ -101,7 +134,7 @@ func (c *LRU[K, V]) Purge() {
 	defer c.mu.Unlock()
 	for k, v := range c.items {
 		if c.onEvict != nil {
-			c.onEvict(k, v.Value)
+			c.onEvict(k, v.Value, EvictPurged)
 		}
 		delete(c.items, k)
 	}
@@ -119,20 +152,23 @@ func (c *LRU[K, V]) Purge() {
 func (c *LRU[K, V]) Add(key K, value V) (evicted bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	now := time.Now()
+	return c.add(key, value, time.Now().Add(c.ttl))
+}
 
+// add adds a value expiring at expiresAt to the cache. Has to be called with lock!
+func (c *LRU[K, V]) add(key K, value V, expiresAt time.Time) bool {
 	// Check for existing item
 	if ent, ok := c.items[key]; ok {
 		c.evictList.MoveToFront(ent)
 		c.removeFromBucket(ent) // remove the entry from its current bucket as expiresAt is renewed
 		ent.Value = value
-		ent.ExpiresAt = now.Add(c.ttl)
+		ent.ExpiresAt = expiresAt
 		c.addToBucket(ent)
 		return false
 	}
 
 	// Add new item
-	ent := c.evictList.PushFrontExpirable(key, value, now.Add(c.ttl))
+	ent := c.evictList.PushFrontExpirable(key, value, expiresAt)
 	c.items[key] = ent
 	c.addToBucket(ent) // adds the entry to the appropriate bucket and sets entry.expireBucket
 
@@ -140,6 +176,7 @@ func (c *LRU[K, V]) Add(key K, value V) (evicted bool) {
 	// Verify size not exceeded
 	if evict {
 		c.removeOldest()
+		c.stats.Evicted(1)
 	}
 	return evict
 }
@@ -151,12 +188,17 @@ func (c *LRU[K, V]) Get(key K) (value V, ok bool) {
 	var ent *internal.Entry[K, V]
 	if ent, ok = c.items[key]; ok {
 		// Expired item check
-		if time.Now().After(ent.ExpiresAt) {
-			return value, false
+		if now := time.Now(); now.After(ent.ExpiresAt) {
+			if !c.serveStale(ent, now) {
+				c.stats.Lookup(false)
+				return value, false
+			}
 		}
 		c.evictList.MoveToFront(ent)
+		c.stats.Lookup(true)
 		return ent.Value, true
 	}
+	c.stats.Lookup(false)
 	return
 }
 
@@ -185,13 +227,36 @@ func (c *LRU[K, V]) Peek(key K) (value V, ok bool) {
 	return
 }
 
+// Touch renews the expiration of a live entry to the full TTL of the cache and marks it
+// as recently used, without rewriting the value. Returns false if the key is missing or expired.
+func (c *LRU[K, V]) Touch(key K) bool {
+	return c.UpdateTTL(key, c.ttl)
+}
+
+// UpdateTTL sets a live entry to expire ttl from now and marks it as recently used.
+// Ttl is capped at the TTL of the cache. Returns false if the key is missing or expired.
+func (c *LRU[K, V]) UpdateTTL(key K, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ent, ok := c.items[key]
+	now := time.Now()
+	if !ok || now.After(ent.ExpiresAt) {
+		return false
+	}
+	c.evictList.MoveToFront(ent)
+	c.removeFromBucket(ent)
+	ent.ExpiresAt = now.Add(min(ttl, c.ttl))
+	c.addToBucket(ent)
+	return true
+}
+
 // Remove removes the provided key from the cache, returning if the
 // key was contained.
 func (c *LRU[K, V]) Remove(key K) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if ent, ok := c.items[key]; ok {
-		c.removeElement(ent)
+		c.removeElement(ent, EvictRemoved)
 		return true
 	}
 	return false
@@ -202,7 +267,7 @@ func (c *LRU[K, V]) RemoveOldest() (key K, value V, ok bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if ent := c.evictList.Back(); ent != nil {
-		c.removeElement(ent)
+		c.removeElement(ent, EvictRemoved)
 		return ent.Key, ent.Value, true
 	}
 	return
@@ -250,6 +315,27 @@ func (c *LRU[K, V]) Values() []V {
 	return values
 }
 
+// Range calls fn for each live entry from oldest to newest until fn returns false.
+// The lock is held during iteration, so fn must not call into the cache.
+func (c *LRU[K, V]) Range(fn func(key K, value V) bool) {
+	c.rangeEntries(time.Now(), fn)
+}
+
+// rangeEntries iterates live entries under the lock, reporting whether iteration completed.
+func (c *LRU[K, V]) rangeEntries(now time.Time, fn func(key K, value V) bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for ent := c.evictList.Back(); ent != nil; ent = ent.PrevEntry() {
+		if now.After(ent.ExpiresAt) {
+			continue
+		}
+		if !fn(ent.Key, ent.Value) {
+			return false
+		}
+	}
+	return true
+}
+
 // Len returns the number of items in the cache.
 func (c *LRU[K, V]) Len() int {
 	c.mu.Lock()
@@ -272,6 +358,7 @@ func (c *LRU[K, V]) Resize(size int) (evicted int) {
 	for i := 0; i < diff; i++ {
 		c.removeOldest()
 	}
+	c.stats.Evicted(diff)
 	c.size = size
 	return diff
 }
@@ -291,37 +378,39 @@ func (c *LRU[K, V]) Resize(size int) (evicted int) {
 // removeOldest removes the oldest item from the cache. Has to be called with lock!
 func (c *LRU[K, V]) removeOldest() {
 	if ent := c.evictList.Back(); ent != nil {
-		c.removeElement(ent)
+		c.removeElement(ent, EvictCapacity)
 	}
 }
 
 // removeElement is used to remove a given list element from the cache. Has to be called with lock!
-func (c *LRU[K, V]) removeElement(e *internal.Entry[K, V]) {
+func (c *LRU[K, V]) removeElement(e *internal.Entry[K, V], reason EvictReason) {
 	c.evictList.Remove(e)
 	delete(c.items, e.Key)
 	c.removeFromBucket(e)
 	if c.onEvict != nil {
-		c.onEvict(e.Key, e.Value)
+		c.onEvict(e.Key, e.Value, reason)
 	}
 }
 
-// deleteExpired deletes expired records from the oldest bucket, waiting for the newest entry
-// in it to expire first.
+// deleteExpired deletes expired records starting from the oldest bucket, stopping at the
+// first bucket whose newest entry has not expired yet. Several buckets may be cleaned in
+// one call, so cleanup keeps up even when ticks arrive later than ttl/numBuckets.
 func (c *LRU[K, V]) deleteExpired() {
 	c.mu.Lock()
-	bucketIdx := c.nextCleanupBucket
-	timeToExpire := time.Until(c.buckets[bucketIdx].newestEntry)
-	// wait for newest entry to expire before cleanup without holding lock
-	if timeToExpire > 0 {
-		c.mu.Unlock()
-		time.Sleep(timeToExpire)
-		c.mu.Lock()
-	}
-	for _, ent := range c.buckets[bucketIdx].entries {
-		c.removeElement(ent)
+	defer c.mu.Unlock()
+	now := time.Now()
+	for i := 0; i < numBuckets; i++ {
+		bucketIdx := c.nextCleanupBucket
+		// with stale-while-revalidate, expired entries are kept for staleFor
+		if c.buckets[bucketIdx].newestEntry.Add(c.staleFor).After(now) {
+			return
+		}
+		c.stats.Expired(len(c.buckets[bucketIdx].entries))
+		for _, ent := range c.buckets[bucketIdx].entries {
+			c.removeElement(ent, EvictExpired)
+		}
+		c.nextCleanupBucket = (c.nextCleanupBucket + 1) % numBuckets
 	}
-	c.nextCleanupBucket = (c.nextCleanupBucket + 1) % numBuckets
-	c.mu.Unlock()
 }
 
 // addToBucket adds entry to expire bucket so that it will be cleaned up when the time comes. Has to be called with lock!
@@ -343,3 +432,9 @@ func (c *LRU[K, V]) removeFromBucket(e *internal.Entry[K, V]) {
 func (c *LRU[K, V]) Cap() int {
 	return c.size
 }
+
+// Stats returns hit, miss, eviction and expiration counters together with the current size.
+// Only Get is counted as a lookup.
+func (c *LRU[K, V]) Stats() Stats {
+	return c.stats.Snapshot(c.Len(), c.Cap())
+}
@@ -4,6 +4,7 @@
 package expirable
 
 import (
+	"bytes"
 	"crypto/rand"
 	"fmt"
 	"github.com/code-sigs/go-box/pkg/lru/simplelru"
@@ -11,6 +12,7 @@ import (
 	"math/big"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -125,6 +127,181 @@ func TestLRUInterface(_ *testing.T) {
 	var _ simplelru.LRUCache[int, int] = &LRU[int, int]{}
 }
 
+func TestLRUStats(t *testing.T) {
+	lc := NewLRU[string, string](1, nil, 20*time.Millisecond)
+
+	lc.Add("key1", "val1")
+	lc.Add("key2", "val2")
+	lc.Get("key1")
+	lc.Get("key2")
+
+	s := lc.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.Evictions != 1 || s.Len != 1 || s.Cap != 1 {
+		t.Fatalf("bad stats: %+v", s)
+	}
+
+	// cleanup walks one bucket per ttl/100, give it a few full rounds
+	for i := 0; i < 50 && lc.Len() > 0; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if s = lc.Stats(); s.Expirations != 1 || s.Len != 0 {
+		t.Fatalf("bad stats after expiration: %+v", s)
+	}
+}
+
+func TestLRUEvictReason(t *testing.T) {
+	reasons := make(map[string]EvictReason)
+	var mu sync.Mutex
+	lc := NewLRUWithEvictReason[string, string](2, func(k, _ string, reason EvictReason) {
+		mu.Lock()
+		reasons[k] = reason
+		mu.Unlock()
+	}, 20*time.Millisecond)
+
+	lc.Add("removed", "v")
+	lc.Remove("removed")
+	lc.Add("capacity", "v")
+	lc.Add("key2", "v")
+	lc.Add("key3", "v")
+	lc.Purge()
+	lc.Add("expired", "v")
+	for i := 0; i < 50 && lc.Len() > 0; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[string]EvictReason{
+		"removed":  EvictRemoved,
+		"capacity": EvictCapacity,
+		"key2":     EvictPurged,
+		"key3":     EvictPurged,
+		"expired":  EvictExpired,
+	}
+	if !reflect.DeepEqual(reasons, want) {
+		t.Fatalf("bad reasons: %v, want %v", reasons, want)
+	}
+}
+
+func TestLRUTouch(t *testing.T) {
+	lc := NewLRU[string, string](2, nil, time.Hour)
+	lc.Add("key1", "val1")
+	lc.Add("key2", "val2")
+
+	if !lc.UpdateTTL("key1", time.Minute) {
+		t.Fatalf("key1 should be updated")
+	}
+	lc.wantKeys(t, []string{"key2", "key1"})
+	lc.mu.Lock()
+	ttl := time.Until(lc.items["key1"].ExpiresAt)
+	lc.mu.Unlock()
+	if ttl > time.Minute || ttl < 50*time.Second {
+		t.Fatalf("bad ttl: %v", ttl)
+	}
+
+	if !lc.Touch("key1") {
+		t.Fatalf("key1 should be touched")
+	}
+	lc.mu.Lock()
+	ttl = time.Until(lc.items["key1"].ExpiresAt)
+	lc.mu.Unlock()
+	if ttl < 59*time.Minute {
+		t.Fatalf("bad ttl after touch: %v", ttl)
+	}
+
+	if !lc.UpdateTTL("key2", -time.Second) {
+		t.Fatalf("key2 should be updated")
+	}
+	if _, ok := lc.Get("key2"); ok {
+		t.Fatalf("key2 should be expired")
+	}
+	if lc.Touch("key2") || lc.Touch("missing") {
+		t.Fatalf("expired or missing keys should not be touched")
+	}
+}
+
+func TestLRUStaleWhileRevalidate(t *testing.T) {
+	lc := NewLRU[string, int](0, nil, 20*time.Millisecond)
+	var refreshes atomic.Int32
+	refreshed := make(chan struct{}, 1)
+	lc.SetStaleWhileRevalidate(time.Hour, func(string) (int, error) {
+		refreshes.Add(1)
+		defer func() { refreshed <- struct{}{} }()
+		return 2, nil
+	})
+
+	lc.Add("key1", 1)
+	time.Sleep(40 * time.Millisecond)
+
+	// expired entries keep being served while the refresh runs
+	if v, ok := lc.Get("key1"); !ok || v != 1 {
+		t.Fatalf("stale value should be returned: %v, %v", v, ok)
+	}
+	<-refreshed
+	if v, ok := lc.Get("key1"); !ok || v != 2 {
+		t.Fatalf("refreshed value should be returned: %v, %v", v, ok)
+	}
+	if n := refreshes.Load(); n != 1 {
+		t.Fatalf("bad refresh count: %v", n)
+	}
+
+	lc.SetStaleWhileRevalidate(0, nil)
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := lc.Get("key1"); ok {
+		t.Fatalf("expired value should not be returned when disabled")
+	}
+}
+
+func TestLRUSnapshot(t *testing.T) {
+	lc := NewLRU[string, string](3, nil, time.Hour)
+	lc.Add("key1", "val1")
+	lc.Add("key2", "val2")
+	lc.Get("key1")
+
+	var buf bytes.Buffer
+	if err := lc.Save(&buf); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	restored := NewLRU[string, string](3, nil, time.Minute)
+	n, err := restored.Load(&buf)
+	if err != nil || n != 2 {
+		t.Fatalf("bad load: %v, err: %v", n, err)
+	}
+	restored.wantKeys(t, []string{"key2", "key1"})
+	// remaining ttl is capped at the ttl of the restored cache
+	restored.mu.Lock()
+	expiresAt := restored.items["key1"].ExpiresAt
+	restored.mu.Unlock()
+	if time.Until(expiresAt) > time.Minute {
+		t.Fatalf("ttl not capped: %v", time.Until(expiresAt))
+	}
+
+	sharded := NewShardedLRU[string, string](4, 0, nil, time.Hour)
+	buf.Reset()
+	if err := restored.Save(&buf); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if n, err := sharded.Load(&buf); err != nil || n != 2 || sharded.Len() != 2 {
+		t.Fatalf("bad sharded load: %v, err: %v", n, err)
+	}
+}
+
+func TestLRUBatch(t *testing.T) {
+	lc := NewLRU[string, string](2, nil, 0)
+
+	if evicted := lc.AddMany(map[string]string{"key1": "val1", "key2": "val2", "key3": "val3"}); evicted != 1 {
+		t.Fatalf("bad evictions: %v", evicted)
+	}
+	got := lc.GetMany([]string{"key1", "key2", "key3"})
+	if len(got) != 2 {
+		t.Fatalf("bad values: %v", got)
+	}
+	if removed := lc.RemoveMany([]string{"key1", "key2", "key3"}); removed != 2 || lc.Len() != 0 {
+		t.Fatalf("bad remove: %v, len %v", removed, lc.Len())
+	}
+}
+
 func TestLRUNoPurge(t *testing.T) {
 	lc := NewLRU[string, string](10, nil, 0)
 
@@ -0,0 +1,222 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package expirable
+
+import (
+	"hash/maphash"
+	"time"
+)
+
+// DefaultShards is the shard count used by NewShardedLRU when shards <= 0.
+const DefaultShards = 16
+
+// ShardedLRU splits the keyspace across several independent expirable LRUs,
+// each guarded by its own lock, to reduce contention under heavy concurrent writes.
+//
+// Recency is tracked per shard, so eviction and the ordering of Keys/Values
+// are only approximately global.
+type ShardedLRU[K comparable, V any] struct {
+	shards []*LRU[K, V]
+	seed   maphash.Seed
+	size   int
+}
+
+// NewShardedLRU returns a thread-safe expirable cache split into the given number of shards.
+//
+// Size is the total capacity shared evenly between shards; 0 makes the cache unlimited.
+// Ttl and onEvict behave the same as in NewLRU.
+func NewShardedLRU[K comparable, V any](shards, size int, onEvict EvictCallback[K, V], ttl time.Duration) *ShardedLRU[K, V] {
+	var cb EvictReasonCallback[K, V]
+	if onEvict != nil {
+		cb = func(k K, v V, _ EvictReason) { onEvict(k, v) }
+	}
+	return NewShardedLRUWithEvictReason(shards, size, cb, ttl)
+}
+
+// NewShardedLRUWithEvictReason is like NewShardedLRU with an eviction callback that receives the reason.
+func NewShardedLRUWithEvictReason[K comparable, V any](shards, size int, onEvict EvictReasonCallback[K, V], ttl time.Duration) *ShardedLRU[K, V] {
+	if shards <= 0 {
+		shards = DefaultShards
+	}
+	if size < 0 {
+		size = 0
+	}
+	c := &ShardedLRU[K, V]{
+		shards: make([]*LRU[K, V], shards),
+		seed:   maphash.MakeSeed(),
+		size:   size,
+	}
+	for i := range c.shards {
+		c.shards[i] = NewLRUWithEvictReason(shardSize(size, shards), onEvict, ttl)
+	}
+	return c
+}
+
+// shardSize spreads size across shards rounding up, so the total capacity is never below size.
+func shardSize(size, shards int) int {
+	if size == 0 {
+		return 0
+	}
+	return (size + shards - 1) / shards
+}
+
+func (c *ShardedLRU[K, V]) shard(key K) *LRU[K, V] {
+	return c.shards[maphash.Comparable(c.seed, key)%uint64(len(c.shards))]
+}
+
+// Purge clears all shards.
+func (c *ShardedLRU[K, V]) Purge() {
+	for _, s := range c.shards {
+		s.Purge()
+	}
+}
+
+// Add adds a value to the cache. Returns true if an eviction occurred.
+func (c *ShardedLRU[K, V]) Add(key K, value V) (evicted bool) {
+	return c.shard(key).Add(key, value)
+}
+
+// Get looks up a key's value from the cache.
+func (c *ShardedLRU[K, V]) Get(key K) (value V, ok bool) {
+	return c.shard(key).Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *ShardedLRU[K, V]) Contains(key K) bool {
+	return c.shard(key).Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *ShardedLRU[K, V]) Peek(key K) (value V, ok bool) {
+	return c.shard(key).Peek(key)
+}
+
+// Touch renews the expiration of a live entry, see LRU.Touch.
+func (c *ShardedLRU[K, V]) Touch(key K) bool {
+	return c.shard(key).Touch(key)
+}
+
+// UpdateTTL sets a live entry to expire ttl from now, see LRU.UpdateTTL.
+func (c *ShardedLRU[K, V]) UpdateTTL(key K, ttl time.Duration) bool {
+	return c.shard(key).UpdateTTL(key, ttl)
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *ShardedLRU[K, V]) Remove(key K) bool {
+	return c.shard(key).Remove(key)
+}
+
+// RemoveOldest removes the entry with the earliest expiration across all shards.
+func (c *ShardedLRU[K, V]) RemoveOldest() (key K, value V, ok bool) {
+	// another goroutine may remove the chosen entry first, retry with the next oldest
+	for {
+		s := c.oldestShard()
+		if s == nil {
+			return
+		}
+		if key, value, ok = s.RemoveOldest(); ok {
+			return
+		}
+	}
+}
+
+// GetOldest returns the entry with the earliest expiration across all shards.
+func (c *ShardedLRU[K, V]) GetOldest() (key K, value V, ok bool) {
+	var oldest time.Time
+	for _, s := range c.shards {
+		s.mu.Lock()
+		if ent := s.evictList.Back(); ent != nil && (!ok || ent.ExpiresAt.Before(oldest)) {
+			key, value, ok, oldest = ent.Key, ent.Value, true, ent.ExpiresAt
+		}
+		s.mu.Unlock()
+	}
+	return
+}
+
+// oldestShard returns the shard holding the entry with the earliest expiration.
+func (c *ShardedLRU[K, V]) oldestShard() *LRU[K, V] {
+	var (
+		found  *LRU[K, V]
+		oldest time.Time
+	)
+	for _, s := range c.shards {
+		s.mu.Lock()
+		if ent := s.evictList.Back(); ent != nil && (found == nil || ent.ExpiresAt.Before(oldest)) {
+			found, oldest = s, ent.ExpiresAt
+		}
+		s.mu.Unlock()
+	}
+	return found
+}
+
+// Keys returns a slice of the keys in the cache, oldest to newest within each shard.
+// Expired entries are filtered out.
+func (c *ShardedLRU[K, V]) Keys() []K {
+	var keys []K
+	for _, s := range c.shards {
+		keys = append(keys, s.Keys()...)
+	}
+	return keys
+}
+
+// Values returns a slice of the values in the cache, oldest to newest within each shard.
+// Expired entries are filtered out.
+func (c *ShardedLRU[K, V]) Values() []V {
+	var values []V
+	for _, s := range c.shards {
+		values = append(values, s.Values()...)
+	}
+	return values
+}
+
+// Range calls fn for each live entry, shard by shard, until fn returns false.
+// Only one shard is locked at a time, so fn must not call into the cache.
+func (c *ShardedLRU[K, V]) Range(fn func(key K, value V) bool) {
+	now := time.Now()
+	for _, s := range c.shards {
+		if !s.rangeEntries(now, fn) {
+			return
+		}
+	}
+}
+
+// Len returns the number of items in the cache.
+func (c *ShardedLRU[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// Resize changes the total cache size, split evenly between shards. Size of 0 means unlimited.
+func (c *ShardedLRU[K, V]) Resize(size int) (evicted int) {
+	if size < 0 {
+		size = 0
+	}
+	per := shardSize(size, len(c.shards))
+	for _, s := range c.shards {
+		evicted += s.Resize(per)
+	}
+	c.size = size
+	return evicted
+}
+
+// Cap returns the total capacity of the cache.
+func (c *ShardedLRU[K, V]) Cap() int {
+	return c.size
+}
+
+// Stats returns the statistics summed across all shards.
+func (c *ShardedLRU[K, V]) Stats() Stats {
+	var s Stats
+	for _, shard := range c.shards {
+		s = s.Add(shard.Stats())
+	}
+	s.Cap = c.size
+	return s
+}
@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package expirable
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/lru/simplelru"
+)
+
+func BenchmarkShardedLRU_Parallel(b *testing.B) {
+	l := NewShardedLRU[int64, int64](16, 8192, nil, 0)
+
+	b.RunParallel(func(pb *testing.PB) {
+		var i int64
+		for pb.Next() {
+			k := i % 32768
+			if i%2 == 0 {
+				l.Add(k, k)
+			} else {
+				l.Get(k)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkLRU_Parallel(b *testing.B) {
+	l := NewLRU[int64, int64](8192, nil, 0)
+
+	b.RunParallel(func(pb *testing.PB) {
+		var i int64
+		for pb.Next() {
+			k := i % 32768
+			if i%2 == 0 {
+				l.Add(k, k)
+			} else {
+				l.Get(k)
+			}
+			i++
+		}
+	})
+}
+
+func TestShardedLRUInterface(_ *testing.T) {
+	var _ simplelru.LRUCache[int, int] = &ShardedLRU[int, int]{}
+}
+
+func TestShardedLRU(t *testing.T) {
+	var evicted int
+	lc := NewShardedLRU[int, int](4, 128, func(k, v int) {
+		if k != v {
+			t.Fatalf("evict values not equal (%v!=%v)", k, v)
+		}
+		evicted++
+	}, 0)
+	if lc.Cap() != 128 {
+		t.Fatalf("bad cap: %v", lc.Cap())
+	}
+
+	for i := 0; i < 1000; i++ {
+		lc.Add(i, i)
+	}
+	if lc.Len() > 128 {
+		t.Fatalf("bad len: %v", lc.Len())
+	}
+	if evicted != 1000-lc.Len() {
+		t.Fatalf("bad evict count: %v", evicted)
+	}
+	for _, k := range lc.Keys() {
+		if v, ok := lc.Get(k); !ok || v != k {
+			t.Fatalf("bad key: %v", k)
+		}
+	}
+	lc.Remove(999)
+	if lc.Contains(999) {
+		t.Fatalf("should not contain 999")
+	}
+
+	lc.Purge()
+	if lc.Len() != 0 {
+		t.Fatalf("bad len: %v", lc.Len())
+	}
+	if evicted != 1000 {
+		t.Fatalf("bad evict count: %v", evicted)
+	}
+}
+
+func TestShardedLRU_Oldest(t *testing.T) {
+	lc := NewShardedLRU[int, int](4, 0, nil, time.Hour)
+	for i := 0; i < 10; i++ {
+		lc.Add(i, i)
+		time.Sleep(time.Millisecond)
+	}
+
+	for want := 0; want < 10; want++ {
+		k, _, ok := lc.GetOldest()
+		if !ok || k != want {
+			t.Fatalf("bad oldest: %v, want %v", k, want)
+		}
+		k, _, ok = lc.RemoveOldest()
+		if !ok || k != want {
+			t.Fatalf("bad removed oldest: %v, want %v", k, want)
+		}
+	}
+	if _, _, ok := lc.RemoveOldest(); ok {
+		t.Fatalf("should be empty")
+	}
+}
+
+func TestShardedLRU_Resize(t *testing.T) {
+	lc := NewShardedLRU[int, int](4, 0, nil, 0)
+	for i := 0; i < 100; i++ {
+		lc.Add(i, i)
+	}
+	evicted := lc.Resize(8)
+	if lc.Len() > 8 || evicted != 100-lc.Len() {
+		t.Fatalf("bad resize: len %v, evicted %v", lc.Len(), evicted)
+	}
+	if lc.Cap() != 8 {
+		t.Fatalf("bad cap: %v", lc.Cap())
+	}
+}
+
+func TestShardedLRU_Concurrent(t *testing.T) {
+	lc := NewShardedLRU[int, int](8, 0, nil, 0)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				lc.Add(g*1000+i, i)
+				lc.Get(g*1000 + i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	keys := lc.Keys()
+	sort.Ints(keys)
+	if len(keys) != 8000 || keys[0] != 0 || keys[7999] != 7999 {
+		t.Fatalf("bad keys: %v", len(keys))
+	}
+}
+
+func TestShardedLRU_Batch(t *testing.T) {
+	lc := NewShardedLRU[int, int](4, 0, nil, 0)
+
+	entries := make(map[int]int)
+	for i := 0; i < 100; i++ {
+		entries[i] = i
+	}
+	if evicted := lc.AddMany(entries); evicted != 0 {
+		t.Fatalf("bad evictions: %v", evicted)
+	}
+	got := lc.GetMany([]int{0, 50, 99, 100})
+	if len(got) != 3 || got[50] != 50 {
+		t.Fatalf("bad values: %v", got)
+	}
+	if removed := lc.RemoveMany([]int{0, 50, 99, 100}); removed != 3 || lc.Len() != 97 {
+		t.Fatalf("bad remove: %v, len %v", removed, lc.Len())
+	}
+}
+
+func TestShardedLRU_Range(t *testing.T) {
+	lc := NewShardedLRU[int, int](4, 0, nil, 0)
+	for i := 0; i < 100; i++ {
+		lc.Add(i, i)
+	}
+
+	seen := make(map[int]bool)
+	lc.Range(func(k, v int) bool {
+		if k != v {
+			t.Fatalf("bad value for %v: %v", k, v)
+		}
+		seen[k] = true
+		return true
+	})
+	if len(seen) != 100 {
+		t.Fatalf("bad range count: %v", len(seen))
+	}
+
+	n := 0
+	lc.Range(func(int, int) bool {
+		n++
+		return n < 10
+	})
+	if n != 10 {
+		t.Fatalf("range did not stop: %v", n)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package expirable
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"time"
+)
+
+// snapshotEntry is a persisted cache entry with its remaining lifetime.
+type snapshotEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+	TTL   time.Duration
+}
+
+// snapshot is the gob-encoded form written by Save.
+type snapshot[K comparable, V any] struct {
+	Entries []snapshotEntry[K, V] // oldest to newest
+}
+
+// Save writes all live entries with their remaining TTL to w using gob,
+// so the cache can be restored with Load after a restart.
+// Keys and values must be gob-encodable.
+func (c *LRU[K, V]) Save(w io.Writer) error {
+	c.mu.Lock()
+	snap := snapshot[K, V]{Entries: c.entries(time.Now())}
+	c.mu.Unlock()
+	if err := gob.NewEncoder(w).Encode(snap); err != nil {
+		return fmt.Errorf("lru: encode snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load adds the entries written by Save, keeping their remaining TTL and
+// recency order. Entries that expired in the meantime are skipped.
+// Returns the number of entries loaded.
+func (c *LRU[K, V]) Load(r io.Reader) (int, error) {
+	var snap snapshot[K, V]
+	if err := gob.NewDecoder(r).Decode(&snap); err != nil {
+		return 0, fmt.Errorf("lru: decode snapshot: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.load(snap.Entries, time.Now()), nil
+}
+
+// entries returns live entries from oldest to newest. Has to be called with lock!
+func (c *LRU[K, V]) entries(now time.Time) []snapshotEntry[K, V] {
+	out := make([]snapshotEntry[K, V], 0, len(c.items))
+	for ent := c.evictList.Back(); ent != nil; ent = ent.PrevEntry() {
+		if ttl := ent.ExpiresAt.Sub(now); ttl > 0 {
+			out = append(out, snapshotEntry[K, V]{Key: ent.Key, Value: ent.Value, TTL: ttl})
+		}
+	}
+	return out
+}
+
+// load adds entries from oldest to newest. Has to be called with lock!
+func (c *LRU[K, V]) load(entries []snapshotEntry[K, V], now time.Time) int {
+	n := 0
+	for _, e := range entries {
+		if e.TTL <= 0 {
+			continue
+		}
+		// never extend an entry beyond the TTL of this cache
+		c.add(e.Key, e.Value, now.Add(min(e.TTL, c.ttl)))
+		n++
+	}
+	return n
+}
+
+// Save writes the live entries of all shards to w, see LRU.Save.
+func (c *ShardedLRU[K, V]) Save(w io.Writer) error {
+	var snap snapshot[K, V]
+	now := time.Now()
+	for _, s := range c.shards {
+		s.mu.Lock()
+		snap.Entries = append(snap.Entries, s.entries(now)...)
+		s.mu.Unlock()
+	}
+	if err := gob.NewEncoder(w).Encode(snap); err != nil {
+		return fmt.Errorf("lru: encode snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load restores entries written by Save into their shards, see LRU.Load.
+func (c *ShardedLRU[K, V]) Load(r io.Reader) (int, error) {
+	var snap snapshot[K, V]
+	if err := gob.NewDecoder(r).Decode(&snap); err != nil {
+		return 0, fmt.Errorf("lru: decode snapshot: %w", err)
+	}
+	groups := make(map[*LRU[K, V]][]snapshotEntry[K, V])
+	for _, e := range snap.Entries {
+		s := c.shard(e.Key)
+		groups[s] = append(groups[s], e)
+	}
+	n := 0
+	now := time.Now()
+	for s, entries := range groups {
+		s.mu.Lock()
+		n += s.load(entries, now)
+		s.mu.Unlock()
+	}
+	return n, nil
+}
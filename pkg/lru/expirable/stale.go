@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package expirable
+
+import (
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/lru/internal"
+)
+
+// SetStaleWhileRevalidate makes Get return an expired entry for up to staleFor after
+// its expiration, while refresh reloads it in the background. Only one refresh runs
+// per key at a time; a successful refresh stores the new value with a full TTL, a
+// failed one keeps serving the stale value until staleFor passes.
+// A zero staleFor or nil refresh turns the mode off.
+func (c *LRU[K, V]) SetStaleWhileRevalidate(staleFor time.Duration, refresh func(key K) (V, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if staleFor <= 0 || refresh == nil {
+		c.staleFor, c.refresh, c.refreshing = 0, nil, nil
+		return
+	}
+	c.staleFor = staleFor
+	c.refresh = refresh
+	if c.refreshing == nil {
+		c.refreshing = make(map[K]struct{})
+	}
+}
+
+// serveStale reports whether an expired entry may still be returned, starting a
+// background refresh if none is running. Has to be called with lock!
+func (c *LRU[K, V]) serveStale(ent *internal.Entry[K, V], now time.Time) bool {
+	if c.refresh == nil || now.After(ent.ExpiresAt.Add(c.staleFor)) {
+		return false
+	}
+	if _, ok := c.refreshing[ent.Key]; !ok {
+		c.refreshing[ent.Key] = struct{}{}
+		go c.revalidate(ent.Key, c.refresh)
+	}
+	return true
+}
+
+// revalidate reloads key and stores the result, unless the entry was removed meanwhile.
+func (c *LRU[K, V]) revalidate(key K, refresh func(key K) (V, error)) {
+	value, err := refresh(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.refreshing, key)
+	if _, ok := c.items[key]; !ok || err != nil {
+		return
+	}
+	c.add(key, value, time.Now().Add(c.ttl))
+}
+
+// SetStaleWhileRevalidate enables stale-while-revalidate on every shard, see LRU.SetStaleWhileRevalidate.
+func (c *ShardedLRU[K, V]) SetStaleWhileRevalidate(staleFor time.Duration, refresh func(key K) (V, error)) {
+	for _, s := range c.shards {
+		s.SetStaleWhileRevalidate(staleFor, refresh)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package internal
+
+// EvictReason tells an eviction callback why an entry left the cache.
+type EvictReason int
+
+const (
+	EvictCapacity EvictReason = iota // removed to make room or by Resize
+	EvictExpired                     // TTL passed
+	EvictRemoved                     // Remove, RemoveOldest or RemoveMany
+	EvictPurged                      // Purge
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictRemoved:
+		return "removed"
+	case EvictPurged:
+		return "purged"
+	default:
+		return "unknown"
+	}
+}
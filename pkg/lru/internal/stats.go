@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package internal
+
+import "sync/atomic"
+
+// Stats is a point-in-time snapshot of cache statistics.
+type Stats struct {
+	Hits        uint64 // Get calls that found a live entry
+	Misses      uint64 // Get calls that found nothing or an expired entry
+	Evictions   uint64 // entries removed to make room or by Resize
+	Expirations uint64 // entries removed because their TTL passed
+	Len         int    // current number of entries
+	Cap         int    // capacity, 0 means unlimited
+}
+
+// HitRatio returns Hits / (Hits + Misses), or 0 before the first lookup.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// Add returns the sum of two snapshots.
+func (s Stats) Add(o Stats) Stats {
+	return Stats{
+		Hits:        s.Hits + o.Hits,
+		Misses:      s.Misses + o.Misses,
+		Evictions:   s.Evictions + o.Evictions,
+		Expirations: s.Expirations + o.Expirations,
+		Len:         s.Len + o.Len,
+		Cap:         s.Cap + o.Cap,
+	}
+}
+
+// Counters accumulates cache statistics, safe for concurrent use.
+type Counters struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+}
+
+// Lookup records a hit or a miss.
+func (c *Counters) Lookup(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+}
+
+// Evicted records n capacity evictions.
+func (c *Counters) Evicted(n int) {
+	if n > 0 {
+		c.evictions.Add(uint64(n))
+	}
+}
+
+// Expired records n expirations.
+func (c *Counters) Expired(n int) {
+	if n > 0 {
+		c.expirations.Add(uint64(n))
+	}
+}
+
+// Snapshot returns the current counters together with the given length and capacity.
+func (c *Counters) Snapshot(length, capacity int) Stats {
+	return Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+		Len:         length,
+		Cap:         capacity,
+	}
+}
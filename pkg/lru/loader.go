@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lru
+
+import (
+	"fmt"
+	"sync"
+)
+
+// call is an in-flight or completed GetOrLoad call.
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// loadGroup deduplicates concurrent loads of the same key.
+type loadGroup[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+// do runs fn once per key at a time; concurrent callers wait for and share its result.
+func (g *loadGroup[K, V]) do(key K, fn func() (V, error)) (V, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.value, c.err
+	}
+	c := new(call[V])
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+	func() {
+		// a panicking loader must not leave waiters blocked forever
+		defer func() {
+			if r := recover(); r != nil {
+				c.err = fmt.Errorf("lru: loader panic: %v", r)
+			}
+		}()
+		c.value, c.err = fn()
+	}()
+	return c.value, c.err
+}
+
+// GetOrLoad returns the cached value for key, or calls loader to compute it and
+// adds the result to the cache. Concurrent calls for the same missing key share a
+// single loader call. Errors are returned to every waiting caller and not cached.
+func (c *Cache[K, V]) GetOrLoad(key K, loader func(key K) (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	return c.loads.do(key, func() (V, error) {
+		// another caller may have stored the value while we waited for the group lock
+		if value, ok := c.Peek(key); ok {
+			return value, nil
+		}
+		value, err := loader(key)
+		if err != nil {
+			return value, err
+		}
+		c.Add(key, value)
+		return value, nil
+	})
+}
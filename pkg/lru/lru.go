@@ -4,6 +4,9 @@
 package lru
 
 import (
+	"fmt"
+
+	"github.com/code-sigs/go-box/pkg/lru/internal"
 	"github.com/code-sigs/go-box/pkg/lru/simplelru"
 	"sync"
 )
@@ -15,13 +18,28 @@ const (
 
 // Cache is a thread-safe fixed size LRU cache.
 type Cache[K comparable, V any] struct {
-	lru         *simplelru.LRU[K, V]
+	lru         simplelru.LRUCache[K, V]
 	evictedKeys []K
 	evictedVals []V
-	onEvictedCB func(k K, v V)
+	onEvictedCB func(k K, v V, reason EvictReason)
 	lock        sync.RWMutex
+	loads       loadGroup[K, V]
+	stats       internal.Counters
 }
 
+// Stats is a point-in-time snapshot of cache statistics.
+type Stats = internal.Stats
+
+// EvictReason tells an eviction callback why an entry left the cache.
+type EvictReason = internal.EvictReason
+
+const (
+	EvictCapacity = internal.EvictCapacity // removed to make room or by Resize
+	EvictExpired  = internal.EvictExpired  // TTL passed
+	EvictRemoved  = internal.EvictRemoved  // Remove, RemoveOldest or RemoveMany
+	EvictPurged   = internal.EvictPurged   // Purge
+)
+
 // New creates an LRU of the given size.
 func New[K comparable, V any](size int) (*Cache[K, V], error) {
 	return NewWithEvict[K, V](size, nil)
@@ -30,15 +48,61 @@ func New[K comparable, V any](size int) (*Cache[K, V], error) {
 // NewWithEvict constructs a fixed size cache with the given eviction
 // callback.
 func NewWithEvict[K comparable, V any](size int, onEvicted func(key K, value V)) (c *Cache[K, V], err error) {
+	return NewWithPolicy(PolicyLRU, size, onEvicted)
+}
+
+// NewWithEvictReason constructs a fixed size cache whose eviction callback
+// also receives why the entry left the cache.
+func NewWithEvictReason[K comparable, V any](size int, onEvicted func(key K, value V, reason EvictReason)) (*Cache[K, V], error) {
+	return newCache(PolicyLRU, size, onEvicted)
+}
+
+// Policy selects the eviction algorithm of a Cache.
+type Policy string
+
+const (
+	// PolicyLRU evicts the least recently used entry.
+	PolicyLRU Policy = "lru"
+	// PolicyLFU evicts the least frequently used entry.
+	PolicyLFU Policy = "lfu"
+	// PolicyARC adapts between recency and frequency, resisting scans.
+	PolicyARC Policy = "arc"
+	// Policy2Q keeps entries seen once apart from frequently used ones, resisting scans.
+	Policy2Q Policy = "2q"
+)
+
+// NewWithPolicy constructs a fixed size cache using the given eviction policy
+// and an optional eviction callback.
+func NewWithPolicy[K comparable, V any](policy Policy, size int, onEvicted func(key K, value V)) (*Cache[K, V], error) {
+	var cb func(K, V, EvictReason)
+	if onEvicted != nil {
+		cb = func(k K, v V, _ EvictReason) { onEvicted(k, v) }
+	}
+	return newCache(policy, size, cb)
+}
+
+func newCache[K comparable, V any](policy Policy, size int, onEvictedCB func(K, V, EvictReason)) (c *Cache[K, V], err error) {
 	// create a cache with default settings
 	c = &Cache[K, V]{
-		onEvictedCB: onEvicted,
+		onEvictedCB: onEvictedCB,
 	}
-	if onEvicted != nil {
+	var onEvicted simplelru.EvictCallback[K, V]
+	if onEvictedCB != nil {
 		c.initEvictBuffers()
 		onEvicted = c.onEvicted
 	}
-	c.lru, err = simplelru.NewLRU(size, onEvicted)
+	switch policy {
+	case PolicyLRU, "":
+		c.lru, err = simplelru.NewLRU(size, onEvicted)
+	case PolicyLFU:
+		c.lru, err = simplelru.NewLFU(size, onEvicted)
+	case PolicyARC:
+		c.lru, err = simplelru.NewARC(size, onEvicted)
+	case Policy2Q:
+		c.lru, err = simplelru.New2Q(size, onEvicted)
+	default:
+		err = fmt.Errorf("unknown cache policy %q", policy)
+	}
 	return
 }
 
@@ -68,7 +132,7 @@ func (c *Cache[K, V]) Purge() {
 	// invoke callback outside of critical section
 	if c.onEvictedCB != nil {
 		for i := 0; i < len(ks); i++ {
-			c.onEvictedCB(ks[i], vs[i])
+			c.onEvictedCB(ks[i], vs[i], EvictPurged)
 		}
 	}
 }
@@ -79,13 +143,16 @@ func (c *Cache[K, V]) Add(key K, value V) (evicted bool) {
 	var v V
 	c.lock.Lock()
 	evicted = c.lru.Add(key, value)
+	if evicted {
+		c.stats.Evicted(1)
+	}
 	if c.onEvictedCB != nil && evicted {
 		k, v = c.evictedKeys[0], c.evictedVals[0]
 		c.evictedKeys, c.evictedVals = c.evictedKeys[:0], c.evictedVals[:0]
 	}
 	c.lock.Unlock()
 	if c.onEvictedCB != nil && evicted {
-		c.onEvictedCB(k, v)
+		c.onEvictedCB(k, v, EvictCapacity)
 	}
 	return
 }
@@ -94,6 +161,7 @@ func (c *Cache[K, V]) Add(key K, value V) (evicted bool) {
 func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	c.lock.Lock()
 	value, ok = c.lru.Get(key)
+	c.stats.Lookup(ok)
 	c.lock.Unlock()
 	return value, ok
 }
@@ -128,13 +196,16 @@ func (c *Cache[K, V]) ContainsOrAdd(key K, value V) (ok, evicted bool) {
 		return true, false
 	}
 	evicted = c.lru.Add(key, value)
+	if evicted {
+		c.stats.Evicted(1)
+	}
 	if c.onEvictedCB != nil && evicted {
 		k, v = c.evictedKeys[0], c.evictedVals[0]
 		c.evictedKeys, c.evictedVals = c.evictedKeys[:0], c.evictedVals[:0]
 	}
 	c.lock.Unlock()
 	if c.onEvictedCB != nil && evicted {
-		c.onEvictedCB(k, v)
+		c.onEvictedCB(k, v, EvictCapacity)
 	}
 	return false, evicted
 }
@@ -152,13 +223,16 @@ func (c *Cache[K, V]) PeekOrAdd(key K, value V) (previous V, ok, evicted bool) {
 		return previous, true, false
 	}
 	evicted = c.lru.Add(key, value)
+	if evicted {
+		c.stats.Evicted(1)
+	}
 	if c.onEvictedCB != nil && evicted {
 		k, v = c.evictedKeys[0], c.evictedVals[0]
 		c.evictedKeys, c.evictedVals = c.evictedKeys[:0], c.evictedVals[:0]
 	}
 	c.lock.Unlock()
 	if c.onEvictedCB != nil && evicted {
-		c.onEvictedCB(k, v)
+		c.onEvictedCB(k, v, EvictCapacity)
 	}
 	return
 }
@@ -175,7 +249,7 @@ func (c *Cache[K, V]) Remove(key K) (present bool) {
 	}
 	c.lock.Unlock()
 	if c.onEvictedCB != nil && present {
-		c.onEvictedCB(k, v)
+		c.onEvictedCB(k, v, EvictRemoved)
 	}
 	return
 }
@@ -186,6 +260,7 @@ func (c *Cache[K, V]) Resize(size int) (evicted int) {
 	var vs []V
 	c.lock.Lock()
 	evicted = c.lru.Resize(size)
+	c.stats.Evicted(evicted)
 	if c.onEvictedCB != nil && evicted > 0 {
 		ks, vs = c.evictedKeys, c.evictedVals
 		c.initEvictBuffers()
@@ -193,7 +268,7 @@ func (c *Cache[K, V]) Resize(size int) (evicted int) {
 	c.lock.Unlock()
 	if c.onEvictedCB != nil && evicted > 0 {
 		for i := 0; i < len(ks); i++ {
-			c.onEvictedCB(ks[i], vs[i])
+			c.onEvictedCB(ks[i], vs[i], EvictCapacity)
 		}
 	}
 	return evicted
@@ -211,7 +286,7 @@ func (c *Cache[K, V]) RemoveOldest() (key K, value V, ok bool) {
 	}
 	c.lock.Unlock()
 	if c.onEvictedCB != nil && ok {
-		c.onEvictedCB(k, v)
+		c.onEvictedCB(k, v, EvictRemoved)
 	}
 	return
 }
@@ -240,6 +315,14 @@ func (c *Cache[K, V]) Values() []V {
 	return values
 }
 
+// Range calls fn for each entry from oldest to newest until fn returns false.
+// The read lock is held during iteration, so fn must not modify the cache.
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	c.lock.RLock()
+	c.lru.Range(fn)
+	c.lock.RUnlock()
+}
+
 // Len returns the number of items in the cache.
 func (c *Cache[K, V]) Len() int {
 	c.lock.RLock()
@@ -252,3 +335,9 @@ func (c *Cache[K, V]) Len() int {
 func (c *Cache[K, V]) Cap() int {
 	return c.lru.Cap()
 }
+
+// Stats returns hit, miss and eviction counters together with the current size.
+// Only Get and GetOrLoad are counted as lookups.
+func (c *Cache[K, V]) Stats() Stats {
+	return c.stats.Snapshot(c.Len(), c.Cap())
+}
@@ -4,8 +4,15 @@
 package lru
 
 import (
+	"bytes"
+	"errors"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func BenchmarkLRU_Rand(b *testing.B) {
@@ -298,6 +305,191 @@ func TestLRUResize(t *testing.T) {
 	}
 }
 
+func TestNewWithPolicy(t *testing.T) {
+	for _, policy := range []Policy{PolicyLRU, PolicyLFU, PolicyARC, Policy2Q} {
+		var evicted int
+		l, err := NewWithPolicy(policy, 64, func(int, int) { evicted++ })
+		if err != nil {
+			t.Fatalf("%s: err: %v", policy, err)
+		}
+		for i := 0; i < 128; i++ {
+			l.Add(i, i)
+		}
+		if l.Len() != 64 || evicted != 64 {
+			t.Fatalf("%s: bad len %v, evicted %v", policy, l.Len(), evicted)
+		}
+		l.Remove(127)
+		l.Purge()
+		if evicted != 128 {
+			t.Fatalf("%s: bad evicted %v", policy, evicted)
+		}
+	}
+	if _, err := NewWithPolicy[int, int]("mru", 64, nil); err == nil {
+		t.Fatalf("expected unknown policy error")
+	}
+}
+
+func TestLRUBatch(t *testing.T) {
+	var evicted []int
+	l, err := NewWithEvict(3, func(k int, _ int) { evicted = append(evicted, k) })
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if n := l.AddMany(map[int]int{1: 1, 2: 2, 3: 3, 4: 4}); n != 1 || len(evicted) != 1 {
+		t.Fatalf("bad evictions: %v, callbacks %v", n, evicted)
+	}
+	got := l.GetMany([]int{1, 2, 3, 4, 5})
+	if len(got) != 3 {
+		t.Fatalf("bad values: %v", got)
+	}
+	for k, v := range got {
+		if k != v {
+			t.Fatalf("bad value for %v: %v", k, v)
+		}
+	}
+	if n := l.RemoveMany([]int{1, 2, 3, 4, 5}); n != 3 || l.Len() != 0 || len(evicted) != 4 {
+		t.Fatalf("bad remove: %v, len %v, callbacks %v", n, l.Len(), evicted)
+	}
+}
+
+func TestLRUEvictReason(t *testing.T) {
+	reasons := make(map[int]EvictReason)
+	l, err := NewWithEvictReason(2, func(k int, _ int, reason EvictReason) { reasons[k] = reason })
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Remove(2)
+	l.Add(4, 4)
+	l.RemoveMany([]int{4})
+	l.Purge()
+
+	want := map[int]EvictReason{1: EvictCapacity, 2: EvictRemoved, 3: EvictPurged, 4: EvictRemoved}
+	if !reflect.DeepEqual(reasons, want) {
+		t.Fatalf("bad reasons: %v, want %v", reasons, want)
+	}
+	if EvictExpired.String() != "expired" {
+		t.Fatalf("bad reason string: %v", EvictExpired)
+	}
+}
+
+func TestLRURange(t *testing.T) {
+	l, _ := New[int, int](4)
+	for i := 0; i < 4; i++ {
+		l.Add(i, i)
+	}
+	sum := 0
+	l.Range(func(k, v int) bool {
+		sum += v
+		return k < 2
+	})
+	if sum != 3 {
+		t.Fatalf("bad sum: %v", sum)
+	}
+}
+
+func TestLRUSnapshot(t *testing.T) {
+	l, _ := New[string, int](3)
+	l.Add("a", 1)
+	l.Add("b", 2)
+	l.Add("c", 3)
+	l.Get("a")
+
+	var buf bytes.Buffer
+	if err := l.Save(&buf); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	restored, _ := New[string, int](3)
+	n, err := restored.Load(&buf)
+	if err != nil || n != 3 {
+		t.Fatalf("bad load: %v, err: %v", n, err)
+	}
+	restored.wantKeys(t, []string{"b", "c", "a"})
+	if v, ok := restored.Get("c"); !ok || v != 3 {
+		t.Fatalf("bad value: %v", v)
+	}
+}
+
+func TestLRUStats(t *testing.T) {
+	l, err := New[int, int](2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Get(3)
+	l.Get(1)
+	l.Resize(1)
+
+	want := Stats{Hits: 1, Misses: 1, Evictions: 2, Len: 1, Cap: 1}
+	if got := l.Stats(); got != want {
+		t.Fatalf("bad stats: %+v, want %+v", got, want)
+	}
+	if r := want.HitRatio(); r != 0.5 {
+		t.Fatalf("bad hit ratio: %v", r)
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(NewCollector(map[string]StatsSource{"test": l}))
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(families) != 6 {
+		t.Fatalf("bad metric families: %d", len(families))
+	}
+}
+
+func TestLRUGetOrLoad(t *testing.T) {
+	l, err := New[int, int](8)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	var loads atomic.Int32
+	loader := func(k int) (int, error) {
+		loads.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return k * 2, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := l.GetOrLoad(1, loader)
+			if err != nil || v != 2 {
+				t.Errorf("bad value: %v, err: %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("loader called %d times", n)
+	}
+	if v, ok := l.Get(1); !ok || v != 2 {
+		t.Fatalf("value not cached")
+	}
+
+	errLoad := errors.New("load failed")
+	if _, err := l.GetOrLoad(2, func(int) (int, error) { return 0, errLoad }); !errors.Is(err, errLoad) {
+		t.Fatalf("bad err: %v", err)
+	}
+	if l.Contains(2) {
+		t.Fatalf("failed load should not be cached")
+	}
+	if _, err := l.GetOrLoad(3, func(int) (int, error) { panic("boom") }); err == nil {
+		t.Fatalf("loader panic should be returned as error")
+	}
+}
+
 func (c *Cache[K, V]) wantKeys(t *testing.T, want []K) {
 	t.Helper()
 	got := c.Keys()
@@ -0,0 +1,276 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package simplelru
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// Default2QRecentRatio is the ratio of the 2Q cache dedicated
+	// to recently added entries that have only been accessed once.
+	Default2QRecentRatio = 0.25
+
+	// Default2QGhostEntries is the default ratio of ghost
+	// entries kept to track entries recently evicted
+	Default2QGhostEntries = 0.50
+)
+
+// TwoQueue implements a non-thread safe fixed size 2Q cache.
+// 2Q is an enhancement over the standard LRU cache
+// in that it tracks both frequently and recently used
+// entries separately. This avoids a burst in access to new
+// entries from evicting frequently used entries. It adds some
+// additional tracking overhead to the standard LRU cache, and is
+// computationally about 2x the cost, and adds some metadata over
+// head.
+type TwoQueue[K comparable, V any] struct {
+	size        int
+	recentSize  int
+	recentRatio float64
+	ghostRatio  float64
+
+	recent      *LRU[K, V]
+	frequent    *LRU[K, V]
+	recentEvict *LRU[K, struct{}]
+
+	onEvict EvictCallback[K, V]
+}
+
+// New2Q creates a new TwoQueue using the default
+// values for the parameters.
+func New2Q[K comparable, V any](size int, onEvict EvictCallback[K, V]) (*TwoQueue[K, V], error) {
+	return New2QParams[K, V](size, Default2QRecentRatio, Default2QGhostEntries, onEvict)
+}
+
+// New2QParams creates a new TwoQueue using the provided
+// parameter values.
+func New2QParams[K comparable, V any](size int, recentRatio, ghostRatio float64, onEvict EvictCallback[K, V]) (*TwoQueue[K, V], error) {
+	if size <= 0 {
+		return nil, errors.New("must provide a positive size")
+	}
+	if recentRatio < 0.0 || recentRatio > 1.0 {
+		return nil, fmt.Errorf("invalid recent ratio")
+	}
+	if ghostRatio < 0.0 || ghostRatio > 1.0 {
+		return nil, fmt.Errorf("invalid ghost ratio")
+	}
+
+	// Allocate the LRUs, entries only leave them through this cache
+	recent, _ := NewLRU[K, V](size, nil)
+	frequent, _ := NewLRU[K, V](size, nil)
+	recentEvict, _ := NewLRU[K, struct{}](ghostSize(size, ghostRatio), nil)
+
+	c := &TwoQueue[K, V]{
+		size:        size,
+		recentSize:  int(float64(size) * recentRatio),
+		recentRatio: recentRatio,
+		ghostRatio:  ghostRatio,
+		recent:      recent,
+		frequent:    frequent,
+		recentEvict: recentEvict,
+		onEvict:     onEvict,
+	}
+	return c, nil
+}
+
+// ghostSize determines the number of ghost entries, at least one so the ghost LRU is valid
+func ghostSize(size int, ghostRatio float64) int {
+	return max(int(float64(size)*ghostRatio), 1)
+}
+
+// Get looks up a key's value from the cache.
+func (c *TwoQueue[K, V]) Get(key K) (value V, ok bool) {
+	// Check if this is a frequent value
+	if val, ok := c.frequent.Get(key); ok {
+		return val, ok
+	}
+
+	// If the value is contained in recent, then we
+	// promote it to frequent
+	if val, ok := c.recent.Peek(key); ok {
+		c.recent.Remove(key)
+		c.frequent.Add(key, val)
+		return val, ok
+	}
+
+	// No hit
+	return
+}
+
+// Add adds a value to the cache. Returns true if an eviction occurred.
+func (c *TwoQueue[K, V]) Add(key K, value V) (evicted bool) {
+	// Check if the value is frequently used already,
+	// and just update the value
+	if c.frequent.Contains(key) {
+		c.frequent.Add(key, value)
+		return false
+	}
+
+	// Check if the value is recently used, and promote
+	// the value into the frequent list
+	if c.recent.Contains(key) {
+		c.recent.Remove(key)
+		c.frequent.Add(key, value)
+		return false
+	}
+
+	// If the value was recently evicted, add it to the
+	// frequently used list
+	if c.recentEvict.Contains(key) {
+		evicted = c.ensureSpace(true)
+		c.recentEvict.Remove(key)
+		c.frequent.Add(key, value)
+		return evicted
+	}
+
+	// Add to the recently seen list
+	evicted = c.ensureSpace(false)
+	c.recent.Add(key, value)
+	return evicted
+}
+
+// ensureSpace is used to ensure we have space in the cache
+func (c *TwoQueue[K, V]) ensureSpace(recentEvict bool) bool {
+	// If we have space, nothing to do
+	recentLen := c.recent.Len()
+	freqLen := c.frequent.Len()
+	if recentLen+freqLen < c.size {
+		return false
+	}
+	return c.evictOne(recentEvict)
+}
+
+// evictOne evicts a single entry, preferring the recent list when it is over its target size
+func (c *TwoQueue[K, V]) evictOne(recentEvict bool) bool {
+	recentLen := c.recent.Len()
+	if recentLen > 0 && (recentLen > c.recentSize || (recentLen == c.recentSize && !recentEvict) || c.frequent.Len() == 0) {
+		k, v, ok := c.recent.RemoveOldest()
+		if ok {
+			c.recentEvict.Add(k, struct{}{})
+			c.evict(k, v)
+		}
+		return ok
+	}
+
+	// Remove from the frequent list otherwise
+	k, v, ok := c.frequent.RemoveOldest()
+	if ok {
+		c.evict(k, v)
+	}
+	return ok
+}
+
+func (c *TwoQueue[K, V]) evict(key K, value V) {
+	if c.onEvict != nil {
+		c.onEvict(key, value)
+	}
+}
+
+// Contains is used to check if the cache contains a key
+// without updating recency or frequency.
+func (c *TwoQueue[K, V]) Contains(key K) bool {
+	return c.frequent.Contains(key) || c.recent.Contains(key)
+}
+
+// Peek is used to inspect the cache value of a key
+// without updating recency or frequency.
+func (c *TwoQueue[K, V]) Peek(key K) (value V, ok bool) {
+	if val, ok := c.frequent.Peek(key); ok {
+		return val, ok
+	}
+	return c.recent.Peek(key)
+}
+
+// Remove removes the provided key from the cache.
+func (c *TwoQueue[K, V]) Remove(key K) bool {
+	c.recentEvict.Remove(key)
+	for _, l := range []*LRU[K, V]{c.frequent, c.recent} {
+		if val, ok := l.Peek(key); ok {
+			l.Remove(key)
+			c.evict(key, val)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveOldest removes the oldest entry, preferring the recent list.
+func (c *TwoQueue[K, V]) RemoveOldest() (key K, value V, ok bool) {
+	if key, value, ok = c.recent.RemoveOldest(); !ok {
+		key, value, ok = c.frequent.RemoveOldest()
+	}
+	if ok {
+		c.evict(key, value)
+	}
+	return
+}
+
+// GetOldest returns the entry RemoveOldest would remove.
+func (c *TwoQueue[K, V]) GetOldest() (key K, value V, ok bool) {
+	if key, value, ok = c.recent.GetOldest(); ok {
+		return
+	}
+	return c.frequent.GetOldest()
+}
+
+// Keys returns a slice of the keys in the cache.
+// The frequently used keys are first in the returned slice.
+func (c *TwoQueue[K, V]) Keys() []K {
+	return append(c.frequent.Keys(), c.recent.Keys()...)
+}
+
+// Values returns a slice of the values in the cache.
+// The frequently used values are first in the returned slice.
+func (c *TwoQueue[K, V]) Values() []V {
+	return append(c.frequent.Values(), c.recent.Values()...)
+}
+
+// Range calls fn for each entry in the same order as Keys until fn returns false.
+func (c *TwoQueue[K, V]) Range(fn func(key K, value V) bool) {
+	stopped := false
+	c.frequent.Range(func(k K, v V) bool {
+		stopped = !fn(k, v)
+		return !stopped
+	})
+	if !stopped {
+		c.recent.Range(fn)
+	}
+}
+
+// Len returns the number of items in the cache.
+func (c *TwoQueue[K, V]) Len() int {
+	return c.recent.Len() + c.frequent.Len()
+}
+
+// Cap returns the capacity of the cache
+func (c *TwoQueue[K, V]) Cap() int {
+	return c.size
+}
+
+// Purge is used to completely clear the cache.
+func (c *TwoQueue[K, V]) Purge() {
+	for _, l := range []*LRU[K, V]{c.recent, c.frequent} {
+		for _, k := range l.Keys() {
+			v, _ := l.Peek(k)
+			c.evict(k, v)
+		}
+		l.Purge()
+	}
+	c.recentEvict.Purge()
+}
+
+// Resize changes the cache size.
+func (c *TwoQueue[K, V]) Resize(size int) (evicted int) {
+	c.size = size
+	c.recentSize = int(float64(size) * c.recentRatio)
+	for c.Len() > size && c.evictOne(false) {
+		evicted++
+	}
+	c.recent.Resize(size)
+	c.frequent.Resize(size)
+	c.recentEvict.Resize(ghostSize(size, c.ghostRatio))
+	return evicted
+}
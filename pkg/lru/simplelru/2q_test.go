@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package simplelru
+
+import (
+	"testing"
+)
+
+func Test2QInterface(_ *testing.T) {
+	var _ LRUCache[int, int] = &TwoQueue[int, int]{}
+}
+
+func Test2Q(t *testing.T) {
+	evictCounter := 0
+	l, err := New2Q(128, func(k int, v int) {
+		if k != v {
+			t.Fatalf("Evict values not equal (%v!=%v)", k, v)
+		}
+		evictCounter++
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 256; i++ {
+		l.Add(i, i)
+	}
+	if l.Len() != 128 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+	if evictCounter != 128 {
+		t.Fatalf("bad evict count: %v", evictCounter)
+	}
+
+	for i, k := range l.Keys() {
+		if v, ok := l.Get(k); !ok || v != k || v != i+128 {
+			t.Fatalf("bad key: %v", k)
+		}
+	}
+	for i := 0; i < 128; i++ {
+		if _, ok := l.Get(i); ok {
+			t.Fatalf("should be evicted")
+		}
+	}
+	for i := 128; i < 192; i++ {
+		if !l.Remove(i) || l.Remove(i) {
+			t.Fatalf("bad remove %v", i)
+		}
+	}
+
+	l.Purge()
+	if l.Len() != 0 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+	if evictCounter != 256 {
+		t.Fatalf("bad evict count: %v", evictCounter)
+	}
+}
+
+// Test that frequently used entries survive a scan
+func Test2Q_Scan(t *testing.T) {
+	l, err := New2Q[int, int](4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Get(1)
+	l.Get(2)
+	for i := 100; i < 200; i++ {
+		l.Add(i, i)
+	}
+	if !l.Contains(1) || !l.Contains(2) {
+		t.Fatalf("frequent entries should survive a scan: %v", l.Keys())
+	}
+	if l.Len() != 4 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+
+	if evicted := l.Resize(2); evicted != 2 || l.Len() != 2 {
+		t.Fatalf("bad resize: evicted %v, len %v", evicted, l.Len())
+	}
+	if _, err := New2QParams[int, int](4, 2, 0.5, nil); err == nil {
+		t.Fatalf("expected invalid ratio error")
+	}
+}
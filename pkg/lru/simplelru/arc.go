@@ -0,0 +1,285 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package simplelru
+
+import "errors"
+
+// ARC implements a non-thread safe fixed size Adaptive Replacement Cache (ARC).
+// ARC is an enhancement over the standard LRU cache in that tracks both
+// frequency and recency of use. This avoids a burst in access to new
+// entries from evicting the frequently used older entries. It adds some
+// additional tracking overhead to a standard LRU cache, computationally
+// it is roughly 2x the cost, and the extra memory overhead is linear
+// with the size of the cache.
+type ARC[K comparable, V any] struct {
+	size int // Size is the total capacity of the cache
+	p    int // P is the dynamic preference towards T1 or T2
+
+	t1 *LRU[K, V]        // T1 is the LRU for recently accessed items
+	b1 *LRU[K, struct{}] // B1 is the LRU for evictions from t1
+
+	t2 *LRU[K, V]        // T2 is the LRU for frequently accessed items
+	b2 *LRU[K, struct{}] // B2 is the LRU for evictions from t2
+
+	onEvict EvictCallback[K, V]
+}
+
+// NewARC creates an ARC of the given size
+func NewARC[K comparable, V any](size int, onEvict EvictCallback[K, V]) (*ARC[K, V], error) {
+	if size <= 0 {
+		return nil, errors.New("must provide a positive size")
+	}
+	// Create the sub LRUs, entries only leave them through this cache
+	t1, _ := NewLRU[K, V](size, nil)
+	b1, _ := NewLRU[K, struct{}](size, nil)
+	t2, _ := NewLRU[K, V](size, nil)
+	b2, _ := NewLRU[K, struct{}](size, nil)
+
+	c := &ARC[K, V]{
+		size:    size,
+		t1:      t1,
+		b1:      b1,
+		t2:      t2,
+		b2:      b2,
+		onEvict: onEvict,
+	}
+	return c, nil
+}
+
+// Get looks up a key's value from the cache.
+func (c *ARC[K, V]) Get(key K) (value V, ok bool) {
+	// If the value is contained in T1 (recent), then
+	// promote it to T2 (frequent)
+	if val, ok := c.t1.Peek(key); ok {
+		c.t1.Remove(key)
+		c.t2.Add(key, val)
+		return val, ok
+	}
+
+	// Check if the value is contained in T2 (frequent)
+	if val, ok := c.t2.Get(key); ok {
+		return val, ok
+	}
+
+	// No hit
+	return
+}
+
+// Add adds a value to the cache. Returns true if an eviction occurred.
+func (c *ARC[K, V]) Add(key K, value V) (evicted bool) {
+	// Check if the value is contained in T1 (recent), and potentially
+	// promote it to frequent T2
+	if c.t1.Contains(key) {
+		c.t1.Remove(key)
+		c.t2.Add(key, value)
+		return false
+	}
+
+	// Check if the value is already in T2 (frequent) and update it
+	if c.t2.Contains(key) {
+		c.t2.Add(key, value)
+		return false
+	}
+
+	// Check if this value was recently evicted as part of the
+	// recently used list
+	if c.b1.Contains(key) {
+		// T1 set is too small, increase P appropriately
+		delta := 1
+		b1Len := c.b1.Len()
+		b2Len := c.b2.Len()
+		if b2Len > b1Len {
+			delta = b2Len / b1Len
+		}
+		c.p = min(c.p+delta, c.size)
+
+		// Potentially need to make room in the cache
+		if c.t1.Len()+c.t2.Len() >= c.size {
+			evicted = c.replace(false)
+		}
+
+		// Remove from B1
+		c.b1.Remove(key)
+
+		// Add the key to the frequently used list
+		c.t2.Add(key, value)
+		return evicted
+	}
+
+	// Check if this value was recently evicted as part of the
+	// frequently used list
+	if c.b2.Contains(key) {
+		// T2 set is too small, decrease P appropriately
+		delta := 1
+		b1Len := c.b1.Len()
+		b2Len := c.b2.Len()
+		if b1Len > b2Len {
+			delta = b1Len / b2Len
+		}
+		c.p = max(c.p-delta, 0)
+
+		// Potentially need to make room in the cache
+		if c.t1.Len()+c.t2.Len() >= c.size {
+			evicted = c.replace(true)
+		}
+
+		// Remove from B2
+		c.b2.Remove(key)
+
+		// Add the key to the frequently used list
+		c.t2.Add(key, value)
+		return evicted
+	}
+
+	// Potentially need to make room in the cache
+	if c.t1.Len()+c.t2.Len() >= c.size {
+		evicted = c.replace(false)
+	}
+
+	// Keep the size of the ghost buffers trim
+	if c.b1.Len() > c.size-c.p {
+		c.b1.RemoveOldest()
+	}
+	if c.b2.Len() > c.p {
+		c.b2.RemoveOldest()
+	}
+
+	// Add to the recently seen list
+	c.t1.Add(key, value)
+	return evicted
+}
+
+// replace is used to adaptively evict from either T1 or T2
+// based on the current learned value of P
+func (c *ARC[K, V]) replace(b2ContainsKey bool) bool {
+	t1Len := c.t1.Len()
+	if t1Len > 0 && (t1Len > c.p || (t1Len == c.p && b2ContainsKey) || c.t2.Len() == 0) {
+		k, v, ok := c.t1.RemoveOldest()
+		if ok {
+			c.b1.Add(k, struct{}{})
+			c.evict(k, v)
+		}
+		return ok
+	}
+	k, v, ok := c.t2.RemoveOldest()
+	if ok {
+		c.b2.Add(k, struct{}{})
+		c.evict(k, v)
+	}
+	return ok
+}
+
+func (c *ARC[K, V]) evict(key K, value V) {
+	if c.onEvict != nil {
+		c.onEvict(key, value)
+	}
+}
+
+// Contains is used to check if the cache contains a key
+// without updating recency or frequency.
+func (c *ARC[K, V]) Contains(key K) bool {
+	return c.t1.Contains(key) || c.t2.Contains(key)
+}
+
+// Peek is used to inspect the cache value of a key
+// without updating recency or frequency.
+func (c *ARC[K, V]) Peek(key K) (value V, ok bool) {
+	if val, ok := c.t1.Peek(key); ok {
+		return val, ok
+	}
+	return c.t2.Peek(key)
+}
+
+// Remove is used to purge a key from the cache
+func (c *ARC[K, V]) Remove(key K) bool {
+	c.b1.Remove(key)
+	c.b2.Remove(key)
+	for _, l := range []*LRU[K, V]{c.t1, c.t2} {
+		if val, ok := l.Peek(key); ok {
+			l.Remove(key)
+			c.evict(key, val)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveOldest removes the next entry ARC would evict, preferring T1 (recent).
+func (c *ARC[K, V]) RemoveOldest() (key K, value V, ok bool) {
+	if key, value, ok = c.t1.RemoveOldest(); !ok {
+		key, value, ok = c.t2.RemoveOldest()
+	}
+	if ok {
+		c.evict(key, value)
+	}
+	return
+}
+
+// GetOldest returns the entry RemoveOldest would remove.
+func (c *ARC[K, V]) GetOldest() (key K, value V, ok bool) {
+	if key, value, ok = c.t1.GetOldest(); ok {
+		return
+	}
+	return c.t2.GetOldest()
+}
+
+// Keys returns all the cached keys, recent entries (T1) first.
+func (c *ARC[K, V]) Keys() []K {
+	return append(c.t1.Keys(), c.t2.Keys()...)
+}
+
+// Values returns all the cached values, in the same order as Keys.
+func (c *ARC[K, V]) Values() []V {
+	return append(c.t1.Values(), c.t2.Values()...)
+}
+
+// Range calls fn for each entry in the same order as Keys until fn returns false.
+func (c *ARC[K, V]) Range(fn func(key K, value V) bool) {
+	stopped := false
+	c.t1.Range(func(k K, v V) bool {
+		stopped = !fn(k, v)
+		return !stopped
+	})
+	if !stopped {
+		c.t2.Range(fn)
+	}
+}
+
+// Len returns the number of cached entries
+func (c *ARC[K, V]) Len() int {
+	return c.t1.Len() + c.t2.Len()
+}
+
+// Cap returns the capacity of the cache
+func (c *ARC[K, V]) Cap() int {
+	return c.size
+}
+
+// Purge is used to clear the cache
+func (c *ARC[K, V]) Purge() {
+	for _, l := range []*LRU[K, V]{c.t1, c.t2} {
+		for _, k := range l.Keys() {
+			v, _ := l.Peek(k)
+			c.evict(k, v)
+		}
+		l.Purge()
+	}
+	c.b1.Purge()
+	c.b2.Purge()
+	c.p = 0
+}
+
+// Resize changes the cache size.
+func (c *ARC[K, V]) Resize(size int) (evicted int) {
+	for c.Len() > size && c.replace(false) {
+		evicted++
+	}
+	c.size = size
+	c.p = min(c.p, size)
+	c.t1.Resize(size)
+	c.t2.Resize(size)
+	c.b1.Resize(size)
+	c.b2.Resize(size)
+	return evicted
+}
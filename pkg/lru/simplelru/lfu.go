@@ -0,0 +1,248 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package simplelru
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/code-sigs/go-box/pkg/lru/internal"
+)
+
+// LFU implements a non-thread safe fixed size least-frequently-used cache.
+// Entries with the lowest access count are evicted first, ties are broken
+// by recency. All operations except Keys and Values are O(1).
+type LFU[K comparable, V any] struct {
+	size    int
+	items   map[K]*internal.Entry[K, V]
+	counts  map[K]int                       // access count of each key
+	lists   map[int]*internal.LruList[K, V] // entries grouped by access count
+	minFreq int
+	onEvict EvictCallback[K, V]
+}
+
+// NewLFU constructs an LFU of the given size
+func NewLFU[K comparable, V any](size int, onEvict EvictCallback[K, V]) (*LFU[K, V], error) {
+	if size <= 0 {
+		return nil, errors.New("must provide a positive size")
+	}
+	c := &LFU[K, V]{
+		size:    size,
+		items:   make(map[K]*internal.Entry[K, V]),
+		counts:  make(map[K]int),
+		lists:   make(map[int]*internal.LruList[K, V]),
+		onEvict: onEvict,
+	}
+	return c, nil
+}
+
+// Purge is used to completely clear the cache.
+func (c *LFU[K, V]) Purge() {
+	for k, ent := range c.items {
+		if c.onEvict != nil {
+			c.onEvict(k, ent.Value)
+		}
+	}
+	clear(c.items)
+	clear(c.counts)
+	clear(c.lists)
+	c.minFreq = 0
+}
+
+// Add adds a value to the cache. Returns true if an eviction occurred.
+// Updating an existing key counts as an access.
+func (c *LFU[K, V]) Add(key K, value V) (evicted bool) {
+	if ent, ok := c.items[key]; ok {
+		ent.Value = value
+		c.touch(ent)
+		return false
+	}
+
+	evicted = len(c.items) >= c.size
+	if evicted {
+		c.removeOldest()
+	}
+	c.items[key] = c.list(1).PushFront(key, value)
+	c.counts[key] = 1
+	c.minFreq = 1
+	return evicted
+}
+
+// Get looks up a key's value from the cache, incrementing its access count.
+func (c *LFU[K, V]) Get(key K) (value V, ok bool) {
+	if ent, ok := c.items[key]; ok {
+		c.touch(ent)
+		return ent.Value, true
+	}
+	return
+}
+
+// Contains checks if a key is in the cache, without updating the access count.
+func (c *LFU[K, V]) Contains(key K) (ok bool) {
+	_, ok = c.items[key]
+	return ok
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the access count of the key.
+func (c *LFU[K, V]) Peek(key K) (value V, ok bool) {
+	if ent, ok := c.items[key]; ok {
+		return ent.Value, true
+	}
+	return
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *LFU[K, V]) Remove(key K) (present bool) {
+	if ent, ok := c.items[key]; ok {
+		c.removeElement(ent)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest removes the least frequently used item from the cache.
+func (c *LFU[K, V]) RemoveOldest() (key K, value V, ok bool) {
+	if ent := c.oldest(); ent != nil {
+		c.removeElement(ent)
+		return ent.Key, ent.Value, true
+	}
+	return
+}
+
+// GetOldest returns the least frequently used entry
+func (c *LFU[K, V]) GetOldest() (key K, value V, ok bool) {
+	if ent := c.oldest(); ent != nil {
+		return ent.Key, ent.Value, true
+	}
+	return
+}
+
+// Keys returns a slice of the keys in the cache, in eviction order.
+func (c *LFU[K, V]) Keys() []K {
+	keys := make([]K, 0, len(c.items))
+	c.walk(func(ent *internal.Entry[K, V]) bool {
+		keys = append(keys, ent.Key)
+		return true
+	})
+	return keys
+}
+
+// Values returns a slice of the values in the cache, in eviction order.
+func (c *LFU[K, V]) Values() []V {
+	values := make([]V, 0, len(c.items))
+	c.walk(func(ent *internal.Entry[K, V]) bool {
+		values = append(values, ent.Value)
+		return true
+	})
+	return values
+}
+
+// Range calls fn for each entry in eviction order until fn returns false.
+func (c *LFU[K, V]) Range(fn func(key K, value V) bool) {
+	c.walk(func(ent *internal.Entry[K, V]) bool { return fn(ent.Key, ent.Value) })
+}
+
+// Len returns the number of items in the cache.
+func (c *LFU[K, V]) Len() int {
+	return len(c.items)
+}
+
+// Cap returns the capacity of the cache
+func (c *LFU[K, V]) Cap() int {
+	return c.size
+}
+
+// Resize changes the cache size.
+func (c *LFU[K, V]) Resize(size int) (evicted int) {
+	diff := c.Len() - size
+	if diff < 0 {
+		diff = 0
+	}
+	for i := 0; i < diff; i++ {
+		c.removeOldest()
+	}
+	c.size = size
+	return diff
+}
+
+// walk visits entries from least to most frequently used until fn returns false.
+func (c *LFU[K, V]) walk(fn func(ent *internal.Entry[K, V]) bool) {
+	freqs := make([]int, 0, len(c.lists))
+	for f := range c.lists {
+		freqs = append(freqs, f)
+	}
+	sort.Ints(freqs)
+	for _, f := range freqs {
+		for ent := c.lists[f].Back(); ent != nil; ent = ent.PrevEntry() {
+			if !fn(ent) {
+				return
+			}
+		}
+	}
+}
+
+// list returns the list for the given access count, creating it if needed.
+func (c *LFU[K, V]) list(freq int) *internal.LruList[K, V] {
+	l, ok := c.lists[freq]
+	if !ok {
+		l = internal.NewList[K, V]()
+		c.lists[freq] = l
+	}
+	return l
+}
+
+// touch moves the entry to the list of the next access count.
+func (c *LFU[K, V]) touch(ent *internal.Entry[K, V]) {
+	freq := c.counts[ent.Key]
+	c.unlink(ent, freq)
+	c.items[ent.Key] = c.list(freq+1).PushFront(ent.Key, ent.Value)
+	c.counts[ent.Key] = freq + 1
+	if c.minFreq == freq && c.lists[freq] == nil {
+		c.minFreq = freq + 1
+	}
+}
+
+// unlink removes the entry from its frequency list, dropping empty lists.
+func (c *LFU[K, V]) unlink(ent *internal.Entry[K, V], freq int) {
+	l := c.lists[freq]
+	l.Remove(ent)
+	if l.Length() == 0 {
+		delete(c.lists, freq)
+	}
+}
+
+// oldest returns the entry to evict next.
+func (c *LFU[K, V]) oldest() *internal.Entry[K, V] {
+	if len(c.items) == 0 {
+		return nil
+	}
+	if _, ok := c.lists[c.minFreq]; !ok {
+		c.minFreq = 0
+		for f := range c.lists {
+			if c.minFreq == 0 || f < c.minFreq {
+				c.minFreq = f
+			}
+		}
+	}
+	return c.lists[c.minFreq].Back()
+}
+
+// removeOldest removes the least frequently used item from the cache.
+func (c *LFU[K, V]) removeOldest() {
+	if ent := c.oldest(); ent != nil {
+		c.removeElement(ent)
+	}
+}
+
+// removeElement is used to remove a given element from the cache
+func (c *LFU[K, V]) removeElement(e *internal.Entry[K, V]) {
+	c.unlink(e, c.counts[e.Key])
+	delete(c.items, e.Key)
+	delete(c.counts, e.Key)
+	if c.onEvict != nil {
+		c.onEvict(e.Key, e.Value)
+	}
+}
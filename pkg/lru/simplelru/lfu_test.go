@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package simplelru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLFUInterface(_ *testing.T) {
+	var _ LRUCache[int, int] = &LFU[int, int]{}
+}
+
+func TestLFU(t *testing.T) {
+	var evicted []int
+	l, err := NewLFU(3, func(k int, _ int) { evicted = append(evicted, k) })
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Get(1)
+	l.Get(1)
+	l.Get(3)
+
+	// 2 has the lowest count
+	if !l.Add(4, 4) {
+		t.Fatalf("expected eviction")
+	}
+	if !reflect.DeepEqual(evicted, []int{2}) {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+	// 4 has count 1, 3 has count 2, 1 has count 3
+	if got := l.Keys(); !reflect.DeepEqual(got, []int{4, 3, 1}) {
+		t.Fatalf("bad keys: %v", got)
+	}
+	if k, _, ok := l.GetOldest(); !ok || k != 4 {
+		t.Fatalf("bad oldest: %v", k)
+	}
+
+	// Peek and Contains must not change counts
+	l.Peek(4)
+	l.Contains(4)
+	if k, _, ok := l.RemoveOldest(); !ok || k != 4 {
+		t.Fatalf("bad removed oldest: %v", k)
+	}
+
+	if !l.Remove(1) || l.Remove(1) {
+		t.Fatalf("bad remove")
+	}
+	if l.Len() != 1 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+	if v, ok := l.Get(3); !ok || v != 3 {
+		t.Fatalf("bad value: %v", v)
+	}
+
+	l.Add(5, 5)
+	l.Add(6, 6)
+	if evicted := l.Resize(1); evicted != 2 {
+		t.Fatalf("bad resize evicted: %v", evicted)
+	}
+	if got := l.Keys(); !reflect.DeepEqual(got, []int{3}) {
+		t.Fatalf("bad keys: %v", got)
+	}
+
+	l.Purge()
+	if l.Len() != 0 || len(l.Values()) != 0 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+	if _, _, ok := l.RemoveOldest(); ok {
+		t.Fatalf("should be empty")
+	}
+}
@@ -141,6 +141,15 @@ func (c *LRU[K, V]) Values() []V {
 	return values
 }
 
+// Range calls fn for each entry from oldest to newest until fn returns false.
+func (c *LRU[K, V]) Range(fn func(key K, value V) bool) {
+	for ent := c.evictList.Back(); ent != nil; ent = ent.PrevEntry() {
+		if !fn(ent.Key, ent.Value) {
+			return
+		}
+	}
+}
+
 // Len returns the number of items in the cache.
 func (c *LRU[K, V]) Len() int {
 	return c.evictList.Length()
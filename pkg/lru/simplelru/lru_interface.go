@@ -35,6 +35,10 @@ type LRUCache[K comparable, V any] interface {
 	// Values returns a slice of the values in the cache, from oldest to newest.
 	Values() []V
 
+	// Range calls fn for each entry in the same order as Keys, without copying,
+	// until fn returns false.
+	Range(fn func(key K, value V) bool)
+
 	// Returns the number of items in the cache.
 	Len() int
 
@@ -256,3 +256,39 @@ func TestCache_EvictionSameKey(t *testing.T) {
 		t.Errorf("evictedKeys got: %v want: %v", evictedKeys, want)
 	}
 }
+
+// Test that Range visits entries in Keys order for every policy and stops early
+func TestRange(t *testing.T) {
+	lru, _ := NewLRU[int, int](8, nil)
+	lfu, _ := NewLFU[int, int](8, nil)
+	arc, _ := NewARC[int, int](8, nil)
+	twoQ, _ := New2Q[int, int](8, nil)
+
+	for _, l := range []LRUCache[int, int]{lru, lfu, arc, twoQ} {
+		for i := 0; i < 5; i++ {
+			l.Add(i, i*10)
+		}
+		l.Get(2)
+
+		var keys []int
+		l.Range(func(k, v int) bool {
+			if v != k*10 {
+				t.Fatalf("%T: bad value for %v: %v", l, k, v)
+			}
+			keys = append(keys, k)
+			return true
+		})
+		if !reflect.DeepEqual(keys, l.Keys()) {
+			t.Fatalf("%T: bad range order: %v, keys %v", l, keys, l.Keys())
+		}
+
+		n := 0
+		l.Range(func(int, int) bool {
+			n++
+			return n < 2
+		})
+		if n != 2 {
+			t.Fatalf("%T: range did not stop: %v", l, n)
+		}
+	}
+}
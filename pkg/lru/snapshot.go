@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lru
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+// snapshot is the gob-encoded form written by Save.
+type snapshot[K comparable, V any] struct {
+	Keys   []K // oldest to newest
+	Values []V
+}
+
+// Save writes all entries to w using gob, so the cache can be restored with
+// Load after a restart. Keys and values must be gob-encodable.
+func (c *Cache[K, V]) Save(w io.Writer) error {
+	c.lock.RLock()
+	snap := snapshot[K, V]{Keys: c.lru.Keys(), Values: c.lru.Values()}
+	c.lock.RUnlock()
+	if err := gob.NewEncoder(w).Encode(snap); err != nil {
+		return fmt.Errorf("lru: encode snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load adds the entries written by Save, oldest first so that recency order
+// is preserved. Returns the number of entries loaded.
+func (c *Cache[K, V]) Load(r io.Reader) (int, error) {
+	var snap snapshot[K, V]
+	if err := gob.NewDecoder(r).Decode(&snap); err != nil {
+		return 0, fmt.Errorf("lru: decode snapshot: %w", err)
+	}
+	if len(snap.Keys) != len(snap.Values) {
+		return 0, fmt.Errorf("lru: corrupt snapshot: %d keys, %d values", len(snap.Keys), len(snap.Values))
+	}
+	for i, k := range snap.Keys {
+		c.Add(k, snap.Values[i])
+	}
+	return len(snap.Keys), nil
+}
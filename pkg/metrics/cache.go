@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"github.com/code-sigs/go-box/pkg/lru"
+)
+
+// RegisterCache 导出 LRU 缓存的命中、淘汰等统计，标签 cache=name，同名缓存会被替换。
+// lru.Cache、expirable.LRU、expirable.ShardedLRU 与 cache.Local 的 LRU() 均可传入
+func (r *Registry) RegisterCache(name string, cache lru.StatsSource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.caches == nil {
+		c := lru.NewCollector(nil)
+		if err := r.registerer.Register(c); err != nil {
+			return err
+		}
+		r.caches = c
+	}
+	r.caches.Add(name, cache)
+	return nil
+}
+
+// UnregisterCache 停止导出 name 对应的缓存
+func (r *Registry) UnregisterCache(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.caches != nil {
+		r.caches.Remove(name)
+	}
+}
@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Config 指标配置
+type Config struct {
+	Service      string            `mapstructure:"service"`                    // 服务名，作为所有指标的 service 标签
+	Labels       map[string]string `mapstructure:"labels"`                     // 附加到所有指标的固定标签，如 env、region
+	Addr         string            `mapstructure:"addr"`                       // 独立监听地址，如 :9090；为空时不单独监听，HTTP 服务用 router.WithMetrics 挂载
+	Path         string            `mapstructure:"path" default:"/metrics"`    // 暴露指标的路径
+	PushGateway  string            `mapstructure:"pushGateway"`                // Pushgateway 地址，为空不推送，适用于短任务或无法被抓取的实例
+	PushJob      string            `mapstructure:"pushJob"`                    // 推送使用的 job 名，默认 Service
+	PushInterval time.Duration     `mapstructure:"pushInterval" default:"15s"` // 推送间隔
+}
+
+// Registry 封装 prometheus 的注册与采集，所有组件的指标注册到同一个 Registry 并通过一个 /metrics 暴露
+type Registry struct {
+	registerer prometheus.Registerer
+	gatherer   prometheus.Gatherer
+
+	mu     sync.Mutex
+	caches *lru.Collector
+}
+
+var defaultRegistry atomic.Pointer[Registry]
+
+func init() {
+	defaultRegistry.Store(&Registry{registerer: prometheus.DefaultRegisterer, gatherer: prometheus.DefaultGatherer})
+}
+
+// Default 返回全局 Registry，未调用 Init 时直接使用 prometheus 默认注册表。
+// router、rpc、redis、mongo、kafka、breaker 等组件创建指标时注册到这里
+func Default() *Registry {
+	return defaultRegistry.Load()
+}
+
+// SetDefault 替换全局 Registry，需在创建各组件之前调用
+func SetDefault(r *Registry) {
+	defaultRegistry.Store(r)
+}
+
+// New 创建独立的 Registry，包含 Go 运行时与进程指标，labels 附加到所有指标
+func New(labels map[string]string) *Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return &Registry{registerer: prometheus.WrapRegistererWith(labels, reg), gatherer: reg}
+}
+
+// Init 按配置设置全局 Registry，仍基于 prometheus 默认注册表以兼容直接使用 prometheus.MustRegister 的代码；
+// 配置了 Addr 时单独监听，配置了 PushGateway 时定期推送。返回的 shutdown 在退出前调用，
+// 会停止监听并做最后一次推送。cfg 为 nil 时不做任何事
+func Init(cfg *Config) (shutdown func(context.Context) error, err error) {
+	if cfg == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+	job := cfg.PushJob
+	if job == "" {
+		job = cfg.Service
+	}
+	if cfg.PushGateway != "" && job == "" {
+		return nil, fmt.Errorf("metrics: pushJob or service is required when pushGateway is set")
+	}
+	labels := prometheus.Labels{}
+	for k, v := range cfg.Labels {
+		labels[k] = v
+	}
+	if cfg.Service != "" {
+		labels["service"] = cfg.Service
+	}
+	r := &Registry{registerer: prometheus.WrapRegistererWith(labels, prometheus.DefaultRegisterer), gatherer: prometheus.DefaultGatherer}
+	SetDefault(r)
+
+	var closers []func(context.Context) error
+	if cfg.Addr != "" {
+		path := cfg.Path
+		if path == "" {
+			path = "/metrics"
+		}
+		mux := http.NewServeMux()
+		mux.Handle(path, r.Handler())
+		srv := &http.Server{Addr: cfg.Addr, Handler: mux}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Errorw(context.Background(), "metrics server stopped", "addr", cfg.Addr, "error", err.Error())
+			}
+		}()
+		closers = append(closers, srv.Shutdown)
+	}
+	if cfg.PushGateway != "" {
+		closers = append(closers, r.StartPush(cfg.PushGateway, job, cfg.PushInterval))
+	}
+	return func(ctx context.Context) error {
+		var errs []error
+		for _, c := range closers {
+			errs = append(errs, c(ctx))
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+// Registerer 返回注册器，传给 kafka.NewMetrics、breaker.NewMetrics 等
+func (r *Registry) Registerer() prometheus.Registerer {
+	return r.registerer
+}
+
+// Gatherer 返回采集器
+func (r *Registry) Gatherer() prometheus.Gatherer {
+	return r.gatherer
+}
+
+// Handler 返回暴露指标的 http.Handler
+func (r *Registry) Handler() http.Handler {
+	return promhttp.InstrumentMetricHandler(r.registerer, promhttp.HandlerFor(r.gatherer, promhttp.HandlerOpts{}))
+}
+
+// Register 注册自定义 Collector，已注册同名指标时不报错
+func (r *Registry) Register(c prometheus.Collector) error {
+	_, err := Register(r.registerer, c)
+	return err
+}
+
+// Counter 创建或复用计数器，同名但标签不同等定义冲突时 panic
+func (r *Registry) Counter(name, help string, labels ...string) *prometheus.CounterVec {
+	return mustRegister(r.registerer, prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels))
+}
+
+// Gauge 创建或复用仪表盘，同名但标签不同等定义冲突时 panic
+func (r *Registry) Gauge(name, help string, labels ...string) *prometheus.GaugeVec {
+	return mustRegister(r.registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels))
+}
+
+// Histogram 创建或复用直方图，buckets 为 nil 时使用 prometheus.DefBuckets，定义冲突时 panic
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+	return mustRegister(r.registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels))
+}
+
+// Register 注册指标，已存在同名指标时返回已注册的实例
+func Register[C prometheus.Collector](reg prometheus.Registerer, c C) (C, error) {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing, nil
+			}
+		}
+		return c, err
+	}
+	return c, nil
+}
+
+func mustRegister[C prometheus.Collector](reg prometheus.Registerer, c C) C {
+	c, err := Register(reg, c)
+	if err != nil {
+		panic(fmt.Sprintf("metrics: %v", err))
+	}
+	return c
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Push 立即向 Pushgateway 推送一次，替换 job + instance（主机名）分组下的全部指标
+func (r *Registry) Push(ctx context.Context, url, job string) error {
+	if err := r.pusher(url, job).PushContext(ctx); err != nil {
+		return fmt.Errorf("push metrics: %w", err)
+	}
+	return nil
+}
+
+// StartPush 每隔 interval 推送一次，interval <= 0 时为 15s；返回的 stop 停止推送并做最后一次推送，
+// 签名与 router.OnShutdown 一致
+func (r *Registry) StartPush(url, job string, interval time.Duration) (stop func(ctx context.Context) error) {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	pusher := r.pusher(url, job)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := pusher.PushContext(ctx); err != nil {
+					logger.Warnw(ctx, "push metrics failed", "url", url, "job", job, "error", err.Error())
+				}
+				cancel()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func(ctx context.Context) error {
+		var err error
+		once.Do(func() {
+			close(done)
+			<-exited
+			if pushErr := pusher.PushContext(ctx); pushErr != nil {
+				err = fmt.Errorf("push metrics: %w", pushErr)
+			}
+		})
+		return err
+	}
+}
+
+func (r *Registry) pusher(url, job string) *push.Pusher {
+	p := push.New(url, job).Gatherer(r.gatherer)
+	if host, err := os.Hostname(); err == nil {
+		p = p.Grouping("instance", host)
+	}
+	return p
+}
@@ -0,0 +1,63 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `usage:
+  up              执行全部未执行的迁移
+  up-to <version> 执行到指定版本
+  down [steps]    回滚最近的 steps 个迁移，默认 1
+  status          查看迁移状态`
+
+// RunCLI 执行命令行子命令，便于在服务的 main 中提供 `app migrate up` 等入口，status 输出到 out
+func (r *Runner) RunCLI(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("migrate: missing command\n%s", usage)
+	}
+	switch args[0] {
+	case "up":
+		return r.Up(ctx)
+	case "up-to":
+		if len(args) < 2 {
+			return fmt.Errorf("migrate: up-to requires a version\n%s", usage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("migrate: invalid version %q: %w", args[1], err)
+		}
+		return r.UpTo(ctx, version)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("migrate: invalid steps %q", args[1])
+			}
+			steps = n
+		}
+		return r.Down(ctx, steps)
+	case "status":
+		statuses, err := r.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format(time.DateTime)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("migrate: unknown command %q\n%s", args[0], usage)
+	}
+}
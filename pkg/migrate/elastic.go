@@ -0,0 +1,48 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/code-sigs/go-box/pkg/elastic"
+)
+
+// ElasticMapping 为索引追加字段映射的迁移。映射只能追加不能删除，因此不可回滚
+func ElasticMapping[T elastic.IndexNamer](c *elastic.ElasticClient[T], version int64, name, index string, mappings map[string]interface{}) Migration {
+	return Migration{
+		Version: version,
+		Name:    name,
+		Up: func(ctx context.Context) error {
+			return c.PutMapping(ctx, index, mappings)
+		},
+	}
+}
+
+// ElasticReindex 修改已有字段类型等需要重建索引的迁移：按 body 创建 to，从 from 复制文档，再将 alias 原子切换到 to。
+// from 为空表示首次建立别名，不复制文档。旧索引保留以便回滚，确认无误后另行删除；
+// Down 将 alias 切回 from 并删除 to
+func ElasticReindex[T elastic.IndexNamer](c *elastic.ElasticClient[T], version int64, name, alias, from, to string, body map[string]interface{}) Migration {
+	return Migration{
+		Version: version,
+		Name:    name,
+		Up: func(ctx context.Context) error {
+			if err := c.CreateIndex(ctx, to, body); err != nil {
+				return fmt.Errorf("create index %s: %w", to, err)
+			}
+			if from != "" {
+				if err := c.Reindex(ctx, from, to); err != nil {
+					return fmt.Errorf("reindex %s to %s: %w", from, to, err)
+				}
+			}
+			return c.SwitchAlias(ctx, alias, to)
+		},
+		Down: func(ctx context.Context) error {
+			if from != "" {
+				if err := c.SwitchAlias(ctx, alias, from); err != nil {
+					return err
+				}
+			}
+			return c.DeleteIndex(ctx, to)
+		},
+	}
+}
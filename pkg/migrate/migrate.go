@@ -0,0 +1,241 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/redis"
+)
+
+var (
+	ErrDuplicateVersion = errors.New("migrate: duplicate migration version")
+	ErrIrreversible     = errors.New("migrate: migration has no down function")
+	ErrMissingMigration = errors.New("migrate: applied migration is not registered")
+)
+
+// Migration 一个版本化迁移
+type Migration struct {
+	Version int64                           // 版本号，按升序执行，建议使用日期加序号如 2026101501
+	Name    string                          // 描述，记录在迁移表中
+	Up      func(ctx context.Context) error // 执行迁移，应尽量幂等，失败后可重新执行
+	Down    func(ctx context.Context) error // 回滚迁移，为 nil 时不可回滚
+}
+
+// Record 迁移表中的一条记录
+type Record struct {
+	Version   int64     `bson:"_id" json:"version"`
+	Name      string    `bson:"name" json:"name"`
+	AppliedAt time.Time `bson:"appliedAt" json:"appliedAt"`
+	Duration  int64     `bson:"durationMs" json:"durationMs"` // 执行耗时（毫秒）
+}
+
+// Store 保存已执行的迁移
+type Store interface {
+	Applied(ctx context.Context) ([]Record, error)
+	Save(ctx context.Context, record Record) error
+	Delete(ctx context.Context, version int64) error
+}
+
+// Status 迁移及其执行状态
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Option Runner 可选参数
+type Option func(*Runner)
+
+// WithRedis 执行期间持有分布式锁，多个实例同时启动时只有一个执行迁移，其余等待其完成
+func WithRedis(rdb *redis.RedisClient) Option {
+	return func(r *Runner) { r.rdb = rdb }
+}
+
+// WithLockKey 设置分布式锁 key，默认 "migrate"，多个服务共用 redis 时按服务区分
+func WithLockKey(key string) Option {
+	return func(r *Runner) { r.lockKey = key }
+}
+
+// Runner 按版本执行迁移
+type Runner struct {
+	store      Store
+	rdb        *redis.RedisClient
+	lockKey    string
+	migrations map[int64]Migration
+}
+
+// New 创建 Runner，注册迁移后调用 Up
+func New(store Store, opts ...Option) *Runner {
+	r := &Runner{
+		store:      store,
+		lockKey:    "migrate",
+		migrations: make(map[int64]Migration),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Register 注册迁移，版本号重复时返回 ErrDuplicateVersion
+func (r *Runner) Register(migrations ...Migration) error {
+	for _, m := range migrations {
+		if m.Up == nil {
+			return fmt.Errorf("migrate: migration %d has no up function", m.Version)
+		}
+		if _, ok := r.migrations[m.Version]; ok {
+			return fmt.Errorf("%w: %d", ErrDuplicateVersion, m.Version)
+		}
+		r.migrations[m.Version] = m
+	}
+	return nil
+}
+
+// Up 按版本升序执行全部未执行的迁移，适合在服务启动时调用
+func (r *Runner) Up(ctx context.Context) error {
+	return r.UpTo(ctx, 0)
+}
+
+// UpTo 执行版本号不大于 version 的未执行迁移，version 为 0 时执行全部
+func (r *Runner) UpTo(ctx context.Context, version int64) error {
+	return r.withLock(ctx, func(ctx context.Context) error {
+		applied, err := r.appliedSet(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range r.sorted() {
+			if version > 0 && m.Version > version {
+				break
+			}
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			start := time.Now()
+			logger.Infow(ctx, "applying migration", "version", m.Version, "name", m.Name)
+			if err := m.Up(ctx); err != nil {
+				return fmt.Errorf("migrate: up %d %s: %w", m.Version, m.Name, err)
+			}
+			record := Record{Version: m.Version, Name: m.Name, AppliedAt: time.Now(), Duration: time.Since(start).Milliseconds()}
+			if err := r.store.Save(ctx, record); err != nil {
+				return fmt.Errorf("migrate: save %d: %w", m.Version, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Down 按版本降序回滚最近执行的 steps 个迁移
+func (r *Runner) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return nil
+	}
+	return r.withLock(ctx, func(ctx context.Context) error {
+		records, err := r.store.Applied(ctx)
+		if err != nil {
+			return fmt.Errorf("migrate: load applied: %w", err)
+		}
+		sort.Slice(records, func(i, j int) bool { return records[i].Version > records[j].Version })
+		if steps > len(records) {
+			steps = len(records)
+		}
+		for _, record := range records[:steps] {
+			m, ok := r.migrations[record.Version]
+			if !ok {
+				return fmt.Errorf("%w: %d", ErrMissingMigration, record.Version)
+			}
+			if m.Down == nil {
+				return fmt.Errorf("%w: %d %s", ErrIrreversible, m.Version, m.Name)
+			}
+			logger.Infow(ctx, "reverting migration", "version", m.Version, "name", m.Name)
+			if err := m.Down(ctx); err != nil {
+				return fmt.Errorf("migrate: down %d %s: %w", m.Version, m.Name, err)
+			}
+			if err := r.store.Delete(ctx, m.Version); err != nil {
+				return fmt.Errorf("migrate: delete %d: %w", m.Version, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Status 返回已注册迁移与已执行记录的合集，按版本升序
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	records, err := r.store.Applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: load applied: %w", err)
+	}
+	byVersion := make(map[int64]*Status, len(r.migrations)+len(records))
+	for _, m := range r.migrations {
+		byVersion[m.Version] = &Status{Version: m.Version, Name: m.Name}
+	}
+	for _, record := range records {
+		s, ok := byVersion[record.Version]
+		if !ok {
+			s = &Status{Version: record.Version, Name: record.Name}
+			byVersion[record.Version] = s
+		}
+		s.Applied, s.AppliedAt = true, record.AppliedAt
+	}
+	statuses := make([]Status, 0, len(byVersion))
+	for _, s := range byVersion {
+		statuses = append(statuses, *s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+func (r *Runner) sorted() []Migration {
+	migrations := make([]Migration, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations
+}
+
+func (r *Runner) appliedSet(ctx context.Context) (map[int64]struct{}, error) {
+	records, err := r.store.Applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: load applied: %w", err)
+	}
+	applied := make(map[int64]struct{}, len(records))
+	for _, record := range records {
+		applied[record.Version] = struct{}{}
+	}
+	return applied, nil
+}
+
+// withLock 配置了 redis 时等待获取锁后执行 fn，锁在执行期间自动续期
+func (r *Runner) withLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.rdb == nil {
+		return fn(ctx)
+	}
+	lock := redis.NewRedisLock(r.rdb, r.lockKey, 30*time.Second)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		ok, err := lock.Lock()
+		if err != nil {
+			return fmt.Errorf("migrate: acquire lock: %w", err)
+		}
+		if ok {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("migrate: wait for lock: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	defer func() {
+		if _, err := lock.Unlock(); err != nil {
+			logger.Warnw(ctx, "release migrate lock failed", "error", err.Error())
+		}
+	}()
+	return fn(ctx)
+}
@@ -0,0 +1,114 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongo 错误码 IndexNotFound
+const mongoIndexNotFound = 27
+
+// MongoStore 将迁移记录保存在 mongo 集合中，_id 为版本号
+type MongoStore struct {
+	coll *mongo.Collection
+}
+
+// NewMongoStore 创建 MongoStore，collection 为空时使用 "migrations"
+func NewMongoStore(db *mongo.Database, collection string) *MongoStore {
+	if collection == "" {
+		collection = "migrations"
+	}
+	return &MongoStore{coll: db.Collection(collection)}
+}
+
+func (s *MongoStore) Applied(ctx context.Context) ([]Record, error) {
+	cur, err := s.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	if err := cur.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (s *MongoStore) Save(ctx context.Context, record Record) error {
+	_, err := s.coll.ReplaceOne(ctx, bson.M{"_id": record.Version}, record, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (s *MongoStore) Delete(ctx context.Context, version int64) error {
+	_, err := s.coll.DeleteOne(ctx, bson.M{"_id": version})
+	return err
+}
+
+// MongoIndexes 创建索引的迁移，Down 删除这些索引。
+// 未设置 Options.Name 的索引按 mongo 默认规则命名，此时 Keys 须为 bson.D 以保证字段顺序
+func MongoIndexes(version int64, name string, db *mongo.Database, collection string, models ...mongo.IndexModel) Migration {
+	return Migration{
+		Version: version,
+		Name:    name,
+		Up:      CreateIndexes(db, collection, models...),
+		Down: func(ctx context.Context) error {
+			names := make([]string, 0, len(models))
+			for _, model := range models {
+				n, err := indexName(model)
+				if err != nil {
+					return err
+				}
+				names = append(names, n)
+			}
+			return DropIndexes(db, collection, names...)(ctx)
+		},
+	}
+}
+
+// CreateIndexes 返回创建索引的迁移函数，索引已存在且定义相同时不报错
+func CreateIndexes(db *mongo.Database, collection string, models ...mongo.IndexModel) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("create indexes on %s: %w", collection, err)
+		}
+		return nil
+	}
+}
+
+// DropIndexes 返回按名称删除索引的迁移函数，索引不存在时忽略
+func DropIndexes(db *mongo.Database, collection string, names ...string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for _, n := range names {
+			_, err := db.Collection(collection).Indexes().DropOne(ctx, n)
+			var ce mongo.CommandError
+			if errors.As(err, &ce) && ce.Code == mongoIndexNotFound {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("drop index %s on %s: %w", n, collection, err)
+			}
+		}
+		return nil
+	}
+}
+
+// indexName 与 mongo 默认命名一致：字段_方向 以下划线连接
+func indexName(model mongo.IndexModel) (string, error) {
+	if model.Options != nil && model.Options.Name != nil {
+		return *model.Options.Name, nil
+	}
+	keys, ok := model.Keys.(bson.D)
+	if !ok {
+		return "", fmt.Errorf("migrate: index keys must be bson.D or the index must be named, got %T", model.Keys)
+	}
+	parts := make([]string, 0, len(keys)*2)
+	for _, e := range keys {
+		parts = append(parts, e.Key, fmt.Sprint(e.Value))
+	}
+	return strings.Join(parts, "_"), nil
+}
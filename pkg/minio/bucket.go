@@ -0,0 +1,105 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Bucket 返回绑定到指定 bucket 的客户端，与原客户端共享连接，其余配置（加密、外部地址等）保持一致
+func (m *MinIO) Bucket(name string) *MinIO {
+	cfg := *m.cfg
+	cfg.Bucket = name
+	return &MinIO{
+		client: m.client,
+		cfg:    &cfg,
+	}
+}
+
+// BucketName 返回当前客户端绑定的 bucket
+func (m *MinIO) BucketName() string {
+	return m.cfg.Bucket
+}
+
+// EnsureBucket 确保当前 bucket 存在，不存在时创建；isPublic 为 true 时新建的 bucket 设置为公开只读。
+// 已存在的 bucket 不修改策略，GetPermanentlyGetURL 是否返回公开地址仅由 MinIOConfig.IsPublic 决定
+func (m *MinIO) EnsureBucket(ctx context.Context, isPublic bool) error {
+	exists, err := m.client.BucketExists(ctx, m.cfg.Bucket)
+	if err != nil {
+		return fmt.Errorf("failed to check bucket existence: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	err = m.client.MakeBucket(ctx, m.cfg.Bucket, minio.MakeBucketOptions{Region: m.cfg.Region})
+	if err != nil {
+		return fmt.Errorf("failed to create bucket: %w", err)
+	}
+	// 设置 bucket 权限
+	if isPublic {
+		policy := fmt.Sprintf(`{
+										"Version":"2012-10-17",
+										"Statement":[
+											{
+												"Effect":"Allow",
+												"Principal":{"AWS":["*"]},
+												"Action":["s3:GetObject"],
+												"Resource":["arn:aws:s3:::%s/*"]
+											}
+										]
+									}`, m.cfg.Bucket)
+
+		err = m.client.SetBucketPolicy(ctx, m.cfg.Bucket, policy)
+		if err != nil {
+			return fmt.Errorf("failed to set public read-only bucket policy: %w", err)
+		}
+	}
+	return nil
+}
+
+// maxCopyObjectSize S3 CopyObject 单次复制的对象大小上限
+const maxCopyObjectSize = 5 << 30
+
+// CopyObject 复制对象，支持跨 bucket，srcBucket / dstBucket 为空时使用当前 bucket
+func (m *MinIO) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string) (string, error) {
+	if srcBucket == "" {
+		srcBucket = m.cfg.Bucket
+	}
+	if dstBucket == "" {
+		dstBucket = m.cfg.Bucket
+	}
+	srcSSE, err := m.readEncryption(nil)
+	if err != nil {
+		return "", err
+	}
+	dstSSE, err := m.encryption(nil)
+	if err != nil {
+		return "", err
+	}
+	src := minio.CopySrcOptions{
+		Bucket:     srcBucket,
+		Object:     srcObject,
+		Encryption: srcSSE,
+	}
+	dst := minio.CopyDestOptions{
+		Bucket:     dstBucket,
+		Object:     dstObject,
+		Encryption: dstSSE,
+	}
+	// 服务端单次复制最大 5GB，更大的对象通过 ComposeObject 分片复制
+	info, err := m.client.StatObject(ctx, srcBucket, srcObject, minio.StatObjectOptions{ServerSideEncryption: srcSSE})
+	if err != nil {
+		return "", fmt.Errorf("failed to stat source object: %w", err)
+	}
+	if info.Size > maxCopyObjectSize {
+		_, err = m.client.ComposeObject(ctx, dst, src)
+	} else {
+		_, err = m.client.CopyObject(ctx, dst, src)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to copy object: %w", err)
+	}
+	return path.Join(dstBucket, dstObject), nil
+}
@@ -6,8 +6,21 @@ import (
 	"time"
 )
 
+// ObjectStorage 对象存储通用接口，MinIO / S3 / OSS / COS / GCS 均通过 NewObjectStorage 获得实现
 type ObjectStorage interface {
-	UploadFile(ctx context.Context, bucketName, objectName string, reader io.Reader, size int64, contentType string) (string, error)
+	UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (string, error)
+	UploadFileWithOptions(ctx context.Context, objectName string, reader io.Reader, size int64, opts UploadOptions) (string, error)
+	DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error)
+	StatObject(ctx context.Context, objectName string) (ObjectInfo, error)
+	ObjectExists(ctx context.Context, objectName string) (bool, error)
+	ListObjects(ctx context.Context, prefix string, recursive bool, pageSize int, continuationToken string) (*ObjectPage, error)
+	DeleteObject(ctx context.Context, objectName string) error
+	MoveObject(ctx context.Context, srcObject, dstObject string) (string, error)
 	PresignedPutURL(ctx context.Context, objectName string, expiry time.Duration) (string, string, error)
 	PresignedGetURL(ctx context.Context, objectName string, expiry time.Duration, filename string, inline bool, contentType string) (string, error)
 }
+
+var (
+	_ ObjectStorage = (*MinIO)(nil)
+	_ ObjectStorage = (*restStorage)(nil)
+)
@@ -2,6 +2,7 @@ package minio
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -11,10 +12,13 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 type MinIOConfig struct {
+	Provider     string `mapstructure:"provider"` // 连接预设：minio / s3 / oss / cos / gcs，为空为 minio
+	Region       string `mapstructure:"region"`   // 区域，云厂商必填
 	Endpoint     string `mapstructure:"endpoint"`
 	AccessKey    string `mapstructure:"accessKey"`
 	SecretKey    string `mapstructure:"secretKey"`
@@ -22,6 +26,9 @@ type MinIOConfig struct {
 	Bucket       string `mapstructure:"bucket"`
 	IsPublic     bool   `mapstructure:"isPublic"`
 	ExternalAddr string `mapstructure:"externalAddr"`
+	MaxRetries   int    `mapstructure:"maxRetries"` // 单个请求的重试次数，0 使用 SDK 默认值（10）
+
+	Encryption EncryptionOptions `mapstructure:"encryption"` // 默认服务端加密配置，对所有写入生效
 }
 
 type MinIO struct {
@@ -29,7 +36,36 @@ type MinIO struct {
 	cfg    *MinIOConfig
 }
 
+// ObjectInfo 对象元数据
+type ObjectInfo struct {
+	Key          string            // 对象名
+	Size         int64             // 大小（字节）
+	ETag         string            // ETag
+	ContentType  string            // Content-Type
+	LastModified time.Time         // 最后修改时间
+	UserMetadata map[string]string // 用户自定义元数据（x-amz-meta-*）
+	VersionID    string            // 版本号（开启版本控制时有效）
+}
+
+func toObjectInfo(info minio.ObjectInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:          info.Key,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		ContentType:  info.ContentType,
+		LastModified: info.LastModified,
+		UserMetadata: info.UserMetadata,
+		VersionID:    info.VersionID,
+	}
+}
+
 func NewMinIO(cfg *MinIOConfig) (*MinIO, error) {
+	// 补齐默认值时不修改调用方的配置
+	c := *cfg
+	cfg = &c
+	if err := applyProvider(cfg); err != nil {
+		return nil, err
+	}
 	endpoint, err := url.Parse(cfg.Endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("invalid Endpoint: %w", err)
@@ -48,55 +84,73 @@ func NewMinIO(cfg *MinIOConfig) (*MinIO, error) {
 		useSSL = true
 	}
 	client, err := minio.New(endpoint.Host, &minio.Options{
-		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
-		Secure: useSSL,
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       useSSL,
+		Region:       cfg.Region,
+		BucketLookup: bucketLookup(cfg),
+		MaxRetries:   cfg.MaxRetries,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create MinIO client: %w", err)
 	}
 
+	m := &MinIO{
+		client: client,
+		cfg:    cfg,
+	}
 	// 可选：检查 Bucket 是否存在
-	exists, err := client.BucketExists(context.Background(), cfg.Bucket)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check bucket existence: %w", err)
+	if err := m.EnsureBucket(context.Background(), cfg.IsPublic); err != nil {
+		return nil, err
 	}
+	return m, nil
+}
 
-	if !exists {
-		err = client.MakeBucket(context.Background(), cfg.Bucket, minio.MakeBucketOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("failed to create bucket: %w", err)
-		}
-		// 设置 bucket 权限
-		if cfg.IsPublic {
-			policy := fmt.Sprintf(`{
-											"Version":"2012-10-17",
-											"Statement":[
-												{
-													"Effect":"Allow",
-													"Principal":{"AWS":["*"]},
-													"Action":["s3:GetObject"],
-													"Resource":["arn:aws:s3:::%s/*"]
-												}
-											]
-										}`, cfg.Bucket)
-
-			err = client.SetBucketPolicy(context.Background(), cfg.Bucket, policy)
-			if err != nil {
-				return nil, fmt.Errorf("failed to set public read-only bucket policy: %w", err)
-			}
-		}
+// UploadOptions 上传可选参数
+type UploadOptions struct {
+	ContentType  string             // Content-Type
+	UserMetadata map[string]string  // 用户自定义元数据
+	Tags         map[string]string  // 对象标签，用于生命周期规则、计费归属等
+	Encryption   *EncryptionOptions // 服务端加密，nil 使用客户端默认配置
+
+	PartSize         uint64        // 分片大小，0 由 SDK 根据对象大小自动计算
+	Concurrency      uint          // 并发上传分片数，0 使用 SDK 默认值（4）
+	SendContentMD5   bool          // 是否为每个分片计算并发送 Content-MD5
+	DisableMultipart bool          // 禁用分片上传，对象大小不能超过 5GB
+	StreamParts      bool          // 并发缓冲不可 Seek 的 reader，提升流式上传吞吐，占用 Concurrency*PartSize 内存
+	Retries          int           // 整体上传失败后的重试次数，reader 需实现 io.Seeker
+	RetryInterval    time.Duration // 重试间隔，默认 1s，按次数线性递增
+}
 
+func (m *MinIO) putObjectOptions(o UploadOptions) (minio.PutObjectOptions, error) {
+	sse, err := m.encryption(o.Encryption)
+	if err != nil {
+		return minio.PutObjectOptions{}, err
 	}
-	return &MinIO{
-		client: client,
-		cfg:    cfg,
+	return minio.PutObjectOptions{
+		ContentType:           o.ContentType,
+		UserMetadata:          o.UserMetadata,
+		UserTags:              o.Tags,
+		ServerSideEncryption:  sse,
+		PartSize:              o.PartSize,
+		NumThreads:            o.Concurrency,
+		SendContentMd5:        o.SendContentMD5,
+		DisableMultipart:      o.DisableMultipart,
+		ConcurrentStreamParts: o.StreamParts,
 	}, nil
 }
 
 func (m *MinIO) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (string, error) {
-	_, err := m.client.PutObject(ctx, m.cfg.Bucket, objectName, reader, size, minio.PutObjectOptions{ContentType: contentType})
+	return m.UploadFileWithOptions(ctx, objectName, reader, size, UploadOptions{ContentType: contentType})
+}
+
+// UploadFileWithOptions 上传文件，支持元数据、标签等选项
+func (m *MinIO) UploadFileWithOptions(ctx context.Context, objectName string, reader io.Reader, size int64, opts UploadOptions) (string, error) {
+	putOpts, err := m.putObjectOptions(opts)
 	if err != nil {
-		return "", fmt.Errorf("failed to upload file: %w", err)
+		return "", err
+	}
+	if err := m.putObjectWithRetry(ctx, objectName, reader, size, putOpts, opts); err != nil {
+		return "", err
 	}
 
 	//scheme := "http"
@@ -108,6 +162,43 @@ func (m *MinIO) UploadFile(ctx context.Context, objectName string, reader io.Rea
 	return fmt.Sprintf("%s/%s/%s", m.cfg.Endpoint, m.cfg.Bucket, objectName), nil
 }
 
+// putObjectWithRetry 上传失败且 reader 可 Seek 时回到起始位置重试
+func (m *MinIO) putObjectWithRetry(ctx context.Context, objectName string, reader io.Reader, size int64, putOpts minio.PutObjectOptions, opts UploadOptions) error {
+	seeker, seekable := reader.(io.Seeker)
+	var start int64
+	if seekable && opts.Retries > 0 {
+		pos, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			seekable = false
+		}
+		start = pos
+	}
+	interval := opts.RetryInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		_, err = m.client.PutObject(ctx, m.cfg.Bucket, objectName, reader, size, putOpts)
+		if err == nil {
+			return nil
+		}
+		if attempt >= opts.Retries || !seekable || ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-time.After(interval * time.Duration(attempt+1)):
+		case <-ctx.Done():
+			return fmt.Errorf("failed to upload file: %w", ctx.Err())
+		}
+		if _, serr := seeker.Seek(start, io.SeekStart); serr != nil {
+			return fmt.Errorf("failed to rewind reader for retry: %w", serr)
+		}
+	}
+	return fmt.Errorf("failed to upload file: %w", err)
+}
+
 // UploadLocalFile 从本地路径上传文件并自动识别 contentType
 func (m *MinIO) UploadLocalFile(ctx context.Context, objectName, filePath string) (string, error) {
 	// 打开本地文件
@@ -148,6 +239,179 @@ func (m *MinIO) UploadLocalFile(ctx context.Context, objectName, filePath string
 	return m.UploadFile(ctx, objectName, file, stat.Size(), contentType)
 }
 
+// DownloadFile 以流的方式读取对象，调用方负责关闭返回的 io.ReadCloser
+func (m *MinIO) DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error) {
+	return m.getObject(ctx, objectName, minio.GetObjectOptions{})
+}
+
+// DownloadRange 读取对象的 [offset, offset+length) 字节区间，length <= 0 表示读到末尾
+func (m *MinIO) DownloadRange(ctx context.Context, objectName string, offset, length int64) (io.ReadCloser, ObjectInfo, error) {
+	if offset < 0 {
+		return nil, ObjectInfo{}, fmt.Errorf("invalid range offset: %d", offset)
+	}
+	opts := minio.GetObjectOptions{}
+	if offset == 0 && length <= 0 {
+		return m.getObject(ctx, objectName, opts)
+	}
+	end := int64(0)
+	if length > 0 {
+		end = offset + length - 1
+	}
+	if err := opts.SetRange(offset, end); err != nil {
+		return nil, ObjectInfo{}, fmt.Errorf("invalid range: %w", err)
+	}
+	return m.getObject(ctx, objectName, opts)
+}
+
+// DownloadToFile 将对象下载到本地文件，写入临时文件后再重命名，避免留下不完整的文件
+func (m *MinIO) DownloadToFile(ctx context.Context, objectName, filePath string) (ObjectInfo, error) {
+	reader, info, err := m.DownloadFile(ctx, objectName)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer reader.Close()
+
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to create local directory: %w", err)
+	}
+	tmpPath := filePath + ".part"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to create local file: %w", err)
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return ObjectInfo{}, fmt.Errorf("failed to download object: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return ObjectInfo{}, fmt.Errorf("failed to close local file: %w", err)
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return ObjectInfo{}, fmt.Errorf("failed to rename local file: %w", err)
+	}
+	return info, nil
+}
+
+func (m *MinIO) getObject(ctx context.Context, objectName string, opts minio.GetObjectOptions) (io.ReadCloser, ObjectInfo, error) {
+	sse, err := m.readEncryption(nil)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	opts.ServerSideEncryption = sse
+	obj, err := m.client.GetObject(ctx, m.cfg.Bucket, objectName, opts)
+	if err != nil {
+		return nil, ObjectInfo{}, fmt.Errorf("failed to get object: %w", err)
+	}
+	// GetObject 是惰性的，Stat 会触发请求并校验对象是否存在
+	stat, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, ObjectInfo{}, fmt.Errorf("failed to stat object: %w", err)
+	}
+	return obj, toObjectInfo(stat), nil
+}
+
+// StatObject 获取对象元数据（大小、ETag、Content-Type、用户元数据、修改时间），不下载内容
+func (m *MinIO) StatObject(ctx context.Context, objectName string) (ObjectInfo, error) {
+	opts, err := m.statObjectOptions()
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info, err := m.client.StatObject(ctx, m.cfg.Bucket, objectName, opts)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat object: %w", err)
+	}
+	return toObjectInfo(info), nil
+}
+
+// ObjectExists 判断对象是否存在
+func (m *MinIO) ObjectExists(ctx context.Context, objectName string) (bool, error) {
+	opts, err := m.statObjectOptions()
+	if err != nil {
+		return false, err
+	}
+	_, err = m.client.StatObject(ctx, m.cfg.Bucket, objectName, opts)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat object: %w", err)
+	}
+	return true, nil
+}
+
+func (m *MinIO) statObjectOptions() (minio.StatObjectOptions, error) {
+	sse, err := m.readEncryption(nil)
+	if err != nil {
+		return minio.StatObjectOptions{}, err
+	}
+	return minio.StatObjectOptions{ServerSideEncryption: sse}, nil
+}
+
+// ObjectPage 分页列举结果
+type ObjectPage struct {
+	Objects        []ObjectInfo // 当前页对象
+	CommonPrefixes []string     // 非递归列举时的"子目录"
+	NextToken      string       // 下一页游标，为空表示没有更多数据
+}
+
+// afterPrefix 追加在前缀后作为 StartAfter，排在该前缀下所有合法 UTF-8 key 之后
+const afterPrefix = "\U0010FFFF"
+
+// ListObjects 按前缀分页列举对象，continuationToken 为上一页返回的 NextToken
+func (m *MinIO) ListObjects(ctx context.Context, prefix string, recursive bool, pageSize int, continuationToken string) (*ObjectPage, error) {
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+	startAfter := ""
+	if continuationToken != "" {
+		decoded, err := base64.URLEncoding.DecodeString(continuationToken)
+		if err != nil {
+			return nil, fmt.Errorf("invalid continuation token: %w", err)
+		}
+		startAfter = string(decoded)
+	}
+
+	// 读满一页后取消 ctx，停止后台列举
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	opts := minio.ListObjectsOptions{
+		Prefix:     prefix,
+		Recursive:  recursive,
+		StartAfter: startAfter,
+		MaxKeys:    pageSize,
+	}
+
+	page := &ObjectPage{}
+	count := 0
+	lastKey := ""
+	for obj := range m.client.ListObjects(listCtx, m.cfg.Bucket, opts) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		if count == pageSize {
+			// 还有下一条数据，说明存在下一页
+			page.NextToken = base64.URLEncoding.EncodeToString([]byte(lastKey))
+			break
+		}
+		key := obj.Key
+		if !recursive && strings.HasSuffix(obj.Key, "/") && obj.Size == 0 && obj.ETag == "" {
+			page.CommonPrefixes = append(page.CommonPrefixes, obj.Key)
+			// 从整个"子目录"之后继续，否则 StartAfter=dir/ 会再次列出 dir/ 下的对象并重复返回该前缀
+			key += afterPrefix
+		} else {
+			page.Objects = append(page.Objects, toObjectInfo(obj))
+		}
+		// 同一响应中 SDK 先返回对象再返回前缀，游标取本页最大的 key
+		lastKey = max(lastKey, key)
+		count++
+	}
+	return page, nil
+}
+
 func (m *MinIO) PresignedPutURL(ctx context.Context, objectName string, expiry time.Duration) (string, string, error) {
 	if expiry <= 0 {
 		expiry = time.Hour
@@ -174,6 +438,61 @@ func (m *MinIO) PresignedPutURL(ctx context.Context, objectName string, expiry t
 	return u.String(), path.Join(m.cfg.Bucket, objectName), nil
 }
 
+// PresignedPutURLWithEncryption 生成带服务端加密的预签名上传地址，enc 为 nil 时使用客户端默认配置。
+// 加密请求头会参与签名，客户端上传时必须原样携带返回的 headers。
+func (m *MinIO) PresignedPutURLWithEncryption(ctx context.Context, objectName string, expiry time.Duration, enc *EncryptionOptions) (string, http.Header, error) {
+	if expiry <= 0 {
+		expiry = time.Hour
+	}
+	sse, err := m.encryption(enc)
+	if err != nil {
+		return "", nil, err
+	}
+	headers := encryptionHeaders(sse)
+	presignedURL, err := m.client.PresignHeader(ctx, http.MethodPut, m.cfg.Bucket, objectName, expiry, nil, headers)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate presigned upload URL: %w", err)
+	}
+	u, err := m.externalURL(presignedURL)
+	if err != nil {
+		return "", nil, err
+	}
+	return u, headers, nil
+}
+
+// PresignedGetURLWithEncryption 生成 SSE-C 对象的预签名下载地址，客户端下载时必须携带返回的 headers
+func (m *MinIO) PresignedGetURLWithEncryption(ctx context.Context, objectName string, expiry time.Duration, enc *EncryptionOptions) (string, http.Header, error) {
+	if expiry <= 0 {
+		expiry = time.Hour
+	}
+	sse, err := m.readEncryption(enc)
+	if err != nil {
+		return "", nil, err
+	}
+	headers := encryptionHeaders(sse)
+	presignedURL, err := m.client.PresignHeader(ctx, http.MethodGet, m.cfg.Bucket, objectName, expiry, nil, headers)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate presigned download URL: %w", err)
+	}
+	u, err := m.externalURL(presignedURL)
+	if err != nil {
+		return "", nil, err
+	}
+	return u, headers, nil
+}
+
+// externalURL 使用 ExternalAddr 替换预签名地址的 scheme 和 host
+func (m *MinIO) externalURL(u *url.URL) (string, error) {
+	externalURL, err := url.Parse(m.cfg.ExternalAddr)
+	if err != nil {
+		return "", fmt.Errorf("invalid ExternalAddr: %w", err)
+	}
+	out := *u
+	out.Scheme = externalURL.Scheme
+	out.Host = externalURL.Host
+	return out.String(), nil
+}
+
 func (m *MinIO) PresignedGetURL(ctx context.Context, objectName string, expiry time.Duration, filename string, inline bool, contentType string) (string, error) {
 	if expiry <= 0 {
 		expiry = time.Hour
@@ -227,32 +546,112 @@ func (m *MinIO) GetPermanentlyGetURL(ctx context.Context, objectName string) (st
 }
 
 func (m *MinIO) MoveObject(ctx context.Context, srcObject, dstObject string) (string, error) {
-	src := minio.CopySrcOptions{
-		Bucket: m.cfg.Bucket,
-		Object: srcObject,
-	}
-	dst := minio.CopyDestOptions{
-		Bucket: m.cfg.Bucket,
-		Object: dstObject,
-	}
+	return m.MoveObjectBetween(ctx, "", srcObject, "", dstObject)
+}
 
-	_, err := m.client.CopyObject(ctx, dst, src)
+// MoveObjectBetween 跨 bucket 移动对象，bucket 为空时使用当前 bucket
+func (m *MinIO) MoveObjectBetween(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string) (string, error) {
+	if srcBucket == "" {
+		srcBucket = m.cfg.Bucket
+	}
+	dst, err := m.CopyObject(ctx, srcBucket, srcObject, dstBucket, dstObject)
 	if err != nil {
-		return "", fmt.Errorf("failed to copy object: %w", err)
+		return "", err
 	}
 
-	err = m.client.RemoveObject(ctx, m.cfg.Bucket, srcObject, minio.RemoveObjectOptions{})
+	err = m.client.RemoveObject(ctx, srcBucket, srcObject, minio.RemoveObjectOptions{})
 	if err != nil {
 		return "", fmt.Errorf("failed to delete source object: %w", err)
 	}
 
-	return path.Join(m.cfg.Bucket, dstObject), nil
+	return dst, nil
 }
 
+// DeleteObject 删除单个对象，对象不存在时不报错
 func (m *MinIO) DeleteObject(ctx context.Context, object string) error {
 	err := m.client.RemoveObject(ctx, m.cfg.Bucket, object, minio.RemoveObjectOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to delete source object: %w", err)
+		return fmt.Errorf("failed to delete object: %w", err)
 	}
 	return nil
 }
+
+// DeleteError 批量删除中单个对象的失败信息
+type DeleteError struct {
+	ObjectName string
+	Err        error
+}
+
+func (e DeleteError) Error() string {
+	return fmt.Sprintf("%s: %v", e.ObjectName, e.Err)
+}
+
+// DeleteObjects 使用多对象删除接口批量删除，返回删除失败的对象列表
+func (m *MinIO) DeleteObjects(ctx context.Context, objectNames []string) ([]DeleteError, error) {
+	if len(objectNames) == 0 {
+		return nil, nil
+	}
+	objectsCh := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objectsCh)
+		for _, name := range objectNames {
+			select {
+			case objectsCh <- minio.ObjectInfo{Key: name}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	failed := m.removeObjects(ctx, objectsCh)
+	if err := ctx.Err(); err != nil {
+		return failed, fmt.Errorf("delete objects canceled: %w", err)
+	}
+	return failed, nil
+}
+
+// DeletePrefix 删除指定前缀下的全部对象（常用于清理临时上传目录），返回删除数量和失败列表
+func (m *MinIO) DeletePrefix(ctx context.Context, prefix string) (int, []DeleteError, error) {
+	if prefix == "" {
+		return 0, nil, fmt.Errorf("prefix must not be empty")
+	}
+	var (
+		total   int
+		listErr error
+	)
+	objectsCh := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objectsCh)
+		for obj := range m.client.ListObjects(ctx, m.cfg.Bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+			if obj.Err != nil {
+				listErr = obj.Err
+				return
+			}
+			select {
+			case objectsCh <- obj:
+				total++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	failed := m.removeObjects(ctx, objectsCh)
+	if listErr != nil {
+		return total - len(failed), failed, fmt.Errorf("failed to list objects: %w", listErr)
+	}
+	if err := ctx.Err(); err != nil {
+		return total - len(failed), failed, fmt.Errorf("delete prefix canceled: %w", err)
+	}
+	return total - len(failed), failed, nil
+}
+
+// removeObjects 消费 objectsCh 执行删除并收集失败项，生产者需在 ctx 取消时关闭 objectsCh
+func (m *MinIO) removeObjects(ctx context.Context, objectsCh <-chan minio.ObjectInfo) []DeleteError {
+	var failed []DeleteError
+	for e := range m.client.RemoveObjects(ctx, m.cfg.Bucket, objectsCh, minio.RemoveObjectsOptions{}) {
+		failed = append(failed, DeleteError{ObjectName: e.ObjectName, Err: e.Err})
+	}
+	// ctx 取消时 RemoveObjects 可能提前返回，读完 objectsCh 等待生产者退出，调用方随后才能安全读取其结果
+	for range objectsCh {
+	}
+	return failed
+}
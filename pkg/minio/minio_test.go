@@ -0,0 +1,144 @@
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listResult struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	Name                  string
+	Prefix                string
+	KeyCount              int
+	MaxKeys               int
+	IsTruncated           bool
+	NextContinuationToken string     `xml:",omitempty"`
+	Contents              []listItem `xml:"Contents"`
+	CommonPrefixes        []listItem `xml:"CommonPrefixes"`
+}
+
+type listItem struct {
+	Key          string `xml:",omitempty"`
+	Prefix       string `xml:",omitempty"`
+	Size         int64  `xml:",omitempty"`
+	ETag         string `xml:",omitempty"`
+	LastModified string `xml:",omitempty"`
+}
+
+// newListServer 模拟 S3 ListObjectsV2：按 start-after / continuation-token 过滤，按 delimiter 归并前缀
+func newListServer(t *testing.T, keys []string) *MinIO {
+	t.Helper()
+	sort.Strings(keys)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("list-type") != "2" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+		after := q.Get("start-after")
+		if token := q.Get("continuation-token"); token != "" {
+			after = token
+		}
+		maxKeys := 1000
+		if v := q.Get("max-keys"); v != "" {
+			maxKeys = atoi(v)
+		}
+		res := listResult{Name: "test", Prefix: prefix, MaxKeys: maxKeys}
+		seen := map[string]bool{}
+		last := ""
+		for _, key := range keys {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			entry, isPrefix := key, false
+			if delimiter != "" {
+				if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+					entry, isPrefix = key[:len(prefix)+i+len(delimiter)], true
+				}
+			}
+			if key <= after || seen[entry] {
+				continue
+			}
+			if res.KeyCount == maxKeys {
+				res.IsTruncated = true
+				res.NextContinuationToken = last
+				break
+			}
+			seen[entry] = true
+			if isPrefix {
+				res.CommonPrefixes = append(res.CommonPrefixes, listItem{Prefix: entry})
+				last = entry + afterPrefix
+			} else {
+				res.Contents = append(res.Contents, listItem{Key: key, Size: 1, ETag: `"etag"`, LastModified: "2024-01-01T00:00:00.000Z"})
+				last = key
+			}
+			res.KeyCount++
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		require.NoError(t, xml.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	client, err := minio.New(u.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4("key", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	require.NoError(t, err)
+	return &MinIO{client: client, cfg: &MinIOConfig{Bucket: "test"}}
+}
+
+func atoi(s string) int {
+	n := 0
+	for _, c := range s {
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+// listAll 逐页列举直到 NextToken 为空，返回对象与前缀
+func listAll(t *testing.T, m ObjectStorage, prefix string, recursive bool, pageSize int) []string {
+	t.Helper()
+	var entries []string
+	token := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 100, "pagination does not terminate")
+		page, err := m.ListObjects(context.Background(), prefix, recursive, pageSize, token)
+		require.NoError(t, err)
+		for _, obj := range page.Objects {
+			entries = append(entries, obj.Key)
+		}
+		entries = append(entries, page.CommonPrefixes...)
+		if page.NextToken == "" {
+			return entries
+		}
+		token = page.NextToken
+	}
+}
+
+func TestListObjects_NestedPrefixes(t *testing.T) {
+	m := newListServer(t, []string{"a.txt", "dir/a", "dir/b", "dir/sub/c", "dir/sub/d", "dir2/x", "e.txt", "z/y"})
+
+	for _, size := range []int{1, 2, 3, 1000} {
+		assert.ElementsMatch(t, []string{"a.txt", "dir/", "dir2/", "e.txt", "z/"}, listAll(t, m, "", false, size), "page size %d", size)
+		assert.ElementsMatch(t, []string{"dir/a", "dir/b", "dir/sub/"}, listAll(t, m, "dir/", false, size), "page size %d", size)
+		assert.ElementsMatch(t, []string{"a.txt", "dir/a", "dir/b", "dir/sub/c", "dir/sub/d", "dir2/x", "e.txt", "z/y"},
+			listAll(t, m, "", true, size), "page size %d", size)
+	}
+
+	_, err := m.ListObjects(context.Background(), "", false, 1, "!invalid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,258 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	minPartSize     = 5 * 1024 * 1024  // S3 协议要求除最后一片外分片不小于 5MB
+	defaultPartSize = 16 * 1024 * 1024 // 默认分片大小
+	maxPartCount    = 10000            // S3 协议允许的最大分片数
+)
+
+// MultipartOptions 分片上传配置
+type MultipartOptions struct {
+	PartSize    int64              // 分片大小，默认 16MB，最小 5MB
+	Concurrency int                // 并发上传分片数，默认 4
+	ContentType string             // 对象 Content-Type
+	Encryption  *EncryptionOptions // 服务端加密，nil 使用客户端默认配置
+
+	// OnProgress 上传进度回调，uploaded 为已上传字节数（含断点续传前已完成的部分）
+	OnProgress func(uploaded, total int64)
+	// OnPartDone 每个分片完成后回调，调用方可在此持久化 state 以便重启后续传
+	OnPartDone func(state MultipartState)
+}
+
+// UploadedPart 已上传的分片
+type UploadedPart struct {
+	PartNumber int    `json:"partNumber"`
+	ETag       string `json:"etag"`
+	Size       int64  `json:"size"`
+}
+
+// MultipartState 分片上传断点信息，可序列化保存
+type MultipartState struct {
+	UploadID   string         `json:"uploadId"`
+	ObjectName string         `json:"objectName"`
+	PartSize   int64          `json:"partSize"`
+	TotalSize  int64          `json:"totalSize"`
+	Parts      []UploadedPart `json:"parts"`
+}
+
+// MultipartUploader 支持并发分片、进度回调和断点续传的上传器
+type MultipartUploader struct {
+	m    *MinIO
+	core *minio.Core
+	opts MultipartOptions
+}
+
+// NewMultipartUploader 创建分片上传器
+func (m *MinIO) NewMultipartUploader(opts MultipartOptions) *MultipartUploader {
+	if opts.PartSize <= 0 {
+		opts.PartSize = defaultPartSize
+	}
+	if opts.PartSize < minPartSize {
+		opts.PartSize = minPartSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	return &MultipartUploader{
+		m:    m,
+		core: &minio.Core{Client: m.client},
+		opts: opts,
+	}
+}
+
+// Init 初始化分片上传，返回的 state 可持久化后交给 Resume 使用
+func (u *MultipartUploader) Init(ctx context.Context, objectName string, size int64) (*MultipartState, error) {
+	partSize := u.opts.PartSize
+	// 分片数超过上限时自动放大分片
+	for size/partSize >= maxPartCount {
+		partSize *= 2
+	}
+	putOpts, err := u.m.putObjectOptions(UploadOptions{ContentType: u.opts.ContentType, Encryption: u.opts.Encryption})
+	if err != nil {
+		return nil, err
+	}
+	uploadID, err := u.core.NewMultipartUpload(ctx, u.m.cfg.Bucket, objectName, putOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initiate multipart upload: %w", err)
+	}
+	return &MultipartState{
+		UploadID:   uploadID,
+		ObjectName: objectName,
+		PartSize:   partSize,
+		TotalSize:  size,
+	}, nil
+}
+
+// Upload 从头开始分片上传 reader 中的 size 字节，返回对象地址
+func (u *MultipartUploader) Upload(ctx context.Context, objectName string, reader io.ReaderAt, size int64) (string, error) {
+	state, err := u.Init(ctx, objectName, size)
+	if err != nil {
+		return "", err
+	}
+	return u.Resume(ctx, state, reader)
+}
+
+// Resume 根据断点信息继续上传，已在服务端存在的分片会被跳过
+func (u *MultipartUploader) Resume(ctx context.Context, state *MultipartState, reader io.ReaderAt) (string, error) {
+	if state == nil || state.UploadID == "" {
+		return "", fmt.Errorf("invalid multipart state")
+	}
+	// 以服务端记录为准，防止本地记录与实际不一致
+	done, err := u.listParts(ctx, state)
+	if err != nil {
+		return "", err
+	}
+
+	// SSE-C 要求每个分片都携带客户密钥
+	partSSE, err := u.m.readEncryption(u.opts.Encryption)
+	if err != nil {
+		return "", err
+	}
+
+	partCount := int((state.TotalSize + state.PartSize - 1) / state.PartSize)
+	if partCount == 0 {
+		partCount = 1
+	}
+
+	var (
+		mu       sync.Mutex
+		uploaded atomic.Int64
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	parts := make(map[int]UploadedPart, partCount)
+	for n, p := range done {
+		if n <= partCount && p.Size == u.partLength(state, n) {
+			parts[n] = p
+			uploaded.Add(p.Size)
+		}
+	}
+	state.Parts = sortedParts(parts)
+	u.progress(uploaded.Load(), state.TotalSize)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sem := make(chan struct{}, u.opts.Concurrency)
+
+	for n := 1; n <= partCount; n++ {
+		if _, ok := parts[n]; ok {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(partNumber int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			length := u.partLength(state, partNumber)
+			offset := int64(partNumber-1) * state.PartSize
+			section := io.NewSectionReader(reader, offset, length)
+			p, err := u.core.PutObjectPart(ctx, u.m.cfg.Bucket, state.ObjectName, state.UploadID, partNumber, section, length, minio.PutObjectPartOptions{SSE: partSSE})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+					cancel()
+				}
+				return
+			}
+			parts[partNumber] = UploadedPart{PartNumber: partNumber, ETag: p.ETag, Size: length}
+			state.Parts = sortedParts(parts)
+			u.progress(uploaded.Add(length), state.TotalSize)
+			if u.opts.OnPartDone != nil {
+				u.opts.OnPartDone(*state)
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("multipart upload canceled: %w", err)
+	}
+
+	complete := make([]minio.CompletePart, 0, len(state.Parts))
+	for _, p := range state.Parts {
+		complete = append(complete, minio.CompletePart{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+	_, err = u.core.CompleteMultipartUpload(ctx, u.m.cfg.Bucket, state.ObjectName, state.UploadID, complete, minio.PutObjectOptions{ContentType: u.opts.ContentType, ServerSideEncryption: partSSE})
+	if err != nil {
+		return "", fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return fmt.Sprintf("%s/%s/%s", u.m.cfg.Endpoint, u.m.cfg.Bucket, state.ObjectName), nil
+}
+
+// Abort 放弃分片上传并清理服务端已上传的分片
+func (u *MultipartUploader) Abort(ctx context.Context, state *MultipartState) error {
+	if state == nil || state.UploadID == "" {
+		return nil
+	}
+	if err := u.core.AbortMultipartUpload(ctx, u.m.cfg.Bucket, state.ObjectName, state.UploadID); err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	return nil
+}
+
+// listParts 查询服务端已存在的分片
+func (u *MultipartUploader) listParts(ctx context.Context, state *MultipartState) (map[int]UploadedPart, error) {
+	parts := make(map[int]UploadedPart)
+	marker := 0
+	for {
+		result, err := u.core.ListObjectParts(ctx, u.m.cfg.Bucket, state.ObjectName, state.UploadID, marker, 1000)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list uploaded parts: %w", err)
+		}
+		for _, p := range result.ObjectParts {
+			parts[p.PartNumber] = UploadedPart{PartNumber: p.PartNumber, ETag: p.ETag, Size: p.Size}
+		}
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// partLength 计算第 n 个分片的长度
+func (u *MultipartUploader) partLength(state *MultipartState, n int) int64 {
+	offset := int64(n-1) * state.PartSize
+	if remain := state.TotalSize - offset; remain < state.PartSize {
+		return remain
+	}
+	return state.PartSize
+}
+
+func (u *MultipartUploader) progress(uploaded, total int64) {
+	if u.opts.OnProgress != nil {
+		u.opts.OnProgress(uploaded, total)
+	}
+}
+
+func sortedParts(parts map[int]UploadedPart) []UploadedPart {
+	out := make([]UploadedPart, 0, len(parts))
+	for _, p := range parts {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PartNumber < out[j].PartNumber })
+	return out
+}
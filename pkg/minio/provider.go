@@ -0,0 +1,83 @@
+package minio
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Provider 选择对象存储实现：OSS / COS 使用各自的原生接口与签名；
+// MinIO、AWS S3 与 GCS（S3 互操作接口）使用 minio-go，GCS 由 minio-go 按域名自动切换为 V2 签名。
+const (
+	ProviderMinIO = "minio"
+	ProviderS3    = "s3"  // AWS S3
+	ProviderOSS   = "oss" // 阿里云 OSS
+	ProviderCOS   = "cos" // 腾讯云 COS，Bucket 需带 APPID 后缀，如 example-1250000000
+	ProviderGCS   = "gcs" // Google Cloud Storage，需使用 HMAC 密钥
+)
+
+// NewObjectStorage 按 cfg.Provider 创建对象存储客户端，Provider 为空时按 MinIO 处理
+func NewObjectStorage(cfg *MinIOConfig) (ObjectStorage, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case ProviderOSS:
+		return NewOSS(cfg)
+	case ProviderCOS:
+		return NewCOS(cfg)
+	default:
+		m, err := NewMinIO(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+}
+
+// applyProvider 按服务商补齐默认 Endpoint 和 Region，cfg 须为调用方配置的副本
+func applyProvider(cfg *MinIOConfig) error {
+	switch strings.ToLower(cfg.Provider) {
+	case "", ProviderMinIO:
+		return nil
+	case ProviderS3:
+		if cfg.Region == "" {
+			return fmt.Errorf("region is required for provider %s", cfg.Provider)
+		}
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+		}
+	case ProviderOSS:
+		if cfg.Region == "" {
+			return fmt.Errorf("region is required for provider %s", cfg.Provider)
+		}
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = fmt.Sprintf("https://oss-%s.aliyuncs.com", cfg.Region)
+		}
+	case ProviderCOS:
+		if cfg.Region == "" {
+			return fmt.Errorf("region is required for provider %s", cfg.Provider)
+		}
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = fmt.Sprintf("https://cos.%s.myqcloud.com", cfg.Region)
+		}
+	case ProviderGCS:
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = "https://storage.googleapis.com"
+		}
+		if cfg.Region == "" {
+			cfg.Region = "auto"
+		}
+	default:
+		return fmt.Errorf("unsupported storage provider: %s", cfg.Provider)
+	}
+	return nil
+}
+
+// bucketLookup OSS / COS 仅支持虚拟主机寻址，其余服务商自动探测
+func bucketLookup(cfg *MinIOConfig) minio.BucketLookupType {
+	switch strings.ToLower(cfg.Provider) {
+	case ProviderOSS, ProviderCOS:
+		return minio.BucketLookupDNS
+	default:
+		return minio.BucketLookupAuto
+	}
+}
@@ -0,0 +1,393 @@
+package minio
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRESTPutSize 原生接口单次 PUT 的对象大小上限
+const maxRESTPutSize = 5 << 30
+
+// restDialect 云厂商原生 REST 接口的差异：请求头前缀、签名、加密与复制源格式
+type restDialect interface {
+	// headerPrefix 自定义请求头前缀，如 "x-oss-"
+	headerPrefix() string
+	// sign 为请求添加签名，key 为对象名，bucket 级请求为空
+	sign(req *http.Request, bucket, key string, now time.Time)
+	// presign 将签名参数写入 u 的查询串，u 中已有的 response-* 参数参与签名
+	presign(method string, u *url.URL, bucket, key string, now time.Time, expiry time.Duration)
+	// encryptionHeaders 服务端加密请求头
+	encryptionHeaders(e *EncryptionOptions) (http.Header, error)
+	// copySource 复制对象时的源对象请求头值
+	copySource(host, bucket, key string) string
+}
+
+// restStorage 基于云厂商原生 REST 接口的 ObjectStorage 实现，使用虚拟主机寻址 <bucket>.<endpoint>
+type restStorage struct {
+	provider string
+	cfg      *MinIOConfig
+	endpoint *url.URL
+	dialect  restDialect
+	client   *http.Client
+	now      func() time.Time
+}
+
+func newRESTStorage(provider string, cfg *MinIOConfig, dialect restDialect) (*restStorage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("%s: bucket is required", provider)
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("%s: invalid Endpoint %q", provider, cfg.Endpoint)
+	}
+	if cfg.ExternalAddr != "" {
+		if _, err := url.Parse(cfg.ExternalAddr); err != nil {
+			return nil, fmt.Errorf("%s: invalid ExternalAddr: %w", provider, err)
+		}
+	}
+	return &restStorage{
+		provider: provider,
+		cfg:      cfg,
+		endpoint: endpoint,
+		dialect:  dialect,
+		client:   &http.Client{},
+		now:      time.Now,
+	}, nil
+}
+
+// RESTError 云厂商原生接口返回的错误
+type RESTError struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+	RequestID  string `xml:"RequestId"`
+}
+
+func (e *RESTError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("status %d: %s: %s (request id %s)", e.StatusCode, e.Code, e.Message, e.RequestID)
+}
+
+// bucketHost 虚拟主机寻址的域名
+func (s *restStorage) bucketHost() string {
+	return s.cfg.Bucket + "." + s.endpoint.Host
+}
+
+func (s *restStorage) objectURL(key string) *url.URL {
+	return &url.URL{Scheme: s.endpoint.Scheme, Host: s.bucketHost(), Path: "/" + key}
+}
+
+// externalURL 配置了 ExternalAddr（如 CDN 或自定义域名）时替换 scheme 和 host
+func (s *restStorage) externalURL(u *url.URL) string {
+	if s.cfg.ExternalAddr == "" {
+		return u.String()
+	}
+	ext, err := url.Parse(s.cfg.ExternalAddr)
+	if err != nil {
+		return u.String()
+	}
+	out := *u
+	out.Scheme, out.Host = ext.Scheme, ext.Host
+	return out.String()
+}
+
+// do 签名并发送请求，非 2xx 响应转为 *RESTError
+func (s *restStorage) do(ctx context.Context, method, key string, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	u := s.objectURL(key)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = size
+		if size == 0 {
+			req.Body = http.NoBody
+		}
+	}
+	s.dialect.sign(req, s.cfg.Bucket, key, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		restErr := &RESTError{StatusCode: resp.StatusCode}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		_ = xml.Unmarshal(data, restErr)
+		return nil, restErr
+	}
+	return resp, nil
+}
+
+func isNotFound(err error) bool {
+	var restErr *RESTError
+	return errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound
+}
+
+// objectInfo 从响应头解析对象元数据
+func (s *restStorage) objectInfo(key string, h http.Header) ObjectInfo {
+	info := ObjectInfo{
+		Key:         key,
+		ETag:        strings.Trim(h.Get("ETag"), `"`),
+		ContentType: h.Get("Content-Type"),
+		VersionID:   h.Get(s.dialect.headerPrefix() + "version-id"),
+	}
+	info.Size, _ = strconv.ParseInt(h.Get("Content-Length"), 10, 64)
+	info.LastModified, _ = http.ParseTime(h.Get("Last-Modified"))
+	metaPrefix := http.CanonicalHeaderKey(s.dialect.headerPrefix() + "meta-")
+	for k, v := range h {
+		if name, ok := strings.CutPrefix(k, metaPrefix); ok && len(v) > 0 {
+			if info.UserMetadata == nil {
+				info.UserMetadata = map[string]string{}
+			}
+			info.UserMetadata[name] = v[0]
+		}
+	}
+	return info
+}
+
+func (s *restStorage) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (string, error) {
+	return s.UploadFileWithOptions(ctx, objectName, reader, size, UploadOptions{ContentType: contentType})
+}
+
+// UploadFileWithOptions 单次 PUT 上传，size 必须已知且不超过 5GB；分片相关选项不生效
+func (s *restStorage) UploadFileWithOptions(ctx context.Context, objectName string, reader io.Reader, size int64, opts UploadOptions) (string, error) {
+	if size < 0 || size > maxRESTPutSize {
+		return "", fmt.Errorf("%s: object size must be between 0 and 5GB, got %d", s.provider, size)
+	}
+	prefix := s.dialect.headerPrefix()
+	header := http.Header{}
+	if opts.ContentType != "" {
+		header.Set("Content-Type", opts.ContentType)
+	}
+	for k, v := range opts.UserMetadata {
+		header.Set(prefix+"meta-"+k, v)
+	}
+	if len(opts.Tags) > 0 {
+		tags := url.Values{}
+		for k, v := range opts.Tags {
+			tags.Set(k, v)
+		}
+		header.Set(prefix+"tagging", tags.Encode())
+	}
+	enc := opts.Encryption
+	if enc == nil {
+		enc = &s.cfg.Encryption
+	}
+	encHeader, err := s.dialect.encryptionHeaders(enc)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range encHeader {
+		header[k] = v
+	}
+
+	seeker, seekable := reader.(io.Seeker)
+	var start int64
+	if seekable && opts.Retries > 0 {
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			seekable = false
+		}
+	}
+	interval := opts.RetryInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	for attempt := 0; ; attempt++ {
+		var resp *http.Response
+		resp, err = s.do(ctx, http.MethodPut, objectName, nil, header, io.NopCloser(reader), size)
+		if err == nil {
+			resp.Body.Close()
+			return s.externalURL(s.objectURL(objectName)), nil
+		}
+		if attempt >= opts.Retries || !seekable || ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-time.After(interval * time.Duration(attempt+1)):
+		case <-ctx.Done():
+			return "", fmt.Errorf("failed to upload file: %w", ctx.Err())
+		}
+		if _, serr := seeker.Seek(start, io.SeekStart); serr != nil {
+			return "", fmt.Errorf("failed to rewind reader for retry: %w", serr)
+		}
+	}
+	return "", fmt.Errorf("failed to upload file: %w", err)
+}
+
+// DownloadFile 以流的方式读取对象，调用方负责关闭返回的 io.ReadCloser
+func (s *restStorage) DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodGet, objectName, nil, nil, nil, 0)
+	if err != nil {
+		return nil, ObjectInfo{}, fmt.Errorf("failed to get object: %w", err)
+	}
+	return resp.Body, s.objectInfo(objectName, resp.Header), nil
+}
+
+// StatObject 获取对象元数据，不下载内容
+func (s *restStorage) StatObject(ctx context.Context, objectName string) (ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, objectName, nil, nil, nil, 0)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat object: %w", err)
+	}
+	resp.Body.Close()
+	return s.objectInfo(objectName, resp.Header), nil
+}
+
+// ObjectExists 判断对象是否存在
+func (s *restStorage) ObjectExists(ctx context.Context, objectName string) (bool, error) {
+	_, err := s.StatObject(ctx, objectName)
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+type restListResult struct {
+	IsTruncated bool   `xml:"IsTruncated"`
+	NextMarker  string `xml:"NextMarker"`
+	Contents    []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		ETag         string    `xml:"ETag"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+}
+
+// ListObjects 按前缀分页列举对象，continuationToken 为上一页返回的 NextToken（服务端 marker）
+func (s *restStorage) ListObjects(ctx context.Context, prefix string, recursive bool, pageSize int, continuationToken string) (*ObjectPage, error) {
+	if pageSize <= 0 || pageSize > 1000 {
+		pageSize = 1000
+	}
+	query := url.Values{"prefix": {prefix}, "max-keys": {strconv.Itoa(pageSize)}}
+	if !recursive {
+		query.Set("delimiter", "/")
+	}
+	if continuationToken != "" {
+		marker, err := base64.URLEncoding.DecodeString(continuationToken)
+		if err != nil {
+			return nil, fmt.Errorf("invalid continuation token: %w", err)
+		}
+		query.Set("marker", string(marker))
+	}
+	resp, err := s.do(ctx, http.MethodGet, "", query, nil, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+	defer resp.Body.Close()
+	var result restListResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode list result: %w", err)
+	}
+
+	page := &ObjectPage{}
+	last := ""
+	for _, c := range result.Contents {
+		page.Objects = append(page.Objects, ObjectInfo{
+			Key:          c.Key,
+			Size:         c.Size,
+			ETag:         strings.Trim(c.ETag, `"`),
+			LastModified: c.LastModified,
+		})
+		last = max(last, c.Key)
+	}
+	for _, p := range result.CommonPrefixes {
+		page.CommonPrefixes = append(page.CommonPrefixes, p.Prefix)
+		last = max(last, p.Prefix)
+	}
+	if result.IsTruncated {
+		// 服务端 marker 会跳过整个前缀；未返回 NextMarker 时（未指定 delimiter）以最后一个 key 续读
+		next := result.NextMarker
+		if next == "" {
+			next = last
+		}
+		page.NextToken = base64.URLEncoding.EncodeToString([]byte(next))
+	}
+	return page, nil
+}
+
+// DeleteObject 删除单个对象，对象不存在时不报错
+func (s *restStorage) DeleteObject(ctx context.Context, objectName string) error {
+	resp, err := s.do(ctx, http.MethodDelete, objectName, nil, nil, nil, 0)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// MoveObject 服务端复制后删除源对象，返回新对象地址
+func (s *restStorage) MoveObject(ctx context.Context, srcObject, dstObject string) (string, error) {
+	header := http.Header{}
+	header.Set(s.dialect.headerPrefix()+"copy-source", s.dialect.copySource(s.bucketHost(), s.cfg.Bucket, srcObject))
+	encHeader, err := s.dialect.encryptionHeaders(&s.cfg.Encryption)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range encHeader {
+		header[k] = v
+	}
+	resp, err := s.do(ctx, http.MethodPut, dstObject, nil, header, nil, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to copy object: %w", err)
+	}
+	resp.Body.Close()
+	if err := s.DeleteObject(ctx, srcObject); err != nil {
+		return "", fmt.Errorf("failed to delete source object: %w", err)
+	}
+	return s.externalURL(s.objectURL(dstObject)), nil
+}
+
+// PresignedPutURL 生成预签名上传地址，返回地址与 <bucket>/<object>
+func (s *restStorage) PresignedPutURL(ctx context.Context, objectName string, expiry time.Duration) (string, string, error) {
+	if expiry <= 0 {
+		expiry = time.Hour
+	}
+	u := s.objectURL(objectName)
+	s.dialect.presign(http.MethodPut, u, s.cfg.Bucket, objectName, s.now(), expiry)
+	return s.externalURL(u), path.Join(s.cfg.Bucket, objectName), nil
+}
+
+// PresignedGetURL 生成预签名下载地址，filename 不为空时设置 Content-Disposition
+func (s *restStorage) PresignedGetURL(ctx context.Context, objectName string, expiry time.Duration, filename string, inline bool, contentType string) (string, error) {
+	if expiry <= 0 {
+		expiry = time.Hour
+	}
+	query := url.Values{}
+	if filename != "" {
+		disposition := "attachment"
+		if inline {
+			disposition = "inline"
+		}
+		query.Set("response-content-disposition", fmt.Sprintf("%s; filename=\"%s\"", disposition, url.PathEscape(filename)))
+	}
+	if contentType != "" {
+		query.Set("response-content-type", contentType)
+	}
+	u := s.objectURL(objectName)
+	u.RawQuery = query.Encode()
+	s.dialect.presign(http.MethodGet, u, s.cfg.Bucket, objectName, s.now(), expiry)
+	return s.externalURL(u), nil
+}
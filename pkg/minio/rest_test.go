@@ -0,0 +1,308 @@
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBucket 模拟 OSS / COS 的对象读写与 ListObjects（marker 分页，marker 为前缀时跳过整个前缀）
+type fakeBucket struct {
+	mu       sync.Mutex
+	objects  map[string]string
+	headers  map[string]http.Header
+	requests []*http.Request
+}
+
+func (b *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests = append(b.requests, r)
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		b.list(w, r.URL.Query())
+	case r.Method == http.MethodPut:
+		if src := r.Header.Get("X-Oss-Copy-Source") + r.Header.Get("X-Cos-Copy-Source"); src != "" {
+			srcKey, _ := url.PathUnescape(src[strings.Index(src[1:], "/")+2:])
+			b.objects[key], b.headers[key] = b.objects[srcKey], b.headers[srcKey]
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		b.objects[key], b.headers[key] = string(data), r.Header.Clone()
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := b.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				io.WriteString(w, "<Error><Code>NoSuchKey</Code><Message>missing</Message><RequestId>req-1</RequestId></Error>")
+			}
+			return
+		}
+		for k, v := range b.headers[key] {
+			if strings.Contains(strings.ToLower(k), "-meta-") || k == "Content-Type" {
+				w.Header()[k] = v
+			}
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		w.Header().Set("Content-Length", itoa(len(data)))
+		if r.Method == http.MethodGet {
+			io.WriteString(w, data)
+		}
+	case r.Method == http.MethodDelete:
+		delete(b.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (b *fakeBucket) list(w http.ResponseWriter, q url.Values) {
+	prefix, delimiter, marker := q.Get("prefix"), q.Get("delimiter"), q.Get("marker")
+	maxKeys := atoi(q.Get("max-keys"))
+	keys := make([]string, 0, len(b.objects))
+	for k := range b.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var res struct {
+		XMLName        xml.Name   `xml:"ListBucketResult"`
+		IsTruncated    bool       `xml:"IsTruncated"`
+		NextMarker     string     `xml:"NextMarker,omitempty"`
+		Contents       []listItem `xml:"Contents"`
+		CommonPrefixes []listItem `xml:"CommonPrefixes"`
+	}
+	count, last := 0, ""
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) || key <= marker ||
+			(strings.HasSuffix(marker, delimiter) && delimiter != "" && strings.HasPrefix(key, marker)) {
+			continue
+		}
+		entry, isPrefix := key, false
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				entry, isPrefix = key[:len(prefix)+i+len(delimiter)], true
+			}
+		}
+		if isPrefix && entry == last {
+			continue
+		}
+		if count == maxKeys {
+			res.IsTruncated = true
+			if delimiter != "" {
+				res.NextMarker = last
+			}
+			break
+		}
+		if isPrefix {
+			res.CommonPrefixes = append(res.CommonPrefixes, listItem{Prefix: entry})
+		} else {
+			res.Contents = append(res.Contents, listItem{Key: key, Size: int64(len(b.objects[key])), ETag: `"etag"`, LastModified: "2024-01-01T00:00:00.000Z"})
+		}
+		count, last = count+1, entry
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(res)
+}
+
+func itoa(n int) string {
+	if n == 0 {
+		return "0"
+	}
+	var b []byte
+	for ; n > 0; n /= 10 {
+		b = append([]byte{byte('0' + n%10)}, b...)
+	}
+	return string(b)
+}
+
+// newRESTTestStorage 将虚拟主机请求全部转发到本地 fakeBucket，保留原始 Host 头
+func newRESTTestStorage(t *testing.T, provider string) (*restStorage, *fakeBucket) {
+	t.Helper()
+	bucket := &fakeBucket{objects: map[string]string{}, headers: map[string]http.Header{}}
+	srv := httptest.NewServer(bucket)
+	t.Cleanup(srv.Close)
+
+	cfg := &MinIOConfig{Provider: provider, Region: "test-region", Bucket: "example-1250000000", AccessKey: "ak", SecretKey: "sk"}
+	s, err := NewObjectStorage(cfg)
+	require.NoError(t, err)
+	rs := s.(*restStorage)
+	addr := srv.Listener.Addr().String()
+	rs.client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+		DialTLSContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return nil, assert.AnError
+		},
+	}}
+	rs.endpoint.Scheme = "http"
+	return rs, bucket
+}
+
+func TestOSSSignature(t *testing.T) {
+	// 官方文档示例
+	d := ossDialect{accessKey: "44CF9590006BF252F707", secretKey: "OtxrzxIsfpFjA7SwPzILwy8Bw21TLhquhboDYROV"}
+	header := http.Header{}
+	header.Set("Content-MD5", "ODBGOERFMDMzQTczRUY3NUE3NzA5QzdFNUYzMDQxNEM=")
+	header.Set("Content-Type", "text/html")
+	header.Set("X-OSS-Meta-Author", "foo@bar.com")
+	header.Set("X-OSS-Magic", "abracadabra")
+	sig := d.signature(http.MethodPut, header, "Thu, 17 Nov 2005 18:49:58 GMT", ossResource("oss-example", "nelson", nil))
+	assert.Equal(t, "26NBxoKdsyly4EDv6inkoDft/yA=", sig)
+
+	assert.Equal(t, "/b/k?acl&response-content-type=text/plain",
+		ossResource("b", "k", url.Values{"response-content-type": {"text/plain"}, "acl": {""}, "prefix": {"p"}}))
+}
+
+func TestCOSAuthorization(t *testing.T) {
+	d := cosDialect{secretID: "id", secretKey: "key"}
+	u, _ := url.Parse("https://example-1250000000.cos.ap-guangzhou.myqcloud.com/a%20b.txt?response-content-type=text%2Fplain&Prefix=x")
+	start := time.Unix(1557989151, 0)
+	auth := d.authorization(http.MethodGet, u, http.Header{"Host": {u.Host}, "X-Cos-Acl": {"private"}}, start, start.Add(2*time.Hour))
+
+	q, err := url.ParseQuery(strings.ReplaceAll(auth, ";", "%3B"))
+	require.NoError(t, err)
+	assert.Equal(t, "sha1", q.Get("q-sign-algorithm"))
+	assert.Equal(t, "1557989151;1557996351", q.Get("q-sign-time"))
+	assert.Equal(t, "host;x-cos-acl", q.Get("q-header-list"))
+	assert.Equal(t, "prefix;response-content-type", q.Get("q-url-param-list"))
+
+	httpString := "get\n/a b.txt\nprefix=x&response-content-type=text%2Fplain\nhost=" + u.Host + "&x-cos-acl=private\n"
+	want := hmacSHA1Hex(hmacSHA1Hex("key", "1557989151;1557996351"), "sha1\n1557989151;1557996351\n"+sha1Hex(httpString)+"\n")
+	assert.Equal(t, want, q.Get("q-signature"))
+}
+
+func TestRESTStorage_ObjectLifecycle(t *testing.T) {
+	for _, provider := range []string{ProviderOSS, ProviderCOS} {
+		t.Run(provider, func(t *testing.T) {
+			s, bucket := newRESTTestStorage(t, provider)
+			ctx := context.Background()
+			prefix := s.dialect.headerPrefix()
+
+			addr, err := s.UploadFileWithOptions(ctx, "docs/a.txt", strings.NewReader("hello"), 5, UploadOptions{
+				ContentType:  "text/plain",
+				UserMetadata: map[string]string{"owner": "alice"},
+				Tags:         map[string]string{"team": "infra"},
+				Encryption:   &EncryptionOptions{Type: EncryptionSSES3},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "http://example-1250000000."+s.endpoint.Host+"/docs/a.txt", addr)
+
+			put := bucket.requests[0]
+			assert.Equal(t, "example-1250000000."+s.endpoint.Host, put.Host)
+			assert.Equal(t, "alice", put.Header.Get(prefix+"meta-owner"))
+			assert.Equal(t, "team=infra", put.Header.Get(prefix+"tagging"))
+			assert.Equal(t, "AES256", put.Header.Get(prefix+"server-side-encryption"))
+			assert.NotEmpty(t, put.Header.Get("Authorization"))
+
+			info, err := s.StatObject(ctx, "docs/a.txt")
+			require.NoError(t, err)
+			assert.Equal(t, int64(5), info.Size)
+			assert.Equal(t, "etag", info.ETag)
+			assert.Equal(t, "text/plain", info.ContentType)
+			assert.Equal(t, map[string]string{"Owner": "alice"}, info.UserMetadata)
+
+			rc, _, err := s.DownloadFile(ctx, "docs/a.txt")
+			require.NoError(t, err)
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			assert.Equal(t, "hello", string(data))
+
+			_, err = s.MoveObject(ctx, "docs/a.txt", "docs/b.txt")
+			require.NoError(t, err)
+			exists, err := s.ObjectExists(ctx, "docs/a.txt")
+			require.NoError(t, err)
+			assert.False(t, exists)
+			exists, err = s.ObjectExists(ctx, "docs/b.txt")
+			require.NoError(t, err)
+			assert.True(t, exists)
+
+			_, _, err = s.DownloadFile(ctx, "missing")
+			var restErr *RESTError
+			require.ErrorAs(t, err, &restErr)
+			assert.Equal(t, "NoSuchKey", restErr.Code)
+			assert.NoError(t, s.DeleteObject(ctx, "missing"))
+
+			_, err = s.UploadFile(ctx, "unknown-size", strings.NewReader("x"), -1, "")
+			assert.Error(t, err)
+
+			_, err = s.UploadFile(ctx, "empty", strings.NewReader(""), 0, "")
+			require.NoError(t, err)
+			assert.Empty(t, bucket.requests[len(bucket.requests)-1].TransferEncoding)
+		})
+	}
+}
+
+func TestRESTStorage_ListObjects(t *testing.T) {
+	for _, provider := range []string{ProviderOSS, ProviderCOS} {
+		t.Run(provider, func(t *testing.T) {
+			s, bucket := newRESTTestStorage(t, provider)
+			for _, key := range []string{"a.txt", "dir/a", "dir/b", "dir/sub/c", "dir/sub/d", "dir2/x", "e.txt", "z/y"} {
+				bucket.objects[key] = "x"
+			}
+			for _, size := range []int{1, 2, 3, 1000} {
+				assert.ElementsMatch(t, []string{"a.txt", "dir/", "dir2/", "e.txt", "z/"}, listAll(t, s, "", false, size), "page size %d", size)
+				assert.ElementsMatch(t, []string{"dir/a", "dir/b", "dir/sub/"}, listAll(t, s, "dir/", false, size), "page size %d", size)
+				assert.ElementsMatch(t, []string{"a.txt", "dir/a", "dir/b", "dir/sub/c", "dir/sub/d", "dir2/x", "e.txt", "z/y"},
+					listAll(t, s, "", true, size), "page size %d", size)
+			}
+		})
+	}
+}
+
+func TestRESTStorage_PresignedURLs(t *testing.T) {
+	oss, _ := newRESTTestStorage(t, ProviderOSS)
+	oss.now = func() time.Time { return time.Unix(1700000000, 0) }
+	oss.cfg.ExternalAddr = "https://cdn.example.com"
+
+	raw, err := oss.PresignedGetURL(context.Background(), "a b.pdf", time.Minute, "报告.pdf", false, "application/pdf")
+	require.NoError(t, err)
+	u, _ := url.Parse(raw)
+	assert.Equal(t, "cdn.example.com", u.Host)
+	assert.Equal(t, "/a b.pdf", u.Path)
+	q := u.Query()
+	assert.Equal(t, "ak", q.Get("OSSAccessKeyId"))
+	assert.Equal(t, "1700000060", q.Get("Expires"))
+	assert.Equal(t, `attachment; filename="`+url.PathEscape("报告.pdf")+`"`, q.Get("response-content-disposition"))
+	assert.Equal(t, oss.dialect.(ossDialect).signature(http.MethodGet, http.Header{}, "1700000060",
+		ossResource(oss.cfg.Bucket, "a b.pdf", q)), q.Get("Signature"))
+
+	cos, _ := newRESTTestStorage(t, ProviderCOS)
+	raw, objectPath, err := cos.PresignedPutURL(context.Background(), "up/x.bin", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "example-1250000000/up/x.bin", objectPath)
+	u, _ = url.Parse(raw)
+	q = u.Query()
+	assert.Equal(t, "host", q.Get("q-header-list"))
+	assert.NotEmpty(t, q.Get("q-signature"))
+	start, end, _ := strings.Cut(q.Get("q-sign-time"), ";")
+	assert.Equal(t, int64(3600), int64(atoi(end)-atoi(start)))
+}
+
+func TestNewObjectStorage_Providers(t *testing.T) {
+	cfg := &MinIOConfig{Provider: ProviderOSS, Region: "cn-hangzhou", Bucket: "b"}
+	s, err := NewObjectStorage(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "oss-cn-hangzhou.aliyuncs.com", s.(*restStorage).endpoint.Host)
+	assert.Empty(t, cfg.Endpoint, "caller config must not be mutated")
+
+	s, err = NewObjectStorage(&MinIOConfig{Provider: "COS", Region: "ap-guangzhou", Bucket: "b-125"})
+	require.NoError(t, err)
+	assert.Equal(t, "cos.ap-guangzhou.myqcloud.com", s.(*restStorage).endpoint.Host)
+
+	s, err = NewObjectStorage(&MinIOConfig{Provider: ProviderOSS, Bucket: "b"})
+	assert.Error(t, err)
+	assert.Nil(t, s)
+}
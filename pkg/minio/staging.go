@@ -0,0 +1,157 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/utils"
+	"github.com/minio/minio-go/v7"
+)
+
+// StagingOptions 两阶段上传配置
+type StagingOptions struct {
+	Prefix       string        // 暂存区前缀，默认 ".staging/"
+	TTL          time.Duration // 暂存对象最长保留时间，超时未提交的对象会被清理，默认 24h
+	ReapInterval time.Duration // 后台清理间隔，默认 1h
+}
+
+// Stager 两阶段上传：先上传到暂存区，确认后 Commit 移动到正式位置，放弃时 Abort 清理
+type Stager struct {
+	m    *MinIO
+	opts StagingOptions
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewStager 创建两阶段上传器
+func (m *MinIO) NewStager(opts StagingOptions) *Stager {
+	if opts.Prefix == "" {
+		opts.Prefix = ".staging/"
+	}
+	if !strings.HasSuffix(opts.Prefix, "/") {
+		opts.Prefix += "/"
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.ReapInterval <= 0 {
+		opts.ReapInterval = time.Hour
+	}
+	return &Stager{
+		m:    m,
+		opts: opts,
+		stop: make(chan struct{}),
+	}
+}
+
+// Stage 上传到暂存区，返回暂存对象名，用于后续 Commit / Abort
+func (s *Stager) Stage(ctx context.Context, objectName string, reader io.Reader, size int64, opts UploadOptions) (string, error) {
+	if objectName == "" {
+		return "", fmt.Errorf("object name must not be empty")
+	}
+	stagedName := s.opts.Prefix + utils.GenerateUUIDStr() + "/" + strings.TrimPrefix(objectName, "/")
+	if _, err := s.m.UploadFileWithOptions(ctx, stagedName, reader, size, opts); err != nil {
+		return "", err
+	}
+	return stagedName, nil
+}
+
+// Commit 将暂存对象移动到正式位置（Stage 时指定的 objectName），返回 bucket/objectName；
+// 超过 5GB 的对象由 CopyObject 分片复制
+func (s *Stager) Commit(ctx context.Context, stagedName string) (string, error) {
+	objectName, err := s.objectName(stagedName)
+	if err != nil {
+		return "", err
+	}
+	return s.m.MoveObject(ctx, stagedName, objectName)
+}
+
+// Abort 删除暂存对象
+func (s *Stager) Abort(ctx context.Context, stagedName string) error {
+	if _, err := s.objectName(stagedName); err != nil {
+		return err
+	}
+	return s.m.DeleteObject(ctx, stagedName)
+}
+
+// objectName 从暂存对象名中解析正式对象名
+func (s *Stager) objectName(stagedName string) (string, error) {
+	rest, ok := strings.CutPrefix(stagedName, s.opts.Prefix)
+	if !ok {
+		return "", fmt.Errorf("object %s is not in staging area", stagedName)
+	}
+	_, objectName, ok := strings.Cut(rest, "/")
+	if !ok || objectName == "" {
+		return "", fmt.Errorf("invalid staged object name: %s", stagedName)
+	}
+	return path.Clean(objectName), nil
+}
+
+// Reap 清理超过 TTL 仍未提交的暂存对象，返回删除数量
+func (s *Stager) Reap(ctx context.Context) (int, error) {
+	deadline := time.Now().Add(-s.opts.TTL)
+	var listErr error
+	total := 0
+	objectsCh := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objectsCh)
+		for obj := range s.m.client.ListObjects(ctx, s.m.cfg.Bucket, minio.ListObjectsOptions{Prefix: s.opts.Prefix, Recursive: true}) {
+			if obj.Err != nil {
+				listErr = obj.Err
+				return
+			}
+			if obj.LastModified.After(deadline) {
+				continue
+			}
+			total++
+			select {
+			case objectsCh <- obj:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	failed := s.m.removeObjects(ctx, objectsCh)
+	if listErr != nil {
+		return total - len(failed), fmt.Errorf("failed to list staged objects: %w", listErr)
+	}
+	if len(failed) > 0 {
+		return total - len(failed), fmt.Errorf("failed to delete %d staged objects: %w", len(failed), failed[0])
+	}
+	return total - len(failed), nil
+}
+
+// StartReaper 启动后台清理协程，onError 可为 nil
+func (s *Stager) StartReaper(onError func(error)) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.opts.ReapInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), s.opts.ReapInterval)
+				if _, err := s.Reap(ctx); err != nil && onError != nil {
+					onError(err)
+				}
+				cancel()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close 停止后台清理协程
+func (s *Stager) Close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.wg.Wait()
+}
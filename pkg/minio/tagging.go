@@ -0,0 +1,38 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+// PutObjectTags 设置对象标签（覆盖已有标签）
+func (m *MinIO) PutObjectTags(ctx context.Context, objectName string, objectTags map[string]string) error {
+	t, err := tags.NewTags(objectTags, true)
+	if err != nil {
+		return fmt.Errorf("invalid object tags: %w", err)
+	}
+	if err := m.client.PutObjectTagging(ctx, m.cfg.Bucket, objectName, t, minio.PutObjectTaggingOptions{}); err != nil {
+		return fmt.Errorf("failed to put object tags: %w", err)
+	}
+	return nil
+}
+
+// GetObjectTags 获取对象标签
+func (m *MinIO) GetObjectTags(ctx context.Context, objectName string) (map[string]string, error) {
+	t, err := m.client.GetObjectTagging(ctx, m.cfg.Bucket, objectName, minio.GetObjectTaggingOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object tags: %w", err)
+	}
+	return t.ToMap(), nil
+}
+
+// RemoveObjectTags 删除对象的全部标签
+func (m *MinIO) RemoveObjectTags(ctx context.Context, objectName string) error {
+	if err := m.client.RemoveObjectTagging(ctx, m.cfg.Bucket, objectName, minio.RemoveObjectTaggingOptions{}); err != nil {
+		return fmt.Errorf("failed to remove object tags: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/lru/expirable"
+	"github.com/minio/minio-go/v7"
+)
+
+// StorageUsage 前缀下的存储用量
+type StorageUsage struct {
+	Prefix    string    // 统计前缀
+	Objects   int64     // 对象数量
+	Bytes     int64     // 总字节数
+	ScannedAt time.Time // 统计时间
+}
+
+// Usage 遍历前缀下的全部对象统计数量和总大小，对象较多时耗时较长，可配合 UsageCache 使用
+func (m *MinIO) Usage(ctx context.Context, prefix string) (StorageUsage, error) {
+	usage := StorageUsage{Prefix: prefix}
+	for obj := range m.client.ListObjects(ctx, m.cfg.Bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return StorageUsage{}, fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		usage.Objects++
+		usage.Bytes += obj.Size
+	}
+	if err := ctx.Err(); err != nil {
+		return StorageUsage{}, fmt.Errorf("usage scan canceled: %w", err)
+	}
+	usage.ScannedAt = time.Now()
+	return usage, nil
+}
+
+// UsageCache 缓存前缀用量统计，适用于配额校验、管理后台等允许短暂不一致的场景
+type UsageCache struct {
+	m     *MinIO
+	cache *expirable.LRU[string, StorageUsage]
+}
+
+// NewUsageCache 创建用量缓存，size 为最多缓存的前缀数，ttl 为缓存有效期
+func (m *MinIO) NewUsageCache(size int, ttl time.Duration) *UsageCache {
+	return &UsageCache{
+		m:     m,
+		cache: expirable.NewLRU[string, StorageUsage](size, nil, ttl),
+	}
+}
+
+// Usage 优先返回缓存结果，未命中或已过期时重新统计
+func (c *UsageCache) Usage(ctx context.Context, prefix string) (StorageUsage, error) {
+	if usage, ok := c.cache.Get(c.key(prefix)); ok {
+		return usage, nil
+	}
+	usage, err := c.m.Usage(ctx, prefix)
+	if err != nil {
+		return StorageUsage{}, err
+	}
+	c.cache.Add(c.key(prefix), usage)
+	return usage, nil
+}
+
+// Invalidate 使前缀的缓存失效，通常在上传或删除后调用
+func (c *UsageCache) Invalidate(prefix string) {
+	c.cache.Remove(c.key(prefix))
+}
+
+func (c *UsageCache) key(prefix string) string {
+	return c.m.cfg.Bucket + "/" + prefix
+}
@@ -20,6 +20,7 @@ type MongoConfig struct {
 	MaxPoolSize    uint64 `mapstructure:"maxPoolSize"`    // 最大连接池大小
 	ConnectTimeout int64  `mapstructure:"connectTimeout"` // 连接超时时间（单位：秒）
 	ReadPreference string `mapstructure:"readPreference"` // 读取偏好（primary/nearest/secondaryPreferred）
+	Metrics        bool   `mapstructure:"metrics"`        // 启用命令指标，注册到 metrics.Default()
 }
 
 // New 初始化 MongoDB 客户端并返回 client 和 database 实例。
@@ -31,12 +32,18 @@ func New(cfg *MongoConfig) (*mongo.Client, *mongo.Database, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	monitor := newTraceMonitor()
+	if cfg.Metrics {
+		monitor = chainMonitors(monitor, newMetricsMonitor())
+	}
+
 	// 构建连接选项
 	opts := options.Client().
 		ApplyURI(cfg.URI).
 		SetMinPoolSize(cfg.MinPoolSize).
 		SetMaxPoolSize(cfg.MaxPoolSize).
-		SetConnectTimeout(timeout)
+		SetConnectTimeout(timeout).
+		SetMonitor(monitor)
 
 	// 设置读偏好
 	switch cfg.ReadPreference {
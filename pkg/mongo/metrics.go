@@ -0,0 +1,52 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/code-sigs/go-box/pkg/metrics"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// newMetricsMonitor 按命令统计调用数与耗时，注册到 metrics.Default()
+func newMetricsMonitor() *event.CommandMonitor {
+	reg := metrics.Default()
+	commands := reg.Counter("mongo_commands_total", "MongoDB commands executed, partitioned by result: success, error.", "command", "result")
+	duration := reg.Histogram("mongo_command_duration_seconds", "MongoDB command latency.", nil, "command")
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			commands.WithLabelValues(e.CommandName, "success").Inc()
+			duration.WithLabelValues(e.CommandName).Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			commands.WithLabelValues(e.CommandName, "error").Inc()
+			duration.WithLabelValues(e.CommandName).Observe(e.Duration.Seconds())
+		},
+	}
+}
+
+// chainMonitors 依次调用多个 CommandMonitor
+func chainMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				if m.Started != nil {
+					m.Started(ctx, e)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				if m.Succeeded != nil {
+					m.Succeeded(ctx, e)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				if m.Failed != nil {
+					m.Failed(ctx, e)
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/code-sigs/go-box/pkg/trace"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// newTraceMonitor 为每条命令创建客户端 span，按 RequestID 关联开始和结束事件
+func newTraceMonitor() *event.CommandMonitor {
+	var spans sync.Map // int64 -> oteltrace.Span
+	end := func(requestID int64, err string) {
+		v, ok := spans.LoadAndDelete(requestID)
+		if !ok {
+			return
+		}
+		span := v.(oteltrace.Span)
+		if err != "" {
+			span.SetStatus(codes.Error, err)
+		}
+		span.End()
+	}
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			_, span := trace.Start(ctx, "mongo "+e.CommandName,
+				oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+				oteltrace.WithAttributes(
+					attribute.String("db.system.name", "mongodb"),
+					attribute.String("db.namespace", e.DatabaseName),
+					attribute.String("db.operation.name", e.CommandName),
+				),
+			)
+			spans.Store(e.RequestID, span)
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			end(e.RequestID, "")
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			end(e.RequestID, e.Failure)
+		},
+	}
+}
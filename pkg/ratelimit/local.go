@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/lru/expirable"
+)
+
+// bucketState 令牌桶状态
+type bucketState struct {
+	tokens float64
+	last   time.Time
+}
+
+// LocalTokenBucket 进程内令牌桶
+type LocalTokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒补充的令牌数
+	burst  int
+	states *expirable.LRU[string, *bucketState]
+}
+
+// NewLocalTokenBucket 创建进程内令牌桶，每 period 补充 limit 个令牌，容量为 burst，
+// size 为最多跟踪的 key 数，0 不限
+func NewLocalTokenBucket(limit int, period time.Duration, burst, size int) *LocalTokenBucket {
+	if burst <= 0 {
+		burst = limit
+	}
+	rate := float64(limit) / period.Seconds()
+	// 桶补满后状态与新建一致，可以过期淘汰
+	ttl := time.Duration(float64(burst)/rate*float64(time.Second)) + time.Second
+	return &LocalTokenBucket{
+		rate:   rate,
+		burst:  burst,
+		states: expirable.NewLRU[string, *bucketState](size, nil, ttl),
+	}
+}
+
+func (l *LocalTokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+func (l *LocalTokenBucket) AllowN(_ context.Context, key string, n int) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	st, ok := l.states.Get(key)
+	if !ok {
+		st = &bucketState{tokens: float64(l.burst), last: now}
+	}
+	st.tokens = math.Min(float64(l.burst), st.tokens+now.Sub(st.last).Seconds()*l.rate)
+	st.last = now
+	l.states.Add(key, st)
+
+	if st.tokens >= float64(n) {
+		st.tokens -= float64(n)
+		return Result{Allowed: true, Remaining: int(st.tokens)}, nil
+	}
+	wait := (float64(n) - st.tokens) / l.rate
+	return Result{Remaining: int(st.tokens), RetryAfter: time.Duration(wait * float64(time.Second))}, nil
+}
+
+// windowState 滑动窗口状态，window 为当前固定窗口的序号
+type windowState struct {
+	window int64
+	curr   int
+	prev   int
+}
+
+// LocalSlidingWindow 进程内滑动窗口计数
+type LocalSlidingWindow struct {
+	mu     sync.Mutex
+	limit  int
+	period time.Duration
+	states *expirable.LRU[string, *windowState]
+}
+
+// NewLocalSlidingWindow 创建进程内滑动窗口限流，任意 period 长度内最多约 limit 个请求
+func NewLocalSlidingWindow(limit int, period time.Duration, size int) *LocalSlidingWindow {
+	return &LocalSlidingWindow{
+		limit:  limit,
+		period: period,
+		states: expirable.NewLRU[string, *windowState](size, nil, 2*period),
+	}
+}
+
+func (l *LocalSlidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+func (l *LocalSlidingWindow) AllowN(_ context.Context, key string, n int) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now().UnixNano()
+	period := l.period.Nanoseconds()
+	window := now / period
+	st, ok := l.states.Get(key)
+	if !ok {
+		st = &windowState{window: window}
+	}
+	switch st.window {
+	case window:
+	case window - 1:
+		st.window, st.prev, st.curr = window, st.curr, 0
+	default:
+		st.window, st.prev, st.curr = window, 0, 0
+	}
+	l.states.Add(key, st)
+
+	allowed, remaining, retry := slidingWindow(l.limit, period, now-window*period, st.prev, st.curr, n)
+	if allowed {
+		st.curr += n
+	}
+	return Result{Allowed: allowed, Remaining: remaining, RetryAfter: time.Duration(retry)}, nil
+}
+
+// slidingWindow 按上一窗口剩余比例加权估算当前请求数，elapsed 为当前窗口已经过的时间（纳秒），
+// 返回是否放行、放行后的剩余配额，以及未放行时需要等待的纳秒数
+func slidingWindow(limit int, period, elapsed int64, prev, curr, n int) (bool, int, int64) {
+	weight := float64(period-elapsed) / float64(period)
+	used := float64(prev)*weight + float64(curr)
+	if used+float64(n) <= float64(limit) {
+		return true, int(float64(limit) - used - float64(n)), 0
+	}
+	remaining := int(math.Max(0, float64(limit)-used))
+	// 当前窗口的请求数已足够超限时，只能等到下个窗口再按比例释放
+	free := float64(limit - curr - n)
+	if prev == 0 || free < 0 {
+		return false, remaining, period - elapsed
+	}
+	// 上一窗口的权重降到 free/prev 时恰好放行
+	target := int64(math.Ceil((1 - free/float64(prev)) * float64(period)))
+	return false, remaining, max(target-elapsed, 1)
+}
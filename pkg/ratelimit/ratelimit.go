@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/redis"
+)
+
+// ErrLimited Wait 在 ctx 结束前仍未获得配额
+var ErrLimited = errors.New("ratelimit: rate limit exceeded")
+
+// 限流算法
+const (
+	TokenBucket   = "tokenBucket"   // 令牌桶，允许 Burst 大小的突发
+	SlidingWindow = "slidingWindow" // 滑动窗口计数，按前后两个固定窗口加权估算
+)
+
+// 存储后端
+const (
+	BackendLocal = "local" // 进程内，单实例限流
+	BackendRedis = "redis" // Redis Lua 脚本，集群共享配额
+)
+
+// Config 限流配置，HTTP 中间件、gRPC 拦截器与 Kafka 消费者共用
+type Config struct {
+	Algorithm string        `mapstructure:"algorithm" default:"tokenBucket"` // tokenBucket / slidingWindow
+	Backend   string        `mapstructure:"backend" default:"local"`         // local / redis
+	Limit     int           `mapstructure:"limit"`                           // 每个周期允许的请求数
+	Period    time.Duration `mapstructure:"period" default:"1s"`             // 周期
+	Burst     int           `mapstructure:"burst"`                           // 令牌桶容量，默认等于 Limit
+	Prefix    string        `mapstructure:"prefix" default:"ratelimit:"`     // Redis key 前缀
+	Size      int           `mapstructure:"size" default:"100000"`           // 本地后端最多跟踪的 key 数
+}
+
+// Result 一次限流判断的结果
+type Result struct {
+	Allowed    bool          // 是否放行
+	Remaining  int           // 剩余配额
+	RetryAfter time.Duration // 未放行时建议的重试等待时间
+}
+
+// Limiter 按 key 独立限流，key 可以是用户、IP、方法名等，为空表示全局
+type Limiter interface {
+	// Allow 消耗 1 个配额
+	Allow(ctx context.Context, key string) (Result, error)
+	// AllowN 消耗 n 个配额，不足时不消耗
+	AllowN(ctx context.Context, key string, n int) (Result, error)
+}
+
+// New 根据配置创建限流器，Backend 为 redis 时 rdb 不能为空
+func New(cfg Config, rdb *redis.RedisClient) (Limiter, error) {
+	if cfg.Limit <= 0 {
+		return nil, fmt.Errorf("ratelimit: limit must be positive, got %d", cfg.Limit)
+	}
+	if cfg.Period <= 0 {
+		cfg.Period = time.Second
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = cfg.Limit
+	}
+	switch cfg.Backend {
+	case BackendLocal, "":
+		switch cfg.Algorithm {
+		case TokenBucket, "":
+			return NewLocalTokenBucket(cfg.Limit, cfg.Period, cfg.Burst, cfg.Size), nil
+		case SlidingWindow:
+			return NewLocalSlidingWindow(cfg.Limit, cfg.Period, cfg.Size), nil
+		}
+	case BackendRedis:
+		if rdb == nil {
+			return nil, fmt.Errorf("ratelimit: redis client is required for backend %q", cfg.Backend)
+		}
+		switch cfg.Algorithm {
+		case TokenBucket, "":
+			return NewRedisTokenBucket(rdb, cfg.Prefix, cfg.Limit, cfg.Period, cfg.Burst), nil
+		case SlidingWindow:
+			return NewRedisSlidingWindow(rdb, cfg.Prefix, cfg.Limit, cfg.Period), nil
+		}
+	default:
+		return nil, fmt.Errorf("ratelimit: unsupported backend %q", cfg.Backend)
+	}
+	return nil, fmt.Errorf("ratelimit: unsupported algorithm %q", cfg.Algorithm)
+}
+
+// Wait 阻塞直到获得 1 个配额，ctx 结束时返回包装了 ErrLimited 的错误
+func Wait(ctx context.Context, l Limiter, key string) error {
+	return WaitN(ctx, l, key, 1)
+}
+
+// WaitN 阻塞直到获得 n 个配额
+func WaitN(ctx context.Context, l Limiter, key string, n int) error {
+	for {
+		res, err := l.AllowN(ctx, key, n)
+		if err != nil {
+			return err
+		}
+		if res.Allowed {
+			return nil
+		}
+		delay := res.RetryAfter
+		if delay < time.Millisecond {
+			delay = time.Millisecond
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %w", ErrLimited, ctx.Err())
+		}
+	}
+}
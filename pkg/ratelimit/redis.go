@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript 令牌桶，使用 Redis 时钟避免实例间时钟偏差。
+// KEYS[1] 桶 key；ARGV: 每微秒补充的令牌数、容量、本次消耗数
+// 返回 {是否放行, 剩余令牌, 需等待的微秒数}
+var tokenBucketScript = goredis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local wait = 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+else
+	wait = math.ceil((n - tokens) / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', string.format('%d', now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate / 1000) + 1000)
+return {allowed, math.floor(tokens), wait}
+`)
+
+// slidingWindowScript 滑动窗口计数，前后两个固定窗口的计数存于同一个 hash，兼容 Redis Cluster。
+// KEYS[1] 窗口 key；ARGV: 上限、周期（毫秒）、本次消耗数
+// 返回 {是否放行, 剩余配额, 需等待的毫秒数}
+var slidingWindowScript = goredis.NewScript(`
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local window = math.floor(now / period)
+local data = redis.call('HMGET', KEYS[1], 'window', 'curr', 'prev')
+local w = tonumber(data[1])
+local curr = tonumber(data[2]) or 0
+local prev = tonumber(data[3]) or 0
+if w == nil or w < window - 1 then
+	curr = 0
+	prev = 0
+elseif w == window - 1 then
+	prev = curr
+	curr = 0
+end
+local elapsed = now - window * period
+local used = prev * (period - elapsed) / period + curr
+local allowed = 0
+local wait = 0
+if used + n <= limit then
+	allowed = 1
+	curr = curr + n
+	used = used + n
+else
+	local free = limit - curr - n
+	if prev == 0 or free < 0 then
+		wait = period - elapsed
+	else
+		wait = math.max(1, math.ceil((1 - free / prev) * period) - elapsed)
+	end
+end
+redis.call('HSET', KEYS[1], 'window', window, 'curr', curr, 'prev', prev)
+redis.call('PEXPIRE', KEYS[1], period * 2)
+return {allowed, math.max(0, math.floor(limit - used)), wait}
+`)
+
+// RedisTokenBucket 基于 Redis 的令牌桶，集群共享配额
+type RedisTokenBucket struct {
+	rdb    *redis.RedisClient
+	prefix string
+	rate   float64 // 每微秒补充的令牌数
+	burst  int
+}
+
+// NewRedisTokenBucket 创建 Redis 令牌桶，每 period 补充 limit 个令牌，容量为 burst
+func NewRedisTokenBucket(rdb *redis.RedisClient, prefix string, limit int, period time.Duration, burst int) *RedisTokenBucket {
+	if burst <= 0 {
+		burst = limit
+	}
+	return &RedisTokenBucket{
+		rdb:    rdb,
+		prefix: prefix,
+		rate:   float64(limit) / float64(period.Microseconds()),
+		burst:  burst,
+	}
+}
+
+func (l *RedisTokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+func (l *RedisTokenBucket) AllowN(ctx context.Context, key string, n int) (Result, error) {
+	vals, err := tokenBucketScript.Run(ctx, l.rdb.DB(), []string{l.prefix + key}, l.rate, l.burst, n).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit: redis token bucket: %w", err)
+	}
+	return Result{
+		Allowed:    vals[0] == 1,
+		Remaining:  int(vals[1]),
+		RetryAfter: time.Duration(vals[2]) * time.Microsecond,
+	}, nil
+}
+
+// RedisSlidingWindow 基于 Redis 的滑动窗口计数，集群共享配额
+type RedisSlidingWindow struct {
+	rdb    *redis.RedisClient
+	prefix string
+	limit  int
+	period int64 // 毫秒
+}
+
+// NewRedisSlidingWindow 创建 Redis 滑动窗口限流，period 精度为毫秒
+func NewRedisSlidingWindow(rdb *redis.RedisClient, prefix string, limit int, period time.Duration) *RedisSlidingWindow {
+	return &RedisSlidingWindow{
+		rdb:    rdb,
+		prefix: prefix,
+		limit:  limit,
+		period: max(period.Milliseconds(), 1),
+	}
+}
+
+func (l *RedisSlidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+func (l *RedisSlidingWindow) AllowN(ctx context.Context, key string, n int) (Result, error) {
+	vals, err := slidingWindowScript.Run(ctx, l.rdb.DB(), []string{l.prefix + key}, l.limit, l.period, n).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit: redis sliding window: %w", err)
+	}
+	return Result{
+		Allowed:    vals[0] == 1,
+		Remaining:  int(vals[1]),
+		RetryAfter: time.Duration(vals[2]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/code-sigs/go-box/pkg/breaker"
+	"github.com/redis/go-redis/v9"
+)
+
+// breakerName Redis 熔断器在 breaker.Default 中的名称
+const breakerName = "redis"
+
+// breakerHook 所有命令共用名为 "redis" 的熔断器，key 不存在与事务冲突不计入失败
+type breakerHook struct{}
+
+func (breakerHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (breakerHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		return breaker.DoWithAcceptable(ctx, breakerName, func(ctx context.Context) error {
+			return next(ctx, cmd)
+		}, acceptableRedisError)
+	}
+}
+
+func (breakerHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		return breaker.DoWithAcceptable(ctx, breakerName, func(ctx context.Context) error {
+			return next(ctx, cmds)
+		}, acceptableRedisError)
+	}
+}
+
+func acceptableRedisError(err error) bool {
+	return errors.Is(err, redis.Nil) || errors.Is(err, redis.TxFailedErr)
+}
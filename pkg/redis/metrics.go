@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// metricsHook 按命令统计调用数与耗时，pipeline 按 "pipeline" 记一次
+type metricsHook struct {
+	commands *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newMetricsHook() metricsHook {
+	reg := metrics.Default()
+	return metricsHook{
+		commands: reg.Counter("redis_commands_total", "Redis commands executed, partitioned by result: success, nil, error.", "command", "result"),
+		duration: reg.Histogram("redis_command_duration_seconds", "Redis command latency.",
+			[]float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}, "command"),
+	}
+}
+
+func (h metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.observe(cmd.Name(), start, err)
+		return err
+	}
+}
+
+func (h metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		h.observe("pipeline", start, err)
+		return err
+	}
+}
+
+func (h metricsHook) observe(command string, start time.Time, err error) {
+	result := "success"
+	switch {
+	case errors.Is(err, redis.Nil):
+		result = "nil"
+	case err != nil:
+		result = "error"
+	}
+	h.commands.WithLabelValues(command, result).Inc()
+	h.duration.WithLabelValues(command).Observe(time.Since(start).Seconds())
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/code-sigs/go-box/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
@@ -22,6 +23,8 @@ type RedisConfig struct {
 	ReadTimeout  int64    `mapstructure:"readTimeout"`  // 读取超时(秒)
 	WriteTimeout int64    `mapstructure:"writeTimeout"` // 写入超时(秒)
 	IdleTimeout  int64    `mapstructure:"idleTimeout"`  // 空闲连接超时时间(秒)
+	Breaker      bool     `mapstructure:"breaker"`      // 启用熔断，参数通过 breaker.Configure("redis", ...) 调整
+	Metrics      bool     `mapstructure:"metrics"`      // 启用命令指标，注册到 metrics.Default()
 }
 
 // RedisClient 封装后的Redis客户端
@@ -52,6 +55,14 @@ func NewRedisClient(cfg *RedisConfig) (*RedisClient, error) {
 		}) // 测试连接
 	}
 
+	rdb.AddHook(traceHook{})
+	if cfg.Metrics {
+		rdb.AddHook(newMetricsHook())
+	}
+	if cfg.Breaker {
+		rdb.AddHook(breakerHook{})
+	}
+
 	ctx := context.Background()
 	if _, err := rdb.Ping(ctx).Result(); err != nil {
 		return nil, fmt.Errorf("connect to redis failed: %v", err)
@@ -379,19 +390,20 @@ func (l *RedisLock) Unlock() (bool, error) {
 			return 0
 		end
 	`
-	ctx := context.Background()
-	for i := 0; i < 3; i++ {
+	var released bool
+	err := utils.Retry(context.Background(), 3, utils.ConstantBackoff(50*time.Millisecond), func(ctx context.Context) error {
 		res, err := l.client.Eval(ctx, luaScript, []string{l.key}, l.value).Result()
-		if err == nil {
-			if v, ok := res.(int64); ok && v == 1 {
-				return true, nil
-			}
-			return false, nil
+		if err != nil {
+			return err
 		}
-		time.Sleep(50 * time.Millisecond)
+		v, ok := res.(int64)
+		released = ok && v == 1
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to release lock after retries: %w", err)
 	}
-
-	return false, errors.New("failed to release lock after retries")
+	return released, nil
 }
 
 // startAutoRenew periodically renews the lock TTL
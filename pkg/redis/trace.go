@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/code-sigs/go-box/pkg/trace"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// traceHook 为每条命令和 pipeline 创建客户端 span
+type traceHook struct{}
+
+func (traceHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (traceHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := trace.Start(ctx, "redis "+cmd.Name(),
+			oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+			oteltrace.WithAttributes(
+				attribute.String("db.system.name", "redis"),
+				attribute.String("db.operation.name", cmd.Name()),
+			),
+		)
+		err := next(ctx, cmd)
+		trace.End(span, traceErr(err))
+		return err
+	}
+}
+
+func (traceHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, 0, len(cmds))
+		for _, cmd := range cmds {
+			names = append(names, cmd.Name())
+		}
+		ctx, span := trace.Start(ctx, "redis pipeline",
+			oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+			oteltrace.WithAttributes(
+				attribute.String("db.system.name", "redis"),
+				attribute.String("db.operation.name", strings.Join(names, " ")),
+				attribute.Int("db.operation.batch.size", len(cmds)),
+			),
+		)
+		err := next(ctx, cmds)
+		trace.End(span, traceErr(err))
+		return err
+	}
+}
+
+// traceErr key 不存在不视为错误
+func traceErr(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
@@ -3,14 +3,19 @@ package etcd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/code-sigs/go-box/pkg/logger"
 	registry "github.com/code-sigs/go-box/pkg/registry/registry_interface"
+	"github.com/code-sigs/go-box/pkg/utils"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
+// errWatchClosed watch 通道被服务端或网络中断关闭
+var errWatchClosed = errors.New("etcd watch channel closed")
+
 type EtcdRegistry struct {
 	cli     *clientv3.Client
 	cache   map[string][]*registry.ServiceInstance
@@ -31,6 +36,11 @@ func NewEtcdRegistry(endpoints []string, dialTimeout time.Duration) (*EtcdRegist
 	}, nil
 }
 
+// Client 返回底层 etcd 连接，可供配置中心等复用
+func (e *EtcdRegistry) Client() *clientv3.Client {
+	return e.cli
+}
+
 func (e *EtcdRegistry) Register(ctx context.Context, info *registry.ServiceInfo) error {
 	key := "/go-box-services/" + info.Name + "/" + info.Address
 
@@ -91,7 +101,7 @@ func (e *EtcdRegistry) Watch(ctx context.Context, serviceName string) (<-chan []
 	prefix := "/go-box-services/" + serviceName + "/"
 	out := make(chan []*registry.ServiceInstance, 10) // 缓冲防止阻塞
 
-	go func() {
+	utils.Go(ctx, "etcd-watch:"+serviceName, func(ctx context.Context) error {
 		defer close(out)
 
 		loadInstances := func() ([]*registry.ServiceInstance, error) {
@@ -132,7 +142,7 @@ func (e *EtcdRegistry) Watch(ctx context.Context, serviceName string) (<-chan []
 
 		instances, err := loadInstances()
 		if err != nil {
-			return
+			return err
 		}
 		// 更新本地缓存
 		e.cacheMu.Lock()
@@ -140,23 +150,16 @@ func (e *EtcdRegistry) Watch(ctx context.Context, serviceName string) (<-chan []
 		e.cacheMu.Unlock()
 		sendInstances(instances)
 
-		backoff := time.Second
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-			}
-
+		// watch 中断后按指数退避（等抖动，至少等待 500ms）重新建立，直到 ctx 取消
+		_ = utils.Retry(ctx, 0, utils.EqualJitterBackoff(time.Second, 30*time.Second), func(ctx context.Context) error {
 			watchChan := e.cli.Watch(ctx, prefix, clientv3.WithPrefix(), clientv3.WithPrevKV())
 			for watchResp := range watchChan {
-				if watchResp.Err() != nil {
-					break
+				if err := watchResp.Err(); err != nil {
+					return err
 				}
 				instances, err := loadInstances()
 				if err != nil {
-					break
+					return err
 				}
 				// 更新本地缓存
 				e.cacheMu.Lock()
@@ -164,14 +167,12 @@ func (e *EtcdRegistry) Watch(ctx context.Context, serviceName string) (<-chan []
 				e.cacheMu.Unlock()
 				sendInstances(instances)
 			}
-
-			time.Sleep(backoff)
-			backoff *= 2
-			if backoff > 30*time.Second {
-				backoff = 30 * time.Second
-			}
-		}
-	}()
+			return errWatchClosed
+		}, utils.OnRetry(func(attempt int, err error, delay time.Duration) {
+			logger.Warnw(ctx, "registry", "err", err.Error(), "watch key", prefix, "retry in", delay)
+		}))
+		return nil
+	})
 
 	return out, nil
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/code-sigs/go-box/pkg/registry/registry_interface"
+	"github.com/code-sigs/go-box/pkg/utils"
 	"github.com/go-zookeeper/zk"
 )
 
@@ -70,44 +71,45 @@ func (z *ZkRegistry) Unregister(ctx context.Context, info *registry_interface.Se
 func (z *ZkRegistry) Watch(ctx context.Context, serviceName string) (<-chan []*registry_interface.ServiceInstance, error) {
 	ch := make(chan []*registry_interface.ServiceInstance)
 
-	go func() {
+	utils.Go(ctx, "zk-watch:"+serviceName, func(ctx context.Context) error {
 		defer close(ch)
-		for {
-			children, _, events, err := z.conn.ChildrenW(z.servicePath(serviceName))
-			if err != nil {
-				time.Sleep(time.Second * 2)
-				continue
-			}
+		// 节点读取失败时每 2 秒重试，直到 ctx 取消
+		return utils.Retry(ctx, 0, utils.ConstantBackoff(2*time.Second), func(ctx context.Context) error {
+			for {
+				children, _, events, err := z.conn.ChildrenW(z.servicePath(serviceName))
+				if err != nil {
+					return err
+				}
 
-			instances := []*registry_interface.ServiceInstance{}
-			for _, child := range children {
-				fullPath := fmt.Sprintf("%s/%s", z.servicePath(serviceName), child)
-				data, _, err := z.conn.Get(fullPath)
-				if err == nil {
-					instances = append(instances, &registry_interface.ServiceInstance{
-						Address: string(data),
-					})
+				instances := []*registry_interface.ServiceInstance{}
+				for _, child := range children {
+					fullPath := fmt.Sprintf("%s/%s", z.servicePath(serviceName), child)
+					data, _, err := z.conn.Get(fullPath)
+					if err == nil {
+						instances = append(instances, &registry_interface.ServiceInstance{
+							Address: string(data),
+						})
+					}
+				}
+				// 更新本地缓存
+				z.cacheMu.Lock()
+				z.cache[serviceName] = instances
+				z.cacheMu.Unlock()
+
+				select {
+				case ch <- instances:
+				case <-ctx.Done():
+					return nil
 				}
-			}
-			// 更新本地缓存
-			z.cacheMu.Lock()
-			z.cache[serviceName] = instances
-			z.cacheMu.Unlock()
-
-			select {
-			case ch <- instances:
-			case <-ctx.Done():
-				return
-			}
 
-			select {
-			case <-events:
-				continue
-			case <-ctx.Done():
-				return
+				select {
+				case <-events:
+				case <-ctx.Done():
+					return nil
+				}
 			}
-		}
-	}()
+		})
+	})
 
 	return ch, nil
 }
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+
+	"github.com/code-sigs/go-box/pkg/utils"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -23,6 +25,7 @@ type BaseRepository[T any, K comparable] interface {
 	FindOne(ctx context.Context, filter map[string]any, opts ...*options.FindOneOptions) (*T, error)
 	Find(ctx context.Context, filter map[string]any, sort map[string]int) ([]*T, error)
 	Paginate(ctx context.Context, page int, limit int, filter map[string]any, sort map[string]int) ([]*T, int64, error)
+	PaginateRequest(ctx context.Context, req utils.PageRequest, filter map[string]any) (*utils.PageResponse[*T], error)
 	GetMaxUpdatedAt(ctx context.Context) (int64, error)
 	Count(ctx context.Context, filter map[string]any) (int64, error)
 	WithTransaction(ctx context.Context, fn func(txCtx context.Context) error) error
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/code-sigs/go-box/pkg/breaker"
+	"github.com/code-sigs/go-box/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -18,10 +20,29 @@ import (
 type MongoRepository[T any, K comparable] struct {
 	collection *mongo.Collection
 	idField    string
+	breaker    string // 熔断器名称，为空不熔断
+}
+
+// Option MongoRepository 的可选参数
+type Option func(*repoOptions)
+
+type repoOptions struct {
+	breaker string
+}
+
+// WithBreaker 通过 breaker.Default 中名为 name 的熔断器执行数据库操作，
+// name 为空时使用 "mongo:<集合名>"
+func WithBreaker(name string) Option {
+	return func(o *repoOptions) {
+		if name == "" {
+			name = "mongo:"
+		}
+		o.breaker = name
+	}
 }
 
 // NewMongoRepository 创建新的 MongoRepository，自动推导集合名。
-func NewMongoRepository[T any, K comparable](db *mongo.Database) *MongoRepository[T, K] {
+func NewMongoRepository[T any, K comparable](db *mongo.Database, opts ...Option) *MongoRepository[T, K] {
 	var entity T
 	t := reflect.TypeOf(entity)
 	if t.Kind() == reflect.Ptr {
@@ -29,10 +50,28 @@ func NewMongoRepository[T any, K comparable](db *mongo.Database) *MongoRepositor
 	}
 	collectionName := toSnakeCase(t.Name())
 	collection := db.Collection(collectionName)
+	var o repoOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.breaker == "mongo:" {
+		o.breaker += collectionName
+	}
 	return &MongoRepository[T, K]{
 		collection: collection,
 		idField:    "_id",
+		breaker:    o.breaker,
+	}
+}
+
+// do 启用熔断时通过熔断器执行 fn，记录不存在、唯一键冲突不计入失败
+func (r *MongoRepository[T, K]) do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.breaker == "" {
+		return fn(ctx)
 	}
+	return breaker.DoWithAcceptable(ctx, r.breaker, fn, func(err error) bool {
+		return errors.Is(err, mongo.ErrNoDocuments) || mongo.IsDuplicateKeyError(err)
+	})
 }
 
 // CreateIndexGeneric 创建 MongoDB 索引
@@ -85,12 +124,21 @@ func (r *MongoRepository[T, K]) CreateIndex(ctx context.Context, keys map[string
 		Options: indexOpts,
 	}
 
-	return r.collection.Indexes().CreateOne(ctx, model)
+	var name string
+	err := r.do(ctx, func(ctx context.Context) error {
+		var err error
+		name, err = r.collection.Indexes().CreateOne(ctx, model)
+		return err
+	})
+	return name, err
 }
 
 func (r *MongoRepository[T, K]) Create(ctx context.Context, entity *T) (*T, error) {
 	setTimestampsAndID(entity, r.idField, false)
-	_, err := r.collection.InsertOne(ctx, entity)
+	err := r.do(ctx, func(ctx context.Context) error {
+		_, err := r.collection.InsertOne(ctx, entity)
+		return err
+	})
 	return entity, err
 }
 
@@ -108,15 +156,19 @@ func (r *MongoRepository[T, K]) CreateMany(ctx context.Context, entities []*T) e
 	}
 
 	// 插入数据库
-	_, err := r.collection.InsertMany(ctx, docs)
-	return err
+	return r.do(ctx, func(ctx context.Context) error {
+		_, err := r.collection.InsertMany(ctx, docs)
+		return err
+	})
 }
 
 func (r *MongoRepository[T, K]) GetByID(ctx context.Context, id K) (*T, error) {
 	filter := bson.M{r.idField: id}
 	ApplyUnDeletedFilter(filter)
 	var result T
-	err := r.collection.FindOne(ctx, filter).Decode(&result)
+	err := r.do(ctx, func(ctx context.Context) error {
+		return r.collection.FindOne(ctx, filter).Decode(&result)
+	})
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, nil
 	}
@@ -141,8 +193,10 @@ func (r *MongoRepository[T, K]) Update(ctx context.Context, entity *T) error {
 	}
 	setTimestampsAndID(entity, r.idField, true)
 	filter := bson.M{r.idField: id}
-	_, err := r.collection.ReplaceOne(ctx, filter, entity)
-	return err
+	return r.do(ctx, func(ctx context.Context) error {
+		_, err := r.collection.ReplaceOne(ctx, filter, entity)
+		return err
+	})
 }
 
 // UpdateFields 只更新指定字段
@@ -157,7 +211,12 @@ func (r *MongoRepository[T, K]) UpdateFields(ctx context.Context, id K, updates
 	update := bson.M{"$set": updates}
 
 	// 执行更新
-	result, err := r.collection.UpdateOne(ctx, filter, update)
+	var result *mongo.UpdateResult
+	err := r.do(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = r.collection.UpdateOne(ctx, filter, update)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -175,7 +234,12 @@ func (r *MongoRepository[T, K]) UpdateOne(ctx context.Context, filter map[string
 	//	update["updatedAt"] = time.Now()
 	//}
 	// 执行更新
-	result, err := r.collection.UpdateOne(ctx, filter, update)
+	var result *mongo.UpdateResult
+	err := r.do(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = r.collection.UpdateOne(ctx, filter, update)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -190,8 +254,10 @@ func (r *MongoRepository[T, K]) UpdateOne(ctx context.Context, filter map[string
 func (r *MongoRepository[T, K]) Delete(ctx context.Context, id K) error {
 	filter := bson.M{r.idField: id}
 	update := bson.M{"$set": bson.M{"deletedAt": time.Now()}}
-	_, err := r.collection.UpdateOne(ctx, filter, update)
-	return err
+	return r.do(ctx, func(ctx context.Context) error {
+		_, err := r.collection.UpdateOne(ctx, filter, update)
+		return err
+	})
 }
 
 // DeleteMany 软删除多个文档，根据传入的 ID 列表进行更新
@@ -213,20 +279,26 @@ func (r *MongoRepository[T, K]) DeleteMany(ctx context.Context, ids []K) error {
 	}
 
 	// 执行更新
-	_, err := r.collection.UpdateMany(ctx, filter, update)
-	return err
+	return r.do(ctx, func(ctx context.Context) error {
+		_, err := r.collection.UpdateMany(ctx, filter, update)
+		return err
+	})
 }
 
 func (r *MongoRepository[T, K]) HardDeleteOne(ctx context.Context, filter map[string]any) error {
-	_, err := r.collection.DeleteOne(ctx, filter)
-	return err
+	return r.do(ctx, func(ctx context.Context) error {
+		_, err := r.collection.DeleteOne(ctx, filter)
+		return err
+	})
 }
 
 // HardDelete 直接从数据库中物理删除文档（非软删除）
 func (r *MongoRepository[T, K]) HardDelete(ctx context.Context, id K) error {
 	filter := bson.M{r.idField: id}
-	_, err := r.collection.DeleteOne(ctx, filter)
-	return err
+	return r.do(ctx, func(ctx context.Context) error {
+		_, err := r.collection.DeleteOne(ctx, filter)
+		return err
+	})
 }
 
 // HardDeleteMany 直接物理删除多个文档，根据传入的 ID 列表进行删除
@@ -241,19 +313,23 @@ func (r *MongoRepository[T, K]) HardDeleteMany(ctx context.Context, ids []K) err
 	}
 
 	// 执行删除
-	_, err := r.collection.DeleteMany(ctx, filter)
-	return err
+	return r.do(ctx, func(ctx context.Context) error {
+		_, err := r.collection.DeleteMany(ctx, filter)
+		return err
+	})
 }
 
 func (r *MongoRepository[T, K]) List(ctx context.Context) ([]*T, error) {
 	filter := bson.M{}
 	ApplyUnDeletedFilter(filter)
-	cursor, err := r.collection.Find(ctx, filter)
-	if err != nil {
-		return nil, err
-	}
 	var results []*T
-	err = cursor.All(ctx, &results)
+	err := r.do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &results)
+	})
 	return results, err
 }
 
@@ -262,7 +338,9 @@ func (r *MongoRepository[T, K]) FindOne(ctx context.Context, filter map[string]a
 	// 自动排除软删除数据
 	ApplyUnDeletedFilter(filter)
 	var result T
-	err := r.collection.FindOne(ctx, bson.M(filter), opts...).Decode(&result)
+	err := r.do(ctx, func(ctx context.Context) error {
+		return r.collection.FindOne(ctx, bson.M(filter), opts...).Decode(&result)
+	})
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, nil
 	}
@@ -281,15 +359,15 @@ func (r *MongoRepository[T, K]) Find(ctx context.Context, filter map[string]any,
 	// 设置查询选项
 	opts := options.Find().SetSort(bsonSort)
 
-	// 执行查询
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-
-	// 解析结果
+	// 执行查询并解析结果
 	var results []*T
-	err = cursor.All(ctx, &results)
+	err := r.do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &results)
+	})
 	return results, err
 }
 
@@ -300,16 +378,56 @@ func (r *MongoRepository[T, K]) Paginate(
 	filter map[string]any,
 	sort map[string]int,
 ) ([]*T, int64, error) {
-	// 自动添加未删除条件
-	ApplyUnDeletedFilter(filter)
 	// 将 map[string]int 转换为 bson.D
 	var bsonSort bson.D
 	for key, order := range sort {
 		bsonSort = append(bsonSort, bson.E{Key: key, Value: order})
 	}
+	return r.paginate(ctx, page, limit, filter, bsonSort)
+}
+
+// PaginateRequest 按通用分页请求查询，排序字段保持 req.Sort 中的顺序
+func (r *MongoRepository[T, K]) PaginateRequest(
+	ctx context.Context,
+	req utils.PageRequest,
+	filter map[string]any,
+) (*utils.PageResponse[*T], error) {
+	req = req.Normalize()
+	var bsonSort bson.D
+	for _, f := range req.SortFields() {
+		order := 1
+		if f.Desc {
+			order = -1
+		}
+		bsonSort = append(bsonSort, bson.E{Key: f.Field, Value: order})
+	}
+	list, total, err := r.paginate(ctx, req.Page, req.PageSize, filter, bsonSort)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewPageResponse(req, list, total), nil
+}
+
+func (r *MongoRepository[T, K]) paginate(
+	ctx context.Context,
+	page int,
+	limit int,
+	filter map[string]any,
+	bsonSort bson.D,
+) ([]*T, int64, error) {
+	if filter == nil {
+		filter = map[string]any{}
+	}
+	// 自动添加未删除条件
+	ApplyUnDeletedFilter(filter)
 
 	// 统计总数
-	total, err := r.collection.CountDocuments(ctx, filter)
+	var total int64
+	err := r.do(ctx, func(ctx context.Context) error {
+		var err error
+		total, err = r.collection.CountDocuments(ctx, filter)
+		return err
+	})
 	if err != nil {
 		return nil, 0, err
 	}
@@ -323,15 +441,16 @@ func (r *MongoRepository[T, K]) Paginate(
 			SetLimit(int64(limit)).
 			SetSort(bsonSort)
 	}
-	// 执行查询
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// 解析结果
+	// 执行查询并解析结果
 	var results []*T
-	if err := cursor.All(ctx, &results); err != nil {
+	err = r.do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &results)
+	})
+	if err != nil {
 		return nil, 0, err
 	}
 
@@ -340,7 +459,13 @@ func (r *MongoRepository[T, K]) Paginate(
 
 func (r *MongoRepository[T, K]) Count(ctx context.Context, filter map[string]any) (int64, error) {
 	ApplyUnDeletedFilter(filter)
-	return r.collection.CountDocuments(ctx, bson.M(filter))
+	var total int64
+	err := r.do(ctx, func(ctx context.Context) error {
+		var err error
+		total, err = r.collection.CountDocuments(ctx, bson.M(filter))
+		return err
+	})
+	return total, err
 }
 
 func (r *MongoRepository[T, K]) GetMaxUpdatedAt(ctx context.Context) (int64, error) {
@@ -353,27 +478,26 @@ func (r *MongoRepository[T, K]) GetMaxUpdatedAt(ctx context.Context) (int64, err
 		},
 	}
 
-	cursor, err := r.collection.Aggregate(ctx, pipeline)
-	if err != nil {
-		return 0, err
-	}
-	defer cursor.Close(ctx)
-
 	var result struct {
 		Max time.Time `bson:"max"`
 	}
-
-	if cursor.Next(ctx) {
-		if err := cursor.Decode(&result); err != nil {
-			return 0, err
+	found := false
+	err := r.do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
 		}
-		return result.Max.Unix(), nil
-	}
-
-	if err := cursor.Err(); err != nil {
+		defer cursor.Close(ctx)
+		if cursor.Next(ctx) {
+			found = true
+			return cursor.Decode(&result)
+		}
+		return cursor.Err()
+	})
+	if err != nil || !found {
 		return 0, err
 	}
-	return 0, nil
+	return result.Max.Unix(), nil
 }
 
 func (r *MongoRepository[T, K]) WithTransaction(ctx context.Context, fn func(txCtx context.Context) error) error {
@@ -0,0 +1,84 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxFormMemory multipart 表单在内存中保留的上限，超出部分写入临时文件
+const maxFormMemory = 32 << 20
+
+// bindRequest 按请求方式绑定请求结构体：GET、HEAD、DELETE 取查询参数，
+// 表单请求取查询参数与表单字段，protobuf、msgpack 等按注册的 Codec 解码，其余按 JSON 请求体绑定；
+// 路径参数（如 /users/:id）最后绑定，覆盖请求体中的同名字段，全部绑定后统一校验
+func bindRequest(c *gin.Context, req any) error {
+	if err := decodeRequest(c, req); err != nil {
+		return err
+	}
+	return bindParams(c, req)
+}
+
+// bindParams 绑定路径参数并校验请求结构体
+func bindParams(c *gin.Context, req any) error {
+	if len(c.Params) > 0 {
+		params := make(map[string][]string, len(c.Params))
+		for _, p := range c.Params {
+			params[p.Key] = []string{p.Value}
+		}
+		if err := mapValues(req, params, "uri"); err != nil {
+			return err
+		}
+	}
+	return binding.Validator.ValidateStruct(req)
+}
+
+func decodeRequest(c *gin.Context, req any) error {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return mapValues(req, c.Request.URL.Query(), "form")
+	}
+	switch c.ContentType() {
+	case binding.MIMEPOSTForm:
+		if err := c.Request.ParseForm(); err != nil {
+			return err
+		}
+		return mapValues(req, c.Request.Form, "form")
+	case binding.MIMEMultipartPOSTForm:
+		if err := c.Request.ParseMultipartForm(maxFormMemory); err != nil {
+			return err
+		}
+		return mapValues(req, c.Request.Form, "form")
+	}
+	if c.Request.Body == nil {
+		return errors.New("invalid request")
+	}
+	if cd := requestCodec(c); cd != nil {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return err
+		}
+		return cd.Unmarshal(data, req)
+	}
+	// 与 ShouldBindJSON 一致，但校验推迟到路径参数绑定之后
+	dec := json.NewDecoder(c.Request.Body)
+	if binding.EnableDecoderUseNumber {
+		dec.UseNumber()
+	}
+	if binding.EnableDecoderDisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(req)
+}
+
+// mapValues 字段名优先取 tag 标签，没有时取 json 标签，与 JSON 绑定共用同一请求结构体（含 protobuf 生成的类型）
+func mapValues(req any, values map[string][]string, tag string) error {
+	if err := binding.MapFormWithTag(req, values, "json"); err != nil {
+		return err
+	}
+	return binding.MapFormWithTag(req, values, tag)
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type listRequest struct {
+	Keyword string   `json:"keyword"`
+	Page    int      `json:"page,omitempty" form:"p" binding:"min=1"`
+	Tags    []string `json:"tags"`
+}
+
+func mockListFunc(ctx context.Context, req *listRequest) (*listRequest, error) {
+	return req, nil
+}
+
+func serveBinding(method, target, contentType, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(method, "/list", GenericGRPCHandler(mockListFunc, DefaultContextInjector))
+
+	req, _ := http.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBindRequest_Query(t *testing.T) {
+	w := serveBinding(http.MethodGet, "/list?keyword=box&p=2&tags=a&tags=b", "", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp StandardResponse[*listRequest]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "box", resp.Data.Keyword)
+	assert.Equal(t, 2, resp.Data.Page)
+	assert.Equal(t, []string{"a", "b"}, resp.Data.Tags)
+}
+
+func TestBindRequest_QueryValidation(t *testing.T) {
+	w := serveBinding(http.MethodGet, "/list?keyword=box", "", "")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBindRequest_Form(t *testing.T) {
+	w := serveBinding(http.MethodPost, "/list?p=3", "application/x-www-form-urlencoded", "keyword=box")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp StandardResponse[*listRequest]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "box", resp.Data.Keyword)
+	assert.Equal(t, 3, resp.Data.Page)
+}
+
+type updateRequest struct {
+	ID   int64  `json:"id" uri:"id" binding:"required"`
+	Name string `json:"name"`
+}
+
+func mockUpdateFunc(ctx context.Context, req *updateRequest) (*updateRequest, error) {
+	return req, nil
+}
+
+func TestBindRequest_PathParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/users/:id", TypedHandler(mockUpdateFunc, DefaultContextInjector))
+
+	// 路径参数覆盖请求体中的同名字段
+	req, _ := http.NewRequest(http.MethodPut, "/users/42", strings.NewReader(`{"id":7,"name":"box"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp StandardResponse[*updateRequest]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(42), resp.Data.ID)
+	assert.Equal(t, "box", resp.Data.Name)
+
+	req, _ = http.NewRequest(http.MethodPut, "/users/abc", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,150 @@
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	MIMEProtobuf = "application/x-protobuf"
+	MIMEMsgpack  = "application/msgpack"
+
+	codecsKey = "router.codecs"
+)
+
+// Codec 请求体与响应的编解码器，按 Content-Type / Accept 协商，JSON 为默认编码不经过 Codec
+type Codec interface {
+	// ContentType 编码对应的 MIME 类型
+	ContentType() string
+	// Unmarshal 将请求体解码到请求结构体指针
+	Unmarshal(data []byte, v any) error
+	// Marshal 编码响应，resp.Data 为 handler 返回的原始值
+	Marshal(resp StandardResponse[any]) ([]byte, error)
+}
+
+// defaultCodecs 内置编解码器，Router.WithCodec 注册的同类型编解码器优先
+var defaultCodecs = []Codec{ProtobufCodec{}, MsgpackCodec{}}
+
+// ProtobufCodec application/x-protobuf：请求与成功响应直接为 proto 消息，不带 StandardResponse 外层；
+// 出错时响应体为 google.rpc.Status，code 为业务错误码，响应头 X-Code 同样携带错误码
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) ContentType() string { return MIMEProtobuf }
+
+func (ProtobufCodec) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("router: %T is not a proto message", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+func (ProtobufCodec) Marshal(resp StandardResponse[any]) ([]byte, error) {
+	if resp.Code != 0 {
+		return proto.Marshal(status.New(codes.Code(resp.Code), resp.Message).Proto())
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+	msg, ok := resp.Data.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("router: %T is not a proto message", resp.Data)
+	}
+	return proto.Marshal(msg)
+}
+
+// MsgpackCodec application/msgpack：结构与 JSON 响应一致，字段名取 codec 标签，没有时取 json 标签
+type MsgpackCodec struct{}
+
+// msgpackHandle 按新版规范区分 str 与 bin，解码到 any 时字符串不再是 []byte
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.RawToString = true
+	return h
+}()
+
+func (MsgpackCodec) ContentType() string { return MIMEMsgpack }
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	return codec.NewDecoderBytes(data, msgpackHandle).Decode(v)
+}
+
+func (MsgpackCodec) Marshal(resp StandardResponse[any]) ([]byte, error) {
+	data, err := normalizeResponseData(resp.Data)
+	if err != nil {
+		return nil, err
+	}
+	resp.Data = data
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, msgpackHandle).Encode(resp); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// codecMiddleware 将 Router 注册的编解码器放入请求上下文
+func codecMiddleware(codecs []Codec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(codecsKey, codecs)
+		c.Next()
+	}
+}
+
+func registeredCodecs(c *gin.Context) []Codec {
+	if v, ok := c.Get(codecsKey); ok {
+		return v.([]Codec)
+	}
+	return defaultCodecs
+}
+
+func lookupCodec(c *gin.Context, contentType string) Codec {
+	for _, cd := range registeredCodecs(c) {
+		if cd.ContentType() == contentType {
+			return cd
+		}
+	}
+	return nil
+}
+
+// requestCodec 按 Content-Type 选择请求体的编解码器，JSON 与表单返回 nil
+func requestCodec(c *gin.Context) Codec {
+	return lookupCodec(c, c.ContentType())
+}
+
+// responseCodec 按 Accept 选择响应编码，未指定时与请求体编码一致，返回 nil 时使用 JSON
+func responseCodec(c *gin.Context) Codec {
+	accept := c.GetHeader("Accept")
+	if accept == "" || accept == "*/*" {
+		return requestCodec(c)
+	}
+	offered := []string{binding.MIMEJSON}
+	for _, cd := range registeredCodecs(c) {
+		offered = append(offered, cd.ContentType())
+	}
+	return lookupCodec(c, c.NegotiateFormat(offered...))
+}
+
+// writeResponse 按协商的编码写出响应
+func writeResponse(c *gin.Context, code int, resp StandardResponse[any]) {
+	cd := responseCodec(c)
+	if cd == nil {
+		c.JSON(code, resp)
+		return
+	}
+	body, err := cd.Marshal(resp)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: "marshal response failed: " + err.Error()})
+		return
+	}
+	c.Header("X-Code", strconv.FormatInt(resp.Code, 10))
+	c.Data(code, cd.ContentType(), body)
+}
@@ -0,0 +1,79 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/code-sigs/go-box/pkg/rpcerror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func mockProtoFunc(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	if req.GetValue() == "" {
+		return nil, rpcerror.WrapCode(5100, "value is required")
+	}
+	return wrapperspb.String("Hello, " + req.GetValue()), nil
+}
+
+func serveCodec(contentType, accept string, body []byte) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/codec", TypedHandler(mockProtoFunc, DefaultContextInjector))
+
+	req, _ := http.NewRequest(http.MethodPost, "/codec", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCodec_Protobuf(t *testing.T) {
+	body, _ := proto.Marshal(wrapperspb.String("GoBox"))
+	w := serveCodec(MIMEProtobuf, "", body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, MIMEProtobuf, w.Header().Get("Content-Type"))
+	var resp wrapperspb.StringValue
+	assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Hello, GoBox", resp.GetValue())
+
+	body, _ = proto.Marshal(wrapperspb.String(""))
+	w = serveCodec(MIMEProtobuf, "", body)
+	assert.Equal(t, "5100", w.Header().Get("X-Code"))
+	var st spb.Status
+	assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), &st))
+	assert.Equal(t, int32(5100), st.GetCode())
+}
+
+func TestCodec_Msgpack(t *testing.T) {
+	var body []byte
+	_ = codec.NewEncoderBytes(&body, msgpackHandle).Encode(map[string]any{"value": "GoBox"})
+	w := serveCodec(MIMEMsgpack, "", body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, MIMEMsgpack, w.Header().Get("Content-Type"))
+	var resp StandardResponse[map[string]any]
+	assert.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), msgpackHandle).Decode(&resp))
+	assert.Equal(t, "Hello, GoBox", resp.Data["value"])
+}
+
+func TestCodec_AcceptJSON(t *testing.T) {
+	body, _ := proto.Marshal(wrapperspb.String("GoBox"))
+	w := serveCodec(MIMEProtobuf, "application/json", body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.Contains(t, w.Body.String(), "Hello, GoBox")
+}
@@ -2,12 +2,19 @@ package router
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"reflect"
 	"strconv"
 
 	"github.com/code-sigs/go-box/pkg/rpcerror"
+	"github.com/code-sigs/go-box/pkg/trace"
+	"github.com/code-sigs/go-box/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
@@ -19,16 +26,32 @@ type StandardResponse[T any] struct {
 	Data    T      `json:"data,omitempty"`
 }
 
+// 请求体绑定时支持 binding:"mobile"、binding:"idcard" 等自定义校验标签
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		_ = utils.RegisterValidations(v)
+	}
+}
+
 // ContextInjector 定义上下文注入函数类型
 type ContextInjector func(c *gin.Context, ctx context.Context) context.Context
 
-// DefaultContextInjector 默认的上下文注入函数
+// DefaultContextInjector 默认的上下文注入函数，从请求头恢复 W3C traceparent / tracestate 和 X-Trace-ID
 func DefaultContextInjector(c *gin.Context, ctx context.Context) context.Context {
 	traceID := c.GetHeader("X-Trace-ID")
 	if traceID != "" {
 		ctx = context.WithValue(ctx, "trace_id", traceID)
 	}
-	return ctx
+	return extractTrace(c, ctx)
+}
+
+// extractTrace 未经过 TraceMiddleware 时从请求头恢复链路，已有 span 时保持不变；
+// 用户、租户 baggage 不信任客户端，只由 requestContext 从鉴权中间件写入的值设置
+func extractTrace(c *gin.Context, ctx context.Context) context.Context {
+	if oteltrace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	return trace.ExtractUntrusted(ctx, propagation.HeaderCarrier(c.Request.Header))
 }
 
 // GenericGRPCHandler 适配任意签名的 gRPC 方法
@@ -38,7 +61,7 @@ func GenericGRPCHandler(grpcFunc any, ctxInjector ContextInjector) gin.HandlerFu
 
 	return func(c *gin.Context) {
 		if fnType.Kind() != reflect.Func || fnType.NumIn() < 2 || fnType.NumOut() != 2 {
-			c.JSON(http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: "invalid grpcFunc signature"})
+			writeResponse(c, http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: "invalid grpcFunc signature"})
 			return
 		}
 
@@ -50,8 +73,8 @@ func GenericGRPCHandler(grpcFunc any, ctxInjector ContextInjector) gin.HandlerFu
 			reqPtr = reflect.New(reqType)
 		}
 
-		if err := c.ShouldBindJSON(reqPtr.Interface()); err != nil {
-			c.JSON(http.StatusBadRequest, StandardResponse[any]{Code: 400, Message: "Invalid request: " + err.Error()})
+		if err := bindRequest(c, reqPtr.Interface()); err != nil {
+			writeResponse(c, http.StatusBadRequest, StandardResponse[any]{Code: 400, Message: "Invalid request: " + err.Error()})
 			return
 		}
 
@@ -62,56 +85,99 @@ func GenericGRPCHandler(grpcFunc any, ctxInjector ContextInjector) gin.HandlerFu
 			reqVal = reqPtr.Elem()
 		}
 
-		ctx := context.WithValue(c.Request.Context(), "clientip", c.ClientIP())
-		userID := c.Value("user-id")
-		platformID := c.Value("platform-id")
-		tenantID := c.Value("tenant-id")
-		natType := c.Value("nat-type")
-
-		if userID != nil {
-			ctx = context.WithValue(ctx, "user-id", userID)
-		}
-		if platformID != nil {
-			ctx = context.WithValue(ctx, "platform-id", platformID)
-		}
-		if tenantID != nil {
-			ctx = context.WithValue(ctx, "tenant-id", tenantID)
-		}
-		if natType != nil {
-			ctx = context.WithValue(ctx, "nat-type", natType)
-		}
-		out := fnVal.Call([]reflect.Value{reflect.ValueOf(ctx), reqVal})
+		out := fnVal.Call([]reflect.Value{reflect.ValueOf(requestContext(c, ctxInjector)), reqVal})
 
 		if len(out) != 2 {
-			c.JSON(http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: "grpcFunc must return two values"})
+			writeResponse(c, http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: "grpcFunc must return two values"})
 			return
 		}
 
 		if !out[1].IsNil() {
 			if err, ok := out[1].Interface().(error); ok {
-				if rpcErr := rpcerror.UnWrap(err); rpcErr != nil {
-					c.JSON(http.StatusOK, StandardResponse[any]{
-						Code:    rpcErr.Code,
-						Message: rpcErr.Message,
-						Details: rpcErr.Details,
-						Data:    nil,
-					})
-					return
-				}
-				c.JSON(http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: err.Error(), Data: nil})
+				writeError(c, err)
 			} else {
-				c.JSON(http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: "unknown error", Data: nil})
+				writeResponse(c, http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: "unknown error", Data: nil})
 			}
 			return
 		}
+		writeData(c, out[0].Interface())
+	}
+}
 
-		data, err := normalizeResponseData(out[0].Interface())
+// TypedHandler 与 GenericGRPCHandler 行为一致，但在编译期确定请求类型，不使用反射，供 Bind 与 boxgen 生成的代码使用
+func TypedHandler[Req any, Resp any](fn func(context.Context, *Req) (Resp, error), ctxInjector ContextInjector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := new(Req)
+		if err := bindRequest(c, req); err != nil {
+			writeResponse(c, http.StatusBadRequest, StandardResponse[any]{Code: 400, Message: "Invalid request: " + err.Error()})
+			return
+		}
+		resp, err := fn(requestContext(c, ctxInjector), req)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: "marshal response failed: " + err.Error(), Data: nil})
+			writeError(c, err)
 			return
 		}
-		c.JSON(http.StatusOK, StandardResponse[any]{Code: 0, Message: "ok", Data: data})
+		writeData(c, resp)
+	}
+}
+
+// requestContext 构造传给 gRPC 方法的 ctx：客户端 IP、注入器写入的值，以及用户、租户等身份信息
+func requestContext(c *gin.Context, ctxInjector ContextInjector) context.Context {
+	ctx := context.WithValue(c.Request.Context(), "clientip", c.ClientIP())
+	if ctxInjector != nil {
+		ctx = ctxInjector(c, ctx)
+	}
+	userID := c.Value("user-id")
+	platformID := c.Value("platform-id")
+	tenantID := c.Value("tenant-id")
+	natType := c.Value("nat-type")
+
+	// 租户和用户通过 baggage 端到端透传，下游用 trace.GetBaggage 读取
+	if userID != nil {
+		ctx = trace.WithBaggage(ctx, trace.BaggageUserID, fmt.Sprint(userID))
+	}
+	if platformID != nil {
+		ctx = context.WithValue(ctx, "platform-id", platformID)
+	}
+	if tenantID != nil {
+		ctx = trace.WithBaggage(ctx, trace.BaggageTenantID, fmt.Sprint(tenantID))
+	}
+	if natType != nil {
+		ctx = context.WithValue(ctx, "nat-type", natType)
+	}
+	return ctx
+}
+
+// writeError 业务错误按错误码映射 HTTP 状态并本地化消息，其余错误返回 500
+func writeError(c *gin.Context, err error) {
+	code, resp := errorResponse(c, err)
+	writeResponse(c, code, resp)
+}
+
+func errorResponse(c *gin.Context, err error) (int, StandardResponse[any]) {
+	if rpcErr := rpcerror.UnWrap(rpcerror.FromError(err)); rpcErr != nil {
+		langs := rpcerror.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+		return rpcerror.HTTPStatus(rpcErr.Code), StandardResponse[any]{
+			Code:    rpcErr.Code,
+			Message: rpcerror.Localize(rpcErr.Code, rpcErr.Message, langs...),
+			Details: rpcErr.Details,
+			Data:    nil,
+		}
+	}
+	return http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: err.Error(), Data: nil}
+}
+
+func writeData(c *gin.Context, resp any) {
+	if responseCodec(c) != nil {
+		writeResponse(c, http.StatusOK, StandardResponse[any]{Code: 0, Message: "ok", Data: resp})
+		return
+	}
+	data, err := normalizeResponseData(resp)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse[any]{Code: 500, Message: "marshal response failed: " + err.Error(), Data: nil})
+		return
 	}
+	c.JSON(http.StatusOK, StandardResponse[any]{Code: 0, Message: "ok", Data: data})
 }
 
 func normalizeResponseData(data any) (any, error) {
@@ -0,0 +1,32 @@
+package router
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware 按方法、路由模板和状态码统计请求数与耗时，注册到 metrics.Default()；
+// 未匹配的路由统一记为 "unmatched"，避免按原始路径产生大量时间序列
+func MetricsMiddleware() gin.HandlerFunc {
+	reg := metrics.Default()
+	requests := reg.Counter("http_server_requests_total", "HTTP requests handled, partitioned by route and status code.", "method", "route", "status")
+	duration := reg.Histogram("http_server_request_duration_seconds", "HTTP request latency.", nil, "method", "route")
+	inflight := reg.Gauge("http_server_requests_in_flight", "HTTP requests currently being handled.")
+	return func(c *gin.Context) {
+		start := time.Now()
+		inflight.WithLabelValues().Inc()
+		defer inflight.WithLabelValues().Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		requests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		duration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
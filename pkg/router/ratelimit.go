@@ -0,0 +1,78 @@
+package router
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// KeyFunc 从请求中提取限流 key
+type KeyFunc func(c *gin.Context) string
+
+// KeyByIP 按客户端 IP 限流
+func KeyByIP() KeyFunc {
+	return func(c *gin.Context) string { return c.ClientIP() }
+}
+
+// KeyByHeader 按请求头限流，如 API Key；请求头为空时退回客户端 IP
+func KeyByHeader(name string) KeyFunc {
+	return func(c *gin.Context) string {
+		if v := c.GetHeader(name); v != "" {
+			return name + ":" + v
+		}
+		return c.ClientIP()
+	}
+}
+
+// KeyByUser 按鉴权中间件（如 auth.GinMiddleware）写入 gin.Context 的 user-id 限流，匿名请求退回客户端 IP
+func KeyByUser() KeyFunc {
+	return func(c *gin.Context) string {
+		if v, ok := c.Get("user-id"); ok && v != nil {
+			return "user:" + fmt.Sprint(v)
+		}
+		return c.ClientIP()
+	}
+}
+
+// RateLimitMiddleware 按 key 限流，超限返回 429 与 Retry-After 头；
+// keyFunc 为 nil 时按客户端 IP 限流，限流器出错时放行
+func RateLimitMiddleware(l ratelimit.Limiter, keyFunc KeyFunc) gin.HandlerFunc {
+	if keyFunc == nil {
+		keyFunc = KeyByIP()
+	}
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		res, err := l.Allow(ctx, keyFunc(c))
+		if err != nil {
+			logger.Warnw(ctx, "rate limiter failed", "path", c.Request.URL.Path, "error", err.Error())
+			c.Next()
+			return
+		}
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+			writeResponse(c, http.StatusTooManyRequests, StandardResponse[any]{Code: http.StatusTooManyRequests, Message: "too many requests"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// WithRateLimit 为单个路由限流，如 r.POST("/sms/send", srv.Send, router.WithRateLimit(l, router.KeyByUser()))；
+// 配额按路由独立计算，多个路由可共用同一个 Limiter，keyFunc 为 nil 时按客户端 IP
+func WithRateLimit(l ratelimit.Limiter, keyFunc KeyFunc) RouteOption {
+	if keyFunc == nil {
+		keyFunc = KeyByIP()
+	}
+	return func(e *routeEntry) {
+		e.middlewares = append(e.middlewares, RateLimitMiddleware(l, func(c *gin.Context) string {
+			return c.Request.Method + " " + c.FullPath() + ":" + keyFunc(c)
+		}))
+	}
+}
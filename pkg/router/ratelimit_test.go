@@ -0,0 +1,43 @@
+package router
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := ratelimit.NewLocalTokenBucket(1, time.Minute, 1, 100)
+	r := New()
+	r.POST("/limited", mockGRPCFunc, WithRateLimit(limiter, KeyByHeader("X-API-Key")))
+	r.POST("/other", mockGRPCFunc, WithRateLimit(limiter, KeyByHeader("X-API-Key")))
+	r.POST("/free", mockGRPCFunc)
+	engine := r.newEngine()
+
+	call := func(path, key string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{"name":"GoBox"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, call("/limited", "a").Code)
+	w := call("/limited", "a")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	// 不同 key、不同路由的配额相互独立
+	assert.Equal(t, http.StatusOK, call("/limited", "b").Code)
+	assert.Equal(t, http.StatusOK, call("/other", "a").Code)
+	assert.Equal(t, http.StatusOK, call("/free", "a").Code)
+	assert.Equal(t, http.StatusOK, call("/free", "a").Code)
+}
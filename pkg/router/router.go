@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,14 +13,41 @@ import (
 	"time"
 
 	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/metrics"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
 type routeEntry struct {
-	path    string
-	handler gin.HandlerFunc
+	method      string
+	path        string
+	middlewares []gin.HandlerFunc // 仅作用于该路由，在 handler 之前执行
+	handler     gin.HandlerFunc
+}
+
+// RouteOption 注册路由时的可选配置，如 WithRateLimit
+type RouteOption func(e *routeEntry)
+
+func newRouteEntry(method, path string, handler gin.HandlerFunc, opts []RouteOption) routeEntry {
+	e := routeEntry{method: method, path: path, handler: handler}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return e
+}
+
+// WithMiddleware 为单个路由添加中间件，在全局与分组中间件之后执行，
+// 如 r.POST("/user/update", srv.UpdateUser, router.WithMiddleware(auth))
+func WithMiddleware(mw ...gin.HandlerFunc) RouteOption {
+	return func(e *routeEntry) {
+		e.middlewares = append(e.middlewares, mw...)
+	}
+}
+
+func (e routeEntry) handlers() []gin.HandlerFunc {
+	return append(append([]gin.HandlerFunc{}, e.middlewares...), e.handler)
 }
 
 type Router struct {
@@ -27,12 +55,16 @@ type Router struct {
 	proxyHeader []string
 	middlewares []gin.HandlerFunc // 新增：用户自定义中间件
 	group       []*RouterGroup
+	onShutdown  []func(ctx context.Context) error
+	metricsPath string
+	codecs      []Codec
 }
 
 type RouterGroup struct {
 	name     string
 	handlers []gin.HandlerFunc
 	routes   []routeEntry
+	groups   []*RouterGroup // 子分组
 	injector func(c *gin.Context, ctx context.Context) context.Context
 }
 
@@ -55,10 +87,32 @@ func (r *Router) Use(mw ...gin.HandlerFunc) *Router {
 	return r
 }
 
+// OnShutdown 注册退出时执行的清理函数，在 HTTP 服务停止后按注册顺序调用，
+// 共享关闭超时，如 cron.Scheduler.Stop
+func (r *Router) OnShutdown(fn ...func(ctx context.Context) error) *Router {
+	r.onShutdown = append(r.onShutdown, fn...)
+	return r
+}
+
+// WithMetrics 启用 HTTP 指标并在 path（为空时 /metrics）暴露 metrics.Default() 中全部组件的指标
+func (r *Router) WithMetrics(path string) *Router {
+	if path == "" {
+		path = "/metrics"
+	}
+	r.metricsPath = path
+	return r
+}
+
+// WithCodec 注册请求与响应的编解码器，与内置的 protobuf、msgpack 同 ContentType 时覆盖内置实现
+func (r *Router) WithCodec(codecs ...Codec) *Router {
+	r.codecs = append(r.codecs, codecs...)
+	return r
+}
+
 func (r *Router) injector(c *gin.Context, ctx context.Context) context.Context {
+	ctx = extractTrace(c, ctx)
 	md := metadata.New(nil)
 	md.Append("clientip", c.ClientIP())
-	logger.Infof(c, "injector clientip: %s", c.ClientIP())
 	if len(r.proxyHeader) == 0 {
 		for key, values := range c.Request.Header {
 			for _, value := range values {
@@ -83,6 +137,7 @@ func (r *Router) injector(c *gin.Context, ctx context.Context) context.Context {
 	return ctx
 }
 
+// Group 创建路由分组，handlers 仅作用于分组内的路由，如只对需要登录的接口鉴权
 func (r *Router) Group(name string, handlers ...gin.HandlerFunc) *RouterGroup {
 	group := &RouterGroup{
 		name:     name,
@@ -95,15 +150,52 @@ func (r *Router) Group(name string, handlers ...gin.HandlerFunc) *RouterGroup {
 }
 
 // Register 注册一个 gRPC 方法与其绑定路径
-func (r *Router) POST(path string, grpcFunc any) {
-	h := GenericGRPCHandler(grpcFunc, r.injector)
-	r.routes = append(r.routes, routeEntry{
-		path:    path,
-		handler: h,
-	})
+func (r *Router) POST(path string, grpcFunc any, opts ...RouteOption) {
+	r.Handle(http.MethodPost, path, grpcFunc, opts...)
+}
+
+// GET 注册只读的 gRPC 方法，请求结构体从查询参数绑定，字段名取 form 标签，没有时取 json 标签
+func (r *Router) GET(path string, grpcFunc any, opts ...RouteOption) {
+	r.Handle(http.MethodGet, path, grpcFunc, opts...)
+}
+
+// Handle 以任意 HTTP 方法注册 gRPC 方法，path 可包含 /users/:id 形式的路径参数，
+// 按 uri 标签（没有时取 json 标签）绑定到请求结构体
+func (r *Router) Handle(method, path string, grpcFunc any, opts ...RouteOption) {
+	r.routes = append(r.routes, newRouteEntry(method, path, GenericGRPCHandler(grpcFunc, r.injector), opts))
+}
+
+// Group 创建子分组，路径与中间件在父分组之上叠加，如按 API 版本划分：
+// api := r.Group("/api", auth); v1 := api.Group("/v1"); v2 := api.Group("/v2", v2Only)
+func (r *RouterGroup) Group(name string, handlers ...gin.HandlerFunc) *RouterGroup {
+	group := &RouterGroup{
+		name:     name,
+		handlers: handlers,
+		routes:   []routeEntry{},
+		injector: r.injector,
+	}
+	r.groups = append(r.groups, group)
+	return group
+}
+
+// register 将分组及其子分组的路由注册到 gin
+func (r *RouterGroup) register(parent gin.IRouter) {
+	g := parent.Group(r.name, r.handlers...)
+	for _, route := range r.routes {
+		g.Handle(route.method, route.path, route.handlers()...)
+	}
+	for _, child := range r.groups {
+		child.register(g)
+	}
+}
+
+// Use 添加分组中间件，仅作用于该分组的路由
+func (r *RouterGroup) Use(mw ...gin.HandlerFunc) *RouterGroup {
+	r.handlers = append(r.handlers, mw...)
+	return r
 }
 
-func (r *RouterGroup) RegisterRPCClient(path string, grpcFunc any) {
+func (r *RouterGroup) RegisterRPCClient(path string, grpcFunc any, opts ...RouteOption) {
 	h := GenericGRPCHandler(func(ctx context.Context, req any) (any, error) {
 		fnVal := reflect.ValueOf(grpcFunc)
 		fnType := fnVal.Type()
@@ -146,25 +238,69 @@ func (r *RouterGroup) RegisterRPCClient(path string, grpcFunc any) {
 		return resp, err
 	}, r.injector)
 
-	r.routes = append(r.routes, routeEntry{
-		path:    path,
-		handler: h,
-	})
+	r.routes = append(r.routes, newRouteEntry(http.MethodPost, path, h, opts))
 }
 
-func (r *RouterGroup) POST(path string, grpcFunc any) {
-	h := GenericGRPCHandler(grpcFunc, r.injector)
-	r.routes = append(r.routes, routeEntry{
-		path:    path,
-		handler: h,
-	})
+func (r *RouterGroup) POST(path string, grpcFunc any, opts ...RouteOption) {
+	r.Handle(http.MethodPost, path, grpcFunc, opts...)
 }
 
-// Run 启动 Box 服务，支持用户自定义中间件，并实现优雅关闭
-func (r *Router) Run(addr string, beforeRun func(g *gin.Engine), shutdown func(), isDebug bool) error {
-	if !isDebug {
-		gin.SetMode(gin.ReleaseMode)
-	}
+func (r *RouterGroup) GET(path string, grpcFunc any, opts ...RouteOption) {
+	r.Handle(http.MethodGet, path, grpcFunc, opts...)
+}
+
+func (r *RouterGroup) Handle(method, path string, grpcFunc any, opts ...RouteOption) {
+	r.routes = append(r.routes, newRouteEntry(method, path, GenericGRPCHandler(grpcFunc, r.injector), opts))
+}
+
+// Routes Router 与 RouterGroup 的公共接口，供 Bind 与 boxgen 生成的代码注册路由
+type Routes interface {
+	addRoute(method, path string, build func(ContextInjector) gin.HandlerFunc, opts []RouteOption)
+}
+
+func (r *Router) addRoute(method, path string, build func(ContextInjector) gin.HandlerFunc, opts []RouteOption) {
+	r.routes = append(r.routes, newRouteEntry(method, path, build(r.injector), opts))
+}
+
+func (r *RouterGroup) addRoute(method, path string, build func(ContextInjector) gin.HandlerFunc, opts []RouteOption) {
+	r.routes = append(r.routes, newRouteEntry(method, path, build(r.injector), opts))
+}
+
+// Bind 以 TypedHandler 注册 POST 路由，与 POST 相比不使用反射，签名错误在编译期发现，
+// 如 router.Bind(r, "/user/get", srv.GetUser)
+func Bind[Req any, Resp any](r Routes, path string, fn func(context.Context, *Req) (Resp, error), opts ...RouteOption) {
+	BindMethod(r, http.MethodPost, path, fn, opts...)
+}
+
+// BindGet 与 Bind 相同但注册为 GET 路由，请求结构体从查询参数绑定
+func BindGet[Req any, Resp any](r Routes, path string, fn func(context.Context, *Req) (Resp, error), opts ...RouteOption) {
+	BindMethod(r, http.MethodGet, path, fn, opts...)
+}
+
+// BindMethod 以任意 HTTP 方法注册，用于 RESTful 路由，如 router.BindMethod(r, http.MethodPut, "/users/:id", srv.UpdateUser)
+func BindMethod[Req any, Resp any](r Routes, method, path string, fn func(context.Context, *Req) (Resp, error), opts ...RouteOption) {
+	r.addRoute(method, path, func(injector ContextInjector) gin.HandlerFunc {
+		return TypedHandler(fn, injector)
+	}, opts)
+}
+
+// BindStream 以 StreamHandler 注册服务端流式方法，结果按 NDJSON 逐条写出，适合列表导出等长耗时接口，
+// 如 router.BindStream(r, "/user/export", srv.ExportUsers)
+func BindStream[Req any, Resp any](r Routes, path string, fn func(*Req, grpc.ServerStreamingServer[Resp]) error, opts ...RouteOption) {
+	r.addRoute(http.MethodPost, path, func(injector ContextInjector) gin.HandlerFunc {
+		return StreamHandler(fn, injector)
+	}, opts)
+}
+
+// BindClientStream 将 gRPC 客户端的服务端流方法注册为 NDJSON 路由，如 router.BindClientStream(r, "/user/export", client.ExportUsers)
+func BindClientStream[Req any, Resp any](r Routes, path string, fn func(context.Context, *Req, ...grpc.CallOption) (grpc.ServerStreamingClient[Resp], error), opts ...RouteOption) {
+	r.addRoute(http.MethodPost, path, func(injector ContextInjector) gin.HandlerFunc {
+		return ClientStreamHandler(fn, injector)
+	}, opts)
+}
+
+// newEngine 创建 gin 引擎，安装全局中间件并注册全部路由
+func (r *Router) newEngine() *gin.Engine {
 	engine := gin.New()
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // 如果 AllowCredentials: true，请指定域名
@@ -174,30 +310,64 @@ func (r *Router) Run(addr string, beforeRun func(g *gin.Engine), shutdown func()
 		AllowCredentials: false,         // 为 true 时，不允许 * 出现在 AllowOrigins、AllowHeaders 中
 		MaxAge:           12 * time.Hour,
 	}))
-	engine.Use(gin.Recovery(), gin.Logger())
+	engine.Use(gin.Recovery(), TraceMiddleware(), logger.GinLogger())
+	if r.metricsPath != "" {
+		engine.Use(MetricsMiddleware())
+		engine.GET(r.metricsPath, gin.WrapH(metrics.Default().Handler()))
+	}
+	if len(r.codecs) > 0 {
+		engine.Use(codecMiddleware(append(append([]Codec{}, r.codecs...), defaultCodecs...)))
+	}
 	for _, mw := range r.middlewares {
 		engine.Use(mw)
 	}
 	for _, route := range r.routes {
-		engine.POST(route.path, route.handler)
+		engine.Handle(route.method, route.path, route.handlers()...)
 	}
 	for _, group := range r.group {
-		groupEngine := engine.Group(group.name, group.handlers...)
-		for _, route := range group.routes {
-			groupEngine.POST(route.path, route.handler)
-		}
+		group.register(engine)
+	}
+	return engine
+}
+
+// Run 启动 Box 服务，支持用户自定义中间件，并实现优雅关闭；
+// opts 中配置了 WithAutocert 或带证书的 WithTLSConfig 时以 HTTPS 提供服务
+func (r *Router) Run(addr string, beforeRun func(g *gin.Engine), shutdown func(), isDebug bool, opts ...ServerOption) error {
+	return r.serve(addr, "", "", beforeRun, shutdown, isDebug, opts)
+}
+
+// RunTLS 与 Run 相同但以 HTTPS 提供服务，证书从 certFile、keyFile 加载；
+// 使用 WithAutocert 或带证书的 WithTLSConfig 时 certFile、keyFile 可为空
+func (r *Router) RunTLS(addr, certFile, keyFile string, beforeRun func(g *gin.Engine), shutdown func(), isDebug bool, opts ...ServerOption) error {
+	if certFile == "" && keyFile == "" && !hasCertificate(applyServerOptions(opts).serverTLSConfig()) {
+		return errors.New("router: RunTLS requires certFile and keyFile, WithAutocert or WithTLSConfig with certificates")
+	}
+	return r.serve(addr, certFile, keyFile, beforeRun, shutdown, isDebug, opts)
+}
+
+func (r *Router) serve(addr, certFile, keyFile string, beforeRun func(g *gin.Engine), shutdown func(), isDebug bool, opts []ServerOption) error {
+	if !isDebug {
+		gin.SetMode(gin.ReleaseMode)
 	}
+	engine := r.newEngine()
 	if beforeRun != nil {
 		beforeRun(engine)
 	}
-	srv := &http.Server{
-		Addr:    addr,
-		Handler: engine,
+	srv := newServer(addr, engine, opts)
+	useTLS := certFile != "" || hasCertificate(srv.TLSConfig)
+	if !useTLS && srv.TLSConfig != nil {
+		return errors.New("router: WithTLSConfig requires Certificates or GetCertificate, or use RunTLS with certFile and keyFile")
 	}
 
 	// 启动服务
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if useTLS {
+			err = srv.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			panic("listen: " + err.Error())
 		}
 	}()
@@ -211,5 +381,13 @@ func (r *Router) Run(addr string, beforeRun func(g *gin.Engine), shutdown func()
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	return srv.Shutdown(ctx)
+	err := srv.Shutdown(ctx)
+	for _, fn := range r.onShutdown {
+		if hookErr := fn(ctx); hookErr != nil {
+			logger.Errorw(ctx, "shutdown hook failed", "error", hookErr.Error())
+		}
+	}
+	// 服务停止后刷新异步日志
+	_ = logger.Close()
+	return err
 }
@@ -8,7 +8,9 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/code-sigs/go-box/pkg/errs"
 	"github.com/code-sigs/go-box/pkg/rpcerror"
+	"github.com/code-sigs/go-box/pkg/trace"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -48,7 +50,7 @@ func TestGenericGRPCHandler_Success(t *testing.T) {
 	var resp StandardResponse[*TestResponse]
 	err := json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.Equal(t, int32(0), resp.Code)
+	assert.Equal(t, int64(0), resp.Code)
 	assert.Equal(t, "ok", resp.Message)
 	assert.Equal(t, "Hello, GoBox", resp.Data.Greet)
 }
@@ -70,7 +72,7 @@ func TestGenericGRPCHandler_BadRequest(t *testing.T) {
 	var resp StandardResponse[any]
 	err := json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.Equal(t, int32(400), resp.Code)
+	assert.Equal(t, int64(400), resp.Code)
 }
 
 func TestGenericGRPCHandler_Error(t *testing.T) {
@@ -91,6 +93,262 @@ func TestGenericGRPCHandler_Error(t *testing.T) {
 	var resp StandardResponse[any]
 	err := json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.Equal(t, int32(5100), resp.Code)
+	assert.Equal(t, int64(5100), resp.Code)
 	assert.Contains(t, resp.Message, "mock grpc error")
 }
+
+func TestGenericGRPCHandler_HTTPStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rpcerror.RegisterHTTPStatusRange(400100, 400199, http.StatusUnauthorized)
+	rpcerror.RegisterHTTPStatus(400104, http.StatusNotFound)
+
+	cases := []struct {
+		code   int
+		status int
+	}{
+		{400101, http.StatusUnauthorized},
+		{400104, http.StatusNotFound},
+		{400200, http.StatusOK},
+	}
+	for _, tc := range cases {
+		fn := func(ctx context.Context, req *TestRequest) (*TestResponse, error) {
+			return nil, rpcerror.WrapCode(tc.code, "mapped error")
+		}
+		router := gin.New()
+		router.POST("/test", GenericGRPCHandler(fn, DefaultContextInjector))
+
+		req, _ := http.NewRequest("POST", "/test", bytes.NewBufferString(`{"name":"GoBox"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.status, w.Code, "code %d", tc.code)
+		var resp StandardResponse[any]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(tc.code), resp.Code)
+	}
+}
+
+func TestGenericGRPCHandler_LocalizedMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rpcerror.RegisterMessages("en", map[int64]string{5200: "record not found ({code})"})
+	rpcerror.RegisterMessages("zh-CN", map[int64]string{5200: "记录不存在"})
+
+	fn := func(ctx context.Context, req *TestRequest) (*TestResponse, error) {
+		return nil, rpcerror.WrapCode(5200, "not found")
+	}
+	router := gin.New()
+	router.POST("/test", GenericGRPCHandler(fn, DefaultContextInjector))
+
+	cases := map[string]string{
+		"en-US,en;q=0.9":    "record not found (5200)",
+		"fr;q=0.8,zh;q=0.5": "记录不存在",
+		"":                  "记录不存在",
+	}
+	for header, want := range cases {
+		req, _ := http.NewRequest("POST", "/test", bytes.NewBufferString(`{"name":"GoBox"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", header)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp StandardResponse[any]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, want, resp.Message, "Accept-Language %q", header)
+	}
+}
+
+func TestGenericGRPCHandler_WrapErrorCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fn := func(ctx context.Context, req *TestRequest) (*TestResponse, error) {
+		return nil, errs.WithCode(errs.New("user missing"), errs.ErrorNotFound)
+	}
+	router := gin.New()
+	router.POST("/test", GenericGRPCHandler(fn, DefaultContextInjector))
+
+	req, _ := http.NewRequest("POST", "/test", bytes.NewBufferString(`{"name":"GoBox"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp StandardResponse[any]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(errs.ErrorNotFound), resp.Code)
+	assert.Equal(t, "user missing", resp.Message)
+	assert.Contains(t, resp.Details, "router_test.go")
+}
+
+func TestGenericGRPCHandler_Baggage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var userID, tenantID string
+	fn := func(ctx context.Context, req *TestRequest) (*TestResponse, error) {
+		userID = trace.GetBaggage(ctx, trace.BaggageUserID)
+		tenantID = trace.GetBaggage(ctx, trace.BaggageTenantID)
+		return &TestResponse{}, nil
+	}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user-id", int64(42))
+		c.Set("tenant-id", "acme")
+	})
+	router.POST("/test", GenericGRPCHandler(fn, DefaultContextInjector))
+
+	req, _ := http.NewRequest("POST", "/test", bytes.NewBufferString(`{"name":"GoBox"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "42", userID)
+	assert.Equal(t, "acme", tenantID)
+}
+
+func TestGenericGRPCHandler_UntrustedBaggage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var userID, tenantID, region string
+	fn := func(ctx context.Context, req *TestRequest) (*TestResponse, error) {
+		userID = trace.GetBaggage(ctx, trace.BaggageUserID)
+		tenantID = trace.GetBaggage(ctx, trace.BaggageTenantID)
+		region = trace.GetBaggage(ctx, "region")
+		return &TestResponse{}, nil
+	}
+	router := gin.New()
+	router.Use(TraceMiddleware())
+	router.POST("/test", GenericGRPCHandler(fn, DefaultContextInjector))
+	router.POST("/auth", func(c *gin.Context) {
+		c.Set("user-id", "42")
+		c.Next()
+	}, GenericGRPCHandler(fn, DefaultContextInjector))
+
+	call := func(path string) {
+		req, _ := http.NewRequest("POST", path, bytes.NewBufferString(`{"name":"GoBox"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("baggage", "user-id=1,tenant-id=other,region=eu")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// 客户端传入的用户、租户被丢弃，其余 baggage 照常透传
+	call("/test")
+	assert.Empty(t, userID)
+	assert.Empty(t, tenantID)
+	assert.Equal(t, "eu", region)
+
+	call("/auth")
+	assert.Equal(t, "42", userID)
+	assert.Empty(t, tenantID)
+}
+
+func TestGenericGRPCHandler_Traceparent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var traceID string
+	fn := func(ctx context.Context, req *TestRequest) (*TestResponse, error) {
+		traceID = trace.GetTraceID(ctx)
+		return &TestResponse{}, nil
+	}
+	router := gin.New()
+	router.POST("/test", GenericGRPCHandler(fn, DefaultContextInjector))
+
+	req, _ := http.NewRequest("POST", "/test", bytes.NewBufferString(`{"name":"GoBox"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+}
+
+func TestTypedHandler_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := New()
+	Bind(r, "/test", mockGRPCFunc)
+	Bind(r.Group("/v1"), "/test", mockGRPCFuncError)
+	assert.Len(t, r.routes, 1)
+	assert.Len(t, r.group[0].routes, 1)
+
+	engine := gin.New()
+	engine.POST("/test", r.routes[0].handler)
+	engine.POST("/v1/test", r.group[0].routes[0].handler)
+
+	req, _ := http.NewRequest("POST", "/test", bytes.NewBufferString(`{"name":"GoBox"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp StandardResponse[*TestResponse]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Hello, GoBox", resp.Data.Greet)
+
+	req, _ = http.NewRequest("POST", "/v1/test", bytes.NewBufferString(`{"name":"GoBox"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	var errResp StandardResponse[any]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, int64(5100), errResp.Code)
+}
+
+func TestRouteMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+	r := New()
+	r.POST("/login", mockGRPCFunc)
+	r.POST("/user/update", mockGRPCFunc, WithMiddleware(auth))
+	api := r.Group("/api").Use(auth)
+	Bind(api, "/user/get", mockGRPCFunc)
+	engine := r.newEngine()
+
+	call := func(path, token string) int {
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{"name":"GoBox"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, call("/login", ""))
+	assert.Equal(t, http.StatusUnauthorized, call("/user/update", ""))
+	assert.Equal(t, http.StatusOK, call("/user/update", "token"))
+	assert.Equal(t, http.StatusUnauthorized, call("/api/user/get", ""))
+	assert.Equal(t, http.StatusOK, call("/api/user/get", "token"))
+}
+
+func TestNestedGroup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mark := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			c.Writer.Header().Add("X-Middleware", name)
+			c.Next()
+		}
+	}
+	r := New()
+	api := r.Group("/api", mark("api"))
+	v1 := api.Group("/v1")
+	Bind(v1, "/hello", mockGRPCFunc)
+	v2 := api.Group("/v2", mark("v2"))
+	v2.POST("/hello", mockGRPCFunc)
+	engine := r.newEngine()
+
+	call := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{"name":"GoBox"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call("/api/v1/hello")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"api"}, w.Header().Values("X-Middleware"))
+	w = call("/api/v2/hello")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"api", "v2"}, w.Header().Values("X-Middleware"))
+	assert.Equal(t, http.StatusNotFound, call("/v1/hello").Code)
+}
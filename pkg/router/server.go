@@ -0,0 +1,83 @@
+package router
+
+import (
+	"crypto/tls"
+	"net/http"
+	"slices"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// ServerOption Run / RunTLS 的可选配置
+type ServerOption func(o *serverOptions)
+
+type serverOptions struct {
+	tlsConfig   *tls.Config
+	certManager *autocert.Manager
+}
+
+// WithTLSConfig 自定义 TLS 配置，如最低版本、加密套件、客户端证书校验（ClientCAs、ClientAuth）；
+// 与 RunTLS 的证书文件或 WithAutocert 一起使用，单独用于 Run 时须设置 Certificates 或 GetCertificate
+func WithTLSConfig(cfg *tls.Config) ServerOption {
+	return func(o *serverOptions) {
+		o.tlsConfig = cfg
+	}
+}
+
+// WithAutocert 通过 ACME（默认 Let's Encrypt）为 domains 自动签发并续期证书，证书缓存在 cacheDir，
+// 使用 TLS-ALPN-01 验证，监听地址须能从公网以 443 端口访问
+func WithAutocert(cacheDir string, domains ...string) ServerOption {
+	return WithCertManager(&autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cacheDir),
+	})
+}
+
+// WithCertManager 使用自定义的 autocert.Manager，如指定 ACME 目录、邮箱或共享缓存
+func WithCertManager(m *autocert.Manager) ServerOption {
+	return func(o *serverOptions) {
+		o.certManager = m
+	}
+}
+
+func applyServerOptions(opts []ServerOption) *serverOptions {
+	o := &serverOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// serverTLSConfig 合并自定义 TLS 配置与 autocert，均未配置时返回 nil
+func (o *serverOptions) serverTLSConfig() *tls.Config {
+	if o.certManager == nil {
+		return o.tlsConfig
+	}
+	if o.tlsConfig == nil {
+		return o.certManager.TLSConfig()
+	}
+	cfg := o.tlsConfig.Clone()
+	cfg.GetCertificate = o.certManager.GetCertificate
+	if len(cfg.NextProtos) == 0 {
+		cfg.NextProtos = []string{"h2", "http/1.1"}
+	}
+	if !slices.Contains(cfg.NextProtos, acme.ALPNProto) {
+		cfg.NextProtos = append(cfg.NextProtos, acme.ALPNProto)
+	}
+	return cfg
+}
+
+// hasCertificate TLS 配置自身能否提供服务端证书
+func hasCertificate(cfg *tls.Config) bool {
+	return cfg != nil && (len(cfg.Certificates) > 0 || cfg.GetCertificate != nil || cfg.GetConfigForClient != nil)
+}
+
+func newServer(addr string, handler http.Handler, opts []ServerOption) *http.Server {
+	return &http.Server{
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: applyServerOptions(opts).serverTLSConfig(),
+	}
+}
@@ -0,0 +1,42 @@
+package router
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/acme"
+)
+
+func TestNewServer_TLSConfig(t *testing.T) {
+	srv := newServer(":8080", http.NotFoundHandler(), nil)
+	assert.Nil(t, srv.TLSConfig)
+
+	custom := &tls.Config{MinVersion: tls.VersionTLS13, ClientAuth: tls.RequireAndVerifyClientCert}
+	srv = newServer(":8443", http.NotFoundHandler(), []ServerOption{WithTLSConfig(custom)})
+	assert.Same(t, custom, srv.TLSConfig)
+
+	srv = newServer(":443", http.NotFoundHandler(), []ServerOption{WithTLSConfig(custom), WithAutocert(t.TempDir(), "example.com")})
+	assert.NotSame(t, custom, srv.TLSConfig)
+	assert.Equal(t, uint16(tls.VersionTLS13), srv.TLSConfig.MinVersion)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, srv.TLSConfig.ClientAuth)
+	assert.NotNil(t, srv.TLSConfig.GetCertificate)
+	assert.Contains(t, srv.TLSConfig.NextProtos, acme.ALPNProto)
+	assert.Nil(t, custom.GetCertificate)
+}
+
+func TestRunTLS_RequiresCertificate(t *testing.T) {
+	assert.Error(t, New().RunTLS(":0", "", "", nil, nil, true))
+}
+
+func TestRun_TLSConfigWithoutCertificate(t *testing.T) {
+	opts := []ServerOption{WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})}
+	assert.Error(t, New().Run(":0", nil, nil, true, opts...))
+	assert.Error(t, New().RunTLS(":0", "", "", nil, nil, true, opts...))
+
+	assert.False(t, hasCertificate(nil))
+	assert.False(t, hasCertificate(&tls.Config{}))
+	assert.True(t, hasCertificate(&tls.Config{Certificates: []tls.Certificate{{}}}))
+	assert.True(t, hasCertificate(applyServerOptions([]ServerOption{WithAutocert(t.TempDir(), "example.com")}).serverTLSConfig()))
+}
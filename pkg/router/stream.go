@@ -0,0 +1,172 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// NDJSONContentType 流式响应的内容类型，每行一个 StandardResponse，出错时最后一行为错误
+const NDJSONContentType = "application/x-ndjson"
+
+var errHeaderSent = errors.New("router: stream header already sent")
+
+// ndjsonWriter 逐条写出消息并立即 flush，不在内存中缓存整个结果
+type ndjsonWriter struct {
+	c       *gin.Context
+	started bool
+}
+
+// start 写出响应头，之后状态码不可再修改
+func (w *ndjsonWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.c.Header("Content-Type", NDJSONContentType)
+	// 阻止 nginx 等反向代理缓冲分块响应
+	w.c.Header("X-Accel-Buffering", "no")
+	w.c.Status(http.StatusOK)
+	w.c.Writer.WriteHeaderNow()
+	w.c.Writer.Flush()
+}
+
+func (w *ndjsonWriter) write(msg any) error {
+	if err := w.c.Request.Context().Err(); err != nil {
+		return err
+	}
+	data, err := normalizeResponseData(msg)
+	if err != nil {
+		return err
+	}
+	return w.writeLine(StandardResponse[any]{Code: 0, Message: "ok", Data: data})
+}
+
+func (w *ndjsonWriter) writeLine(resp StandardResponse[any]) error {
+	line, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	w.start()
+	if _, err := w.c.Writer.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// finish 结束响应：首条消息前出错按普通响应返回对应状态码，之后出错追加一行错误
+func (w *ndjsonWriter) finish(err error) {
+	switch {
+	case err == nil:
+		w.start()
+	case w.c.Request.Context().Err() != nil:
+		// 客户端已断开
+	case !w.started:
+		writeError(w.c, err)
+	default:
+		_, resp := errorResponse(w.c, err)
+		_ = w.writeLine(resp)
+	}
+}
+
+// httpServerStream 以 HTTP 响应实现 grpc.ServerStreamingServer，供服务端流式方法直接写出
+type httpServerStream[Resp any] struct {
+	ctx context.Context
+	w   *ndjsonWriter
+}
+
+func (s *httpServerStream[Resp]) Send(m *Resp) error {
+	return s.w.write(m)
+}
+
+// SetHeader 元数据写为 HTTP 响应头，需在首条消息前调用
+func (s *httpServerStream[Resp]) SetHeader(md metadata.MD) error {
+	if s.w.started {
+		return errHeaderSent
+	}
+	for k, vs := range md {
+		for _, v := range vs {
+			s.w.c.Writer.Header().Add(k, v)
+		}
+	}
+	return nil
+}
+
+func (s *httpServerStream[Resp]) SendHeader(md metadata.MD) error {
+	if err := s.SetHeader(md); err != nil {
+		return err
+	}
+	s.w.start()
+	return nil
+}
+
+// SetTrailer 分块响应不携带 trailer，忽略
+func (s *httpServerStream[Resp]) SetTrailer(metadata.MD) {}
+
+func (s *httpServerStream[Resp]) Context() context.Context {
+	return s.ctx
+}
+
+func (s *httpServerStream[Resp]) SendMsg(m any) error {
+	msg, ok := m.(*Resp)
+	if !ok {
+		return fmt.Errorf("router: unexpected stream message type %T", m)
+	}
+	return s.Send(msg)
+}
+
+func (s *httpServerStream[Resp]) RecvMsg(any) error {
+	return errors.New("router: RecvMsg is not supported on server-streaming routes")
+}
+
+// StreamHandler 将服务端流式 gRPC 方法适配为 NDJSON 响应，每次 Send 写出一行并 flush
+func StreamHandler[Req any, Resp any](fn func(*Req, grpc.ServerStreamingServer[Resp]) error, ctxInjector ContextInjector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := new(Req)
+		if err := bindRequest(c, req); err != nil {
+			writeResponse(c, http.StatusBadRequest, StandardResponse[any]{Code: 400, Message: "Invalid request: " + err.Error()})
+			return
+		}
+		w := &ndjsonWriter{c: c}
+		w.finish(fn(req, &httpServerStream[Resp]{ctx: requestContext(c, ctxInjector), w: w}))
+	}
+}
+
+// ClientStreamHandler 将 gRPC 客户端的服务端流转发为 NDJSON 响应，用于网关；客户端断开时随请求 ctx 取消下游调用
+func ClientStreamHandler[Req any, Resp any](fn func(context.Context, *Req, ...grpc.CallOption) (grpc.ServerStreamingClient[Resp], error), ctxInjector ContextInjector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := new(Req)
+		if err := bindRequest(c, req); err != nil {
+			writeResponse(c, http.StatusBadRequest, StandardResponse[any]{Code: 400, Message: "Invalid request: " + err.Error()})
+			return
+		}
+		w := &ndjsonWriter{c: c}
+		stream, err := fn(requestContext(c, ctxInjector), req)
+		if err != nil {
+			w.finish(err)
+			return
+		}
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				w.finish(nil)
+				return
+			}
+			if err == nil {
+				err = w.write(msg)
+			}
+			if err != nil {
+				w.finish(err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/code-sigs/go-box/pkg/rpcerror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func mockStreamFunc(req *TestRequest, stream grpc.ServerStreamingServer[TestResponse]) error {
+	if req.Name == "" {
+		return rpcerror.WrapCode(5100, "name is required")
+	}
+	for _, greet := range []string{"Hello", "Hi"} {
+		if err := stream.Send(&TestResponse{Greet: greet + ", " + req.Name}); err != nil {
+			return err
+		}
+	}
+	if req.Name == "fail" {
+		return rpcerror.WrapCode(5101, "export interrupted")
+	}
+	return nil
+}
+
+func serveStream(t *testing.T, name string) (*httptest.ResponseRecorder, []StandardResponse[*TestResponse]) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/stream", StreamHandler(mockStreamFunc, DefaultContextInjector))
+
+	body, _ := json.Marshal(TestRequest{Name: name})
+	req, _ := http.NewRequest("POST", "/stream", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var lines []StandardResponse[*TestResponse]
+	scanner := bufio.NewScanner(bytes.NewReader(w.Body.Bytes()))
+	for scanner.Scan() {
+		var line StandardResponse[*TestResponse]
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	return w, lines
+}
+
+func TestStreamHandler_Success(t *testing.T) {
+	w, lines := serveStream(t, "GoBox")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, NDJSONContentType, w.Header().Get("Content-Type"))
+	assert.True(t, w.Flushed)
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, "Hello, GoBox", lines[0].Data.Greet)
+		assert.Equal(t, "Hi, GoBox", lines[1].Data.Greet)
+	}
+}
+
+func TestStreamHandler_ErrorBeforeFirstMessage(t *testing.T) {
+	w, lines := serveStream(t, "")
+
+	// 尚未写出消息时按普通 JSON 响应返回
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, int64(5100), lines[0].Code)
+	}
+}
+
+func TestStreamHandler_ErrorAfterMessages(t *testing.T) {
+	w, lines := serveStream(t, "fail")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, int64(0), lines[1].Code)
+		assert.Equal(t, int64(5101), lines[2].Code)
+	}
+}
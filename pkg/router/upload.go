@@ -0,0 +1,248 @@
+package router
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/minio"
+	"github.com/code-sigs/go-box/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxFieldSize multipart 中普通字段的大小上限
+	maxFieldSize = 1 << 20
+	// 大小未知时 SDK 按分片缓冲上传，未指定分片大小会按 5TB 对象估算出约 528MB 的缓冲，
+	// 因此按 MaxFileSize 取值，限制在 S3 最小分片 5MB 与 16MB 之间
+	minUploadPartSize = 5 << 20
+	maxUploadPartSize = 16 << 20
+)
+
+var (
+	errFileTooLarge   = errors.New("file too large")
+	errTooManyFiles   = errors.New("too many files")
+	errFileTypeDenied = errors.New("file type not allowed")
+)
+
+// UploadConfig 上传路由配置
+type UploadConfig struct {
+	Storage      minio.ObjectStorage // 对象存储，必填
+	Prefix       string              // 对象名前缀，如 "avatars/"
+	MaxFileSize  int64               // 单个文件大小上限，默认 32MB
+	MaxFiles     int                 // 单次请求的文件数上限，默认 10
+	AllowedTypes []string            // 允许的 Content-Type，支持 "image/" 形式的前缀，为空不限制
+
+	// ObjectName 生成对象名，默认 Prefix + 日期/UUID + 扩展名
+	ObjectName func(c *gin.Context, field, filename string) string
+}
+
+func (cfg UploadConfig) withDefaults() UploadConfig {
+	if cfg.MaxFileSize <= 0 {
+		cfg.MaxFileSize = 32 << 20
+	}
+	if cfg.MaxFiles <= 0 {
+		cfg.MaxFiles = 10
+	}
+	if cfg.ObjectName == nil {
+		cfg.ObjectName = func(c *gin.Context, field, filename string) string {
+			return cfg.Prefix + time.Now().Format("2006/01/02/") + utils.GenerateUUIDStr() + strings.ToLower(path.Ext(filename))
+		}
+	}
+	return cfg
+}
+
+// partSize 单个文件上传时缓冲的分片大小
+func (cfg UploadConfig) partSize() uint64 {
+	return uint64(max(min(cfg.MaxFileSize, maxUploadPartSize), minUploadPartSize))
+}
+
+func (cfg UploadConfig) allowed(contentType string) bool {
+	if len(cfg.AllowedTypes) == 0 {
+		return true
+	}
+	for _, t := range cfg.AllowedTypes {
+		if contentType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(contentType, t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadedFile 已写入对象存储的文件
+type UploadedFile struct {
+	Field       string `json:"field"`       // 表单字段名
+	Filename    string `json:"filename"`    // 客户端上传的文件名
+	ObjectName  string `json:"objectName"`  // 对象名
+	URL         string `json:"url"`         // 对象存储返回的地址
+	Size        int64  `json:"size"`        // 字节数
+	ContentType string `json:"contentType"` // 请求中声明或按内容识别的类型
+}
+
+type uploadedFilesKey struct{}
+
+// UploadedFiles 返回上传路由本次请求写入的文件，供 handler 读取大小、类型等信息
+func UploadedFiles(ctx context.Context) []UploadedFile {
+	files, _ := ctx.Value(uploadedFilesKey{}).([]UploadedFile)
+	return files
+}
+
+// UploadHandler 流式接收 multipart/form-data：文件逐个写入对象存储，不落盘也不整体缓存，
+// 对象名按表单字段名（form 标签，没有时取 json 标签）绑定到请求结构体的 string / []string 字段，
+// 其余字段与路径参数照常绑定；绑定失败或 fn 返回错误时删除本次上传的对象
+func UploadHandler[Req any, Resp any](cfg UploadConfig, fn func(context.Context, *Req) (Resp, error), ctxInjector ContextInjector) gin.HandlerFunc {
+	if cfg.Storage == nil {
+		panic("router: UploadConfig.Storage is required")
+	}
+	cfg = cfg.withDefaults()
+	return func(c *gin.Context) {
+		ctx := requestContext(c, ctxInjector)
+		values, files, err := receiveMultipart(c, ctx, cfg)
+		if err != nil {
+			removeUploaded(ctx, cfg.Storage, files)
+			writeUploadError(c, err)
+			return
+		}
+		req := new(Req)
+		if err = mapValues(req, values, "form"); err == nil {
+			err = bindParams(c, req)
+		}
+		if err != nil {
+			removeUploaded(ctx, cfg.Storage, files)
+			writeResponse(c, http.StatusBadRequest, StandardResponse[any]{Code: 400, Message: "Invalid request: " + err.Error()})
+			return
+		}
+		resp, err := fn(context.WithValue(ctx, uploadedFilesKey{}, files), req)
+		if err != nil {
+			removeUploaded(ctx, cfg.Storage, files)
+			writeError(c, err)
+			return
+		}
+		writeData(c, resp)
+	}
+}
+
+// BindUpload 以 UploadHandler 注册 POST 上传路由，如 router.BindUpload(r, "/user/avatar", cfg, srv.SetAvatar)
+func BindUpload[Req any, Resp any](r Routes, path string, cfg UploadConfig, fn func(context.Context, *Req) (Resp, error), opts ...RouteOption) {
+	r.addRoute(http.MethodPost, path, func(injector ContextInjector) gin.HandlerFunc {
+		return UploadHandler(cfg, fn, injector)
+	}, opts)
+}
+
+// receiveMultipart 按顺序读取各部分，返回普通字段与文件对象名，以及已上传的文件（出错时用于清理）
+func receiveMultipart(c *gin.Context, ctx context.Context, cfg UploadConfig) (map[string][]string, []UploadedFile, error) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+	values := map[string][]string{}
+	var files []UploadedFile
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return values, files, nil
+		}
+		if err != nil {
+			return values, files, err
+		}
+		field := part.FormName()
+		switch {
+		case field == "":
+		case part.FileName() == "":
+			data, err := io.ReadAll(io.LimitReader(part, maxFieldSize+1))
+			if err != nil {
+				return values, files, err
+			}
+			if len(data) > maxFieldSize {
+				return values, files, fmt.Errorf("field %s: value too large", field)
+			}
+			values[field] = append(values[field], string(data))
+		case len(files) >= cfg.MaxFiles:
+			return values, files, errTooManyFiles
+		default:
+			file, err := storePart(c, ctx, cfg, part)
+			if err != nil {
+				return values, files, fmt.Errorf("field %s: %w", field, err)
+			}
+			files = append(files, file)
+			values[field] = append(values[field], file.ObjectName)
+		}
+		_ = part.Close()
+	}
+}
+
+func storePart(c *gin.Context, ctx context.Context, cfg UploadConfig, part *multipart.Part) (UploadedFile, error) {
+	file := UploadedFile{
+		Field:       part.FormName(),
+		Filename:    path.Base(strings.ReplaceAll(part.FileName(), "\\", "/")),
+		ContentType: part.Header.Get("Content-Type"),
+	}
+	body := bufio.NewReaderSize(part, 512)
+	if file.ContentType == "" || file.ContentType == "application/octet-stream" {
+		head, _ := body.Peek(512)
+		file.ContentType = http.DetectContentType(head)
+	}
+	if !cfg.allowed(file.ContentType) {
+		return file, errFileTypeDenied
+	}
+	file.ObjectName = cfg.ObjectName(c, file.Field, file.Filename)
+	counter := &limitedCounter{r: body, limit: cfg.MaxFileSize}
+	// 大小未知（-1）时由 SDK 分片上传
+	url, err := cfg.Storage.UploadFileWithOptions(ctx, file.ObjectName, counter, -1, minio.UploadOptions{
+		ContentType: file.ContentType,
+		PartSize:    cfg.partSize(),
+	})
+	if err != nil {
+		if errors.Is(err, errFileTooLarge) || counter.n > cfg.MaxFileSize {
+			return file, errFileTooLarge
+		}
+		return file, err
+	}
+	file.URL, file.Size = url, counter.n
+	return file, nil
+}
+
+// limitedCounter 统计读取的字节数，超出 limit 时返回 errFileTooLarge 中止上传
+type limitedCounter struct {
+	r     io.Reader
+	n     int64
+	limit int64
+}
+
+func (l *limitedCounter) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.limit {
+		return n, errFileTooLarge
+	}
+	return n, err
+}
+
+func removeUploaded(ctx context.Context, storage minio.ObjectStorage, files []UploadedFile) {
+	// 请求可能已取消，清理不受其影响
+	ctx = context.WithoutCancel(ctx)
+	for _, f := range files {
+		if err := storage.DeleteObject(ctx, f.ObjectName); err != nil {
+			logger.Errorw(ctx, "remove uploaded object failed", "object", f.ObjectName, "error", err.Error())
+		}
+	}
+}
+
+func writeUploadError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, errFileTooLarge), errors.Is(err, errTooManyFiles):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, errFileTypeDenied):
+		status = http.StatusUnsupportedMediaType
+	}
+	writeResponse(c, status, StandardResponse[any]{Code: int64(status), Message: "Invalid upload: " + err.Error()})
+}
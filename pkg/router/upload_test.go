@@ -0,0 +1,116 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/code-sigs/go-box/pkg/minio"
+	"github.com/code-sigs/go-box/pkg/rpcerror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStorage struct {
+	minio.ObjectStorage
+	objects map[string][]byte
+}
+
+func (m *memoryStorage) UploadFileWithOptions(ctx context.Context, objectName string, reader io.Reader, size int64, opts minio.UploadOptions) (string, error) {
+	// 大小未知时必须指定分片大小，否则 SDK 会为每个文件分配数百 MB 的缓冲
+	if size < 0 && (opts.PartSize < minUploadPartSize || opts.PartSize > maxUploadPartSize) {
+		return "", fmt.Errorf("unexpected part size %d", opts.PartSize)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	m.objects[objectName] = data
+	return "mem://" + objectName, nil
+}
+
+func (m *memoryStorage) DeleteObject(ctx context.Context, objectName string) error {
+	delete(m.objects, objectName)
+	return nil
+}
+
+type avatarRequest struct {
+	UserID string   `json:"userId" binding:"required"`
+	Avatar string   `json:"avatar" binding:"required"`
+	Extra  []string `form:"extra"`
+}
+
+func mockAvatarFunc(ctx context.Context, req *avatarRequest) (map[string]any, error) {
+	if req.UserID == "fail" {
+		return nil, rpcerror.WrapCode(5100, "save failed")
+	}
+	return map[string]any{"request": req, "files": UploadedFiles(ctx)}, nil
+}
+
+func serveUpload(storage *memoryStorage, userID string, files map[string]string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/avatar", UploadHandler(UploadConfig{Storage: storage, Prefix: "avatars/", MaxFileSize: 16, AllowedTypes: []string{"text/"}}, mockAvatarFunc, DefaultContextInjector))
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("userId", userID)
+	for field, content := range files {
+		fw, _ := mw.CreateFormFile(field, field+".txt")
+		_, _ = fw.Write([]byte(content))
+	}
+	_ = mw.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, "/avatar", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUploadHandler_Success(t *testing.T) {
+	storage := &memoryStorage{objects: map[string][]byte{}}
+	w := serveUpload(storage, "u1", map[string]string{"avatar": "hello"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp StandardResponse[struct {
+		Request avatarRequest  `json:"request"`
+		Files   []UploadedFile `json:"files"`
+	}]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "u1", resp.Data.Request.UserID)
+	assert.Contains(t, resp.Data.Request.Avatar, "avatars/")
+	if assert.Len(t, resp.Data.Files, 1) {
+		assert.Equal(t, int64(5), resp.Data.Files[0].Size)
+		assert.Equal(t, "avatar.txt", resp.Data.Files[0].Filename)
+	}
+	assert.Equal(t, []byte("hello"), storage.objects[resp.Data.Request.Avatar])
+}
+
+func TestUploadHandler_TooLarge(t *testing.T) {
+	storage := &memoryStorage{objects: map[string][]byte{}}
+	w := serveUpload(storage, "u1", map[string]string{"avatar": "this content is too large"})
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Empty(t, storage.objects)
+}
+
+func TestUploadHandler_RemoveOnError(t *testing.T) {
+	storage := &memoryStorage{objects: map[string][]byte{}}
+	w := serveUpload(storage, "fail", map[string]string{"avatar": "hello"})
+
+	var resp StandardResponse[any]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(5100), resp.Code)
+	assert.Empty(t, storage.objects)
+
+	w = serveUpload(storage, "u1", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,278 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/ws"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// wsBridge 一个 WebSocket 连接与 gRPC 双向流之间的桥接：
+// 客户端的每个文本帧是一条 JSON 请求，服务端的每个帧是一条 StandardResponse，出错时最后一帧为错误
+type wsBridge struct {
+	c    *gin.Context
+	conn *websocket.Conn
+	cfg  ws.Config
+	mu   sync.Mutex // 串行化数据帧写入，控制帧由 WriteControl 保证并发安全
+}
+
+func upgradeWS(c *gin.Context, upgrader *websocket.Upgrader, cfg ws.Config) (*wsBridge, bool) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade 失败时已向客户端写入错误响应
+		logger.Warnw(c.Request.Context(), "websocket upgrade failed", "error", err.Error())
+		return nil, false
+	}
+	return &wsBridge{c: c, conn: conn, cfg: cfg}, true
+}
+
+func (b *wsBridge) writeFrame(resp StandardResponse[any]) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_ = b.conn.SetWriteDeadline(time.Now().Add(b.cfg.WriteTimeout))
+	return b.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (b *wsBridge) send(msg any) error {
+	data, err := normalizeResponseData(msg)
+	if err != nil {
+		return err
+	}
+	return b.writeFrame(StandardResponse[any]{Code: 0, Message: "ok", Data: data})
+}
+
+// readLoop 读取客户端帧直到连接关闭，返回 io.EOF 表示客户端正常关闭
+func (b *wsBridge) readLoop(handle func(data []byte)) error {
+	b.conn.SetReadLimit(b.cfg.MaxMessageSize)
+	_ = b.conn.SetReadDeadline(time.Now().Add(b.cfg.PongTimeout))
+	b.conn.SetPongHandler(func(string) error {
+		return b.conn.SetReadDeadline(time.Now().Add(b.cfg.PongTimeout))
+	})
+	for {
+		_, data, err := b.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) {
+				return io.EOF
+			}
+			return err
+		}
+		_ = b.conn.SetReadDeadline(time.Now().Add(b.cfg.PongTimeout))
+		handle(data)
+	}
+}
+
+func (b *wsBridge) pingLoop(ctx context.Context) {
+	ticker := time.NewTicker(b.cfg.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := b.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(b.cfg.WriteTimeout)); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// finish 出错时先写出错误帧，再发送关闭帧并关闭连接
+func (b *wsBridge) finish(ctx context.Context, err error) {
+	defer b.conn.Close()
+	closeCode := websocket.CloseNormalClosure
+	switch {
+	case ctx.Err() != nil:
+		// 客户端已断开
+		return
+	case err != nil:
+		_, resp := errorResponse(b.c, err)
+		_ = b.writeFrame(resp)
+		closeCode = websocket.CloseInternalServerErr
+	}
+	_ = b.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, ""), time.Now().Add(b.cfg.WriteTimeout))
+}
+
+// decodeFrame 解析并校验一条请求，无效时回复 400 错误帧并跳过，不中断连接
+func decodeFrame[Req any](b *wsBridge, data []byte) (*Req, bool) {
+	req := new(Req)
+	err := json.Unmarshal(data, req)
+	if err == nil {
+		err = binding.Validator.ValidateStruct(req)
+	}
+	if err != nil {
+		_ = b.writeFrame(StandardResponse[any]{Code: 400, Message: "Invalid request: " + err.Error()})
+		return nil, false
+	}
+	return req, true
+}
+
+// wsServerStream 以 WebSocket 连接实现 grpc.BidiStreamingServer
+type wsServerStream[Req any, Resp any] struct {
+	ctx     context.Context
+	b       *wsBridge
+	recv    chan *Req
+	readErr error // recv 关闭前写入
+}
+
+func (s *wsServerStream[Req, Resp]) Recv() (*Req, error) {
+	select {
+	case req, ok := <-s.recv:
+		if !ok {
+			return nil, s.readErr
+		}
+		return req, nil
+	case <-s.ctx.Done():
+		// 读循环结束时先关闭 recv 再取消 ctx，优先返回读循环的结果
+		select {
+		case req, ok := <-s.recv:
+			if !ok {
+				return nil, s.readErr
+			}
+			return req, nil
+		default:
+			return nil, s.ctx.Err()
+		}
+	}
+}
+
+func (s *wsServerStream[Req, Resp]) Send(m *Resp) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	return s.b.send(m)
+}
+
+// SetHeader 握手已完成，无法再写响应头
+func (s *wsServerStream[Req, Resp]) SetHeader(metadata.MD) error {
+	return errHeaderSent
+}
+
+func (s *wsServerStream[Req, Resp]) SendHeader(metadata.MD) error {
+	return errHeaderSent
+}
+
+func (s *wsServerStream[Req, Resp]) SetTrailer(metadata.MD) {}
+
+func (s *wsServerStream[Req, Resp]) Context() context.Context {
+	return s.ctx
+}
+
+func (s *wsServerStream[Req, Resp]) SendMsg(m any) error {
+	msg, ok := m.(*Resp)
+	if !ok {
+		return fmt.Errorf("router: unexpected stream message type %T", m)
+	}
+	return s.Send(msg)
+}
+
+func (s *wsServerStream[Req, Resp]) RecvMsg(any) error {
+	return errors.New("router: RecvMsg is not supported on websocket routes, use Recv")
+}
+
+// WSHandler 将请求升级为 WebSocket 并桥接到双向流 gRPC 方法：客户端每个 JSON 帧对应一次 Recv，
+// 每次 Send 写出一帧 StandardResponse；客户端关闭时 Recv 返回 io.EOF，断开时 ctx 取消
+func WSHandler[Req any, Resp any](cfg ws.Config, fn func(grpc.BidiStreamingServer[Req, Resp]) error, ctxInjector ContextInjector) gin.HandlerFunc {
+	cfg = cfg.WithDefaults()
+	upgrader := &websocket.Upgrader{CheckOrigin: cfg.CheckOrigin}
+	return func(c *gin.Context) {
+		b, ok := upgradeWS(c, upgrader, cfg)
+		if !ok {
+			return
+		}
+		ctx, cancel := context.WithCancel(requestContext(c, ctxInjector))
+		defer cancel()
+		stream := &wsServerStream[Req, Resp]{ctx: ctx, b: b, recv: make(chan *Req)}
+		go func() {
+			stream.readErr = b.readLoop(func(data []byte) {
+				if req, ok := decodeFrame[Req](b, data); ok {
+					select {
+					case stream.recv <- req:
+					case <-ctx.Done():
+					}
+				}
+			})
+			close(stream.recv)
+			cancel()
+		}()
+		go b.pingLoop(ctx)
+		b.finish(ctx, fn(stream))
+	}
+}
+
+// ClientWSHandler 将 WebSocket 桥接到 gRPC 客户端的双向流，用于网关；客户端关闭时对下游 CloseSend
+func ClientWSHandler[Req any, Resp any](cfg ws.Config, fn func(context.Context, ...grpc.CallOption) (grpc.BidiStreamingClient[Req, Resp], error), ctxInjector ContextInjector) gin.HandlerFunc {
+	cfg = cfg.WithDefaults()
+	upgrader := &websocket.Upgrader{CheckOrigin: cfg.CheckOrigin}
+	return func(c *gin.Context) {
+		b, ok := upgradeWS(c, upgrader, cfg)
+		if !ok {
+			return
+		}
+		ctx, cancel := context.WithCancel(requestContext(c, ctxInjector))
+		defer cancel()
+		stream, err := fn(ctx)
+		if err != nil {
+			b.finish(ctx, err)
+			return
+		}
+		go func() {
+			err := b.readLoop(func(data []byte) {
+				if req, ok := decodeFrame[Req](b, data); ok {
+					if err := stream.Send(req); err != nil && !errors.Is(err, io.EOF) {
+						logger.Warnw(ctx, "websocket forward failed", "error", err.Error())
+					}
+				}
+			})
+			if errors.Is(err, io.EOF) {
+				_ = stream.CloseSend()
+				return
+			}
+			cancel()
+		}()
+		go b.pingLoop(ctx)
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				b.finish(ctx, nil)
+				return
+			}
+			if err == nil {
+				err = b.send(resp)
+			}
+			if err != nil {
+				b.finish(ctx, err)
+				return
+			}
+		}
+	}
+}
+
+// BindWS 以 WSHandler 注册 GET 路由，如 router.BindWS(r, "/chat", ws.Config{}, srv.Chat)
+func BindWS[Req any, Resp any](r Routes, path string, cfg ws.Config, fn func(grpc.BidiStreamingServer[Req, Resp]) error, opts ...RouteOption) {
+	r.addRoute(http.MethodGet, path, func(injector ContextInjector) gin.HandlerFunc {
+		return WSHandler(cfg, fn, injector)
+	}, opts)
+}
+
+// BindClientWS 以 ClientWSHandler 注册 GET 路由，如 router.BindClientWS(r, "/chat", ws.Config{}, client.Chat)
+func BindClientWS[Req any, Resp any](r Routes, path string, cfg ws.Config, fn func(context.Context, ...grpc.CallOption) (grpc.BidiStreamingClient[Req, Resp], error), opts ...RouteOption) {
+	r.addRoute(http.MethodGet, path, func(injector ContextInjector) gin.HandlerFunc {
+		return ClientWSHandler(cfg, fn, injector)
+	}, opts)
+}
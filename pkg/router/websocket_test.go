@@ -0,0 +1,95 @@
+package router
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/code-sigs/go-box/pkg/rpcerror"
+	"github.com/code-sigs/go-box/pkg/ws"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func mockChatFunc(stream grpc.BidiStreamingServer[TestRequest, TestResponse]) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if req.Name == "bye" {
+			return rpcerror.WrapCode(5100, "conversation closed")
+		}
+		if err := stream.Send(&TestResponse{Greet: "Hello, " + req.Name}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestWSHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/chat", WSHandler(ws.Config{}, mockChatFunc, DefaultContextInjector))
+	srv := httptest.NewServer(engine)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/chat", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	var resp StandardResponse[*TestResponse]
+	assert.NoError(t, conn.WriteJSON(TestRequest{Name: "GoBox"}))
+	assert.NoError(t, conn.ReadJSON(&resp))
+	assert.Equal(t, "Hello, GoBox", resp.Data.Greet)
+
+	// 无效帧只回复错误，不中断连接
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("invalid json")))
+	assert.NoError(t, conn.ReadJSON(&resp))
+	assert.Equal(t, int64(400), resp.Code)
+
+	assert.NoError(t, conn.WriteJSON(TestRequest{Name: "bye"}))
+	var errResp StandardResponse[any]
+	assert.NoError(t, conn.ReadJSON(&errResp))
+	assert.Equal(t, int64(5100), errResp.Code)
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseInternalServerErr))
+}
+
+func TestWSHandler_CheckOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/chat", WSHandler(ws.Config{}, mockChatFunc, DefaultContextInjector))
+	engine.GET("/open", WSHandler(ws.Config{CheckOrigin: ws.AllowOrigins("https://app.example.com")}, mockChatFunc, DefaultContextInjector))
+	srv := httptest.NewServer(engine)
+	defer srv.Close()
+
+	dial := func(path, origin string) (*http.Response, error) {
+		conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+path, http.Header{"Origin": {origin}})
+		if err == nil {
+			conn.Close()
+		}
+		return resp, err
+	}
+
+	// 默认只允许同源，拒绝跨站握手
+	resp, err := dial("/chat", "https://evil.example.com")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_, err = dial("/chat", srv.URL)
+	assert.NoError(t, err)
+
+	_, err = dial("/open", "https://app.example.com")
+	assert.NoError(t, err)
+	_, err = dial("/open", "https://evil.example.com")
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package rpcerror
+
+import (
+	"errors"
+
+	"github.com/code-sigs/go-box/pkg/errs"
+)
+
+// Internal 系统异常（errs.ErrorInternal）
+func Internal(msg string) error {
+	return wrapCode(2, errs.ErrorInternal, msg)
+}
+
+// Invalid 参数错误（errs.ErrorArgs）
+func Invalid(msg string) error {
+	return wrapCode(2, errs.ErrorArgs, msg)
+}
+
+// NotFound 记录不存在（errs.ErrorNotFound）
+func NotFound(msg string) error {
+	return wrapCode(2, errs.ErrorNotFound, msg)
+}
+
+// NoPermission 无操作权限（errs.ErrorNoPermission）
+func NoPermission(msg string) error {
+	return wrapCode(2, errs.ErrorNoPermission, msg)
+}
+
+// NoUser 用户不存在（errs.ErrorNoUser）
+func NoUser(msg string) error {
+	return wrapCode(2, errs.ErrorNoUser, msg)
+}
+
+// WrongPassword 密码错误（errs.ErrorPassword）
+func WrongPassword(msg string) error {
+	return wrapCode(2, errs.ErrorPassword, msg)
+}
+
+// InvalidToken 无效 token（errs.ErrorInvalidToken）
+func InvalidToken(msg string) error {
+	return wrapCode(2, errs.ErrorInvalidToken, msg)
+}
+
+// FromError 将带 code 的 errs.WrapError 转换为 RPCError，
+// 已是 RPCError 或不带 code 的错误原样返回
+func FromError(err error) error {
+	if err == nil || IsRPCError(err) {
+		return err
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		we, ok := e.(*errs.WrapError)
+		if !ok || we.Code() == 0 {
+			continue
+		}
+		msg := we.Message()
+		if msg == "" {
+			if cause := we.Unwrap(); cause != nil {
+				msg = cause.Error()
+			}
+		}
+		return Wrap(&RPCError{Code: int64(we.Code()), Message: msg, Details: we.Location()})
+	}
+	return err
+}
@@ -46,12 +46,17 @@ func Wrap(e *RPCError) error {
 
 // WrapCode 返回结构化错误，可指定 gRPC code
 func WrapCode(code int, msg string) error {
+	return wrapCode(2, code, msg)
+}
+
+// wrapCode 生成结构化错误，skip 为调用位置相对本函数的栈深度
+func wrapCode(skip int, code int, msg string) error {
 	e := &RPCError{
 		Code:    int64(code),
 		Message: msg,
 	}
 	if code != 0 {
-		if pc, _, line, ok := runtime.Caller(1); ok {
+		if pc, _, line, ok := runtime.Caller(skip); ok {
 			funcName := runtime.FuncForPC(pc).Name()
 			//e.Details = " [" + file + ":" + funcName + ":" + strconv.Itoa(line) + "]"
 			// 只保留 file 的最后3级目录
@@ -0,0 +1,63 @@
+package rpcerror
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/code-sigs/go-box/pkg/errs"
+)
+
+// httpRule 业务错误码区间 [from, to] 对应的 HTTP 状态码
+type httpRule struct {
+	from, to int64
+	status   int
+}
+
+var httpRules struct {
+	sync.RWMutex
+	exact  map[int64]int
+	ranges []httpRule
+}
+
+// RegisterHTTPStatus 指定单个业务错误码返回的 HTTP 状态码，优先于 errs.Register 登记的状态码和区间映射
+func RegisterHTTPStatus(code int64, status int) {
+	httpRules.Lock()
+	defer httpRules.Unlock()
+	if httpRules.exact == nil {
+		httpRules.exact = make(map[int64]int)
+	}
+	httpRules.exact[code] = status
+}
+
+// RegisterHTTPStatusRange 指定业务错误码区间 [from, to] 返回的 HTTP 状态码，
+// 如 RegisterHTTPStatusRange(500000, 500099, 500)；区间重叠时范围更小的优先
+func RegisterHTTPStatusRange(from, to int64, status int) {
+	if from > to {
+		from, to = to, from
+	}
+	httpRules.Lock()
+	defer httpRules.Unlock()
+	httpRules.ranges = append(httpRules.ranges, httpRule{from: from, to: to, status: status})
+}
+
+// HTTPStatus 返回业务错误码对应的 HTTP 状态码，未注册时返回 200，错误码放在响应体中
+func HTTPStatus(code int64) int {
+	httpRules.RLock()
+	defer httpRules.RUnlock()
+	if status, ok := httpRules.exact[code]; ok {
+		return status
+	}
+	if info, ok := errs.Lookup(int(code)); ok && info.HTTPStatus != 0 {
+		return info.HTTPStatus
+	}
+	status, width := http.StatusOK, int64(-1)
+	for _, r := range httpRules.ranges {
+		if code < r.from || code > r.to {
+			continue
+		}
+		if w := r.to - r.from; width < 0 || w < width {
+			status, width = r.status, w
+		}
+	}
+	return status
+}
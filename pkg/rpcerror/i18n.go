@@ -0,0 +1,116 @@
+package rpcerror
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage 请求语言都没有对应文案时使用的语言，为空则保留原始消息
+var DefaultLanguage = "zh-CN"
+
+var catalog struct {
+	sync.RWMutex
+	messages map[string]map[int64]string // 语言(小写) -> 错误码 -> 文案模板
+}
+
+// RegisterMessages 注册某个语言下各错误码的文案，模板中的 {code} 会被替换为错误码，
+// 重复注册时后者覆盖前者
+func RegisterMessages(lang string, messages map[int64]string) {
+	lang = normalizeLang(lang)
+	catalog.Lock()
+	defer catalog.Unlock()
+	if catalog.messages == nil {
+		catalog.messages = make(map[string]map[int64]string)
+	}
+	m := catalog.messages[lang]
+	if m == nil {
+		m = make(map[int64]string, len(messages))
+		catalog.messages[lang] = m
+	}
+	for code, tmpl := range messages {
+		m[code] = tmpl
+	}
+}
+
+// Localize 按 langs 的顺序查找错误码的文案，依次尝试完整语言（en-US）和主语言（en），
+// 最后尝试 DefaultLanguage，都没有时返回 fallback
+func Localize(code int64, fallback string, langs ...string) string {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	for _, lang := range langs {
+		if tmpl, ok := lookupMessage(lang, code); ok {
+			return strings.ReplaceAll(tmpl, "{code}", strconv.FormatInt(code, 10))
+		}
+	}
+	if tmpl, ok := lookupMessage(DefaultLanguage, code); ok {
+		return strings.ReplaceAll(tmpl, "{code}", strconv.FormatInt(code, 10))
+	}
+	return fallback
+}
+
+// lookupMessage 先按完整语言查找，再按主语言查找，调用方需持有读锁
+func lookupMessage(lang string, code int64) (string, bool) {
+	lang = normalizeLang(lang)
+	if lang == "" {
+		return "", false
+	}
+	if tmpl, ok := catalog.messages[lang][code]; ok {
+		return tmpl, true
+	}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		tmpl, ok := catalog.messages[base][code]
+		return tmpl, ok
+	}
+	return "", false
+}
+
+// LocalizeError 将 err 中 RPCError 的消息替换为对应语言的文案，非 RPCError 原样返回
+func LocalizeError(err error, langs ...string) error {
+	e := UnWrap(err)
+	if e == nil {
+		return err
+	}
+	msg := Localize(e.Code, e.Message, langs...)
+	if msg == e.Message {
+		return err
+	}
+	e.Message = msg
+	return Wrap(e)
+}
+
+// ParseAcceptLanguage 解析 Accept-Language 头，按权重从高到低返回语言列表
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			items = append(items, weighted{lang: lang, q: q})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].q > items[j].q })
+	langs := make([]string, len(items))
+	for i, it := range items {
+		langs[i] = it.lang
+	}
+	return langs
+}
+
+// normalizeLang 统一为小写并使用 "-" 分隔，如 zh_CN -> zh-cn
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
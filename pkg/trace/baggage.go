@@ -0,0 +1,55 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// 端到端透传的 baggage key
+const (
+	BaggageTenantID = "tenant-id"
+	BaggageUserID   = "user-id"
+)
+
+// BaggageKeys HTTP 注入器和 gRPC 拦截器透传的 baggage key
+var BaggageKeys = []string{BaggageTenantID, BaggageUserID}
+
+// WithBaggage 写入 baggage，随 traceparent 一起经 HTTP、gRPC、Kafka 传递到下游；key 或 value 不合法时原样返回 ctx
+func WithBaggage(ctx context.Context, key, value string) context.Context {
+	m, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx
+	}
+	b, err := baggage.FromContext(ctx).SetMember(m)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, b)
+}
+
+// WithoutBaggage 删除 baggage 中的 key，用于丢弃不可信来源写入的身份字段
+func WithoutBaggage(ctx context.Context, keys ...string) context.Context {
+	b := baggage.FromContext(ctx)
+	if b.Len() == 0 {
+		return ctx
+	}
+	for _, key := range keys {
+		b = b.DeleteMember(key)
+	}
+	return baggage.ContextWithBaggage(ctx, b)
+}
+
+// GetBaggage 读取 baggage，不存在时兼容读取以字符串 key 写入 ctx 的旧值
+func GetBaggage(ctx context.Context, key string) string {
+	if ctx == nil {
+		return ""
+	}
+	if v := baggage.FromContext(ctx).Member(key).Value(); v != "" {
+		return v
+	}
+	if v, ok := ctx.Value(key).(string); ok {
+		return v
+	}
+	return ""
+}
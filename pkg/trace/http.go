@@ -0,0 +1,56 @@
+package trace
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// InjectHTTP 将 ctx 中的链路写入请求头（traceparent / tracestate / baggage / x-trace-id）
+func InjectHTTP(req *http.Request) {
+	Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+}
+
+// Transport 为出站 HTTP 请求创建客户端 span 并注入 W3C 链路头
+type Transport struct {
+	Base http.RoundTripper // 为 nil 时使用 http.DefaultTransport
+}
+
+// NewTransport 包装 base，用于 http.Client{Transport: trace.NewTransport(nil)}
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx, span := Start(req.Context(), "HTTP "+req.Method,
+		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+		oteltrace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.Redacted()),
+			attribute.String("server.address", req.URL.Hostname()),
+		),
+	)
+	// RoundTripper 不应修改调用方的请求，注入前先克隆
+	req = req.Clone(ctx)
+	InjectHTTP(req)
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 500 {
+		span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", resp.StatusCode))
+	}
+	span.End()
+	return resp, nil
+}
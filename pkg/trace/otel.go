@@ -0,0 +1,124 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName go-box 创建 span 时使用的 tracer 名称
+const instrumentationName = "github.com/code-sigs/go-box"
+
+// Config OpenTelemetry 链路追踪配置
+type Config struct {
+	Enabled     bool    `mapstructure:"enabled"`     // 是否上报 span，关闭时仍透传链路上下文
+	ServiceName string  `mapstructure:"serviceName"` // 服务名
+	Endpoint    string  `mapstructure:"endpoint"`    // OTLP gRPC 地址，如 otel-collector:4317
+	Insecure    bool    `mapstructure:"insecure"`    // 不使用 TLS 连接 collector
+	SampleRatio float64 `mapstructure:"sampleRatio"` // 采样率 0~1，默认 1；上游已采样的请求始终采样
+}
+
+// propagator W3C traceparent / tracestate 与 baggage，未调用 Init 时也可用于透传
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Init 初始化 OTLP 导出和全局 TracerProvider，返回的 shutdown 在退出前调用以刷新未上报的 span。
+// cfg 为 nil 或未开启时只设置全局传播器
+func Init(ctx context.Context, cfg *Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagator)
+	if cfg == nil || !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{}
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("create otel resource: %w", err)
+	}
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Tracer 返回 go-box 使用的 tracer，未调用 Init 时为 noop 实现
+func Tracer() oteltrace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start 创建子 span，调用方负责 End
+func Start(ctx context.Context, name string, opts ...oteltrace.SpanStartOption) (context.Context, oteltrace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// End 结束 span，err 不为 nil 时记录错误并标记状态
+func End(span oteltrace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject 将 ctx 中的链路信息写入 carrier：traceparent / tracestate / baggage 以及兼容的 x-trace-id
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	propagator.Inject(ctx, carrier)
+	traceID := GetTraceID(ctx)
+	if traceID == "" {
+		return
+	}
+	if carrier.Get(traceKey) == "" {
+		carrier.Set(traceKey, traceID)
+	}
+	// 只有自定义 traceID、没有 span 时仍输出未采样的 traceparent，便于外部系统串联
+	if carrier.Get("traceparent") == "" {
+		carrier.Set("traceparent", FormatTraceparent(traceID))
+	}
+}
+
+// Extract 从 carrier 恢复链路：traceparent 作为远端父 span，x-trace-id 作为兼容的 traceID
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	ctx = propagator.Extract(ctx, carrier)
+	if id := carrier.Get(traceKey); id != "" && ctx.Value(traceKey) == nil {
+		ctx = WithTraceID(ctx, id)
+	}
+	return ctx
+}
+
+// ExtractUntrusted 同 Extract，但丢弃 baggage 中的 BaggageKeys，用于接收外部请求的 HTTP 入口：
+// 用户、租户只能由鉴权中间件写入，不能由客户端通过 baggage 请求头伪造；ctx 中已有的值保持不变
+func ExtractUntrusted(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	trusted := baggage.FromContext(ctx)
+	ctx = WithoutBaggage(Extract(ctx, carrier), BaggageKeys...)
+	for _, key := range BaggageKeys {
+		if v := trusted.Member(key).Value(); v != "" {
+			ctx = WithBaggage(ctx, key, v)
+		}
+	}
+	return ctx
+}
@@ -2,9 +2,14 @@ package trace
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 const traceKey = "x-trace-id"
@@ -17,6 +22,7 @@ func WithNewTraceID(ctx context.Context) context.Context {
 	return context.WithValue(ctx, traceKey, GenerateTraceID())
 }
 
+// GetTraceID 返回 ctx 中的 traceID，未显式设置时使用 OpenTelemetry span 的 trace-id
 func GetTraceID(ctx context.Context) string {
 	if ctx == nil {
 		return ""
@@ -27,5 +33,48 @@ func GetTraceID(ctx context.Context) string {
 			return id
 		}
 	}
+	if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
 	return ""
 }
+
+// WithTraceID 将指定的 traceID 写入 ctx，用于从消息头、请求头中恢复链路
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceKey, traceID)
+}
+
+// FormatTraceparent 生成 W3C traceparent 头，traceID 不是 32 位十六进制时取其 MD5 作为 trace-id；
+// 没有真实 span，sampled 标志置 0，不强制下游 ParentBased 采样器采样
+func FormatTraceparent(traceID string) string {
+	tid := strings.ToLower(traceID)
+	if !isHex(tid, 32) {
+		sum := md5.Sum([]byte(traceID))
+		tid = hex.EncodeToString(sum[:])
+	}
+	return fmt.Sprintf("00-%s-%016x-00", tid, rand.Uint64())
+}
+
+// ParseTraceparent 解析 W3C traceparent 头，返回其中的 trace-id
+func ParseTraceparent(header string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || !isHex(parts[0], 2) || !isHex(parts[1], 32) || !isHex(parts[2], 16) {
+		return "", false
+	}
+	if parts[1] == strings.Repeat("0", 32) {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
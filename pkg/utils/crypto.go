@@ -0,0 +1,281 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrCiphertextTooShort = errors.New("ciphertext too short")
+	ErrPasswordMismatch   = errors.New("password mismatch")
+	ErrUnknownHashFormat  = errors.New("unknown password hash format")
+)
+
+// AESGCMEncrypt 使用 AES-GCM 加密，key 长度为 16/24/32 字节，返回 nonce + 密文，
+// additionalData 为可选的附加认证数据，解密时需一致
+func AESGCMEncrypt(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// AESGCMDecrypt 解密 AESGCMEncrypt 的输出，密文被篡改或 key 不匹配时返回错误
+func AESGCMDecrypt(key, ciphertext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, ErrCiphertextTooShort
+	}
+	nonce, data := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, data, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("aes-gcm decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+// AESGCMEncryptString 加密字符串并输出 URL 安全的 base64，便于存库或放入 URL
+func AESGCMEncryptString(key []byte, plaintext string) (string, error) {
+	out, err := AESGCMEncrypt(key, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(out), nil
+}
+
+// AESGCMDecryptString 解密 AESGCMEncryptString 的输出
+func AESGCMDecryptString(key []byte, ciphertext string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("decode ciphertext: %w", err)
+	}
+	out, err := AESGCMDecrypt(key, data, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create aes cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+	return gcm, nil
+}
+
+// GenerateRSAKey 生成 RSA 私钥，bits 建议不小于 2048
+func GenerateRSAKey(bits int) (*rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, fmt.Errorf("generate rsa key: %w", err)
+	}
+	return key, nil
+}
+
+// EncodeRSAPrivateKey 将私钥编码为 PKCS#8 PEM
+func EncodeRSAPrivateKey(key *rsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal rsa private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// EncodeRSAPublicKey 将公钥编码为 PKIX PEM
+func EncodeRSAPublicKey(key *rsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal rsa public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParseRSAPrivateKey 解析 PEM 私钥，支持 PKCS#1 与 PKCS#8
+func ParseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("parse rsa private key: no pem block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse rsa private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("parse rsa private key: unexpected key type %T", key)
+	}
+	return rsaKey, nil
+}
+
+// ParseRSAPublicKey 解析 PEM 公钥，支持 PKIX、PKCS#1 与证书
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("parse rsa public key: no pem block found")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse rsa public key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("parse rsa public key: unexpected key type %T", key)
+	}
+	return rsaKey, nil
+}
+
+// RSASign 使用 SHA-256 + PKCS#1 v1.5 对 data 签名，与大多数第三方开放平台兼容
+func RSASign(key *rsa.PrivateKey, data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("rsa sign: %w", err)
+	}
+	return sig, nil
+}
+
+// RSAVerify 校验 RSASign 生成的签名，不匹配时返回错误
+func RSAVerify(key *rsa.PublicKey, data, sig []byte) error {
+	digest := sha256.Sum256(data)
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return fmt.Errorf("rsa verify: %w", err)
+	}
+	return nil
+}
+
+// Argon2Params argon2id 参数，默认值参考 RFC 9106 的低内存推荐配置
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params HashPassword 使用的参数
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// HashPassword 使用 argon2id 计算密码哈希，输出 PHC 格式：
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>。不要再用 MD5Hash 存储密码
+func HashPassword(password string) (string, error) {
+	return HashPasswordArgon2(password, DefaultArgon2Params)
+}
+
+// HashPasswordArgon2 使用指定参数计算 argon2id 哈希
+func HashPasswordArgon2(password string, p Argon2Params) (string, error) {
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+	hash := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+// HashPasswordBcrypt 使用 bcrypt 计算密码哈希，cost <= 0 时使用 bcrypt.DefaultCost
+func HashPasswordBcrypt(password string, cost int) (string, error) {
+	if cost <= 0 {
+		cost = bcrypt.DefaultCost
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt hash: %w", err)
+	}
+	return string(hash), nil
+}
+
+// VerifyPassword 校验密码，按哈希前缀自动识别 argon2id 与 bcrypt，
+// 不匹配返回 ErrPasswordMismatch
+func VerifyPassword(encoded, password string) error {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return verifyArgon2(encoded, password)
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrPasswordMismatch
+		}
+		return err
+	default:
+		return ErrUnknownHashFormat
+	}
+}
+
+func verifyArgon2(encoded, password string) error {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return ErrUnknownHashFormat
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return fmt.Errorf("%w: unsupported argon2 version %q", ErrUnknownHashFormat, parts[2])
+	}
+	var p Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnknownHashFormat, err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnknownHashFormat, err)
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnknownHashFormat, err)
+	}
+	got := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(want)))
+	if subtle.ConstantTimeCompare(got, want) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAESGCM(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	aad := []byte("user:1")
+
+	ct, err := AESGCMEncrypt(key, []byte("hello"), aad)
+	require.NoError(t, err)
+	pt, err := AESGCMDecrypt(key, ct, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(pt))
+
+	// 随机 nonce，相同明文每次密文不同
+	ct2, err := AESGCMEncrypt(key, []byte("hello"), aad)
+	require.NoError(t, err)
+	assert.NotEqual(t, ct, ct2)
+
+	tampered := append([]byte(nil), ct...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = AESGCMDecrypt(key, tampered, aad)
+	assert.Error(t, err)
+
+	_, err = AESGCMDecrypt(bytes.Repeat([]byte{2}, 32), ct, aad)
+	assert.Error(t, err)
+	_, err = AESGCMDecrypt(key, ct, []byte("user:2"))
+	assert.Error(t, err)
+	_, err = AESGCMDecrypt(key, ct[:10], aad)
+	assert.ErrorIs(t, err, ErrCiphertextTooShort)
+
+	_, err = AESGCMEncrypt([]byte("short"), []byte("hello"), nil)
+	assert.Error(t, err)
+}
+
+func TestAESGCMString(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 16)
+	s, err := AESGCMEncryptString(key, "手机号 13800138000")
+	require.NoError(t, err)
+	assert.NotContains(t, s, "+")
+	assert.NotContains(t, s, "/")
+	got, err := AESGCMDecryptString(key, s)
+	require.NoError(t, err)
+	assert.Equal(t, "手机号 13800138000", got)
+
+	_, err = AESGCMDecryptString(key, "!!!")
+	assert.Error(t, err)
+}
+
+func TestRSASignVerify(t *testing.T) {
+	key, err := GenerateRSAKey(2048)
+	require.NoError(t, err)
+
+	pkcs8, err := EncodeRSAPrivateKey(key)
+	require.NoError(t, err)
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pkix, err := EncodeRSAPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	pkcs1Pub := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)})
+
+	data := []byte("app_id=1&ts=1700000000")
+	for name, priv := range map[string][]byte{"pkcs8": pkcs8, "pkcs1": pkcs1} {
+		t.Run(name, func(t *testing.T) {
+			k, err := ParseRSAPrivateKey(priv)
+			require.NoError(t, err)
+			sig, err := RSASign(k, data)
+			require.NoError(t, err)
+			for _, pub := range [][]byte{pkix, pkcs1Pub} {
+				pk, err := ParseRSAPublicKey(pub)
+				require.NoError(t, err)
+				assert.NoError(t, RSAVerify(pk, data, sig))
+				assert.Error(t, RSAVerify(pk, []byte("app_id=2&ts=1700000000"), sig))
+			}
+		})
+	}
+
+	other, err := GenerateRSAKey(2048)
+	require.NoError(t, err)
+	sig, err := RSASign(key, data)
+	require.NoError(t, err)
+	assert.Error(t, RSAVerify(&other.PublicKey, data, sig))
+
+	_, err = ParseRSAPrivateKey([]byte("not pem"))
+	assert.Error(t, err)
+	_, err = ParseRSAPublicKey(pkcs8)
+	assert.Error(t, err)
+}
+
+func TestPasswordHash(t *testing.T) {
+	params := Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	argon, err := HashPasswordArgon2("s3cret", params)
+	require.NoError(t, err)
+	assert.Contains(t, argon, "$argon2id$v=19$m=1024,t=1,p=1$")
+	bc, err := HashPasswordBcrypt("s3cret", 4)
+	require.NoError(t, err)
+
+	for _, encoded := range []string{argon, bc} {
+		assert.NoError(t, VerifyPassword(encoded, "s3cret"))
+		assert.ErrorIs(t, VerifyPassword(encoded, "wrong"), ErrPasswordMismatch)
+	}
+
+	// 相同密码每次加盐不同
+	again, err := HashPasswordArgon2("s3cret", params)
+	require.NoError(t, err)
+	assert.NotEqual(t, argon, again)
+
+	assert.ErrorIs(t, VerifyPassword(MD5Hash("s3cret"), "s3cret"), ErrUnknownHashFormat)
+	assert.ErrorIs(t, VerifyPassword("$argon2id$v=19$broken", "s3cret"), ErrUnknownHashFormat)
+	assert.ErrorIs(t, VerifyPassword("$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$aGFzaA", "s3cret"), ErrUnknownHashFormat)
+	assert.ErrorIs(t, VerifyPassword("$argon2id$v=19$m=1024,t=1,p=1$!!$aGFzaA", "s3cret"), ErrUnknownHashFormat)
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+)
+
+// RestartPolicy 托管 goroutine 退出后的重启策略
+type RestartPolicy int
+
+const (
+	RestartNever     RestartPolicy = iota // 不重启，默认
+	RestartOnPanic                        // 仅 panic 后重启
+	RestartOnFailure                      // panic 或返回错误后重启
+	RestartAlways                         // 除 ctx 取消外总是重启
+)
+
+// GoOption Go 的可选参数
+type GoOption func(*goOptions)
+
+type goOptions struct {
+	policy      RestartPolicy
+	backoff     Backoff
+	maxRestarts int
+}
+
+// WithRestart 设置重启策略，backoff 为 nil 时使用 1s ~ 30s 的指数退避
+func WithRestart(policy RestartPolicy, backoff Backoff) GoOption {
+	return func(o *goOptions) {
+		o.policy = policy
+		o.backoff = backoff
+	}
+}
+
+// WithMaxRestarts 限制最大重启次数，<= 0 表示不限
+func WithMaxRestarts(n int) GoOption {
+	return func(o *goOptions) { o.maxRestarts = n }
+}
+
+// managed 记录运行中的托管 goroutine，idle 在数量归零时关闭，供 WaitAll 等待
+var managed = struct {
+	sync.Mutex
+	count   int
+	idle    chan struct{}
+	running map[string]int
+}{running: make(map[string]int)}
+
+// Go 启动托管 goroutine：panic 会被恢复并连同堆栈记录日志，按重启策略重新运行，
+// ctx 取消后不再重启。进程退出前调用 WaitAll 等待全部结束
+func Go(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...GoOption) {
+	o := goOptions{policy: RestartNever}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.backoff == nil {
+		o.backoff = ExponentialBackoff(time.Second, 30*time.Second)
+	}
+
+	managed.Lock()
+	if managed.count == 0 {
+		managed.idle = make(chan struct{})
+	}
+	managed.count++
+	managed.running[name]++
+	managed.Unlock()
+
+	go func() {
+		defer func() {
+			managed.Lock()
+			if managed.running[name]--; managed.running[name] <= 0 {
+				delete(managed.running, name)
+			}
+			if managed.count--; managed.count == 0 {
+				close(managed.idle)
+			}
+			managed.Unlock()
+		}()
+		runManaged(ctx, name, fn, o)
+	}()
+}
+
+func runManaged(ctx context.Context, name string, fn func(ctx context.Context) error, o goOptions) {
+	for restarts := 0; ; restarts++ {
+		err := safeCall(ctx, fn)
+		var perr *PanicError
+		panicked := errors.As(err, &perr)
+		switch {
+		case panicked:
+			logger.Errorw(ctx, "goroutine panic", "goroutine", name, "panic", fmt.Sprint(perr.Value), "stack", string(perr.Stack))
+		case err != nil && ctx.Err() == nil:
+			logger.Warnw(ctx, "goroutine exited with error", "goroutine", name, "error", err.Error())
+		}
+
+		if ctx.Err() != nil || !o.shouldRestart(err, panicked) {
+			return
+		}
+		if o.maxRestarts > 0 && restarts >= o.maxRestarts {
+			logger.Errorw(ctx, "goroutine restart limit reached", "goroutine", name, "restarts", restarts)
+			return
+		}
+		delay := o.backoff(restarts + 1)
+		logger.Warnw(ctx, "goroutine restarting", "goroutine", name, "delay", delay.String())
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (o goOptions) shouldRestart(err error, panicked bool) bool {
+	switch o.policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	case RestartOnPanic:
+		return panicked
+	default:
+		return false
+	}
+}
+
+// WaitAll 等待所有托管 goroutine 结束，ctx 超时时返回仍在运行的名称
+func WaitAll(ctx context.Context) error {
+	managed.Lock()
+	idle := managed.idle
+	managed.Unlock()
+	if idle == nil {
+		return nil
+	}
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		managed.Lock()
+		names := make([]string, 0, len(managed.running))
+		for name := range managed.running {
+			names = append(names, name)
+		}
+		managed.Unlock()
+		sort.Strings(names)
+		return fmt.Errorf("wait goroutines: %w, still running: %s", ctx.Err(), strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError goroutine 中 panic 恢复后得到的错误
+type PanicError struct {
+	Value any    // recover() 返回值
+	Stack []byte // panic 时的调用栈
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n%s", e.Value, e.Stack)
+}
+
+// Unwrap panic 值本身是 error 时返回它
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Group 限制最大并发的 goroutine 组，panic 会转为 *PanicError，
+// 任一任务返回错误后取消组内 ctx，尚未启动的任务不再执行
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup 创建 goroutine 组，limit <= 0 表示不限并发，返回的 ctx 在首个错误或 Wait 返回时取消
+func NewGroup(ctx context.Context, limit int) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &Group{ctx: ctx, cancel: cancel}
+	if limit > 0 {
+		g.sem = make(chan struct{}, limit)
+	}
+	return g, ctx
+}
+
+// Go 启动任务，并发已满时阻塞等待空位；ctx 已取消时放弃执行并记录取消原因
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	if g.ctx.Err() != nil {
+		g.setErr(context.Cause(g.ctx))
+		return
+	}
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		case <-g.ctx.Done():
+			g.setErr(context.Cause(g.ctx))
+			return
+		}
+	}
+	g.start(fn)
+}
+
+// TryGo 并发未满时启动任务并返回 true，并发已满或 ctx 已取消时返回 false
+func (g *Group) TryGo(fn func(ctx context.Context) error) bool {
+	if g.ctx.Err() != nil {
+		return false
+	}
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(fn)
+	return true
+}
+
+// Wait 等待所有已启动的任务结束，返回首个错误
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(g.err)
+	return g.err
+}
+
+func (g *Group) start(fn func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		if err := safeCall(g.ctx, fn); err != nil {
+			g.setErr(err)
+		}
+	}()
+}
+
+func (g *Group) setErr(err error) {
+	g.errOnce.Do(func() {
+		g.err = err
+		g.cancel(err)
+	})
+}
+
+// safeCall 执行 fn 并把 panic 转为 *PanicError
+func safeCall(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn(ctx)
+}
+
+// ForEach 以最多 limit 个并发对 items 逐个执行 fn，返回首个错误
+func ForEach[T any](ctx context.Context, items []T, limit int, fn func(ctx context.Context, item T) error) error {
+	g, _ := NewGroup(ctx, limit)
+	for _, item := range items {
+		g.Go(func(ctx context.Context) error { return fn(ctx, item) })
+	}
+	return g.Wait()
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 分页参数的默认值与上限，服务可在启动时调整
+var (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// SortField 单个排序字段
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// PageRequest 通用分页请求，Page 从 1 开始。
+// Sort 以逗号分隔多个字段，支持 "-createdAt" 与 "createdAt:desc" 两种写法，默认升序
+type PageRequest struct {
+	Page     int    `json:"page" form:"page"`
+	PageSize int    `json:"pageSize" form:"pageSize"`
+	Sort     string `json:"sort" form:"sort"`
+}
+
+// ParsePageRequest 从 URL 查询参数解析分页请求（page、pageSize 或 page_size、sort），并做规范化
+func ParsePageRequest(values url.Values) PageRequest {
+	size := values.Get("pageSize")
+	if size == "" {
+		size = values.Get("page_size")
+	}
+	p := PageRequest{Sort: values.Get("sort")}
+	p.Page, _ = strconv.Atoi(values.Get("page"))
+	p.PageSize, _ = strconv.Atoi(size)
+	return p.Normalize()
+}
+
+// Normalize 将 Page 修正为 >= 1，PageSize 为空时取 DefaultPageSize，超过 MaxPageSize 时截断
+func (p PageRequest) Normalize() PageRequest {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PageSize <= 0 {
+		p.PageSize = DefaultPageSize
+	}
+	if MaxPageSize > 0 && p.PageSize > MaxPageSize {
+		p.PageSize = MaxPageSize
+	}
+	return p
+}
+
+// Offset 规范化后需要跳过的条数
+func (p PageRequest) Offset() int {
+	p = p.Normalize()
+	return (p.Page - 1) * p.PageSize
+}
+
+// Limit 规范化后的每页条数
+func (p PageRequest) Limit() int {
+	return p.Normalize().PageSize
+}
+
+// SortFields 按顺序解析 Sort，忽略空字段
+func (p PageRequest) SortFields() []SortField {
+	var fields []SortField
+	for _, part := range strings.Split(p.Sort, ",") {
+		part = strings.TrimSpace(part)
+		desc := false
+		if name, ok := strings.CutPrefix(part, "-"); ok {
+			part, desc = name, true
+		} else if name, order, ok := strings.Cut(part, ":"); ok {
+			part, desc = name, strings.EqualFold(strings.TrimSpace(order), "desc")
+		}
+		part = strings.TrimSpace(strings.TrimPrefix(part, "+"))
+		if part == "" {
+			continue
+		}
+		fields = append(fields, SortField{Field: part, Desc: desc})
+	}
+	return fields
+}
+
+// PageResponse 通用分页响应
+type PageResponse[T any] struct {
+	List       []T   `json:"list"`
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	PageSize   int   `json:"pageSize"`
+	TotalPages int   `json:"totalPages"`
+}
+
+// NewPageResponse 根据请求和查询结果构造分页响应，list 为 nil 时输出空数组
+func NewPageResponse[T any](req PageRequest, list []T, total int64) *PageResponse[T] {
+	req = req.Normalize()
+	if list == nil {
+		list = []T{}
+	}
+	pages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+	return &PageResponse[T]{
+		List:       list,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: pages,
+	}
+}
+
+// HasMore 是否还有下一页
+func (r *PageResponse[T]) HasMore() bool {
+	return r.Page < r.TotalPages
+}
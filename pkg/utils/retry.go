@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Backoff 返回第 attempt 次重试前的等待时间，attempt 从 1 开始
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff 每次重试前等待固定时间
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int) time.Duration { return d }
+}
+
+// ExponentialBackoff 指数退避 + 全抖动：在 [0, min(max, base*2^(attempt-1))] 内随机
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	if max < base {
+		max = base
+	}
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return time.Duration(rand.Int63n(int64(d) + 1))
+	}
+}
+
+// EqualJitterBackoff 指数退避 + 等抖动：在 [d/2, d] 内随机，d = min(max, base*2^(attempt-1))，
+// 保证至少等待一半时间，适合重连等不希望立即重试的场景
+func EqualJitterBackoff(base, max time.Duration) Backoff {
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	if max < base {
+		max = base
+	}
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		half := d / 2
+		return half + time.Duration(rand.Int63n(int64(d-half)+1))
+	}
+}
+
+// RetryOption Retry 的可选参数
+type RetryOption func(*retryOptions)
+
+type retryOptions struct {
+	retryIf func(error) bool
+	onRetry func(attempt int, err error, delay time.Duration)
+}
+
+// RetryIf 设置可重试错误的判断，返回 false 时立即返回该错误，默认所有错误都重试
+func RetryIf(fn func(err error) bool) RetryOption {
+	return func(o *retryOptions) { o.retryIf = fn }
+}
+
+// OnRetry 每次重试等待前回调，attempt 为刚失败的次数，delay 为即将等待的时间
+func OnRetry(fn func(attempt int, err error, delay time.Duration)) RetryOption {
+	return func(o *retryOptions) { o.onRetry = fn }
+}
+
+// Retry 执行 fn 直到成功、遇到不可重试错误、达到 attempts 次或 ctx 取消。
+// attempts <= 0 表示不限次数；backoff 为 nil 时不等待。
+// 次数用尽时返回最后一次的错误，等待期间 ctx 取消时返回包装了 ctx.Err() 的错误
+func Retry(ctx context.Context, attempts int, backoff Backoff, fn func(ctx context.Context) error, opts ...RetryOption) error {
+	var o retryOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return retryCanceled(err, lastErr)
+		}
+		lastErr = fn(ctx)
+		if lastErr == nil {
+			return nil
+		}
+		if o.retryIf != nil && !o.retryIf(lastErr) {
+			return lastErr
+		}
+		if attempts > 0 && attempt >= attempts {
+			return lastErr
+		}
+
+		var delay time.Duration
+		if backoff != nil {
+			delay = backoff(attempt)
+		}
+		if o.onRetry != nil {
+			o.onRetry(attempt, lastErr, delay)
+		}
+		if delay <= 0 {
+			continue
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return retryCanceled(ctx.Err(), lastErr)
+		}
+	}
+}
+
+func retryCanceled(ctxErr, lastErr error) error {
+	if lastErr == nil {
+		return ctxErr
+	}
+	return fmt.Errorf("%w (last error: %v)", ctxErr, lastErr)
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTemporary = errors.New("temporary")
+
+func TestRetry_AttemptsExhausted(t *testing.T) {
+	calls := 0
+	var retried []int
+	err := Retry(context.Background(), 3, ConstantBackoff(time.Millisecond), func(ctx context.Context) error {
+		calls++
+		return errTemporary
+	}, OnRetry(func(attempt int, err error, delay time.Duration) {
+		retried = append(retried, attempt)
+		assert.Equal(t, time.Millisecond, delay)
+	}))
+	assert.ErrorIs(t, err, errTemporary)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []int{1, 2}, retried)
+}
+
+func TestRetry_Succeeds(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), 0, nil, func(ctx context.Context) error {
+		if calls++; calls < 5 {
+			return errTemporary
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, calls)
+}
+
+func TestRetry_RetryIfFalse(t *testing.T) {
+	permanent := errors.New("permanent")
+	calls := 0
+	err := Retry(context.Background(), 5, nil, func(ctx context.Context) error {
+		calls++
+		if calls == 2 {
+			return permanent
+		}
+		return errTemporary
+	}, RetryIf(func(err error) bool { return errors.Is(err, errTemporary) }))
+	assert.ErrorIs(t, err, permanent)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetry_CanceledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	start := time.Now()
+	err := Retry(ctx, 0, ConstantBackoff(time.Hour), func(ctx context.Context) error {
+		calls++
+		return errTemporary
+	}, OnRetry(func(int, error, time.Duration) { cancel() }))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Contains(t, err.Error(), errTemporary.Error())
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(start), time.Minute)
+
+	// ctx 已取消时不执行 fn
+	err = Retry(ctx, 3, nil, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBackoff(t *testing.T) {
+	full := ExponentialBackoff(100*time.Millisecond, time.Second)
+	equal := EqualJitterBackoff(100*time.Millisecond, time.Second)
+	for attempt, ceil := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		for i := 0; i < 100; i++ {
+			d := full(attempt)
+			assert.True(t, d >= 0 && d <= ceil, "full jitter attempt %d: %v", attempt, d)
+			d = equal(attempt)
+			assert.True(t, d >= ceil/2 && d <= ceil, "equal jitter attempt %d: %v", attempt, d)
+		}
+	}
+}
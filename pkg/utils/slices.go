@@ -0,0 +1,92 @@
+package utils
+
+// Map 对每个元素执行 fn，返回结果切片
+func Map[T, R any](s []T, fn func(T) R) []R {
+	out := make([]R, len(s))
+	for i, v := range s {
+		out[i] = fn(v)
+	}
+	return out
+}
+
+// Filter 返回 fn 为 true 的元素，不修改原切片
+func Filter[T any](s []T, fn func(T) bool) []T {
+	out := make([]T, 0, len(s))
+	for _, v := range s {
+		if fn(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Unique 去重并保持首次出现的顺序
+func Unique[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	out := make([]T, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+// Chunk 按 size 切分为多个子切片，最后一段可能不足 size，子切片与原切片共享底层数组。
+// 常用于 Mongo / ES 分批写入，size <= 0 时 panic
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic("utils.Chunk: size must be positive")
+	}
+	out := make([][]T, 0, (len(s)+size-1)/size)
+	for i := 0; i < len(s); i += size {
+		end := min(i+size, len(s))
+		out = append(out, s[i:end:end])
+	}
+	return out
+}
+
+// GroupBy 按 key 分组，组内保持原顺序
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	out := make(map[K][]T)
+	for _, v := range s {
+		k := key(v)
+		out[k] = append(out[k], v)
+	}
+	return out
+}
+
+// Keys 返回 map 的所有 key，顺序不固定
+func Keys[K comparable, V any](m map[K]V) []K {
+	out := make([]K, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+// Values 返回 map 的所有 value，顺序不固定
+func Values[K comparable, V any](m map[K]V) []V {
+	out := make([]V, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	return out
+}
+
+// Diff 返回在 a 中但不在 b 中的元素，保持 a 的顺序
+func Diff[T comparable](a, b []T) []T {
+	exclude := make(map[T]struct{}, len(b))
+	for _, v := range b {
+		exclude[v] = struct{}{}
+	}
+	out := make([]T, 0, len(a))
+	for _, v := range a {
+		if _, ok := exclude[v]; !ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
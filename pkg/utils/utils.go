@@ -106,7 +106,7 @@ func GetMilliTimestamp() int64 {
 	return time.Now().UnixNano() / 1e6
 }
 
-// MD5Hash 支持多个字符串拼接后计算 MD5 值
+// MD5Hash 支持多个字符串拼接后计算 MD5 值，仅用于生成缓存键等非安全场景，密码请使用 HashPassword
 func MD5Hash(first string, others ...string) string {
 	// 预分配 buffer 容量（减少内存分配）
 	totalLen := len(first)
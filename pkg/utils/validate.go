@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// 校验失败的错误类型，可通过 errors.Is 判断
+var (
+	ErrInvalidMobile = errors.New("invalid mobile number")
+	ErrInvalidEmail  = errors.New("invalid email")
+	ErrInvalidIDCard = errors.New("invalid id card number")
+	ErrInvalidURL    = errors.New("invalid url")
+	ErrInvalidIP     = errors.New("invalid ip")
+	ErrInvalidCIDR   = errors.New("invalid cidr")
+)
+
+// InvalidError 校验失败的详情，Unwrap 返回对应的 ErrInvalidXxx
+type InvalidError struct {
+	Kind   error  // ErrInvalidXxx 之一
+	Value  string // 被校验的值
+	Reason string // 失败原因，可为空
+}
+
+func (e *InvalidError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%v: %q", e.Kind, e.Value)
+	}
+	return fmt.Sprintf("%v: %q, %s", e.Kind, e.Value, e.Reason)
+}
+
+func (e *InvalidError) Unwrap() error {
+	return e.Kind
+}
+
+func invalid(kind error, value, reason string) error {
+	return &InvalidError{Kind: kind, Value: value, Reason: reason}
+}
+
+var (
+	mobileRegexp = regexp.MustCompile(`^1[3-9]\d{9}$`)
+	idCardRegexp = regexp.MustCompile(`^\d{17}[\dXx]$`)
+)
+
+// ValidateMobile 校验中国大陆手机号，允许 +86 / 86 前缀
+func ValidateMobile(s string) error {
+	n := strings.TrimPrefix(strings.TrimPrefix(s, "+"), "86")
+	if len(n) != 11 {
+		n = s
+	}
+	if !mobileRegexp.MatchString(n) {
+		return invalid(ErrInvalidMobile, s, "")
+	}
+	return nil
+}
+
+// ValidateEmail 校验邮箱地址，不接受带显示名的形式（如 "Tom <tom@example.com>"）
+func ValidateEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return invalid(ErrInvalidEmail, s, err.Error())
+	}
+	if addr.Address != s || addr.Name != "" {
+		return invalid(ErrInvalidEmail, s, "display name not allowed")
+	}
+	if at := strings.LastIndexByte(s, '@'); !strings.Contains(s[at+1:], ".") {
+		return invalid(ErrInvalidEmail, s, "domain must contain a dot")
+	}
+	return nil
+}
+
+// idCardWeights 18 位身份证前 17 位的加权因子（GB 11643-1999）
+var idCardWeights = [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+const idCardCheckCodes = "10X98765432"
+
+// ValidateIDCard 校验 18 位居民身份证号，包括出生日期和末位校验码
+func ValidateIDCard(s string) error {
+	if !idCardRegexp.MatchString(s) {
+		return invalid(ErrInvalidIDCard, s, "must be 17 digits followed by a digit or X")
+	}
+	birth, err := time.Parse("20060102", s[6:14])
+	if err != nil || birth.Year() < 1900 || birth.After(time.Now()) {
+		return invalid(ErrInvalidIDCard, s, "invalid birth date")
+	}
+	sum := 0
+	for i, w := range idCardWeights {
+		sum += int(s[i]-'0') * w
+	}
+	if strings.ToUpper(s[17:]) != string(idCardCheckCodes[sum%11]) {
+		return invalid(ErrInvalidIDCard, s, "checksum mismatch")
+	}
+	return nil
+}
+
+// ValidateURL 校验带主机名的 http / https 地址
+func ValidateURL(s string) error {
+	u, err := url.ParseRequestURI(s)
+	if err != nil {
+		return invalid(ErrInvalidURL, s, err.Error())
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return invalid(ErrInvalidURL, s, "scheme must be http or https")
+	}
+	if u.Hostname() == "" {
+		return invalid(ErrInvalidURL, s, "missing host")
+	}
+	return nil
+}
+
+// ValidateIP 校验 IPv4 / IPv6 地址
+func ValidateIP(s string) error {
+	if net.ParseIP(s) == nil {
+		return invalid(ErrInvalidIP, s, "")
+	}
+	return nil
+}
+
+// ValidateCIDR 校验 CIDR 网段，如 10.0.0.0/8
+func ValidateCIDR(s string) error {
+	if _, _, err := net.ParseCIDR(s); err != nil {
+		return invalid(ErrInvalidCIDR, s, "")
+	}
+	return nil
+}
+
+// RegisterValidations 向校验器注册 mobile、idcard 标签，
+// email、url、ip、cidr 使用 validator 内置标签即可
+func RegisterValidations(v *validator.Validate) error {
+	rules := map[string]func(string) error{
+		"mobile": ValidateMobile,
+		"idcard": ValidateIDCard,
+	}
+	for tag, fn := range rules {
+		err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			return fn(fl.Field().String()) == nil
+		})
+		if err != nil {
+			return fmt.Errorf("register validation %s: %w", tag, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/code-sigs/go-box/pkg/kafka"
+	"github.com/code-sigs/go-box/pkg/redis"
+	"github.com/code-sigs/go-box/pkg/utils"
+)
+
+// Target 消息投递范围
+type Target string
+
+const (
+	TargetUser Target = "user" // 指定用户的全部连接
+	TargetRoom Target = "room" // 指定房间的全部连接
+	TargetAll  Target = "all"  // 所有连接
+)
+
+// Envelope 跨实例广播的消息
+type Envelope struct {
+	Origin string `json:"origin"`       // 发送实例的 Hub ID，实例收到自己发出的消息时忽略
+	Target Target `json:"target"`       // 投递范围
+	To     string `json:"to,omitempty"` // 用户 ID 或房间名，TargetAll 时为空
+	Type   int    `json:"type"`         // websocket.TextMessage 或 websocket.BinaryMessage
+	Data   []byte `json:"data"`         // 消息内容
+}
+
+// Broker 跨实例消息通道，Hub 发送的消息经 Broker 送达其他实例上的连接
+type Broker interface {
+	// Publish 广播消息给所有实例
+	Publish(ctx context.Context, env *Envelope) error
+	// Subscribe 阻塞接收消息直到 ctx 结束，连接断开时返回错误，由 Hub 负责重连
+	Subscribe(ctx context.Context, handler func(ctx context.Context, env *Envelope)) error
+	// Close 释放资源
+	Close() error
+}
+
+// RedisBroker 基于 Redis pub/sub，消息不持久化，订阅断开期间的消息会丢失
+type RedisBroker struct {
+	rdb     *redis.RedisClient
+	channel string
+}
+
+// NewRedisBroker 创建 Redis 通道，channel 为空时使用 "ws:broadcast"，同一业务的实例需使用相同 channel
+func NewRedisBroker(rdb *redis.RedisClient, channel string) *RedisBroker {
+	if channel == "" {
+		channel = "ws:broadcast"
+	}
+	return &RedisBroker{rdb: rdb, channel: channel}
+}
+
+func (b *RedisBroker) Publish(ctx context.Context, env *Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("ws: marshal envelope: %w", err)
+	}
+	return b.rdb.DB().Publish(ctx, b.channel, data).Err()
+}
+
+func (b *RedisBroker) Subscribe(ctx context.Context, handler func(ctx context.Context, env *Envelope)) error {
+	ps := b.rdb.DB().Subscribe(ctx, b.channel)
+	defer ps.Close()
+	// 等待订阅确认，保证返回前的错误能被 Hub 感知
+	if _, err := ps.Receive(ctx); err != nil {
+		return fmt.Errorf("ws: subscribe %s: %w", b.channel, err)
+	}
+	ch := ps.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return errors.New("ws: redis subscription closed")
+			}
+			var env Envelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				continue
+			}
+			handler(ctx, &env)
+		}
+	}
+}
+
+func (b *RedisBroker) Close() error {
+	return nil
+}
+
+// KafkaBroker 基于 Kafka，每个实例使用独立的消费组以收到全部消息，从最新位点开始消费。
+// 实例重启后会生成新的消费组，旧消费组由 broker 按 offsets.retention 清理
+type KafkaBroker struct {
+	k        *kafka.Kafka[Envelope]
+	topic    string
+	group    string
+	producer *kafka.Producer[Envelope]
+}
+
+// NewKafkaBroker 创建 Kafka 通道，groupPrefix 为空时使用 "ws-"
+func NewKafkaBroker(k *kafka.Kafka[Envelope], topic, groupPrefix string) (*KafkaBroker, error) {
+	if groupPrefix == "" {
+		groupPrefix = "ws-"
+	}
+	producer, err := k.NewProducer(topic)
+	if err != nil {
+		return nil, fmt.Errorf("ws: create kafka producer: %w", err)
+	}
+	return &KafkaBroker{
+		k:        k,
+		topic:    topic,
+		group:    groupPrefix + utils.GenerateUUIDStr(),
+		producer: producer,
+	}, nil
+}
+
+func (b *KafkaBroker) Publish(ctx context.Context, env *Envelope) error {
+	return b.producer.SendContext(ctx, env)
+}
+
+func (b *KafkaBroker) Subscribe(ctx context.Context, handler func(ctx context.Context, env *Envelope)) error {
+	consumer, err := b.k.NewConsumerGroup(b.topic, b.group, func(ctx context.Context, env *Envelope) error {
+		handler(ctx, env)
+		return nil
+	}, kafka.ConsumerOptions{})
+	if err != nil {
+		return fmt.Errorf("ws: create kafka consumer: %w", err)
+	}
+	defer consumer.Close()
+	return consumer.Run(ctx)
+}
+
+func (b *KafkaBroker) Close() error {
+	return b.producer.Close()
+}
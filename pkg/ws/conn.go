@@ -0,0 +1,163 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrConnClosed = errors.New("ws: connection closed")
+	ErrQueueFull  = errors.New("ws: send queue full")
+)
+
+type outbound struct {
+	typ  int
+	data []byte
+}
+
+// Conn 一个 WebSocket 连接，发送经过有界队列由单独的协程写出
+type Conn struct {
+	id     string
+	userID string
+	hub    *Hub
+	ws     *websocket.Conn
+	send   chan outbound
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	rooms map[string]struct{}
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// ID 连接 ID，进程内唯一
+func (c *Conn) ID() string {
+	return c.id
+}
+
+// UserID 建立连接时识别出的用户，匿名连接为空
+func (c *Conn) UserID() string {
+	return c.userID
+}
+
+// Context 继承握手请求的值（traceID、租户等），连接关闭时取消
+func (c *Conn) Context() context.Context {
+	return c.ctx
+}
+
+// Rooms 当前加入的房间
+func (c *Conn) Rooms() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rooms := make([]string, 0, len(c.rooms))
+	for room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+// Send 发送文本消息，不阻塞；队列已满说明客户端消费过慢，连接会被关闭并返回 ErrQueueFull
+func (c *Conn) Send(data []byte) error {
+	return c.enqueue(outbound{typ: websocket.TextMessage, data: data})
+}
+
+// SendBinary 发送二进制消息
+func (c *Conn) SendBinary(data []byte) error {
+	return c.enqueue(outbound{typ: websocket.BinaryMessage, data: data})
+}
+
+// SendJSON 将 v 编码为 JSON 后以文本消息发送
+func (c *Conn) SendJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Send(data)
+}
+
+// Close 关闭连接，可重复调用
+func (c *Conn) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.cancel()
+	})
+}
+
+func (c *Conn) enqueue(msg outbound) error {
+	select {
+	case <-c.done:
+		return ErrConnClosed
+	default:
+	}
+	select {
+	case c.send <- msg:
+		return nil
+	case <-c.done:
+		return ErrConnClosed
+	default:
+		logger.Warnw(c.ctx, "websocket send queue full, closing connection", "conn", c.id, "userID", c.userID)
+		c.Close()
+		return ErrQueueFull
+	}
+}
+
+// readPump 读取客户端消息并按顺序交给 OnMessage，收到 pong 时延长读超时；退出时从 Hub 注销
+func (c *Conn) readPump() {
+	defer func() {
+		c.Close()
+		c.hub.remove(c)
+	}()
+	cfg := c.hub.cfg
+	c.ws.SetReadLimit(cfg.MaxMessageSize)
+	_ = c.ws.SetReadDeadline(time.Now().Add(cfg.PongTimeout))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(cfg.PongTimeout))
+	})
+	for {
+		typ, data, err := c.ws.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) {
+				logger.Debugw(c.ctx, "websocket read failed", "conn", c.id, "error", err.Error())
+			}
+			return
+		}
+		if c.hub.onMessage != nil {
+			c.hub.onMessage(c, typ, data)
+		}
+	}
+}
+
+// writePump 唯一的写协程，写出队列中的消息并定时发送 ping；退出时关闭底层连接使 readPump 结束
+func (c *Conn) writePump() {
+	cfg := c.hub.cfg
+	ticker := time.NewTicker(cfg.PingInterval)
+	defer func() {
+		ticker.Stop()
+		c.Close()
+		_ = c.ws.Close()
+	}()
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(cfg.WriteTimeout))
+			if err := c.ws.WriteMessage(msg.typ, msg.data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(cfg.WriteTimeout)); err != nil {
+				return
+			}
+		case <-c.done:
+			_ = c.ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(cfg.WriteTimeout))
+			return
+		}
+	}
+}
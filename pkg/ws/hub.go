@@ -0,0 +1,404 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/code-sigs/go-box/pkg/logger"
+	"github.com/code-sigs/go-box/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// Config 连接参数，零值字段使用默认值
+type Config struct {
+	SendQueue      int           `mapstructure:"sendQueue" default:"256"`        // 每个连接的发送队列长度，写满时断开慢连接
+	MaxMessageSize int64         `mapstructure:"maxMessageSize" default:"65536"` // 客户端消息最大字节数
+	WriteTimeout   time.Duration `mapstructure:"writeTimeout" default:"10s"`     // 单条消息写超时
+	PongTimeout    time.Duration `mapstructure:"pongTimeout" default:"60s"`      // 超过该时间未收到任何消息或 pong 时断开
+	PingInterval   time.Duration `mapstructure:"pingInterval" default:"50s"`     // 心跳间隔，需小于 PongTimeout
+
+	CheckOrigin func(r *http.Request) bool `mapstructure:"-"` // 校验握手请求的 Origin，默认只允许同源，跨域时用 AllowOrigins
+}
+
+// AllowOrigins 返回只允许指定 Origin（如 "https://app.example.com"）的 CheckOrigin，
+// 传入 "*" 时允许任意来源，会放开跨站 WebSocket 劫持，仅用于开发环境；没有 Origin 头的非浏览器客户端始终允许
+func AllowOrigins(origins ...string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		for _, o := range origins {
+			if o == "*" || strings.EqualFold(o, origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// WithDefaults 填充零值字段，供 router 等直接使用 Config 的组件复用
+func (c Config) WithDefaults() Config {
+	if c.SendQueue <= 0 {
+		c.SendQueue = 256
+	}
+	if c.MaxMessageSize <= 0 {
+		c.MaxMessageSize = 64 * 1024
+	}
+	if c.WriteTimeout <= 0 {
+		c.WriteTimeout = 10 * time.Second
+	}
+	if c.PongTimeout <= 0 {
+		c.PongTimeout = 60 * time.Second
+	}
+	if c.PingInterval <= 0 || c.PingInterval >= c.PongTimeout {
+		c.PingInterval = c.PongTimeout * 5 / 6
+	}
+	return c
+}
+
+// Hub 管理本实例的连接，按用户和房间索引；配置 Broker 后发送的消息同时送达其他实例上的连接
+type Hub struct {
+	id       string
+	cfg      Config
+	upgrader websocket.Upgrader
+	broker   Broker
+
+	onConnect    func(c *Conn)
+	onMessage    func(c *Conn, typ int, data []byte)
+	onDisconnect func(c *Conn)
+
+	mu     sync.RWMutex
+	conns  map[*Conn]struct{}
+	users  map[string]map[*Conn]struct{}
+	rooms  map[string]map[*Conn]struct{}
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup // 订阅协程与连接的 readPump
+}
+
+// New 创建 Hub，broker 为 nil 时只在本实例内投递
+func New(cfg Config, broker Broker) *Hub {
+	cfg = cfg.WithDefaults()
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &Hub{
+		id:  utils.GenerateUUIDStr(),
+		cfg: cfg,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: cfg.CheckOrigin,
+		},
+		broker: broker,
+		conns:  make(map[*Conn]struct{}),
+		users:  make(map[string]map[*Conn]struct{}),
+		rooms:  make(map[string]map[*Conn]struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	if broker != nil {
+		h.wg.Add(1)
+		go h.subscribe()
+	}
+	return h
+}
+
+// OnConnect 连接建立后、开始收发前调用，可在此加入房间；需在 Serve 之前设置
+func (h *Hub) OnConnect(fn func(c *Conn)) *Hub {
+	h.onConnect = fn
+	return h
+}
+
+// OnMessage 收到客户端消息时调用，同一连接的消息按顺序同步调用，耗时处理应自行异步
+func (h *Hub) OnMessage(fn func(c *Conn, typ int, data []byte)) *Hub {
+	h.onMessage = fn
+	return h
+}
+
+// OnDisconnect 连接断开并从 Hub 注销后调用
+func (h *Hub) OnDisconnect(fn func(c *Conn)) *Hub {
+	h.onDisconnect = fn
+	return h
+}
+
+// ID 实例 ID，用于跨实例广播时识别消息来源
+func (h *Hub) ID() string {
+	return h.id
+}
+
+// Serve 将请求升级为 WebSocket 并注册连接，userID 为空表示匿名连接；升级失败时已向客户端写入错误响应
+func (h *Hub) Serve(w http.ResponseWriter, r *http.Request, userID string) (*Conn, error) {
+	h.mu.RLock()
+	closed := h.closed
+	h.mu.RUnlock()
+	if closed {
+		http.Error(w, "websocket hub closed", http.StatusServiceUnavailable)
+		return nil, ErrConnClosed
+	}
+	wsConn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ws: upgrade: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	c := &Conn{
+		id:     utils.GenerateUUIDStr(),
+		userID: userID,
+		hub:    h,
+		ws:     wsConn,
+		send:   make(chan outbound, h.cfg.SendQueue),
+		ctx:    ctx,
+		cancel: cancel,
+		rooms:  make(map[string]struct{}),
+		done:   make(chan struct{}),
+	}
+	if !h.add(c) {
+		cancel()
+		_ = wsConn.Close()
+		return nil, ErrConnClosed
+	}
+	if h.onConnect != nil {
+		h.onConnect(c)
+	}
+	go c.writePump()
+	go c.readPump()
+	return c, nil
+}
+
+// Handler 返回 gin 处理函数，identify 从请求中识别用户（如校验 token），返回错误时响应 401
+func (h *Hub) Handler(identify func(c *gin.Context) (string, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var userID string
+		if identify != nil {
+			var err error
+			if userID, err = identify(c); err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": http.StatusUnauthorized, "message": err.Error()})
+				return
+			}
+		}
+		if _, err := h.Serve(c.Writer, c.Request, userID); err != nil {
+			logger.Warnw(c.Request.Context(), "websocket upgrade failed", "error", err.Error())
+		}
+	}
+}
+
+// Join 将连接加入房间
+func (h *Hub) Join(c *Conn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.conns[c]; !ok {
+		return
+	}
+	index(h.rooms, room, c)
+	c.mu.Lock()
+	c.rooms[room] = struct{}{}
+	c.mu.Unlock()
+}
+
+// Leave 将连接移出房间
+func (h *Hub) Leave(c *Conn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	unindex(h.rooms, room, c)
+	c.mu.Lock()
+	delete(c.rooms, room)
+	c.mu.Unlock()
+}
+
+// Count 本实例的连接数
+func (h *Hub) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns)
+}
+
+// Online 用户在本实例是否有连接
+func (h *Hub) Online(userID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.users[userID]) > 0
+}
+
+// UserConns 用户在本实例的全部连接
+func (h *Hub) UserConns(userID string) []*Conn {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return collect(h.users[userID])
+}
+
+// RoomSize 房间在本实例的连接数
+func (h *Hub) RoomSize(room string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.rooms[room])
+}
+
+// SendToUser 向用户在所有实例上的连接发送文本消息
+func (h *Hub) SendToUser(ctx context.Context, userID string, data []byte) error {
+	return h.Deliver(ctx, Envelope{Target: TargetUser, To: userID, Type: websocket.TextMessage, Data: data})
+}
+
+// SendToRoom 向房间在所有实例上的连接发送文本消息
+func (h *Hub) SendToRoom(ctx context.Context, room string, data []byte) error {
+	return h.Deliver(ctx, Envelope{Target: TargetRoom, To: room, Type: websocket.TextMessage, Data: data})
+}
+
+// Broadcast 向所有实例上的全部连接发送文本消息
+func (h *Hub) Broadcast(ctx context.Context, data []byte) error {
+	return h.Deliver(ctx, Envelope{Target: TargetAll, Type: websocket.TextMessage, Data: data})
+}
+
+// Deliver 先投递到本实例的连接，再经 Broker 发给其他实例；单个连接发送失败不影响其他连接
+func (h *Hub) Deliver(ctx context.Context, env Envelope) error {
+	if env.Type == 0 {
+		env.Type = websocket.TextMessage
+	}
+	env.Origin = h.id
+	h.deliverLocal(ctx, &env)
+	if h.broker == nil {
+		return nil
+	}
+	if err := h.broker.Publish(ctx, &env); err != nil {
+		return fmt.Errorf("ws: publish: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭全部连接并停止订阅，等待连接退出或 ctx 到期；签名与 router.OnShutdown 一致
+func (h *Hub) Close(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	conns := collect(h.conns)
+	h.mu.Unlock()
+	h.cancel()
+	for _, c := range conns {
+		c.Close()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("close websocket hub: %w", ctx.Err())
+	}
+	if h.broker != nil {
+		if closeErr := h.broker.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (h *Hub) add(c *Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.conns[c] = struct{}{}
+	if c.userID != "" {
+		index(h.users, c.userID, c)
+	}
+	h.wg.Add(1)
+	return true
+}
+
+func (h *Hub) remove(c *Conn) {
+	h.mu.Lock()
+	delete(h.conns, c)
+	if c.userID != "" {
+		unindex(h.users, c.userID, c)
+	}
+	c.mu.Lock()
+	for room := range c.rooms {
+		unindex(h.rooms, room, c)
+	}
+	c.mu.Unlock()
+	h.mu.Unlock()
+
+	if h.onDisconnect != nil {
+		h.onDisconnect(c)
+	}
+	h.wg.Done()
+}
+
+func (h *Hub) deliverLocal(ctx context.Context, env *Envelope) {
+	h.mu.RLock()
+	var targets []*Conn
+	switch env.Target {
+	case TargetUser:
+		targets = collect(h.users[env.To])
+	case TargetRoom:
+		targets = collect(h.rooms[env.To])
+	case TargetAll:
+		targets = collect(h.conns)
+	}
+	h.mu.RUnlock()
+	for _, c := range targets {
+		if err := c.enqueue(outbound{typ: env.Type, data: env.Data}); err != nil {
+			logger.Debugw(ctx, "websocket deliver failed", "conn", c.id, "error", err.Error())
+		}
+	}
+}
+
+// subscribe 接收其他实例的消息，Broker 断开后按指数退避重连
+func (h *Hub) subscribe() {
+	defer h.wg.Done()
+	backoff := time.Second
+	for {
+		err := h.broker.Subscribe(h.ctx, func(ctx context.Context, env *Envelope) {
+			if env.Origin != h.id {
+				h.deliverLocal(ctx, env)
+			}
+		})
+		if h.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Warnw(h.ctx, "websocket broker subscription failed", "error", err.Error())
+		}
+		select {
+		case <-time.After(backoff):
+		case <-h.ctx.Done():
+			return
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+func index(m map[string]map[*Conn]struct{}, key string, c *Conn) {
+	set, ok := m[key]
+	if !ok {
+		set = make(map[*Conn]struct{})
+		m[key] = set
+	}
+	set[c] = struct{}{}
+}
+
+func unindex(m map[string]map[*Conn]struct{}, key string, c *Conn) {
+	if set, ok := m[key]; ok {
+		delete(set, c)
+		if len(set) == 0 {
+			delete(m, key)
+		}
+	}
+}
+
+func collect(set map[*Conn]struct{}) []*Conn {
+	conns := make([]*Conn, 0, len(set))
+	for c := range set {
+		conns = append(conns, c)
+	}
+	return conns
+}